@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"time"
+
+	"github.com/caarlos0/env/v6"
+	"go.uber.org/zap"
+
+	"github.com/Te8va/wallet/internal/config"
+	"github.com/Te8va/wallet/internal/repository"
+	"github.com/Te8va/wallet/internal/service"
+)
+
+func main() {
+	logger, _ := zap.NewProduction()
+	defer logger.Sync()
+
+	var dateStr string
+	flag.StringVar(&dateStr, "date", "", "Accrual date in YYYY-MM-DD format (defaults to yesterday, UTC)")
+	flag.Parse()
+
+	date := time.Now().UTC().AddDate(0, 0, -1)
+	if dateStr != "" {
+		var err error
+		date, err = time.Parse(time.DateOnly, dateStr)
+		if err != nil {
+			logger.Fatal("Invalid date", zap.String("date", dateStr), zap.Error(err))
+		}
+	}
+
+	cfg := config.Config{}
+	if err := env.Parse(&cfg); err != nil {
+		logger.Fatal("Failed to parse env", zap.Error(err))
+	}
+
+	ctx := context.Background()
+	pool, err := repository.GetPgxPool(ctx, cfg.PostgresConn)
+	if err != nil {
+		logger.Fatal("Failed to create postgres connection pool", zap.Error(err))
+	}
+	defer pool.Close()
+
+	interestRepo, err := repository.NewInterestRepository(pool)
+	if err != nil {
+		logger.Fatal("Failed to create interest repository", zap.Error(err))
+	}
+	interestService := service.NewInterestService(interestRepo)
+
+	result, err := interestService.RunForDate(ctx, date)
+	logger.Info("Interest run finished",
+		zap.String("date", result.Date.Format(time.DateOnly)),
+		zap.Int("accrued", result.Accrued),
+		zap.Int("credited", result.Credited),
+		zap.Int("failed", result.Failed),
+	)
+	if err != nil {
+		logger.Fatal("Interest run failed", zap.Error(err))
+	}
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"os"
+	"strings"
+
+	"github.com/caarlos0/env/v6"
+	"go.uber.org/zap"
+
+	"github.com/Te8va/wallet/internal/config"
+	"github.com/Te8va/wallet/internal/domain"
+	"github.com/Te8va/wallet/internal/repository"
+	"github.com/Te8va/wallet/internal/service"
+)
+
+func main() {
+	logger, _ := zap.NewProduction()
+	defer logger.Sync()
+
+	var (
+		projections string
+		opts        domain.ReplayOptions
+	)
+
+	flag.StringVar(&projections, "projection", "", "Comma-separated projections to rebuild (defaults to all)")
+	flag.BoolVar(&opts.FromSnapshot, "from-snapshot", false, "Start from the latest balance snapshots instead of the first event")
+	flag.BoolVar(&opts.DryRun, "dry-run", false, "Rebuild and report without writing the projections")
+	flag.Parse()
+
+	var names []string
+	if projections != "" {
+		names = strings.Split(projections, ",")
+	}
+
+	cfg := config.Config{}
+	if err := env.Parse(&cfg); err != nil {
+		logger.Fatal("Failed to parse env", zap.Error(err))
+	}
+
+	ctx := context.Background()
+	pool, err := repository.GetPgxPool(ctx, cfg.PostgresConn)
+	if err != nil {
+		logger.Fatal("Failed to create postgres connection pool", zap.Error(err))
+	}
+	defer pool.Close()
+
+	replayRepo, err := repository.NewReplayRepository(pool)
+	if err != nil {
+		logger.Fatal("Failed to create replay repository", zap.Error(err))
+	}
+	replayService := service.NewReplayService(replayRepo)
+
+	results, err := replayService.Replay(ctx, names, opts)
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if encErr := enc.Encode(results); encErr != nil {
+		logger.Error("Failed to write results", zap.Error(encErr))
+	}
+
+	if err != nil {
+		logger.Fatal("Replay failed", zap.Strings("available", replayService.Projections()), zap.Error(err))
+	}
+}
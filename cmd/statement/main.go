@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"os"
+
+	"github.com/caarlos0/env/v6"
+	"go.uber.org/zap"
+
+	"github.com/Te8va/wallet/internal/config"
+	"github.com/Te8va/wallet/internal/repository"
+	"github.com/Te8va/wallet/internal/service"
+	"github.com/Te8va/wallet/internal/statement"
+)
+
+func main() {
+	logger, _ := zap.NewProduction()
+	defer logger.Sync()
+
+	var (
+		walletID  string
+		fromStr   string
+		toStr     string
+		formatStr string
+		output    string
+	)
+
+	flag.StringVar(&walletID, "wallet", "", "Wallet ID")
+	flag.StringVar(&fromStr, "from", "", "Period start, YYYY-MM-DD or RFC 3339 time")
+	flag.StringVar(&toStr, "to", "", "Period end, YYYY-MM-DD (inclusive) or RFC 3339 time (exclusive)")
+	flag.StringVar(&formatStr, "format", "text", "Output format (json, csv, text, camt053, ofx)")
+	flag.StringVar(&output, "out", "", "Output file (defaults to stdout)")
+	flag.Parse()
+
+	if walletID == "" {
+		logger.Fatal("Wallet ID is required, use -wallet flag")
+	}
+
+	format, err := statement.ParseFormat(formatStr)
+	if err != nil {
+		logger.Fatal("Invalid format", zap.String("format", formatStr), zap.Error(err))
+	}
+
+	from, to, err := statement.ParsePeriod(fromStr, toStr)
+	if err != nil {
+		logger.Fatal("Invalid period", zap.Error(err))
+	}
+
+	cfg := config.Config{}
+	if err := env.Parse(&cfg); err != nil {
+		logger.Fatal("Failed to parse env", zap.Error(err))
+	}
+
+	ctx := context.Background()
+	pool, err := repository.GetPgxPool(ctx, cfg.PostgresConn)
+	if err != nil {
+		logger.Fatal("Failed to create postgres connection pool", zap.Error(err))
+	}
+	defer pool.Close()
+
+	walletRepo, err := repository.NewWalletRepository(pool)
+	if err != nil {
+		logger.Fatal("Failed to create wallet repository", zap.Error(err))
+	}
+	statementService := service.NewStatementService(walletRepo)
+
+	st, err := statementService.GetStatement(ctx, walletID, from, to)
+	if err != nil {
+		logger.Fatal("Failed to build statement", zap.Error(err))
+	}
+
+	out := os.Stdout
+	if output != "" {
+		out, err = os.Create(output)
+		if err != nil {
+			logger.Fatal("Failed to create output file", zap.Error(err))
+		}
+		defer out.Close()
+	}
+
+	if err = statement.Write(out, format, st, statement.Options{
+		Currency:   cfg.Currency,
+		MinorUnits: cfg.CurrencyMinorUnits,
+	}); err != nil {
+		logger.Fatal("Failed to write statement", zap.Error(err))
+	}
+}
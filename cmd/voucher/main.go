@@ -0,0 +1,148 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"flag"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/caarlos0/env/v6"
+	"go.uber.org/zap"
+
+	"github.com/Te8va/wallet/internal/config"
+	"github.com/Te8va/wallet/internal/domain"
+	"github.com/Te8va/wallet/internal/repository"
+	"github.com/Te8va/wallet/internal/service"
+)
+
+func main() {
+	logger, _ := zap.NewProduction()
+	defer logger.Sync()
+
+	var (
+		treasuryWalletID string
+		value            int64
+		count            int
+		uses             int
+		expiresStr       string
+		output           string
+	)
+
+	flag.StringVar(&treasuryWalletID, "treasury", "", "Treasury wallet ID the batch is funded from")
+	flag.Int64Var(&value, "value", 0, "Value of a voucher")
+	flag.IntVar(&count, "count", 0, "Number of codes to generate")
+	flag.IntVar(&uses, "uses", 1, "Number of times a code can be redeemed, by different wallets")
+	flag.StringVar(&expiresStr, "expires", "", "Expiry, YYYY-MM-DD (valid through that day, UTC) or RFC 3339 time")
+	flag.StringVar(&output, "out", "", "CSV file for the codes (defaults to stdout)")
+	flag.Parse()
+
+	if treasuryWalletID == "" {
+		logger.Fatal("Treasury wallet ID is required, use -treasury flag")
+	}
+	if value <= 0 {
+		logger.Fatal("Value must be more than 0, use -value flag")
+	}
+	if count <= 0 || count > service.MaxVoucherBatchCount {
+		logger.Fatal("Count must be between 1 and the maximum batch size, use -count flag",
+			zap.Int("max", service.MaxVoucherBatchCount))
+	}
+	if uses <= 0 {
+		logger.Fatal("Uses must be more than 0, use -uses flag")
+	}
+
+	expiresAt, err := parseExpiry(expiresStr)
+	if err != nil {
+		logger.Fatal("Invalid expiry, use -expires flag", zap.Error(err))
+	}
+
+	cfg := config.Config{}
+	if err := env.Parse(&cfg); err != nil {
+		logger.Fatal("Failed to parse env", zap.Error(err))
+	}
+
+	// The codes cannot be recovered once the batch is issued, so the output
+	// is opened first.
+	out := os.Stdout
+	if output != "" {
+		out, err = os.OpenFile(output, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+		if err != nil {
+			logger.Fatal("Failed to create output file", zap.Error(err))
+		}
+	}
+
+	ctx := context.Background()
+	pool, err := repository.GetPgxPool(ctx, cfg.PostgresConn)
+	if err != nil {
+		logger.Fatal("Failed to create postgres connection pool", zap.Error(err))
+	}
+	defer pool.Close()
+
+	voucherRepo, err := repository.NewVoucherRepository(pool)
+	if err != nil {
+		logger.Fatal("Failed to create voucher repository", zap.Error(err))
+	}
+	voucherService := service.NewVoucherService(voucherRepo, service.VoucherPolicy{})
+
+	batch, err := voucherService.IssueVoucherBatch(ctx, domain.VoucherBatchParams{
+		TreasuryWalletID: treasuryWalletID,
+		Value:            value,
+		MaxRedemptions:   uses,
+		Count:            count,
+		ExpiresAt:        expiresAt,
+	})
+	if err != nil {
+		logger.Fatal("Failed to issue voucher batch", zap.Error(err))
+	}
+
+	if err = writeCSV(out, batch, cfg.Currency); err != nil {
+		logger.Fatal("Failed to write codes", zap.Int64("voucher_batch_id", batch.ID), zap.Error(err))
+	}
+	if output != "" {
+		if err = out.Close(); err != nil {
+			logger.Fatal("Failed to write codes", zap.Int64("voucher_batch_id", batch.ID), zap.Error(err))
+		}
+	}
+
+	logger.Info("Voucher batch issued",
+		zap.Int64("voucher_batch_id", batch.ID),
+		zap.String("wallet_id", batch.WalletID),
+		zap.Int("count", len(batch.Codes)),
+		zap.Time("expires_at", batch.ExpiresAt),
+	)
+}
+
+// parseExpiry accepts an RFC 3339 time or a date, which is read as the end
+// of that day in UTC.
+func parseExpiry(s string) (time.Time, error) {
+	if t, err := time.Parse(time.DateOnly, s); err == nil {
+		return t.AddDate(0, 0, 1), nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
+
+func writeCSV(w io.Writer, b domain.VoucherBatch, currency string) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"code", "batch_id", "value", "currency", "max_redemptions", "expires_at"}); err != nil {
+		return err
+	}
+
+	for _, code := range b.Codes {
+		err := cw.Write([]string{
+			code,
+			strconv.FormatInt(b.ID, 10),
+			strconv.FormatInt(b.Value, 10),
+			currency,
+			strconv.Itoa(b.MaxRedemptions),
+			b.ExpiresAt.Format(time.RFC3339),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
@@ -13,13 +13,23 @@ import (
 	"github.com/caarlos0/env/v6"
 	"github.com/go-chi/chi/v5"
 	"github.com/golang-migrate/migrate/v4"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	httpSwagger "github.com/swaggo/http-swagger/v2"
 	"go.uber.org/zap"
 
 	"github.com/Te8va/wallet/internal/config"
+	"github.com/Te8va/wallet/internal/domain"
 	"github.com/Te8va/wallet/internal/handler"
+	"github.com/Te8va/wallet/internal/metrics"
 	"github.com/Te8va/wallet/internal/middleware"
+	"github.com/Te8va/wallet/internal/openapi"
+	"github.com/Te8va/wallet/internal/problem"
+	"github.com/Te8va/wallet/internal/ratelimit"
 	"github.com/Te8va/wallet/internal/repository"
 	"github.com/Te8va/wallet/internal/service"
+	"github.com/Te8va/wallet/internal/statement"
+	"github.com/Te8va/wallet/internal/tracing"
 )
 
 func main() {
@@ -36,7 +46,30 @@ func main() {
 		logger.Fatal("Failed to parse env: %v", zap.Error(err))
 	}
 
-	m, err := migrate.New("file://migrations", cfg.PostgresConn)
+	if err := middleware.Initialize(cfg.LogLevel); err != nil {
+		logger.Fatal("Failed to initialize request logger", zap.Error(err))
+	}
+	zap.ReplaceGlobals(middleware.Log)
+
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Options{
+		Exporter:    cfg.TracingExporter,
+		ServiceName: cfg.TracingServiceName,
+		Endpoint:    cfg.TracingEndpoint,
+		Insecure:    cfg.TracingInsecure,
+		SampleRatio: cfg.TracingSampleRatio,
+	})
+	if err != nil {
+		logger.Fatal("Failed to set up tracing", zap.Error(err))
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			logger.Error("Failed to flush traces", zap.Error(err))
+		}
+	}()
+
+	m, err := migrate.New("file://"+cfg.MigrationsPath, cfg.PostgresConn)
 	if err != nil {
 		logger.Fatal("Failed to create migrate", zap.Error(err))
 	}
@@ -48,6 +81,11 @@ func main() {
 
 	logger.Info("Migrations applied successfully")
 
+	migrationVersion, err := repository.LatestMigrationVersion(cfg.MigrationsPath)
+	if err != nil {
+		logger.Fatal("Failed to read migrations", zap.Error(err))
+	}
+
 	ctx := context.Background()
 	pool, err := repository.GetPgxPool(ctx, cfg.PostgresConn)
 	if err != nil {
@@ -64,18 +102,314 @@ func main() {
 	if err != nil {
 		sugar.Fatalf("Failed to create wallet repository: %v", err)
 	}
-	walletService := service.NewWalletService(walletRepo)
-	walletHandler := handler.NewWalletHandler(walletService)
+	walletService := service.NewWalletService(walletRepo, service.WalletPolicy{
+		UniqueReference: cfg.TransactionUniqueReference,
+	})
+	walletHandler := handler.NewWalletHandler(walletService, cfg.Currency)
+
+	campaignRepo, err := repository.NewCampaignRepository(pool)
+	if err != nil {
+		sugar.Fatalf("Failed to create campaign repository: %v", err)
+	}
+	campaignService := service.NewCampaignService(campaignRepo)
+	walletService.SetCashback(campaignService)
+	campaignHandler := handler.NewCampaignHandler(campaignService)
+
+	loyaltyRepo, err := repository.NewLoyaltyRepository(pool)
+	if err != nil {
+		sugar.Fatalf("Failed to create loyalty repository: %v", err)
+	}
+	loyaltyService := service.NewLoyaltyService(loyaltyRepo, service.LoyaltyPolicy{
+		FundingWalletID: cfg.LoyaltyFundingWalletID,
+		PointsPerUnit:   cfg.LoyaltyPointsPerUnit,
+		PointsTTL:       cfg.LoyaltyPointsTTL,
+		BatchSize:       cfg.LoyaltyExpiryBatchSize,
+	})
+	walletService.SetLoyalty(loyaltyService)
+	loyaltyHandler := handler.NewLoyaltyHandler(loyaltyService)
+
+	statementService := service.NewStatementService(walletRepo)
+	statementHandler := handler.NewStatementHandler(statementService, statement.Options{
+		Currency:   cfg.Currency,
+		MinorUnits: cfg.CurrencyMinorUnits,
+	})
+
+	interestRepo, err := repository.NewInterestRepository(pool)
+	if err != nil {
+		sugar.Fatalf("Failed to create interest repository: %v", err)
+	}
+	interestService := service.NewInterestService(interestRepo)
+	interestHandler := handler.NewInterestHandler(interestService)
+
+	pocketRepo, err := repository.NewPocketRepository(pool)
+	if err != nil {
+		sugar.Fatalf("Failed to create pocket repository: %v", err)
+	}
+	pocketService := service.NewPocketService(pocketRepo)
+	pocketHandler := handler.NewPocketHandler(pocketService)
+
+	failurePolicy := domain.FailurePolicy(cfg.SchedulerFailurePolicy)
+	if failurePolicy != domain.FailureSkip && failurePolicy != domain.FailurePause {
+		sugar.Fatalf("Unknown scheduler failure policy: %s", cfg.SchedulerFailurePolicy)
+	}
+
+	scheduleRepo, err := repository.NewScheduleRepository(pool)
+	if err != nil {
+		sugar.Fatalf("Failed to create schedule repository: %v", err)
+	}
+	scheduleService := service.NewScheduleService(scheduleRepo, walletService, service.SchedulePolicy{
+		BatchSize:  cfg.SchedulerBatchSize,
+		Lease:      cfg.SchedulerLease,
+		MaxRetries: cfg.SchedulerMaxRetries,
+		RetryDelay: cfg.SchedulerRetryDelay,
+		OnFailure:  failurePolicy,
+	})
+	scheduleHandler := handler.NewScheduleHandler(scheduleService)
 
-	_, cancelDeleteCtx := context.WithCancel(context.Background())
+	batchRepo, err := repository.NewBatchRepository(pool)
+	if err != nil {
+		sugar.Fatalf("Failed to create batch repository: %v", err)
+	}
+	batchService := service.NewBatchService(batchRepo, service.BatchPolicy{
+		Concurrency: cfg.BatchConcurrency,
+		Lease:       cfg.BatchLease,
+	})
+	batchService.SetRecorder(walletService)
+	batchHandler := handler.NewBatchHandler(batchService, cfg.BatchMaxItems)
+
+	snapshotRepo, err := repository.NewSnapshotRepository(pool)
+	if err != nil {
+		sugar.Fatalf("Failed to create snapshot repository: %v", err)
+	}
+	snapshotService := service.NewSnapshotService(snapshotRepo, cfg.SnapshotMinEntries)
+
+	escrowRepo, err := repository.NewEscrowRepository(pool)
+	if err != nil {
+		sugar.Fatalf("Failed to create escrow repository: %v", err)
+	}
+	escrowService := service.NewEscrowService(escrowRepo, service.EscrowPolicy{
+		BatchSize:      cfg.EscrowBatchSize,
+		DefaultTimeout: cfg.EscrowDefaultTimeout,
+	})
+	escrowHandler := handler.NewEscrowHandler(escrowService)
+
+	paymentRequestRepo, err := repository.NewPaymentRequestRepository(pool)
+	if err != nil {
+		sugar.Fatalf("Failed to create payment request repository: %v", err)
+	}
+	paymentRequestService := service.NewPaymentRequestService(paymentRequestRepo, walletService, service.PaymentRequestPolicy{
+		Currency:   cfg.Currency,
+		DefaultTTL: cfg.PaymentRequestDefaultTTL,
+		BatchSize:  cfg.PaymentRequestBatchSize,
+	})
+	paymentRequestHandler := handler.NewPaymentRequestHandler(paymentRequestService)
+
+	voucherRepo, err := repository.NewVoucherRepository(pool)
+	if err != nil {
+		sugar.Fatalf("Failed to create voucher repository: %v", err)
+	}
+	voucherService := service.NewVoucherService(voucherRepo, service.VoucherPolicy{BatchSize: cfg.VoucherBatchSize})
+	voucherHandler := handler.NewVoucherHandler(voucherService)
+
+	bgCtx, cancelBgCtx := context.WithCancel(context.Background())
+	stopWorkers := make(chan struct{})
+
+	wg.Add(7)
+	go func() {
+		defer wg.Done()
+		runWorker(bgCtx, stopWorkers, "scheduler", cfg.SchedulerInterval, scheduleService.RunDue, logger)
+	}()
+	go func() {
+		defer wg.Done()
+		runWorker(bgCtx, stopWorkers, "batch processor", cfg.BatchInterval, batchService.ProcessNext, logger)
+	}()
+	go func() {
+		defer wg.Done()
+		runWorker(bgCtx, stopWorkers, "balance snapshots", cfg.SnapshotInterval, snapshotService.TakeSnapshots, logger)
+	}()
+	go func() {
+		defer wg.Done()
+		runWorker(bgCtx, stopWorkers, "escrow timeouts", cfg.EscrowInterval, escrowService.RunExpired, logger)
+	}()
+	go func() {
+		defer wg.Done()
+		runWorker(bgCtx, stopWorkers, "payment request expiry", cfg.PaymentRequestInterval, paymentRequestService.RunExpired, logger)
+	}()
+	go func() {
+		defer wg.Done()
+		runWorker(bgCtx, stopWorkers, "voucher expiry", cfg.VoucherInterval, voucherService.RunExpired, logger)
+	}()
+	go func() {
+		defer wg.Done()
+		runWorker(bgCtx, stopWorkers, "points expiry", cfg.LoyaltyExpiryInterval, loyaltyService.RunExpired, logger)
+	}()
+
+	healthRepo, err := repository.NewHealthRepository(pool)
+	if err != nil {
+		sugar.Fatalf("Failed to create health repository: %v", err)
+	}
+	healthService := service.NewHealthService(healthRepo, service.HealthPolicy{
+		MigrationVersion: migrationVersion,
+		MaxPoolUsage:     cfg.HealthMaxPoolUsage,
+		Timeout:          cfg.HealthCheckTimeout,
+	})
+	healthHandler := handler.NewHealthHandler(healthService)
+
+	rateLimitKeys, err := middleware.ParseRateLimitKeys(cfg.RateLimitKeys)
+	if err != nil {
+		sugar.Fatalf("Failed to parse rate limit keys: %v", err)
+	}
+
+	var rateLimitStore ratelimit.Store
+	switch cfg.RateLimitStore {
+	case "none":
+	case "memory":
+		rateLimitStore = ratelimit.NewMemoryStore()
+	case "postgres":
+		rateLimitRepo, err := repository.NewRateLimitRepository(pool)
+		if err != nil {
+			sugar.Fatalf("Failed to create rate limit repository: %v", err)
+		}
+		rateLimitStore = rateLimitRepo
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			runWorker(bgCtx, stopWorkers, "rate limit cleanup", cfg.RateLimitIdleTTL, func(ctx context.Context) (int, error) {
+				return rateLimitRepo.DeleteIdleBuckets(ctx, cfg.RateLimitIdleTTL)
+			}, logger)
+		}()
+	default:
+		sugar.Fatalf("Unknown rate limit store: %s", cfg.RateLimitStore)
+	}
+
+	spec, err := openapi.Load()
+	if err != nil {
+		logger.Fatal("Failed to load OpenAPI document", zap.Error(err))
+	}
+	validate, err := middleware.WithValidation(spec)
+	if err != nil {
+		logger.Fatal("Failed to create request validator", zap.Error(err))
+	}
+
+	// apiMiddlewares run after routing, inside a group, so the rate limiter
+	// sees the walletId parameter.
+	var apiMiddlewares []func(http.Handler) http.Handler
+	if rateLimitStore != nil {
+		apiMiddlewares = append(apiMiddlewares, middleware.WithRateLimit(middleware.RateLimitOptions{
+			Store: rateLimitStore,
+			Keys:  rateLimitKeys,
+			Read:  domain.RateLimit{Rate: cfg.RateLimitReadRPS, Burst: cfg.RateLimitReadBurst},
+			Write: domain.RateLimit{Rate: cfg.RateLimitWriteRPS, Burst: cfg.RateLimitWriteBurst},
+		}))
+	}
+	apiMiddlewares = append(apiMiddlewares, validate)
+
+	prometheus.MustRegister(metrics.NewPoolCollector(pool))
 
 	r := chi.NewRouter()
+	r.Use(middleware.WithRequestID)
+	r.Use(middleware.WithTracing)
 	r.Use(middleware.WithLogging)
+	r.Use(middleware.WithMetrics)
+	r.NotFound(problem.NotFound)
+	r.MethodNotAllowed(problem.MethodNotAllowed)
+
+	r.Handle("/metrics", promhttp.Handler())
+	r.Get("/healthz", healthHandler.LivenessHandler)
+	r.Get("/readyz", healthHandler.ReadinessHandler)
+	r.Get("/openapi.json", openapi.Handler)
+	r.Get("/swagger/*", httpSwagger.Handler(httpSwagger.URL("/openapi.json")))
 
 	r.Route("/api/v1", func(r chi.Router) {
-		r.Post("/wallet", walletHandler.WalletOperationHandler)
+		// Only the wallet and transaction endpoints have v2 successors, so
+		// only they are deprecated.
+		r.Group(func(r chi.Router) {
+			r.Use(middleware.Deprecated("/api/v2", cfg.APIV1Sunset))
+			r.Use(apiMiddlewares...)
 
-		r.Get("/wallets/{walletId}", walletHandler.GetBalanceHandler)
+			r.Post("/wallet", walletHandler.WalletOperationHandler)
+
+			r.Get("/transactions/{transactionId}", walletHandler.GetTransactionHandler)
+
+			r.Get("/wallets/{walletId}", walletHandler.GetBalanceHandler)
+
+			r.Get("/wallets/{walletId}/transactions", walletHandler.ListTransactionsHandler)
+		})
+
+		r.Group(func(r chi.Router) {
+			r.Use(apiMiddlewares...)
+
+			r.Get("/wallets/{walletId}/statement", statementHandler.GetStatementHandler)
+
+			r.Get("/wallets/{walletId}/savings", interestHandler.GetSavingsHandler)
+			r.Put("/wallets/{walletId}/savings", interestHandler.SaveSavingsHandler)
+
+			r.Get("/wallets/{walletId}/pockets", pocketHandler.GetPocketsHandler)
+			r.Post("/wallets/{walletId}/pockets", pocketHandler.CreatePocketHandler)
+			r.Post("/wallets/{walletId}/pocket-moves", pocketHandler.MovePocketHandler)
+
+			r.Get("/wallets/{walletId}/payment-requests/incoming", paymentRequestHandler.ListIncomingHandler)
+			r.Get("/wallets/{walletId}/payment-requests/outgoing", paymentRequestHandler.ListOutgoingHandler)
+
+			r.Get("/wallets/{walletId}/points", loyaltyHandler.GetPointsHandler)
+			r.Post("/wallets/{walletId}/points/redeem", loyaltyHandler.RedeemPointsHandler)
+
+			r.Route("/schedules", func(r chi.Router) {
+				r.Post("/", scheduleHandler.CreateScheduleHandler)
+				r.Get("/", scheduleHandler.ListSchedulesHandler)
+				r.Get("/{scheduleId}", scheduleHandler.GetScheduleHandler)
+				r.Post("/{scheduleId}/pause", scheduleHandler.PauseScheduleHandler)
+				r.Post("/{scheduleId}/resume", scheduleHandler.ResumeScheduleHandler)
+				r.Delete("/{scheduleId}", scheduleHandler.CancelScheduleHandler)
+			})
+
+			r.Post("/splits", walletHandler.SplitPaymentHandler)
+
+			r.Route("/payment-requests", func(r chi.Router) {
+				r.Post("/", paymentRequestHandler.CreatePaymentRequestHandler)
+				r.Get("/{requestId}", paymentRequestHandler.GetPaymentRequestHandler)
+				r.Post("/{requestId}/accept", paymentRequestHandler.AcceptPaymentRequestHandler)
+				r.Post("/{requestId}/decline", paymentRequestHandler.DeclinePaymentRequestHandler)
+			})
+
+			r.Post("/vouchers/redeem", voucherHandler.RedeemVoucherHandler)
+
+			r.Route("/campaigns", func(r chi.Router) {
+				r.Post("/", campaignHandler.CreateCampaignHandler)
+				r.Get("/", campaignHandler.ListCampaignsHandler)
+				r.Get("/{campaignId}", campaignHandler.GetCampaignHandler)
+			})
+
+			r.Route("/loyalty-rules", func(r chi.Router) {
+				r.Post("/", loyaltyHandler.CreateRuleHandler)
+				r.Get("/", loyaltyHandler.ListRulesHandler)
+			})
+
+			r.Post("/batches", batchHandler.SubmitBatchHandler)
+			r.Get("/batches/{batchId}", batchHandler.GetBatchHandler)
+
+			r.Route("/escrows", func(r chi.Router) {
+				r.Post("/", escrowHandler.CreateEscrowHandler)
+				r.Get("/{dealId}", escrowHandler.GetEscrowHandler)
+				r.Post("/{dealId}/release", escrowHandler.ReleaseEscrowHandler)
+				r.Post("/{dealId}/refund", escrowHandler.RefundEscrowHandler)
+				r.Post("/{dealId}/dispute", escrowHandler.DisputeEscrowHandler)
+				r.Post("/{dealId}/resolve", escrowHandler.ResolveEscrowHandler)
+			})
+		})
+	})
+
+	r.Route("/api/v2", func(r chi.Router) {
+		r.Group(func(r chi.Router) {
+			r.Use(apiMiddlewares...)
+
+			r.Post("/transactions", walletHandler.CreateTransactionHandler)
+			r.Get("/transactions/{transactionId}", walletHandler.GetTransactionV2Handler)
+
+			r.Get("/wallets/{walletId}", walletHandler.GetWalletHandler)
+			r.Get("/wallets/{walletId}/transactions", walletHandler.ListTransactionsV2Handler)
+		})
 	})
 
 	server := &http.Server{
@@ -97,6 +431,13 @@ func main() {
 
 	logger.Info("Shutting down server...")
 
+	// Fail readiness first and keep serving for a while, so load balancers
+	// stop routing new requests here before the listener closes.
+	healthService.SetShuttingDown()
+	time.Sleep(cfg.ShutdownDrainDelay)
+
+	close(stopWorkers)
+
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
@@ -112,11 +453,33 @@ func main() {
 
 	select {
 	case <-waitGroupChan:
-		logger.Info("All delete goroutines successfully finished")
+		logger.Info("All background goroutines successfully finished")
 	case <-time.After(time.Second * 3):
-		cancelDeleteCtx()
-		logger.Info("Some of delete goroutines have not completed their job due to shutdown timeout")
+		cancelBgCtx()
+		logger.Info("Some of background goroutines have not completed their job due to shutdown timeout")
 	}
 
 	logger.Info("Server was shut down")
 }
+
+// runWorker calls run every interval until stop is closed. In-flight runs
+// use ctx, which is only canceled once the shutdown grace period is over.
+func runWorker(ctx context.Context, stop <-chan struct{}, name string, interval time.Duration, run func(context.Context) (int, error), logger *zap.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			n, err := run(ctx)
+			if err != nil {
+				logger.Error("Background worker failed", zap.String("worker", name), zap.Error(err))
+			}
+			if n > 0 {
+				logger.Info("Background worker processed items", zap.String("worker", name), zap.Int("count", n))
+			}
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"os"
+
+	"github.com/caarlos0/env/v6"
+	"go.uber.org/zap"
+
+	"github.com/Te8va/wallet/internal/config"
+	"github.com/Te8va/wallet/internal/repository"
+	"github.com/Te8va/wallet/internal/service"
+)
+
+// exitDrift is returned when the ledger breaks an invariant; other failures
+// exit with 1.
+const exitDrift = 2
+
+func main() {
+	logger, _ := zap.NewProduction()
+	defer logger.Sync()
+
+	var (
+		output     string
+		repairPath string
+	)
+
+	flag.StringVar(&output, "out", "", "JSON report file (defaults to stdout)")
+	flag.StringVar(&repairPath, "repair", "", "Write proposed repairs as a SQL script for review to this file")
+	flag.Parse()
+
+	cfg := config.Config{}
+	if err := env.Parse(&cfg); err != nil {
+		logger.Fatal("Failed to parse env", zap.Error(err))
+	}
+
+	ctx := context.Background()
+	pool, err := repository.GetPgxPool(ctx, cfg.PostgresConn)
+	if err != nil {
+		logger.Fatal("Failed to create postgres connection pool", zap.Error(err))
+	}
+	defer pool.Close()
+
+	checkRepo, err := repository.NewCheckRepository(pool)
+	if err != nil {
+		logger.Fatal("Failed to create check repository", zap.Error(err))
+	}
+	checkService := service.NewCheckService(checkRepo)
+
+	report, err := checkService.CheckLedger(ctx)
+	if err != nil {
+		logger.Fatal("Ledger check failed", zap.Error(err))
+	}
+
+	out := os.Stdout
+	if output != "" {
+		out, err = os.Create(output)
+		if err != nil {
+			logger.Fatal("Failed to create report file", zap.Error(err))
+		}
+		defer out.Close()
+	}
+
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	if err = enc.Encode(report); err != nil {
+		logger.Fatal("Failed to write report", zap.Error(err))
+	}
+
+	if repairPath != "" && len(report.Repairs) > 0 {
+		f, err := os.Create(repairPath)
+		if err != nil {
+			logger.Fatal("Failed to create repair script", zap.Error(err))
+		}
+		if err = repository.WriteRepairScript(f, report.Repairs); err != nil {
+			logger.Fatal("Failed to write repair script", zap.Error(err))
+		}
+		if err = f.Close(); err != nil {
+			logger.Fatal("Failed to write repair script", zap.Error(err))
+		}
+	}
+
+	logger.Info("Ledger check finished",
+		zap.Bool("ok", report.OK),
+		zap.Int("wallets", report.Wallets),
+		zap.Int("transactions", report.Transactions),
+		zap.Int("discrepancies", len(report.Discrepancies)),
+		zap.Int("repairs", len(report.Repairs)),
+	)
+
+	if !report.OK {
+		logger.Sync()
+		os.Exit(exitDrift)
+	}
+}
@@ -0,0 +1,83 @@
+package cron_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/Te8va/wallet/internal/cron"
+)
+
+func TestSchedule_Next(t *testing.T) {
+	from := time.Date(2025, 1, 31, 10, 30, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name     string
+		expr     string
+		expected time.Time
+	}{
+		{
+			name:     "every minute",
+			expr:     "* * * * *",
+			expected: time.Date(2025, 1, 31, 10, 31, 0, 0, time.UTC),
+		},
+		{
+			name:     "every 15 minutes",
+			expr:     "*/15 * * * *",
+			expected: time.Date(2025, 1, 31, 10, 45, 0, 0, time.UTC),
+		},
+		{
+			name:     "daily at 9",
+			expr:     "0 9 * * *",
+			expected: time.Date(2025, 2, 1, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "weekdays at 18:00",
+			expr:     "0 18 * * 1-5",
+			expected: time.Date(2025, 1, 31, 18, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "sunday written as 7",
+			expr:     "0 0 * * 7",
+			expected: time.Date(2025, 2, 2, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "first of the month",
+			expr:     "0 0 1 * *",
+			expected: time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "day of month or day of week",
+			expr:     "0 0 15 * 1",
+			expected: time.Date(2025, 2, 3, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "leap day",
+			expr:     "0 0 29 2 *",
+			expected: time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "never fires",
+			expr:     "0 0 30 2 *",
+			expected: time.Time{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s, err := cron.Parse(tc.expr)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, s.Next(from))
+		})
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "*/0 * * * *", "5-1 * * * *", "a * * * *"} {
+		t.Run(expr, func(t *testing.T) {
+			_, err := cron.Parse(expr)
+			require.Error(t, err)
+		})
+	}
+}
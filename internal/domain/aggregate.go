@@ -0,0 +1,32 @@
+package domain
+
+import (
+	"fmt"
+
+	appErrors "github.com/Te8va/wallet/internal/errors"
+)
+
+// Apply returns the state after the event. Events must belong to the wallet
+// and come in journal order, and the recorded balance after each event must
+// match the folded one, otherwise the log is inconsistent and the state
+// cannot be trusted.
+func (s WalletState) Apply(e Transaction) (WalletState, error) {
+	if e.WalletID != s.WalletID {
+		return s, fmt.Errorf("%w: event %d belongs to wallet %s, not %s", appErrors.ErrInconsistentEvent, e.ID, e.WalletID, s.WalletID)
+	}
+
+	if e.ID <= s.Version {
+		return s, fmt.Errorf("%w: event %d comes after %d", appErrors.ErrInconsistentEvent, e.ID, s.Version)
+	}
+
+	balance := s.Balance + e.Amount
+	if balance != e.Balance {
+		return s, fmt.Errorf("%w: event %d records balance %d, folded %d", appErrors.ErrInconsistentEvent, e.ID, e.Balance, balance)
+	}
+
+	if balance < 0 {
+		return s, fmt.Errorf("%w: event %d makes the balance negative", appErrors.ErrInconsistentEvent, e.ID)
+	}
+
+	return WalletState{WalletID: s.WalletID, Balance: balance, Version: e.ID}, nil
+}
@@ -0,0 +1,67 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/Te8va/wallet/internal/domain"
+	appErrors "github.com/Te8va/wallet/internal/errors"
+)
+
+func TestWalletState_Apply(t *testing.T) {
+	walletID := "123e4567-e89b-12d3-a456-426614174000"
+	start := domain.WalletState{WalletID: walletID, Balance: 1000, Version: 10}
+
+	testCases := []struct {
+		name    string
+		event   domain.Transaction
+		want    domain.WalletState
+		wantErr bool
+	}{
+		{
+			name:  "deposit",
+			event: domain.Transaction{ID: 11, WalletID: walletID, OperationType: domain.DEPOSIT, Amount: 500, Balance: 1500},
+			want:  domain.WalletState{WalletID: walletID, Balance: 1500, Version: 11},
+		},
+		{
+			name:  "withdraw",
+			event: domain.Transaction{ID: 12, WalletID: walletID, OperationType: domain.WITHDRAW, Amount: -1000, Balance: 0},
+			want:  domain.WalletState{WalletID: walletID, Balance: 0, Version: 12},
+		},
+		{
+			name:    "other wallet",
+			event:   domain.Transaction{ID: 11, WalletID: "other", Amount: 500, Balance: 1500},
+			wantErr: true,
+		},
+		{
+			name:    "out of order",
+			event:   domain.Transaction{ID: 10, WalletID: walletID, Amount: 500, Balance: 1500},
+			wantErr: true,
+		},
+		{
+			name:    "recorded balance differs",
+			event:   domain.Transaction{ID: 11, WalletID: walletID, Amount: 500, Balance: 1400},
+			wantErr: true,
+		},
+		{
+			name:    "negative balance",
+			event:   domain.Transaction{ID: 11, WalletID: walletID, Amount: -1500, Balance: -500},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := start.Apply(tc.event)
+			if tc.wantErr {
+				require.ErrorIs(t, err, appErrors.ErrInconsistentEvent)
+				require.Equal(t, start, got)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
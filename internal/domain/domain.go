@@ -1,10 +1,129 @@
 package domain
 
+import "time"
+
 type OperationType string
 
 const (
-	DEPOSIT  OperationType = "DEPOSIT"
-	WITHDRAW OperationType = "WITHDRAW"
+	DEPOSIT      OperationType = "DEPOSIT"
+	WITHDRAW     OperationType = "WITHDRAW"
+	INTEREST     OperationType = "INTEREST"
+	OPENING      OperationType = "OPENING"
+	TRANSFER_OUT OperationType = "TRANSFER_OUT"
+	TRANSFER_IN  OperationType = "TRANSFER_IN"
+
+	SPLIT_OUT OperationType = "SPLIT_OUT"
+	SPLIT_IN  OperationType = "SPLIT_IN"
+
+	ESCROW_HOLD    OperationType = "ESCROW_HOLD"
+	ESCROW_RELEASE OperationType = "ESCROW_RELEASE"
+	ESCROW_REFUND  OperationType = "ESCROW_REFUND"
+
+	VOUCHER_FUND    OperationType = "VOUCHER_FUND"
+	VOUCHER_REDEEM  OperationType = "VOUCHER_REDEEM"
+	VOUCHER_RECLAIM OperationType = "VOUCHER_RECLAIM"
+
+	CASHBACK OperationType = "CASHBACK"
+
+	POINTS_EARN   OperationType = "POINTS_EARN"
+	POINTS_REDEEM OperationType = "POINTS_REDEEM"
+	POINTS_EXPIRE OperationType = "POINTS_EXPIRE"
+	POINTS_PAYOUT OperationType = "POINTS_PAYOUT"
+)
+
+// WalletKind tells user wallets from the internal ones the service keeps
+// funds in. Deposits, withdrawals and transfers are only allowed on user
+// wallets.
+type WalletKind string
+
+const (
+	WalletUser    WalletKind = "USER"
+	WalletEscrow  WalletKind = "ESCROW"
+	WalletVoucher WalletKind = "VOUCHER"
+	WalletPoints  WalletKind = "POINTS"
+)
+
+type EscrowStatus string
+
+const (
+	EscrowFunded   EscrowStatus = "FUNDED"
+	EscrowDisputed EscrowStatus = "DISPUTED"
+	EscrowReleased EscrowStatus = "RELEASED"
+	EscrowRefunded EscrowStatus = "REFUNDED"
+	EscrowSplit    EscrowStatus = "SPLIT"
+)
+
+type EscrowAction string
+
+const (
+	EscrowFund    EscrowAction = "FUND"
+	EscrowRelease EscrowAction = "RELEASE"
+	EscrowRefund  EscrowAction = "REFUND"
+	EscrowDispute EscrowAction = "DISPUTE"
+	EscrowResolve EscrowAction = "RESOLVE"
+	EscrowTimeout EscrowAction = "TIMEOUT"
+)
+
+type DayCountConvention string
+
+const (
+	ACT365 DayCountConvention = "ACT/365"
+	ACT360 DayCountConvention = "ACT/360"
+	ACTACT DayCountConvention = "ACT/ACT"
+)
+
+type ScheduleFrequency string
+
+const (
+	ONCE    ScheduleFrequency = "ONCE"
+	DAILY   ScheduleFrequency = "DAILY"
+	WEEKLY  ScheduleFrequency = "WEEKLY"
+	MONTHLY ScheduleFrequency = "MONTHLY"
+	CRON    ScheduleFrequency = "CRON"
+)
+
+type ScheduleStatus string
+
+const (
+	ScheduleActive    ScheduleStatus = "ACTIVE"
+	SchedulePaused    ScheduleStatus = "PAUSED"
+	ScheduleCanceled  ScheduleStatus = "CANCELED"
+	ScheduleCompleted ScheduleStatus = "COMPLETED"
+	ScheduleFailed    ScheduleStatus = "FAILED"
+)
+
+// FailurePolicy decides what happens to a schedule once an occurrence has
+// exhausted its retries.
+type FailurePolicy string
+
+const (
+	FailureSkip  FailurePolicy = "skip"
+	FailurePause FailurePolicy = "pause"
+)
+
+type BatchMode string
+
+const (
+	BatchBestEffort   BatchMode = "best_effort"
+	BatchAllOrNothing BatchMode = "all_or_nothing"
+)
+
+type BatchStatus string
+
+const (
+	BatchPending    BatchStatus = "PENDING"
+	BatchProcessing BatchStatus = "PROCESSING"
+	BatchCompleted  BatchStatus = "COMPLETED"
+	BatchFailed     BatchStatus = "FAILED"
+)
+
+type BatchItemStatus string
+
+const (
+	BatchItemPending   BatchItemStatus = "PENDING"
+	BatchItemSucceeded BatchItemStatus = "SUCCEEDED"
+	BatchItemFailed    BatchItemStatus = "FAILED"
+	BatchItemSkipped   BatchItemStatus = "SKIPPED"
 )
 
 type Wallet struct {
@@ -13,16 +132,717 @@ type Wallet struct {
 }
 
 type WalletRequest struct {
-	WalletID      string        `json:"valletId"`
-	OperationType OperationType `json:"operationType"`
-	Amount        int64         `json:"amount"`
+	WalletID      string         `json:"valletId"`
+	OperationType OperationType  `json:"operationType"`
+	Amount        int64          `json:"amount"`
+	Reference     string         `json:"reference,omitempty"`
+	Description   string         `json:"description,omitempty"`
+	Metadata      map[string]any `json:"metadata,omitempty"`
+	Tags          []string       `json:"tags,omitempty"`
 }
 
+// BalanceResponse is the v1 wallet balance. Balance is the total; the
+// current balance is also broken down into the spendable main balance and
+// the pockets, which a balance as of a past time is not.
 type BalanceResponse struct {
+	WalletID    string          `json:"wallet_id"`
+	Balance     int64           `json:"balance"`
+	MainBalance *int64          `json:"main_balance,omitempty"`
+	Pockets     []PocketBalance `json:"pockets,omitzero"`
+	AsOf        *time.Time      `json:"as_of,omitempty"`
+}
+
+type Transaction struct {
+	ID             int64          `json:"id"`
+	WalletID       string         `json:"wallet_id"`
+	OperationType  OperationType  `json:"operation_type"`
+	Amount         int64          `json:"amount"`
+	Balance        int64          `json:"balance"`
+	ParentID       int64          `json:"parent_id,omitempty"`
+	IdempotencyKey string         `json:"idempotency_key,omitempty"`
+	Reference      string         `json:"reference,omitempty"`
+	Description    string         `json:"description,omitempty"`
+	Metadata       map[string]any `json:"metadata,omitempty"`
+	Tags           []string       `json:"tags,omitempty"`
+	CreatedAt      time.Time      `json:"created_at"`
+}
+
+// TransactionRequest is the body of POST /api/v2/transactions and the
+// command a deposit or withdrawal is carried out with. v1 requests are
+// converted to it.
+type TransactionRequest struct {
+	WalletID       string         `json:"walletId"`
+	OperationType  OperationType  `json:"operationType"`
+	Amount         int64          `json:"amount"`
+	Currency       string         `json:"currency,omitempty"`
+	IdempotencyKey string         `json:"idempotencyKey,omitempty"`
+	Reference      string         `json:"reference,omitempty"`
+	Description    string         `json:"description,omitempty"`
+	Metadata       map[string]any `json:"metadata,omitempty"`
+	Tags           []string       `json:"tags,omitempty"`
+	// UniqueReference makes Reference a second idempotency key within the
+	// wallet. It is set by the service from its policy, not by clients.
+	UniqueReference bool `json:"-"`
+}
+
+// TransactionResult is the posted transaction. Replayed is set when the
+// idempotency key had already been used for the same operation and the
+// original transaction is returned instead of posting a new one.
+type TransactionResult struct {
+	Transaction Transaction
+	Replayed    bool
+}
+
+// SplitRecipient receives either a fixed Amount or ShareBps basis points
+// (1/100 of a percent) of what is left of the payment after fixed amounts.
+type SplitRecipient struct {
+	WalletID string `json:"walletId"`
+	Amount   int64  `json:"amount,omitempty"`
+	ShareBps int64  `json:"shareBps,omitempty"`
+}
+
+// SplitRequest debits Amount from WalletID and credits it to Recipients.
+type SplitRequest struct {
+	WalletID       string           `json:"walletId"`
+	Amount         int64            `json:"amount"`
+	IdempotencyKey string           `json:"idempotencyKey"`
+	Reference      string           `json:"reference"`
+	Description    string           `json:"description"`
+	Recipients     []SplitRecipient `json:"recipients"`
+}
+
+// SplitLeg is the amount a recipient gets once shares have been allocated.
+type SplitLeg struct {
+	WalletID string
+	Amount   int64
+}
+
+// Split is a split payment with the recipient amounts allocated. The legs
+// add up to Amount.
+type Split struct {
+	WalletID       string
+	Amount         int64
+	IdempotencyKey string
+	Reference      string
+	Description    string
+	Legs           []SplitLeg
+}
+
+// SplitPayment is a posted split payment: the debit of the payer and the
+// credits of the recipients, which all have the debit as their parent.
+// Replayed is set when the idempotency key had already been used for the
+// same split and the original payment is returned.
+type SplitPayment struct {
+	Debit    Transaction   `json:"debit"`
+	Credits  []Transaction `json:"credits"`
+	Replayed bool          `json:"-"`
+}
+
+// TransactionResponse is the v2 representation of a transaction. Amount is
+// the requested amount; the direction is given by OperationType.
+type TransactionResponse struct {
+	ID             int64          `json:"id"`
+	WalletID       string         `json:"walletId"`
+	OperationType  OperationType  `json:"operationType"`
+	Amount         int64          `json:"amount"`
+	Currency       string         `json:"currency"`
+	Balance        int64          `json:"balance"`
+	IdempotencyKey string         `json:"idempotencyKey,omitempty"`
+	Reference      string         `json:"reference,omitempty"`
+	Description    string         `json:"description,omitempty"`
+	Metadata       map[string]any `json:"metadata,omitempty"`
+	Tags           []string       `json:"tags,omitempty"`
+	CreatedAt      time.Time      `json:"createdAt"`
+}
+
+// TransactionFilter selects journal entries of a wallet for the history
+// endpoints. Entries are returned newest first; Before continues a listing
+// after the entry with that id.
+type TransactionFilter struct {
+	WalletID  string
+	Reference string
+	// Tags lists tags that all have to be attached to an entry.
+	Tags   []string
+	From   *time.Time
+	To     *time.Time
+	Before int64
+	Limit  int
+}
+
+type TransactionHistory struct {
+	Transactions []Transaction `json:"transactions"`
+	NextBefore   int64         `json:"next_before,omitempty"`
+}
+
+// TransactionHistoryResponse is the v2 representation of a history page.
+type TransactionHistoryResponse struct {
+	Transactions []TransactionResponse `json:"transactions"`
+	NextBefore   int64                 `json:"nextBefore,omitempty"`
+}
+
+// WalletResponse is the v2 representation of a wallet balance.
+// WalletResponse is the v2 wallet balance, broken down like BalanceResponse.
+type WalletResponse struct {
+	WalletID    string          `json:"walletId"`
+	Balance     int64           `json:"balance"`
+	MainBalance *int64          `json:"mainBalance,omitempty"`
+	Pockets     []PocketBalance `json:"pockets,omitzero"`
+	Currency    string          `json:"currency"`
+	AsOf        *time.Time      `json:"asOf,omitempty"`
+}
+
+// PocketBalance is a pocket in the breakdown of a wallet balance.
+type PocketBalance struct {
+	Name    string `json:"name"`
+	Balance int64  `json:"balance"`
+}
+
+// Pocket is a named part of a wallet balance set aside from spending. The
+// money stays in the wallet total but cannot be withdrawn until it is moved
+// back to the main balance.
+type Pocket struct {
+	WalletID  string    `json:"wallet_id"`
+	Name      string    `json:"name"`
+	Balance   int64     `json:"balance"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type PocketRequest struct {
+	Name string `json:"name"`
+}
+
+// PocketMove moves money between the main balance and a pocket, or between
+// two pockets. An empty From or To stands for the main balance.
+type PocketMove struct {
+	ID        int64     `json:"id"`
+	WalletID  string    `json:"wallet_id"`
+	From      string    `json:"from,omitempty"`
+	To        string    `json:"to,omitempty"`
+	Amount    int64     `json:"amount"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type PocketMoveRequest struct {
+	From   string `json:"from,omitempty"`
+	To     string `json:"to,omitempty"`
+	Amount int64  `json:"amount"`
+}
+
+// PocketBalances breaks the wallet balance down into the main balance, which
+// can be spent, and the pockets.
+type PocketBalances struct {
+	WalletID    string   `json:"wallet_id"`
+	Balance     int64    `json:"balance"`
+	MainBalance int64    `json:"main_balance"`
+	Pockets     []Pocket `json:"pockets"`
+}
+
+// PaymentRequestStatus is the state of a payment request. A request is
+// ACCEPTING while the transfer that pays it is being posted.
+type PaymentRequestStatus string
+
+const (
+	PaymentRequestPending   PaymentRequestStatus = "PENDING"
+	PaymentRequestAccepting PaymentRequestStatus = "ACCEPTING"
+	PaymentRequestPaid      PaymentRequestStatus = "PAID"
+	PaymentRequestDeclined  PaymentRequestStatus = "DECLINED"
+	PaymentRequestExpired   PaymentRequestStatus = "EXPIRED"
+)
+
+// PaymentRequest asks the payer wallet to pay Amount to the payee wallet
+// that issued it.
+type PaymentRequest struct {
+	ID            int64                 `json:"id"`
+	PayeeWalletID string                `json:"payee_wallet_id"`
+	PayerWalletID string                `json:"payer_wallet_id"`
+	Amount        int64                 `json:"amount"`
+	Currency      string                `json:"currency"`
+	Memo          string                `json:"memo,omitempty"`
+	Status        PaymentRequestStatus  `json:"status"`
+	ExpiresAt     time.Time             `json:"expires_at"`
+	CreatedAt     time.Time             `json:"created_at"`
+	UpdatedAt     time.Time             `json:"updated_at"`
+	Events        []PaymentRequestEvent `json:"events,omitempty"`
+}
+
+// PaymentRequestEvent records a status change of a payment request.
+type PaymentRequestEvent struct {
+	ID         int64                `json:"id"`
+	FromStatus PaymentRequestStatus `json:"from_status,omitempty"`
+	ToStatus   PaymentRequestStatus `json:"to_status"`
+	CreatedAt  time.Time            `json:"created_at"`
+}
+
+type PaymentRequestParams struct {
+	PayeeWalletID string    `json:"payeeWalletId"`
+	PayerWalletID string    `json:"payerWalletId"`
+	Amount        int64     `json:"amount"`
+	Currency      string    `json:"currency"`
+	Memo          string    `json:"memo"`
+	ExpiresAt     time.Time `json:"expiresAt"`
+}
+
+type PaymentRequestDirection string
+
+const (
+	// PaymentRequestIncoming are the requests the wallet is asked to pay.
+	PaymentRequestIncoming PaymentRequestDirection = "incoming"
+	// PaymentRequestOutgoing are the requests the wallet has issued.
+	PaymentRequestOutgoing PaymentRequestDirection = "outgoing"
+)
+
+// PaymentRequestFilter selects payment requests of a wallet. An empty
+// Status matches every status.
+type PaymentRequestFilter struct {
+	WalletID  string
+	Direction PaymentRequestDirection
+	Status    PaymentRequestStatus
+}
+
+// Escrow holds funds of a deal in a wallet of kind ESCROW until they are
+// released to the seller, refunded to the buyer or split between them.
+// OnTimeout is the status a FUNDED escrow is moved to at ExpiresAt.
+type Escrow struct {
+	DealID         string        `json:"deal_id"`
+	WalletID       string        `json:"wallet_id"`
+	BuyerWalletID  string        `json:"buyer_wallet_id"`
+	SellerWalletID string        `json:"seller_wallet_id"`
+	Amount         int64         `json:"amount"`
+	Status         EscrowStatus  `json:"status"`
+	OnTimeout      EscrowStatus  `json:"on_timeout"`
+	ExpiresAt      time.Time     `json:"expires_at"`
+	CreatedAt      time.Time     `json:"created_at"`
+	UpdatedAt      time.Time     `json:"updated_at"`
+	Events         []EscrowEvent `json:"events,omitempty"`
+}
+
+// EscrowEvent records a transition of an escrow and the amounts paid out
+// with it.
+type EscrowEvent struct {
+	ID           int64        `json:"id"`
+	Action       EscrowAction `json:"action"`
+	FromStatus   EscrowStatus `json:"from_status,omitempty"`
+	ToStatus     EscrowStatus `json:"to_status"`
+	SellerAmount int64        `json:"seller_amount"`
+	BuyerAmount  int64        `json:"buyer_amount"`
+	Reason       string       `json:"reason,omitempty"`
+	CreatedAt    time.Time    `json:"created_at"`
+}
+
+type EscrowRequest struct {
+	DealID         string       `json:"dealId"`
+	BuyerWalletID  string       `json:"buyerWalletId"`
+	SellerWalletID string       `json:"sellerWalletId"`
+	Amount         int64        `json:"amount"`
+	ExpiresAt      time.Time    `json:"expiresAt"`
+	OnTimeout      EscrowStatus `json:"onTimeout"`
+}
+
+type EscrowActionRequest struct {
+	Reason string `json:"reason"`
+	// SellerAmount is the part paid to the seller when a dispute is
+	// resolved; the rest is refunded to the buyer.
+	SellerAmount int64 `json:"sellerAmount"`
+}
+
+// EscrowTransition is a change of escrow status applied by the repository.
+// It only applies while the escrow is still in status From.
+type EscrowTransition struct {
+	DealID       string
+	Action       EscrowAction
+	From         EscrowStatus
+	To           EscrowStatus
+	SellerAmount int64
+	BuyerAmount  int64
+	Reason       string
+}
+
+// VoucherBatchStatus is the state of a voucher batch. An EXPIRED batch has
+// returned its unredeemed value to the treasury wallet.
+type VoucherBatchStatus string
+
+const (
+	VoucherBatchActive  VoucherBatchStatus = "ACTIVE"
+	VoucherBatchExpired VoucherBatchStatus = "EXPIRED"
+)
+
+// VoucherBatch is a set of voucher codes of the same value. The full value
+// of the batch is moved from the treasury wallet to a wallet of kind VOUCHER
+// when it is issued, and each redemption is paid out of that wallet. A code
+// can be redeemed MaxRedemptions times, at most once by each wallet.
+type VoucherBatch struct {
+	ID               int64              `json:"id"`
+	WalletID         string             `json:"wallet_id"`
+	TreasuryWalletID string             `json:"treasury_wallet_id"`
+	Value            int64              `json:"value"`
+	MaxRedemptions   int                `json:"max_redemptions"`
+	Count            int                `json:"count"`
+	Status           VoucherBatchStatus `json:"status"`
+	ExpiresAt        time.Time          `json:"expires_at"`
+	CreatedAt        time.Time          `json:"created_at"`
+	UpdatedAt        time.Time          `json:"updated_at"`
+	// Codes are only known when the batch is issued; the service keeps
+	// hashes of them.
+	Codes []string `json:"codes,omitempty"`
+}
+
+type VoucherBatchParams struct {
+	TreasuryWalletID string
+	Value            int64
+	MaxRedemptions   int
+	Count            int
+	ExpiresAt        time.Time
+}
+
+type VoucherRedeemRequest struct {
+	Code     string `json:"code"`
+	WalletID string `json:"walletId"`
+}
+
+// Campaign credits RateBps basis points of each qualifying withdrawal back
+// to the wallet, paid from the funding wallet. A withdrawal qualifies when
+// it is posted between StartsAt and EndsAt and is at least MinAmount. The
+// cashback of a wallet is capped at PerWalletCap, unless it is 0, and the
+// cashback of the whole campaign at Budget.
+type Campaign struct {
+	ID              int64     `json:"id"`
+	Name            string    `json:"name"`
+	FundingWalletID string    `json:"funding_wallet_id"`
+	RateBps         int64     `json:"rate_bps"`
+	MinAmount       int64     `json:"min_amount"`
+	PerWalletCap    int64     `json:"per_wallet_cap"`
+	Budget          int64     `json:"budget"`
+	Spent           int64     `json:"spent"`
+	StartsAt        time.Time `json:"starts_at"`
+	EndsAt          time.Time `json:"ends_at"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+type CampaignParams struct {
+	Name            string    `json:"name"`
+	FundingWalletID string    `json:"fundingWalletId"`
+	RateBps         int64     `json:"rateBps"`
+	MinAmount       int64     `json:"minAmount"`
+	PerWalletCap    int64     `json:"perWalletCap"`
+	Budget          int64     `json:"budget"`
+	StartsAt        time.Time `json:"startsAt"`
+	EndsAt          time.Time `json:"endsAt"`
+}
+
+// CashbackCredit is the cashback a campaign owes for a source transaction,
+// before the campaign budget and the per-wallet cap are applied.
+type CashbackCredit struct {
+	CampaignID          int64
+	SourceTransactionID int64
+	WalletID            string
+	Amount              int64
+}
+
+// LoyaltyRule earns RateBps basis points of each qualifying withdrawal as
+// loyalty points: 10000 is one point per unit spent. A withdrawal qualifies
+// when it is posted between StartsAt and EndsAt, is at least MinAmount and,
+// when Tag is set, carries the tag.
+type LoyaltyRule struct {
+	ID        int64      `json:"id"`
+	Name      string     `json:"name"`
+	RateBps   int64      `json:"rate_bps"`
+	MinAmount int64      `json:"min_amount"`
+	Tag       string     `json:"tag,omitempty"`
+	StartsAt  time.Time  `json:"starts_at"`
+	EndsAt    *time.Time `json:"ends_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+type LoyaltyRuleParams struct {
+	Name      string     `json:"name"`
+	RateBps   int64      `json:"rateBps"`
+	MinAmount int64      `json:"minAmount"`
+	Tag       string     `json:"tag"`
+	StartsAt  time.Time  `json:"startsAt"`
+	EndsAt    *time.Time `json:"endsAt"`
+}
+
+// PointsLot is a batch of points earned together. Points are spent from
+// the lot that expires first, and what is left of a lot at ExpiresAt
+// expires.
+type PointsLot struct {
+	ID        int64     `json:"id"`
+	Points    int64     `json:"points"`
+	Remaining int64     `json:"remaining"`
+	ExpiresAt time.Time `json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// PointsBalance is the loyalty points balance of a wallet. Points are kept
+// in a wallet of kind POINTS, which can't be used with money operations.
+type PointsBalance struct {
+	WalletID       string      `json:"wallet_id"`
+	PointsWalletID string      `json:"points_wallet_id,omitempty"`
+	Balance        int64       `json:"balance"`
+	Lots           []PointsLot `json:"lots"`
+}
+
+// PointsEarning is the points a rule awards for a source transaction.
+type PointsEarning struct {
+	RuleID              int64
+	SourceTransactionID int64
+	WalletID            string
+	Points              int64
+	ExpiresAt           time.Time
+}
+
+type PointsRedeemRequest struct {
+	Points         int64  `json:"points"`
+	IdempotencyKey string `json:"idempotencyKey,omitempty"`
+}
+
+// PointsRedemption exchanges Points of the wallet for Amount paid from the
+// funding wallet.
+type PointsRedemption struct {
+	WalletID        string
+	FundingWalletID string
+	Points          int64
+	Amount          int64
+	IdempotencyKey  string
+}
+
+// PointsRedemptionResult is the points debit and the money credit of a
+// redemption. Replayed is set when a repeated idempotency key returned a
+// stored redemption.
+type PointsRedemptionResult struct {
+	Points   int64       `json:"points"`
+	Amount   int64       `json:"amount"`
+	Debit    Transaction `json:"debit"`
+	Credit   Transaction `json:"credit"`
+	Replayed bool        `json:"-"`
+}
+
+type SavingsAccount struct {
+	WalletID      string             `json:"wallet_id"`
+	AnnualRateBps int64              `json:"annual_rate_bps"`
+	DayCount      DayCountConvention `json:"day_count"`
+	AccruedNano   int64              `json:"accrued_nano"`
+}
+
+type SavingsRequest struct {
+	AnnualRateBps int64              `json:"annualRateBps"`
+	DayCount      DayCountConvention `json:"dayCount"`
+}
+
+type InterestRunResult struct {
+	Date     time.Time `json:"date"`
+	Accrued  int       `json:"accrued"`
+	Credited int       `json:"credited"`
+	Failed   int       `json:"failed"`
+}
+
+type Schedule struct {
+	ID           int64             `json:"id"`
+	FromWalletID string            `json:"from_wallet_id"`
+	ToWalletID   string            `json:"to_wallet_id"`
+	Amount       int64             `json:"amount"`
+	Frequency    ScheduleFrequency `json:"frequency"`
+	Cron         string            `json:"cron,omitempty"`
+	StartAt      time.Time         `json:"start_at"`
+	NextRunAt    time.Time         `json:"next_run_at"`
+	OccurrenceAt time.Time         `json:"occurrence_at"`
+	Status       ScheduleStatus    `json:"status"`
+	Attempts     int               `json:"attempts"`
+	LastError    string            `json:"last_error,omitempty"`
+	LastRunAt    *time.Time        `json:"last_run_at,omitempty"`
+	CreatedAt    time.Time         `json:"created_at"`
+}
+
+type ScheduleRequest struct {
+	FromWalletID string            `json:"fromWalletId"`
+	ToWalletID   string            `json:"toWalletId"`
+	Amount       int64             `json:"amount"`
+	Frequency    ScheduleFrequency `json:"frequency"`
+	Cron         string            `json:"cron"`
+	StartAt      time.Time         `json:"startAt"`
+}
+
+type Batch struct {
+	ID          int64       `json:"id"`
+	Mode        BatchMode   `json:"mode"`
+	Status      BatchStatus `json:"status"`
+	Total       int         `json:"total"`
+	Succeeded   int         `json:"succeeded"`
+	Failed      int         `json:"failed"`
+	CreatedAt   time.Time   `json:"created_at"`
+	CompletedAt *time.Time  `json:"completed_at,omitempty"`
+	Items       []BatchItem `json:"items,omitempty"`
+}
+
+type BatchItem struct {
+	Line          int             `json:"line"`
+	WalletID      string          `json:"wallet_id"`
+	OperationType OperationType   `json:"operation_type"`
+	Amount        int64           `json:"amount"`
+	Status        BatchItemStatus `json:"status"`
+	Error         string          `json:"error,omitempty"`
+	TransactionID int64           `json:"transaction_id,omitempty"`
+}
+
+type Statement struct {
+	WalletID       string           `json:"wallet_id"`
+	From           time.Time        `json:"from"`
+	To             time.Time        `json:"to"`
+	OpeningBalance int64            `json:"opening_balance"`
+	ClosingBalance int64            `json:"closing_balance"`
+	Transactions   []Transaction    `json:"transactions"`
+	Totals         []OperationTotal `json:"totals"`
+}
+
+type OperationTotal struct {
+	OperationType OperationType `json:"operation_type"`
+	Count         int           `json:"count"`
+	Amount        int64         `json:"amount"`
+}
+
+// Problem is an RFC 7807 problem details object. Code is a stable
+// machine-readable identifier of the problem; Title and Detail are for
+// humans and may change.
+type Problem struct {
+	Type      string       `json:"type"`
+	Title     string       `json:"title"`
+	Status    int          `json:"status"`
+	Code      string       `json:"code"`
+	Detail    string       `json:"detail,omitempty"`
+	Instance  string       `json:"instance,omitempty"`
+	RequestID string       `json:"request_id,omitempty"`
+	Errors    []FieldError `json:"errors,omitempty"`
+}
+
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+type CheckKind string
+
+const (
+	CheckNegativeBalance   CheckKind = "NEGATIVE_BALANCE"
+	CheckBalanceMismatch   CheckKind = "BALANCE_MISMATCH"
+	CheckJournalChain      CheckKind = "JOURNAL_CHAIN"
+	CheckUnbalancedPosting CheckKind = "UNBALANCED_POSTING"
+	CheckOrphanRecord      CheckKind = "ORPHAN_RECORD"
+	CheckPocketMismatch    CheckKind = "POCKET_MISMATCH"
+	CheckPointsMismatch    CheckKind = "POINTS_MISMATCH"
+)
+
+var CheckKinds = []CheckKind{
+	CheckNegativeBalance,
+	CheckBalanceMismatch,
+	CheckJournalChain,
+	CheckUnbalancedPosting,
+	CheckOrphanRecord,
+	CheckPocketMismatch,
+	CheckPointsMismatch,
+}
+
+type Discrepancy struct {
+	Check         CheckKind `json:"check"`
+	WalletID      string    `json:"wallet_id,omitempty"`
+	TransactionID int64     `json:"transaction_id,omitempty"`
+	Expected      int64     `json:"expected"`
+	Actual        int64     `json:"actual"`
+	Detail        string    `json:"detail,omitempty"`
+}
+
+// Repair proposes setting a stored value to the one derived from the journal.
+type Repair struct {
+	Check         CheckKind `json:"check"`
+	WalletID      string    `json:"wallet_id"`
+	TransactionID int64     `json:"transaction_id,omitempty"`
+	From          int64     `json:"from"`
+	To            int64     `json:"to"`
+}
+
+type LedgerReport struct {
+	CheckedAt     time.Time         `json:"checked_at"`
+	Wallets       int               `json:"wallets"`
+	Transactions  int               `json:"transactions"`
+	OK            bool              `json:"ok"`
+	Counts        map[CheckKind]int `json:"counts"`
+	Discrepancies []Discrepancy     `json:"discrepancies"`
+	Repairs       []Repair          `json:"repairs"`
+}
+
+// WalletState is the wallet aggregate rebuilt from its events, the journal
+// entries. Version is the id of the last applied entry.
+type WalletState struct {
 	WalletID string `json:"wallet_id"`
 	Balance  int64  `json:"balance"`
+	Version  int64  `json:"version"`
+}
+
+type ReplayOptions struct {
+	// FromSnapshot starts every wallet from its latest balance snapshot
+	// instead of its first event.
+	FromSnapshot bool
+	// DryRun rebuilds the projection without writing it.
+	DryRun bool
+}
+
+type ReplayResult struct {
+	Projection   string `json:"projection"`
+	FromSnapshot bool   `json:"from_snapshot"`
+	DryRun       bool   `json:"dry_run"`
+	Events       int    `json:"events"`
+	Wallets      int    `json:"wallets"`
+	Changed      int    `json:"changed"`
+	LastEventID  int64  `json:"last_event_id"`
+}
+
+type HealthStatus string
+
+const (
+	HealthOK   HealthStatus = "ok"
+	HealthFail HealthStatus = "fail"
+)
+
+type HealthCheck struct {
+	Name      string       `json:"name"`
+	Status    HealthStatus `json:"status"`
+	LatencyMs float64      `json:"latency_ms"`
+	Error     string       `json:"error,omitempty"`
+}
+
+type HealthReport struct {
+	Status HealthStatus  `json:"status"`
+	Checks []HealthCheck `json:"checks,omitempty"`
 }
 
-type ErrorResponse struct {
-	Error string `json:"error"`
+// RateLimit is a token bucket: Burst tokens at most, refilled at Rate tokens
+// per second.
+type RateLimit struct {
+	Rate  float64
+	Burst int
+}
+
+type RateLimitResult struct {
+	Allowed   bool
+	Remaining int
+	// RetryAfter is the wait until the next token, set when not allowed.
+	RetryAfter time.Duration
+	// Reset is the wait until the bucket is full again.
+	Reset time.Duration
+}
+
+// Result describes the bucket state after a take left tokens in it.
+func (l RateLimit) Result(tokens float64, allowed bool) RateLimitResult {
+	res := RateLimitResult{
+		Allowed:   allowed,
+		Remaining: int(tokens),
+	}
+
+	if l.Rate > 0 {
+		res.Reset = time.Duration((float64(l.Burst) - tokens) / l.Rate * float64(time.Second))
+		if !allowed {
+			res.RetryAfter = time.Duration((1 - tokens) / l.Rate * float64(time.Second))
+		}
+	}
+
+	return res
 }
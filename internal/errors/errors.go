@@ -3,6 +3,40 @@ package errors
 import "errors"
 
 var (
-	ErrWalletNotFound    = errors.New("wallet not found")
-	ErrInsufficientFunds = errors.New("insufficient funds")
+	ErrWalletNotFound         = errors.New("wallet not found")
+	ErrInsufficientFunds      = errors.New("insufficient funds")
+	ErrSavingsAccountNotFound = errors.New("savings account not found")
+	ErrDuplicateOperation     = errors.New("operation already processed")
+	ErrIdempotencyKeyReused   = errors.New("idempotency key was used for a different operation")
+	ErrDuplicateReference     = errors.New("reference was already used for a different operation")
+	ErrCurrencyMismatch       = errors.New("currency is not supported by this wallet")
+	ErrTransactionNotFound    = errors.New("transaction not found")
+	ErrSameWallet             = errors.New("source and destination wallets must differ")
+	ErrPocketNotFound         = errors.New("pocket not found")
+	ErrPocketExists           = errors.New("pocket already exists")
+	ErrSamePocket             = errors.New("source and destination of a move must differ")
+	ErrInvalidSplit           = errors.New("split amounts do not add up to the payment amount")
+	ErrPaymentRequestNotFound = errors.New("payment request not found")
+	ErrPaymentRequestExpired  = errors.New("payment request has expired")
+	ErrPaymentRequestState    = errors.New("payment request state does not allow this action")
+	ErrWalletKindNotAllowed   = errors.New("operation is not allowed on this kind of wallet")
+	ErrEscrowNotFound         = errors.New("escrow not found")
+	ErrEscrowExists           = errors.New("escrow for this deal already exists")
+	ErrInvalidEscrowState     = errors.New("escrow state does not allow this action")
+	ErrInvalidEscrowSplit     = errors.New("seller amount must be between 0 and the escrow amount")
+	ErrVoucherNotFound        = errors.New("voucher not found")
+	ErrVoucherExpired         = errors.New("voucher has expired")
+	ErrVoucherRedeemed        = errors.New("voucher has already been redeemed")
+	ErrInvalidVoucherBatch    = errors.New("invalid voucher batch")
+	ErrCampaignNotFound       = errors.New("campaign not found")
+	ErrInvalidCampaign        = errors.New("campaign must end after it starts")
+	ErrInvalidLoyaltyRule     = errors.New("loyalty rule must end after it starts")
+	ErrInvalidPointsAmount    = errors.New("points must be a positive multiple of the conversion rate")
+	ErrRedemptionDisabled     = errors.New("points redemption is not configured")
+	ErrScheduleNotFound       = errors.New("schedule not found")
+	ErrInvalidScheduleState   = errors.New("schedule state does not allow this action")
+	ErrInvalidCron            = errors.New("invalid cron expression")
+	ErrBatchNotFound          = errors.New("batch not found")
+	ErrInconsistentEvent      = errors.New("event does not apply to wallet state")
+	ErrUnknownProjection      = errors.New("unknown projection")
 )
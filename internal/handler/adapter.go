@@ -0,0 +1,28 @@
+package handler
+
+import "github.com/Te8va/wallet/internal/domain"
+
+// The v1 API is served by the v2 implementation; these functions convert
+// between the two schemas.
+
+func transactionRequestFromV1(req domain.WalletRequest) domain.TransactionRequest {
+	return domain.TransactionRequest{
+		WalletID:      req.WalletID,
+		OperationType: req.OperationType,
+		Amount:        req.Amount,
+		Reference:     req.Reference,
+		Description:   req.Description,
+		Metadata:      req.Metadata,
+		Tags:          req.Tags,
+	}
+}
+
+func balanceResponseFromV2(wallet domain.WalletResponse) domain.BalanceResponse {
+	return domain.BalanceResponse{
+		WalletID:    wallet.WalletID,
+		Balance:     wallet.Balance,
+		MainBalance: wallet.MainBalance,
+		Pockets:     wallet.Pockets,
+		AsOf:        wallet.AsOf,
+	}
+}
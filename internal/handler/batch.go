@@ -0,0 +1,307 @@
+package handler
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/Te8va/wallet/internal/domain"
+	"github.com/Te8va/wallet/internal/problem"
+)
+
+const (
+	mediaJSON   = "application/json"
+	mediaNDJSON = "application/x-ndjson"
+	mediaCSV    = "text/csv"
+)
+
+var (
+	errBatchTooLarge     = errors.New("batch is too large")
+	errUnsupportedFormat = errors.New("unsupported batch format")
+)
+
+//go:generate mockgen -source=batch.go -destination=mocks/batch_mock.gen.go -package=mocks
+type Batcher interface {
+	SubmitBatch(ctx context.Context, mode domain.BatchMode, items []domain.BatchItem) (domain.Batch, error)
+	GetBatch(ctx context.Context, id int64) (domain.Batch, error)
+}
+
+type BatchHandler struct {
+	srv      Batcher
+	maxItems int
+}
+
+func NewBatchHandler(srv Batcher, maxItems int) *BatchHandler {
+	return &BatchHandler{srv: srv, maxItems: maxItems}
+}
+
+// SubmitBatchHandler accepts a JSON array, NDJSON or CSV body, or a
+// multipart upload of one of them in the "file" field. Lines that fail
+// validation do not reject the batch; they are reported as FAILED.
+func (h *BatchHandler) SubmitBatchHandler(w http.ResponseWriter, r *http.Request) {
+	mode := domain.BatchMode(r.URL.Query().Get("mode"))
+	if mode == "" {
+		mode = domain.BatchBestEffort
+	}
+
+	if mode != domain.BatchBestEffort && mode != domain.BatchAllOrNothing {
+		sendErrorResponse(w, r, problem.Invalid("mode", "Mode must be best_effort or all_or_nothing"))
+		return
+	}
+
+	items, err := h.parseBatch(r)
+	if err != nil {
+		switch {
+		case errors.Is(err, errBatchTooLarge):
+			sendErrorResponse(w, r, problem.New(problem.CodeBatchTooLarge, fmt.Sprintf("Batch must contain at most %d operations", h.maxItems)))
+		case errors.Is(err, errUnsupportedFormat):
+			sendErrorResponse(w, r, problem.New(problem.CodeUnsupportedMediaType, "Content type must be application/json, application/x-ndjson, text/csv or multipart/form-data"))
+		default:
+			sendErrorResponse(w, r, problem.New(problem.CodeMalformedRequest, "Invalid request body"))
+		}
+		return
+	}
+
+	if len(items) == 0 {
+		sendErrorResponse(w, r, problem.Invalid("", "Batch is empty"))
+		return
+	}
+
+	batch, err := h.srv.SubmitBatch(r.Context(), mode, items)
+	if err != nil {
+		sendError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Location", fmt.Sprintf("/api/v1/batches/%d", batch.ID))
+	sendJSONResponse(w, batch, http.StatusAccepted)
+}
+
+func (h *BatchHandler) GetBatchHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "batchId"), 10, 64)
+	if err != nil {
+		sendErrorResponse(w, r, problem.Invalid("batchId", "Invalid batch ID"))
+		return
+	}
+
+	batch, err := h.srv.GetBatch(r.Context(), id)
+	if err != nil {
+		sendError(w, r, err)
+		return
+	}
+
+	sendJSONResponse(w, batch, http.StatusOK)
+}
+
+func (h *BatchHandler) parseBatch(r *http.Request) ([]domain.BatchItem, error) {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		return nil, errUnsupportedFormat
+	}
+
+	if mediaType != "multipart/form-data" {
+		return h.parseBatchBody(r.Body, mediaType)
+	}
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	mediaType, _, err = mime.ParseMediaType(header.Header.Get("Content-Type"))
+	if err != nil || mediaType == "application/octet-stream" {
+		switch strings.ToLower(filepath.Ext(header.Filename)) {
+		case ".json":
+			mediaType = mediaJSON
+		case ".ndjson", ".jsonl":
+			mediaType = mediaNDJSON
+		case ".csv":
+			mediaType = mediaCSV
+		}
+	}
+
+	return h.parseBatchBody(file, mediaType)
+}
+
+func (h *BatchHandler) parseBatchBody(body io.Reader, mediaType string) ([]domain.BatchItem, error) {
+	switch mediaType {
+	case mediaJSON:
+		return h.parseJSONBatch(body)
+	case mediaNDJSON, "application/ndjson", "application/jsonl":
+		return h.parseNDJSONBatch(body)
+	case mediaCSV:
+		return h.parseCSVBatch(body)
+	default:
+		return nil, errUnsupportedFormat
+	}
+}
+
+func (h *BatchHandler) parseJSONBatch(body io.Reader) ([]domain.BatchItem, error) {
+	dec := json.NewDecoder(body)
+
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('[') {
+		return nil, errors.New("expected a JSON array")
+	}
+
+	var items []domain.BatchItem
+	for dec.More() {
+		if len(items) == h.maxItems {
+			return nil, errBatchTooLarge
+		}
+
+		var req domain.WalletRequest
+		if err := dec.Decode(&req); err != nil {
+			return nil, err
+		}
+		items = append(items, newBatchItem(len(items)+1, req, ""))
+	}
+
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+func (h *BatchHandler) parseNDJSONBatch(body io.Reader) ([]domain.BatchItem, error) {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	var items []domain.BatchItem
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		if len(items) == h.maxItems {
+			return nil, errBatchTooLarge
+		}
+
+		var req domain.WalletRequest
+		if err := json.Unmarshal([]byte(text), &req); err != nil {
+			items = append(items, newBatchItem(line, req, "Invalid JSON"))
+			continue
+		}
+		items = append(items, newBatchItem(line, req, ""))
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// parseCSVBatch reads walletId,operationType,amount records. A header row
+// is optional and recognised by its first column.
+func (h *BatchHandler) parseCSVBatch(body io.Reader) ([]domain.BatchItem, error) {
+	reader := csv.NewReader(body)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	var items []domain.BatchItem
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		line, _ := reader.FieldPos(0)
+
+		if line == 1 && isCSVHeader(record) {
+			continue
+		}
+
+		if len(items) == h.maxItems {
+			return nil, errBatchTooLarge
+		}
+
+		if len(record) != 3 {
+			items = append(items, newBatchItem(line, domain.WalletRequest{}, "Expected 3 columns: walletId, operationType, amount"))
+			continue
+		}
+
+		req := domain.WalletRequest{
+			WalletID:      record[0],
+			OperationType: domain.OperationType(record[1]),
+		}
+
+		req.Amount, err = strconv.ParseInt(record[2], 10, 64)
+		if err != nil {
+			items = append(items, newBatchItem(line, req, "Amount must be an integer"))
+			continue
+		}
+
+		items = append(items, newBatchItem(line, req, ""))
+	}
+
+	return items, nil
+}
+
+func isCSVHeader(record []string) bool {
+	if len(record) == 0 {
+		return false
+	}
+	first := strings.ToLower(record[0])
+	return first == "walletid" || first == "valletid" || first == "wallet_id"
+}
+
+// newBatchItem builds a batch item, marking it FAILED when parsing already
+// failed or the request does not pass validation.
+func newBatchItem(line int, req domain.WalletRequest, parseErr string) domain.BatchItem {
+	item := domain.BatchItem{
+		Line:          line,
+		WalletID:      req.WalletID,
+		OperationType: req.OperationType,
+		Amount:        req.Amount,
+		Status:        domain.BatchItemPending,
+	}
+
+	reason := parseErr
+	if reason == "" && (!utf8.ValidString(req.WalletID) || !utf8.ValidString(string(req.OperationType))) {
+		reason = "Request must be valid UTF-8"
+	}
+	if reason == "" {
+		_, reason = validateWalletRequest(req)
+	}
+
+	if reason != "" {
+		item.Status = domain.BatchItemFailed
+		item.Error = reason
+		item.WalletID = truncateRunes(item.WalletID, maxWalletIDLength)
+		item.OperationType = domain.OperationType(truncateRunes(string(item.OperationType), maxOperationTypeLength))
+	}
+
+	return item
+}
+
+// truncateRunes cuts s to at most n characters, the unit the column sizes
+// are given in, so a rejected value still fits its column. Invalid UTF-8 is
+// replaced, as the database would refuse it.
+func truncateRunes(s string, n int) string {
+	s = strings.ToValidUTF8(s, string(utf8.RuneError))
+	for i := range s {
+		if n == 0 {
+			return s[:i]
+		}
+		n--
+	}
+	return s
+}
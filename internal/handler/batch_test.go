@@ -0,0 +1,153 @@
+package handler
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Te8va/wallet/internal/domain"
+	"github.com/Te8va/wallet/internal/handler/mocks"
+)
+
+func TestSubmitBatchHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBatcher := mocks.NewMockBatcher(ctrl)
+	handler := NewBatchHandler(mockBatcher, 3)
+
+	walletID := "123e4567-e89b-12d3-a456-426614174000"
+
+	multipartBody := &bytes.Buffer{}
+	mw := multipart.NewWriter(multipartBody)
+	fw, _ := mw.CreateFormFile("file", "payroll.csv")
+	fw.Write([]byte(walletID + ",DEPOSIT,100\n"))
+	mw.Close()
+
+	testCases := []struct {
+		name        string
+		query       string
+		contentType string
+		body        string
+		mockServ    func()
+		wantCode    int
+		wantBody    string
+	}{
+		{
+			name:        "json array",
+			contentType: "application/json",
+			body:        `[{"valletId":"` + walletID + `","operationType":"DEPOSIT","amount":100},{"valletId":"` + walletID + `","operationType":"WITHDRAW","amount":0}]`,
+			mockServ: func() {
+				mockBatcher.EXPECT().SubmitBatch(gomock.Any(), domain.BatchBestEffort, []domain.BatchItem{
+					{Line: 1, WalletID: walletID, OperationType: domain.DEPOSIT, Amount: 100, Status: domain.BatchItemPending},
+					{Line: 2, WalletID: walletID, OperationType: domain.WITHDRAW, Amount: 0, Status: domain.BatchItemFailed, Error: "Amount must be more than 0"},
+				}).Return(domain.Batch{ID: 7, Mode: domain.BatchBestEffort, Status: domain.BatchPending, Total: 2}, nil)
+			},
+			wantCode: http.StatusAccepted,
+			wantBody: `{"id":7,"mode":"best_effort","status":"PENDING","total":2,"succeeded":0,"failed":0,"created_at":"0001-01-01T00:00:00Z"}`,
+		},
+		{
+			name:        "ndjson with a broken line",
+			query:       "?mode=all_or_nothing",
+			contentType: "application/x-ndjson",
+			body:        `{"valletId":"` + walletID + `","operationType":"DEPOSIT","amount":100}` + "\n\n{oops\n",
+			mockServ: func() {
+				mockBatcher.EXPECT().SubmitBatch(gomock.Any(), domain.BatchAllOrNothing, []domain.BatchItem{
+					{Line: 1, WalletID: walletID, OperationType: domain.DEPOSIT, Amount: 100, Status: domain.BatchItemPending},
+					{Line: 3, Status: domain.BatchItemFailed, Error: "Invalid JSON"},
+				}).Return(domain.Batch{ID: 8}, nil)
+			},
+			wantCode: http.StatusAccepted,
+		},
+		{
+			name:        "csv with header",
+			contentType: "text/csv",
+			body:        "walletId,operationType,amount\n" + walletID + ",WITHDRAW,50\n" + walletID + ",DEPOSIT,ten\n",
+			mockServ: func() {
+				mockBatcher.EXPECT().SubmitBatch(gomock.Any(), domain.BatchBestEffort, []domain.BatchItem{
+					{Line: 2, WalletID: walletID, OperationType: domain.WITHDRAW, Amount: 50, Status: domain.BatchItemPending},
+					{Line: 3, WalletID: walletID, OperationType: domain.DEPOSIT, Status: domain.BatchItemFailed, Error: "Amount must be an integer"},
+				}).Return(domain.Batch{ID: 9}, nil)
+			},
+			wantCode: http.StatusAccepted,
+		},
+		{
+			name:        "csv with an over-long and a broken wallet id",
+			contentType: "text/csv",
+			body:        strings.Repeat("ж", 40) + ",DEPOSIT,100\n" + "\xd0" + walletID[1:] + ",DEPOSIT,100\n",
+			mockServ: func() {
+				mockBatcher.EXPECT().SubmitBatch(gomock.Any(), domain.BatchBestEffort, []domain.BatchItem{
+					{Line: 1, WalletID: strings.Repeat("ж", 36), OperationType: domain.DEPOSIT, Amount: 100, Status: domain.BatchItemFailed, Error: "Wallet ID must be at most 36 characters"},
+					{Line: 2, WalletID: "\uFFFD" + walletID[1:], OperationType: domain.DEPOSIT, Amount: 100, Status: domain.BatchItemFailed, Error: "Request must be valid UTF-8"},
+				}).Return(domain.Batch{ID: 11}, nil)
+			},
+			wantCode: http.StatusAccepted,
+		},
+		{
+			name:        "multipart csv upload",
+			contentType: mw.FormDataContentType(),
+			body:        multipartBody.String(),
+			mockServ: func() {
+				mockBatcher.EXPECT().SubmitBatch(gomock.Any(), domain.BatchBestEffort, []domain.BatchItem{
+					{Line: 1, WalletID: walletID, OperationType: domain.DEPOSIT, Amount: 100, Status: domain.BatchItemPending},
+				}).Return(domain.Batch{ID: 10}, nil)
+			},
+			wantCode: http.StatusAccepted,
+		},
+		{
+			name:        "too many operations",
+			contentType: "text/csv",
+			body:        "a,DEPOSIT,1\nb,DEPOSIT,1\nc,DEPOSIT,1\nd,DEPOSIT,1\n",
+			mockServ:    func() {},
+			wantCode:    http.StatusRequestEntityTooLarge,
+			wantBody:    `{"type":"urn:wallet:problem:BATCH_TOO_LARGE","title":"Batch too large","status":413,"code":"BATCH_TOO_LARGE","detail":"Batch must contain at most 3 operations","instance":"/api/v1/batches"}`,
+		},
+		{
+			name:        "unknown mode",
+			query:       "?mode=sometimes",
+			contentType: "application/json",
+			body:        `[]`,
+			mockServ:    func() {},
+			wantCode:    http.StatusBadRequest,
+			wantBody:    `{"type":"urn:wallet:problem:VALIDATION_FAILED","title":"Validation failed","status":400,"code":"VALIDATION_FAILED","detail":"Mode must be best_effort or all_or_nothing","instance":"/api/v1/batches","errors":[{"field":"mode","message":"Mode must be best_effort or all_or_nothing"}]}`,
+		},
+		{
+			name:        "empty batch",
+			contentType: "application/json",
+			body:        `[]`,
+			mockServ:    func() {},
+			wantCode:    http.StatusBadRequest,
+			wantBody:    `{"type":"urn:wallet:problem:VALIDATION_FAILED","title":"Validation failed","status":400,"code":"VALIDATION_FAILED","detail":"Batch is empty","instance":"/api/v1/batches"}`,
+		},
+		{
+			name:        "unsupported content type",
+			contentType: "application/xml",
+			body:        `<batch/>`,
+			mockServ:    func() {},
+			wantCode:    http.StatusUnsupportedMediaType,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/batches"+tc.query, bytes.NewReader([]byte(tc.body)))
+			req.Header.Set("Content-Type", tc.contentType)
+
+			tc.mockServ()
+
+			w := httptest.NewRecorder()
+			handler.SubmitBatchHandler(w, req)
+
+			require.Equal(t, tc.wantCode, w.Code)
+			if tc.wantBody != "" {
+				require.JSONEq(t, tc.wantBody, w.Body.String())
+			}
+		})
+	}
+}
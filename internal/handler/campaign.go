@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/Te8va/wallet/internal/domain"
+	"github.com/Te8va/wallet/internal/problem"
+)
+
+const maxCampaignNameLength = 128
+
+//go:generate mockgen -source=campaign.go -destination=mocks/campaign_mock.gen.go -package=mocks
+type Campaign interface {
+	CreateCampaign(ctx context.Context, params domain.CampaignParams) (domain.Campaign, error)
+	GetCampaign(ctx context.Context, id int64) (domain.Campaign, error)
+	ListCampaigns(ctx context.Context) ([]domain.Campaign, error)
+}
+
+type CampaignHandler struct {
+	srv Campaign
+}
+
+func NewCampaignHandler(srv Campaign) *CampaignHandler {
+	return &CampaignHandler{srv: srv}
+}
+
+func (h *CampaignHandler) CreateCampaignHandler(w http.ResponseWriter, r *http.Request) {
+	var params domain.CampaignParams
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		sendErrorResponse(w, r, problem.New(problem.CodeMalformedRequest, "Invalid request body"))
+		return
+	}
+
+	switch {
+	case params.Name == "":
+		sendErrorResponse(w, r, problem.Invalid("name", "Name is required"))
+		return
+	case len(params.Name) > maxCampaignNameLength:
+		sendErrorResponse(w, r, problem.Invalid("name", "Name must be at most 128 characters"))
+		return
+	case params.FundingWalletID == "":
+		sendErrorResponse(w, r, problem.Invalid("fundingWalletId", "Funding wallet ID is required"))
+		return
+	case len(params.FundingWalletID) > maxWalletIDLength:
+		sendErrorResponse(w, r, problem.Invalid("fundingWalletId", "Wallet ID must be at most 36 characters"))
+		return
+	case params.RateBps <= 0 || params.RateBps > 10000:
+		sendErrorResponse(w, r, problem.Invalid("rateBps", "rateBps must be between 1 and 10000"))
+		return
+	case params.MinAmount < 0:
+		sendErrorResponse(w, r, problem.Invalid("minAmount", "minAmount must not be negative"))
+		return
+	case params.PerWalletCap < 0:
+		sendErrorResponse(w, r, problem.Invalid("perWalletCap", "perWalletCap must not be negative"))
+		return
+	case params.Budget <= 0:
+		sendErrorResponse(w, r, problem.Invalid("budget", "Budget must be more than 0"))
+		return
+	case params.EndsAt.IsZero():
+		sendErrorResponse(w, r, problem.Invalid("endsAt", "End time is required"))
+		return
+	}
+
+	campaign, err := h.srv.CreateCampaign(r.Context(), params)
+	if err != nil {
+		sendError(w, r, err)
+		return
+	}
+
+	sendJSONResponse(w, campaign, http.StatusCreated)
+}
+
+func (h *CampaignHandler) GetCampaignHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "campaignId"), 10, 64)
+	if err != nil {
+		sendErrorResponse(w, r, problem.Invalid("campaignId", "Invalid campaign ID"))
+		return
+	}
+
+	campaign, err := h.srv.GetCampaign(r.Context(), id)
+	if err != nil {
+		sendError(w, r, err)
+		return
+	}
+
+	sendJSONResponse(w, campaign, http.StatusOK)
+}
+
+func (h *CampaignHandler) ListCampaignsHandler(w http.ResponseWriter, r *http.Request) {
+	campaigns, err := h.srv.ListCampaigns(r.Context())
+	if err != nil {
+		sendError(w, r, err)
+		return
+	}
+
+	if campaigns == nil {
+		campaigns = []domain.Campaign{}
+	}
+
+	sendJSONResponse(w, campaigns, http.StatusOK)
+}
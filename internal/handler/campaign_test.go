@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Te8va/wallet/internal/domain"
+	appErrors "github.com/Te8va/wallet/internal/errors"
+	"github.com/Te8va/wallet/internal/handler/mocks"
+)
+
+func TestCreateCampaignHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCampaign := mocks.NewMockCampaign(ctrl)
+	handler := NewCampaignHandler(mockCampaign)
+
+	startsAt := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+	params := domain.CampaignParams{
+		Name: "spring", FundingWalletID: "marketing", RateBps: 500, PerWalletCap: 1000, Budget: 100000,
+		StartsAt: startsAt, EndsAt: startsAt.AddDate(0, 1, 0),
+	}
+
+	testCases := []struct {
+		name     string
+		body     string
+		mockServ func()
+		wantCode int
+		wantBody string
+	}{
+		{
+			name: "successful",
+			body: `{"name":"spring","fundingWalletId":"marketing","rateBps":500,"perWalletCap":1000,"budget":100000,"startsAt":"2025-04-01T00:00:00Z","endsAt":"2025-05-01T00:00:00Z"}`,
+			mockServ: func() {
+				mockCampaign.EXPECT().CreateCampaign(gomock.Any(), params).Return(domain.Campaign{
+					ID: 1, Name: "spring", FundingWalletID: "marketing", RateBps: 500, PerWalletCap: 1000, Budget: 100000,
+					StartsAt: params.StartsAt, EndsAt: params.EndsAt, CreatedAt: startsAt,
+				}, nil)
+			},
+			wantCode: http.StatusCreated,
+			wantBody: `{"id":1,"name":"spring","funding_wallet_id":"marketing","rate_bps":500,"min_amount":0,"per_wallet_cap":1000,"budget":100000,"spent":0,"starts_at":"2025-04-01T00:00:00Z","ends_at":"2025-05-01T00:00:00Z","created_at":"2025-04-01T00:00:00Z"}`,
+		},
+		{
+			name:     "rate above 100%",
+			body:     `{"name":"spring","fundingWalletId":"marketing","rateBps":10001,"budget":100000,"endsAt":"2025-05-01T00:00:00Z"}`,
+			mockServ: func() {},
+			wantCode: http.StatusBadRequest,
+			wantBody: `{"type":"urn:wallet:problem:VALIDATION_FAILED","title":"Validation failed","status":400,"code":"VALIDATION_FAILED","detail":"rateBps must be between 1 and 10000","instance":"/api/v1/campaigns","errors":[{"field":"rateBps","message":"rateBps must be between 1 and 10000"}]}`,
+		},
+		{
+			name: "ends before it starts",
+			body: `{"name":"spring","fundingWalletId":"marketing","rateBps":500,"perWalletCap":1000,"budget":100000,"startsAt":"2025-04-01T00:00:00Z","endsAt":"2025-05-01T00:00:00Z"}`,
+			mockServ: func() {
+				mockCampaign.EXPECT().CreateCampaign(gomock.Any(), params).Return(domain.Campaign{}, appErrors.ErrInvalidCampaign)
+			},
+			wantCode: http.StatusBadRequest,
+			wantBody: `{"type":"urn:wallet:problem:INVALID_CAMPAIGN","title":"Invalid campaign","status":400,"code":"INVALID_CAMPAIGN","detail":"campaign must end after it starts","instance":"/api/v1/campaigns"}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/campaigns", bytes.NewBufferString(tc.body))
+
+			tc.mockServ()
+
+			w := httptest.NewRecorder()
+			handler.CreateCampaignHandler(w, req)
+
+			require.Equal(t, tc.wantCode, w.Code)
+			require.JSONEq(t, tc.wantBody, w.Body.String())
+		})
+	}
+}
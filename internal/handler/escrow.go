@@ -0,0 +1,153 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/Te8va/wallet/internal/domain"
+	"github.com/Te8va/wallet/internal/problem"
+)
+
+const (
+	maxDealIDLength       = 64
+	maxEscrowReasonLength = 500
+)
+
+//go:generate mockgen -source=escrow.go -destination=mocks/escrow_mock.gen.go -package=mocks
+type Escrow interface {
+	CreateEscrow(ctx context.Context, req domain.EscrowRequest) (domain.Escrow, error)
+	GetEscrow(ctx context.Context, dealID string) (domain.Escrow, error)
+	ReleaseEscrow(ctx context.Context, dealID, reason string) (domain.Escrow, error)
+	RefundEscrow(ctx context.Context, dealID, reason string) (domain.Escrow, error)
+	DisputeEscrow(ctx context.Context, dealID, reason string) (domain.Escrow, error)
+	ResolveEscrow(ctx context.Context, dealID string, sellerAmount int64, reason string) (domain.Escrow, error)
+}
+
+type EscrowHandler struct {
+	srv Escrow
+}
+
+func NewEscrowHandler(srv Escrow) *EscrowHandler {
+	return &EscrowHandler{srv: srv}
+}
+
+func (h *EscrowHandler) CreateEscrowHandler(w http.ResponseWriter, r *http.Request) {
+	var req domain.EscrowRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		sendErrorResponse(w, r, problem.New(problem.CodeMalformedRequest, "Invalid request body"))
+		return
+	}
+
+	switch {
+	case req.DealID == "":
+		sendErrorResponse(w, r, problem.Invalid("dealId", "Deal ID is required"))
+		return
+	case len(req.DealID) > maxDealIDLength:
+		sendErrorResponse(w, r, problem.Invalid("dealId", "Deal ID must be at most 64 characters"))
+		return
+	case req.BuyerWalletID == "":
+		sendErrorResponse(w, r, problem.Invalid("buyerWalletId", "Buyer wallet ID is required"))
+		return
+	case req.SellerWalletID == "":
+		sendErrorResponse(w, r, problem.Invalid("sellerWalletId", "Seller wallet ID is required"))
+		return
+	case req.Amount <= 0:
+		sendErrorResponse(w, r, problem.Invalid("amount", "Amount must be more than 0"))
+		return
+	}
+
+	if req.OnTimeout != "" && req.OnTimeout != domain.EscrowReleased && req.OnTimeout != domain.EscrowRefunded {
+		sendErrorResponse(w, r, problem.Invalid("onTimeout", "onTimeout must be RELEASED or REFUNDED"))
+		return
+	}
+
+	escrow, err := h.srv.CreateEscrow(r.Context(), req)
+	if err != nil {
+		sendError(w, r, err)
+		return
+	}
+
+	sendJSONResponse(w, escrow, http.StatusCreated)
+}
+
+// GetEscrowHandler returns the escrow with its audit trail.
+func (h *EscrowHandler) GetEscrowHandler(w http.ResponseWriter, r *http.Request) {
+	escrow, err := h.srv.GetEscrow(r.Context(), chi.URLParam(r, "dealId"))
+	if err != nil {
+		sendError(w, r, err)
+		return
+	}
+
+	sendJSONResponse(w, escrow, http.StatusOK)
+}
+
+func (h *EscrowHandler) ReleaseEscrowHandler(w http.ResponseWriter, r *http.Request) {
+	h.action(w, r, h.srv.ReleaseEscrow)
+}
+
+func (h *EscrowHandler) RefundEscrowHandler(w http.ResponseWriter, r *http.Request) {
+	h.action(w, r, h.srv.RefundEscrow)
+}
+
+func (h *EscrowHandler) DisputeEscrowHandler(w http.ResponseWriter, r *http.Request) {
+	h.action(w, r, h.srv.DisputeEscrow)
+}
+
+// ResolveEscrowHandler settles a disputed escrow: sellerAmount goes to the
+// seller and the rest is refunded to the buyer.
+func (h *EscrowHandler) ResolveEscrowHandler(w http.ResponseWriter, r *http.Request) {
+	req, ok := decodeEscrowAction(w, r)
+	if !ok {
+		return
+	}
+
+	if req.SellerAmount < 0 {
+		sendErrorResponse(w, r, problem.Invalid("sellerAmount", "Seller amount must not be negative"))
+		return
+	}
+
+	escrow, err := h.srv.ResolveEscrow(r.Context(), chi.URLParam(r, "dealId"), req.SellerAmount, req.Reason)
+	if err != nil {
+		sendError(w, r, err)
+		return
+	}
+
+	sendJSONResponse(w, escrow, http.StatusOK)
+}
+
+func (h *EscrowHandler) action(w http.ResponseWriter, r *http.Request, apply func(ctx context.Context, dealID, reason string) (domain.Escrow, error)) {
+	req, ok := decodeEscrowAction(w, r)
+	if !ok {
+		return
+	}
+
+	escrow, err := apply(r.Context(), chi.URLParam(r, "dealId"), req.Reason)
+	if err != nil {
+		sendError(w, r, err)
+		return
+	}
+
+	sendJSONResponse(w, escrow, http.StatusOK)
+}
+
+// decodeEscrowAction reads the optional body of an escrow action. It writes
+// the error response and returns false when the body is invalid.
+func decodeEscrowAction(w http.ResponseWriter, r *http.Request) (domain.EscrowActionRequest, bool) {
+	var req domain.EscrowActionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		sendErrorResponse(w, r, problem.New(problem.CodeMalformedRequest, "Invalid request body"))
+		return domain.EscrowActionRequest{}, false
+	}
+
+	if len(req.Reason) > maxEscrowReasonLength {
+		sendErrorResponse(w, r, problem.Invalid("reason", "Reason must be at most 500 characters"))
+		return domain.EscrowActionRequest{}, false
+	}
+
+	return req, true
+}
@@ -0,0 +1,169 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Te8va/wallet/internal/domain"
+	appErrors "github.com/Te8va/wallet/internal/errors"
+	"github.com/Te8va/wallet/internal/handler/mocks"
+)
+
+func TestCreateEscrowHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEscrow := mocks.NewMockEscrow(ctrl)
+	handler := NewEscrowHandler(mockEscrow)
+
+	at := time.Date(2025, 4, 1, 10, 0, 0, 0, time.UTC)
+	request := domain.EscrowRequest{
+		DealID:         "deal-1",
+		BuyerWalletID:  "buyer",
+		SellerWalletID: "seller",
+		Amount:         1000,
+		OnTimeout:      domain.EscrowRefunded,
+	}
+
+	testCases := []struct {
+		name     string
+		body     string
+		mockServ func()
+		wantCode int
+		wantBody string
+	}{
+		{
+			name: "successful",
+			body: `{"dealId":"deal-1","buyerWalletId":"buyer","sellerWalletId":"seller","amount":1000,"onTimeout":"REFUNDED"}`,
+			mockServ: func() {
+				mockEscrow.EXPECT().CreateEscrow(gomock.Any(), request).Return(domain.Escrow{
+					DealID: "deal-1", WalletID: "escrow", BuyerWalletID: "buyer", SellerWalletID: "seller", Amount: 1000,
+					Status: domain.EscrowFunded, OnTimeout: domain.EscrowRefunded, ExpiresAt: at.Add(72 * time.Hour),
+					CreatedAt: at, UpdatedAt: at,
+					Events: []domain.EscrowEvent{{ID: 1, Action: domain.EscrowFund, ToStatus: domain.EscrowFunded, CreatedAt: at}},
+				}, nil)
+			},
+			wantCode: http.StatusCreated,
+			wantBody: `{"deal_id":"deal-1","wallet_id":"escrow","buyer_wallet_id":"buyer","seller_wallet_id":"seller","amount":1000,"status":"FUNDED","on_timeout":"REFUNDED","expires_at":"2025-04-04T10:00:00Z","created_at":"2025-04-01T10:00:00Z","updated_at":"2025-04-01T10:00:00Z","events":[{"id":1,"action":"FUND","to_status":"FUNDED","seller_amount":0,"buyer_amount":0,"created_at":"2025-04-01T10:00:00Z"}]}`,
+		},
+		{
+			name:     "invalid timeout action",
+			body:     `{"dealId":"deal-1","buyerWalletId":"buyer","sellerWalletId":"seller","amount":1000,"onTimeout":"SPLIT"}`,
+			mockServ: func() {},
+			wantCode: http.StatusBadRequest,
+			wantBody: `{"type":"urn:wallet:problem:VALIDATION_FAILED","title":"Validation failed","status":400,"code":"VALIDATION_FAILED","detail":"onTimeout must be RELEASED or REFUNDED","instance":"/api/v1/escrows","errors":[{"field":"onTimeout","message":"onTimeout must be RELEASED or REFUNDED"}]}`,
+		},
+		{
+			name: "deal exists",
+			body: `{"dealId":"deal-1","buyerWalletId":"buyer","sellerWalletId":"seller","amount":1000,"onTimeout":"REFUNDED"}`,
+			mockServ: func() {
+				mockEscrow.EXPECT().CreateEscrow(gomock.Any(), request).Return(domain.Escrow{}, appErrors.ErrEscrowExists)
+			},
+			wantCode: http.StatusConflict,
+			wantBody: `{"type":"urn:wallet:problem:ESCROW_EXISTS","title":"Escrow already exists","status":409,"code":"ESCROW_EXISTS","detail":"escrow for this deal already exists","instance":"/api/v1/escrows"}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/escrows", bytes.NewBufferString(tc.body))
+
+			tc.mockServ()
+
+			w := httptest.NewRecorder()
+			handler.CreateEscrowHandler(w, req)
+
+			require.Equal(t, tc.wantCode, w.Code)
+			require.JSONEq(t, tc.wantBody, w.Body.String())
+		})
+	}
+}
+
+func TestEscrowActionHandlers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEscrow := mocks.NewMockEscrow(ctrl)
+	handler := NewEscrowHandler(mockEscrow)
+
+	escrow := func(status domain.EscrowStatus) domain.Escrow {
+		return domain.Escrow{DealID: "deal-1", Amount: 1000, Status: status}
+	}
+
+	testCases := []struct {
+		name     string
+		action   string
+		handler  http.HandlerFunc
+		body     string
+		mockServ func()
+		wantCode int
+		wantBody string
+	}{
+		{
+			name:    "release without body",
+			action:  "release",
+			handler: handler.ReleaseEscrowHandler,
+			mockServ: func() {
+				mockEscrow.EXPECT().ReleaseEscrow(gomock.Any(), "deal-1", "").Return(escrow(domain.EscrowReleased), nil)
+			},
+			wantCode: http.StatusOK,
+			wantBody: `{"deal_id":"deal-1","wallet_id":"","buyer_wallet_id":"","seller_wallet_id":"","amount":1000,"status":"RELEASED","on_timeout":"","expires_at":"0001-01-01T00:00:00Z","created_at":"0001-01-01T00:00:00Z","updated_at":"0001-01-01T00:00:00Z"}`,
+		},
+		{
+			name:    "dispute settled escrow",
+			action:  "dispute",
+			handler: handler.DisputeEscrowHandler,
+			body:    `{"reason":"not delivered"}`,
+			mockServ: func() {
+				mockEscrow.EXPECT().DisputeEscrow(gomock.Any(), "deal-1", "not delivered").Return(domain.Escrow{}, appErrors.ErrInvalidEscrowState)
+			},
+			wantCode: http.StatusConflict,
+			wantBody: `{"type":"urn:wallet:problem:INVALID_ESCROW_STATE","title":"Invalid escrow state","status":409,"code":"INVALID_ESCROW_STATE","detail":"escrow state does not allow this action","instance":"/api/v1/escrows/deal-1/dispute"}`,
+		},
+		{
+			name:    "resolve above amount",
+			action:  "resolve",
+			handler: handler.ResolveEscrowHandler,
+			body:    `{"sellerAmount":2000}`,
+			mockServ: func() {
+				mockEscrow.EXPECT().ResolveEscrow(gomock.Any(), "deal-1", int64(2000), "").Return(domain.Escrow{}, appErrors.ErrInvalidEscrowSplit)
+			},
+			wantCode: http.StatusBadRequest,
+			wantBody: `{"type":"urn:wallet:problem:INVALID_ESCROW_SPLIT","title":"Invalid escrow split","status":400,"code":"INVALID_ESCROW_SPLIT","detail":"seller amount must be between 0 and the escrow amount","instance":"/api/v1/escrows/deal-1/resolve"}`,
+		},
+		{
+			name:     "refund with malformed body",
+			action:   "refund",
+			handler:  handler.RefundEscrowHandler,
+			body:     `{`,
+			mockServ: func() {},
+			wantCode: http.StatusBadRequest,
+			wantBody: `{"type":"urn:wallet:problem:MALFORMED_REQUEST","title":"Malformed request","status":400,"code":"MALFORMED_REQUEST","detail":"Invalid request body","instance":"/api/v1/escrows/deal-1/refund"}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/escrows/deal-1/"+tc.action, bytes.NewBufferString(tc.body))
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("dealId", "deal-1")
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+			tc.mockServ()
+
+			w := httptest.NewRecorder()
+			tc.handler(w, req)
+
+			require.Equal(t, tc.wantCode, w.Code)
+			require.JSONEq(t, tc.wantBody, w.Body.String())
+		})
+	}
+}
@@ -3,65 +3,94 @@ package handler
 import (
 	"context"
 	"encoding/json"
-	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 
 	"github.com/Te8va/wallet/internal/domain"
 	appErrors "github.com/Te8va/wallet/internal/errors"
+	"github.com/Te8va/wallet/internal/openapi"
+	"github.com/Te8va/wallet/internal/problem"
 )
 
 //go:generate mockgen -source=handler.go -destination=mocks/walhandler_mock.gen.go -package=mocks
 type Wallet interface {
-	ProcessTransaction(ctx context.Context, walletID string, opType domain.OperationType, amount int64) error
-	GetBalance(ctx context.Context, walletID string) (int64, error)
+	ProcessTransaction(ctx context.Context, req domain.TransactionRequest) (domain.TransactionResult, error)
+	GetBalance(ctx context.Context, walletID string) (domain.PocketBalances, error)
+	GetBalanceAsOf(ctx context.Context, walletID string, asOf time.Time) (int64, error)
+	GetTransaction(ctx context.Context, id int64) (domain.Transaction, error)
+	SearchTransactions(ctx context.Context, filter domain.TransactionFilter) (domain.TransactionHistory, error)
+	Split(ctx context.Context, req domain.SplitRequest) (domain.SplitPayment, error)
 }
 
+const (
+	maxWalletIDLength      = 36
+	maxOperationTypeLength = 32
+
+	defaultHistoryLimit = 50
+	maxHistoryLimit     = 500
+)
+
+// WalletHandler serves wallet operations and balances. The v2 handlers are
+// the primary implementation; the v1 handlers adapt v1 requests and
+// responses to them.
 type WalletHandler struct {
-	srv Wallet
+	srv      Wallet
+	currency string
 }
 
-func NewWalletHandler(srv Wallet) *WalletHandler {
-	return &WalletHandler{srv: srv}
+func NewWalletHandler(srv Wallet, currency string) *WalletHandler {
+	return &WalletHandler{srv: srv, currency: currency}
 }
 
+// WalletOperationHandler posts the operation and returns the resulting
+// transaction: 201 when it is posted, 200 when the reference has already been
+// used for the same operation. The request is checked against the WalletRequest schema here
+// as well as by middleware.WithValidation, so the handler stays safe on a
+// route mounted without the middleware.
 func (h *WalletHandler) WalletOperationHandler(w http.ResponseWriter, r *http.Request) {
 
 	var req domain.WalletRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		sendErrorResponse(w, "Invalid request body", http.StatusBadRequest)
+		sendErrorResponse(w, r, problem.New(problem.CodeMalformedRequest, "Invalid request body"))
 		return
 	}
 
-	if req.WalletID == "" {
-		sendErrorResponse(w, "Wallet ID is required", http.StatusBadRequest)
+	if field, msg := validateWalletRequest(req); msg != "" {
+		if field == "" {
+			sendErrorResponse(w, r, problem.New(problem.CodeMalformedRequest, msg))
+			return
+		}
+		sendErrorResponse(w, r, problem.Invalid(field, msg))
 		return
 	}
 
-	if req.OperationType != domain.DEPOSIT && req.OperationType != domain.WITHDRAW {
-		sendErrorResponse(w, "Operation type must be DEPOSIT or WITHDRAW", http.StatusBadRequest)
+	res, err := h.processTransaction(r.Context(), transactionRequestFromV1(req))
+	if err != nil {
+		sendError(w, r, err)
 		return
 	}
 
-	if req.Amount <= 0 {
-		sendErrorResponse(w, "Amount must be more than 0", http.StatusBadRequest)
-		return
+	status := http.StatusCreated
+	if res.Replayed {
+		w.Header().Set(IdempotentReplayedHeader, "true")
+		status = http.StatusOK
 	}
 
-	err := h.srv.ProcessTransaction(r.Context(), req.WalletID, req.OperationType, req.Amount)
-	if err != nil {
-		switch {
-		case errors.Is(err, appErrors.ErrInsufficientFunds):
-			sendErrorResponse(w, err.Error(), http.StatusBadRequest)
-		default:
-			sendErrorResponse(w, "Internal server error", http.StatusInternalServerError)
-		}
+	w.Header().Set("Location", fmt.Sprintf("/api/v1/transactions/%d", res.Transaction.ID))
+	sendJSONResponse(w, res.Transaction, status)
+}
+
+func (h *WalletHandler) GetTransactionHandler(w http.ResponseWriter, r *http.Request) {
+	t, ok := h.transaction(w, r)
+	if !ok {
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
+	sendJSONResponse(w, t, http.StatusOK)
 }
 
 func (h *WalletHandler) GetBalanceHandler(w http.ResponseWriter, r *http.Request) {
@@ -69,31 +98,191 @@ func (h *WalletHandler) GetBalanceHandler(w http.ResponseWriter, r *http.Request
 	walletID := chi.URLParam(r, "walletId")
 
 	if walletID == "" {
-		sendErrorResponse(w, "Wallet ID is required", http.StatusBadRequest)
+		sendErrorResponse(w, r, problem.Invalid("walletId", "Wallet ID is required"))
 		return
 	}
 
-	balance, err := h.srv.GetBalance(r.Context(), walletID)
-	if err != nil {
-		if errors.Is(err, appErrors.ErrWalletNotFound) {
-			sendErrorResponse(w, "Wallet not found", http.StatusNotFound)
+	var asOf *time.Time
+	if v := r.URL.Query().Get("as_of"); v != "" {
+		at, err := time.Parse(time.RFC3339Nano, v)
+		if err != nil {
+			sendErrorResponse(w, r, problem.Invalid("as_of", "as_of must be an RFC 3339 time"))
 			return
 		}
-		sendErrorResponse(w, "Internal server error", http.StatusInternalServerError)
+		asOf = &at
+	}
+
+	wallet, err := h.wallet(r.Context(), walletID, asOf)
+	if err != nil {
+		sendError(w, r, err)
 		return
 	}
 
-	response := domain.BalanceResponse{
-		WalletID: walletID,
-		Balance:  balance,
+	sendJSONResponse(w, balanceResponseFromV2(wallet), http.StatusOK)
+}
+
+// processTransaction posts the operation in the wallet currency.
+func (h *WalletHandler) processTransaction(ctx context.Context, req domain.TransactionRequest) (domain.TransactionResult, error) {
+	if req.Currency != "" && req.Currency != h.currency {
+		return domain.TransactionResult{}, appErrors.ErrCurrencyMismatch
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(response)
+	return h.srv.ProcessTransaction(ctx, req)
 }
 
-func sendErrorResponse(w http.ResponseWriter, message string, statusCode int) {
+// wallet returns the current balance with its breakdown, or the total
+// balance as of the given time.
+func (h *WalletHandler) wallet(ctx context.Context, walletID string, asOf *time.Time) (domain.WalletResponse, error) {
+	if asOf != nil {
+		balance, err := h.srv.GetBalanceAsOf(ctx, walletID, *asOf)
+		if err != nil {
+			return domain.WalletResponse{}, err
+		}
+
+		return domain.WalletResponse{
+			WalletID: walletID,
+			Balance:  balance,
+			Currency: h.currency,
+			AsOf:     asOf,
+		}, nil
+	}
+
+	balances, err := h.srv.GetBalance(ctx, walletID)
+	if err != nil {
+		return domain.WalletResponse{}, err
+	}
+
+	pockets := make([]domain.PocketBalance, 0, len(balances.Pockets))
+	for _, p := range balances.Pockets {
+		pockets = append(pockets, domain.PocketBalance{Name: p.Name, Balance: p.Balance})
+	}
+
+	return domain.WalletResponse{
+		WalletID:    walletID,
+		Balance:     balances.Balance,
+		MainBalance: &balances.MainBalance,
+		Pockets:     pockets,
+		Currency:    h.currency,
+	}, nil
+}
+
+// ListTransactionsHandler returns the wallet history, newest first,
+// optionally narrowed down by reference, tags and time.
+func (h *WalletHandler) ListTransactionsHandler(w http.ResponseWriter, r *http.Request) {
+	filter, ok := transactionFilter(w, r)
+	if !ok {
+		return
+	}
+
+	history, err := h.srv.SearchTransactions(r.Context(), filter)
+	if err != nil {
+		sendError(w, r, err)
+		return
+	}
+
+	sendJSONResponse(w, history, http.StatusOK)
+}
+
+// transactionFilter reads the history filter from the walletId route
+// parameter and the query. On failure it answers the request itself and
+// returns false.
+func transactionFilter(w http.ResponseWriter, r *http.Request) (domain.TransactionFilter, bool) {
+	query := r.URL.Query()
+	filter := domain.TransactionFilter{
+		WalletID:  chi.URLParam(r, "walletId"),
+		Reference: query.Get("reference"),
+		Tags:      query["tag"],
+		Limit:     defaultHistoryLimit,
+	}
+
+	if filter.WalletID == "" {
+		sendErrorResponse(w, r, problem.Invalid("walletId", "Wallet ID is required"))
+		return domain.TransactionFilter{}, false
+	}
+
+	bounds := []struct {
+		name string
+		dst  **time.Time
+	}{
+		{"from", &filter.From},
+		{"to", &filter.To},
+	}
+	for _, b := range bounds {
+		v := query.Get(b.name)
+		if v == "" {
+			continue
+		}
+		at, err := time.Parse(time.RFC3339Nano, v)
+		if err != nil {
+			sendErrorResponse(w, r, problem.Invalid(b.name, b.name+" must be an RFC 3339 time"))
+			return domain.TransactionFilter{}, false
+		}
+		*b.dst = &at
+	}
+
+	if v := query.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 1 || limit > maxHistoryLimit {
+			sendErrorResponse(w, r, problem.Invalid("limit", fmt.Sprintf("limit must be between 1 and %d", maxHistoryLimit)))
+			return domain.TransactionFilter{}, false
+		}
+		filter.Limit = limit
+	}
+
+	if v := query.Get("before"); v != "" {
+		before, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || before < 1 {
+			sendErrorResponse(w, r, problem.Invalid("before", "before must be a transaction ID"))
+			return domain.TransactionFilter{}, false
+		}
+		filter.Before = before
+	}
+
+	return filter, true
+}
+
+// transaction loads the transaction named by the transactionId route
+// parameter. On failure it answers the request itself and returns false.
+func (h *WalletHandler) transaction(w http.ResponseWriter, r *http.Request) (domain.Transaction, bool) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "transactionId"), 10, 64)
+	if err != nil {
+		sendErrorResponse(w, r, problem.Invalid("transactionId", "Invalid transaction ID"))
+		return domain.Transaction{}, false
+	}
+
+	t, err := h.srv.GetTransaction(r.Context(), id)
+	if err != nil {
+		sendError(w, r, err)
+		return domain.Transaction{}, false
+	}
+
+	return t, true
+}
+
+// validateWalletRequest checks the request against the WalletRequest schema
+// of the OpenAPI document and returns the offending field with a client-facing
+// message describing what is wrong with it, or an empty message when it is
+// valid.
+func validateWalletRequest(req domain.WalletRequest) (field, msg string) {
+	if err := openapi.ValidateSchema("WalletRequest", req); err != nil {
+		return openapi.Field(err), openapi.Message(err)
+	}
+
+	return "", ""
+}
+
+func sendJSONResponse(w http.ResponseWriter, v any, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(statusCode)
-	json.NewEncoder(w).Encode(domain.ErrorResponse{Error: message})
+	json.NewEncoder(w).Encode(v)
+}
+
+func sendErrorResponse(w http.ResponseWriter, r *http.Request, p domain.Problem) {
+	problem.Write(w, r, p)
+}
+
+// sendError answers with the problem err maps to; errors that are not
+// sentinels of internal/errors become a 500.
+func sendError(w http.ResponseWriter, r *http.Request, err error) {
+	problem.Write(w, r, problem.FromError(err))
 }
@@ -4,9 +4,11 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/golang/mock/gomock"
@@ -15,12 +17,14 @@ import (
 	"github.com/Te8va/wallet/internal/domain"
 	appErrors "github.com/Te8va/wallet/internal/errors"
 	"github.com/Te8va/wallet/internal/handler/mocks"
+	"github.com/Te8va/wallet/internal/middleware"
+	"github.com/Te8va/wallet/internal/openapi"
 )
 
 func setupTestHandler(t *testing.T) (*gomock.Controller, *mocks.MockWallet, *WalletHandler) {
 	ctrl := gomock.NewController(t)
 	mockWallet := mocks.NewMockWallet(ctrl)
-	handler := NewWalletHandler(mockWallet)
+	handler := NewWalletHandler(mockWallet, "RUB")
 	return ctrl, mockWallet, handler
 }
 
@@ -28,13 +32,21 @@ func TestWalletOperationHandler(t *testing.T) {
 	ctrl, mockWallet, handler := setupTestHandler(t)
 	defer ctrl.Finish()
 
+	doc, err := openapi.Load()
+	require.NoError(t, err)
+	validate, err := middleware.WithValidation(doc)
+	require.NoError(t, err)
+	h := validate(http.HandlerFunc(handler.WalletOperationHandler))
+
 	testCases := []struct {
-		name        string
-		contentType string
-		body        interface{}
-		mockServ    func()
-		wantCode    int
-		mockErr     string
+		name         string
+		contentType  string
+		body         interface{}
+		mockServ     func()
+		wantCode     int
+		wantHeader   string
+		wantReplayed string
+		mockErr      string
 	}{
 		{
 			name:        "successful deposit",
@@ -45,10 +57,14 @@ func TestWalletOperationHandler(t *testing.T) {
 				Amount:        1000,
 			},
 			mockServ: func() {
-				mockWallet.EXPECT().ProcessTransaction(gomock.Any(), "123e4567-e89b-12d3-a456-426614174000", domain.DEPOSIT, int64(1000)).Return(nil)
+				mockWallet.EXPECT().ProcessTransaction(gomock.Any(), domain.TransactionRequest{WalletID: "123e4567-e89b-12d3-a456-426614174000", OperationType: domain.DEPOSIT, Amount: 1000}).Return(domain.TransactionResult{Transaction: domain.Transaction{
+					ID: 7, WalletID: "123e4567-e89b-12d3-a456-426614174000", OperationType: domain.DEPOSIT, Amount: 1000, Balance: 1500,
+					CreatedAt: time.Date(2025, 4, 1, 10, 0, 0, 0, time.UTC),
+				}}, nil)
 			},
-			wantCode: http.StatusOK,
-			mockErr:  "",
+			wantCode:   http.StatusCreated,
+			wantHeader: "/api/v1/transactions/7",
+			mockErr:    `{"id":7,"wallet_id":"123e4567-e89b-12d3-a456-426614174000","operation_type":"DEPOSIT","amount":1000,"balance":1500,"created_at":"2025-04-01T10:00:00Z"}`,
 		},
 		{
 			name:        "successful withdraw",
@@ -59,10 +75,14 @@ func TestWalletOperationHandler(t *testing.T) {
 				Amount:        500,
 			},
 			mockServ: func() {
-				mockWallet.EXPECT().ProcessTransaction(gomock.Any(), "123e4567-e89b-12d3-a456-426614174000", domain.WITHDRAW, int64(500)).Return(nil)
+				mockWallet.EXPECT().ProcessTransaction(gomock.Any(), domain.TransactionRequest{WalletID: "123e4567-e89b-12d3-a456-426614174000", OperationType: domain.WITHDRAW, Amount: 500}).Return(domain.TransactionResult{Transaction: domain.Transaction{
+					ID: 8, WalletID: "123e4567-e89b-12d3-a456-426614174000", OperationType: domain.WITHDRAW, Amount: -500, Balance: 1000,
+					CreatedAt: time.Date(2025, 4, 1, 10, 5, 0, 0, time.UTC),
+				}}, nil)
 			},
-			wantCode: http.StatusOK,
-			mockErr:  "",
+			wantCode:   http.StatusCreated,
+			wantHeader: "/api/v1/transactions/8",
+			mockErr:    `{"id":8,"wallet_id":"123e4567-e89b-12d3-a456-426614174000","operation_type":"WITHDRAW","amount":-500,"balance":1000,"created_at":"2025-04-01T10:05:00Z"}`,
 		},
 		{
 			name:        "invalid content type",
@@ -70,7 +90,7 @@ func TestWalletOperationHandler(t *testing.T) {
 			body:        "not json",
 			mockServ:    func() {},
 			wantCode:    http.StatusBadRequest,
-			mockErr:     `{"error":"Invalid request body"}`,
+			mockErr:     `{"type":"urn:wallet:problem:MALFORMED_REQUEST","title":"Malformed request","status":400,"code":"MALFORMED_REQUEST","detail":"Invalid request body","instance":"/api/v1/wallet"}`,
 		},
 		{
 			name:        "missing wallet ID",
@@ -82,7 +102,35 @@ func TestWalletOperationHandler(t *testing.T) {
 			},
 			mockServ: func() {},
 			wantCode: http.StatusBadRequest,
-			mockErr:  `{"error":"Wallet ID is required"}`,
+			mockErr:  `{"type":"urn:wallet:problem:VALIDATION_FAILED","title":"Validation failed","status":400,"code":"VALIDATION_FAILED","detail":"Wallet ID is required","instance":"/api/v1/wallet","errors":[{"field":"valletId","message":"Wallet ID is required"}]}`,
+		},
+		{
+			name:        "wallet ID too long",
+			contentType: "application/json",
+			body: domain.WalletRequest{
+				WalletID:      "123e4567-e89b-12d3-a456-4266141740001",
+				OperationType: domain.DEPOSIT,
+				Amount:        1000,
+			},
+			mockServ: func() {},
+			wantCode: http.StatusBadRequest,
+			mockErr:  `{"type":"urn:wallet:problem:VALIDATION_FAILED","title":"Validation failed","status":400,"code":"VALIDATION_FAILED","detail":"Wallet ID must be at most 36 characters","instance":"/api/v1/wallet","errors":[{"field":"valletId","message":"Wallet ID must be at most 36 characters"}]}`,
+		},
+		{
+			name:        "missing amount",
+			contentType: "application/json",
+			body:        `{"valletId":"123e4567-e89b-12d3-a456-426614174000","operationType":"DEPOSIT"}`,
+			mockServ:    func() {},
+			wantCode:    http.StatusBadRequest,
+			mockErr:     `{"type":"urn:wallet:problem:VALIDATION_FAILED","title":"Validation failed","status":400,"code":"VALIDATION_FAILED","detail":"Amount must be more than 0","instance":"/api/v1/wallet","errors":[{"field":"amount","message":"Amount must be more than 0"}]}`,
+		},
+		{
+			name:        "amount is not a number",
+			contentType: "application/json",
+			body:        `{"valletId":"123e4567-e89b-12d3-a456-426614174000","operationType":"DEPOSIT","amount":"1000"}`,
+			mockServ:    func() {},
+			wantCode:    http.StatusBadRequest,
+			mockErr:     `{"type":"urn:wallet:problem:MALFORMED_REQUEST","title":"Malformed request","status":400,"code":"MALFORMED_REQUEST","detail":"Invalid request body","instance":"/api/v1/wallet"}`,
 		},
 		{
 			name:        "invalid operation type",
@@ -94,7 +142,7 @@ func TestWalletOperationHandler(t *testing.T) {
 			},
 			mockServ: func() {},
 			wantCode: http.StatusBadRequest,
-			mockErr:  `{"error":"Operation type must be DEPOSIT or WITHDRAW"}`,
+			mockErr:  `{"type":"urn:wallet:problem:VALIDATION_FAILED","title":"Validation failed","status":400,"code":"VALIDATION_FAILED","detail":"Operation type must be DEPOSIT or WITHDRAW","instance":"/api/v1/wallet","errors":[{"field":"operationType","message":"Operation type must be DEPOSIT or WITHDRAW"}]}`,
 		},
 		{
 			name:        "zero amount",
@@ -106,7 +154,7 @@ func TestWalletOperationHandler(t *testing.T) {
 			},
 			mockServ: func() {},
 			wantCode: http.StatusBadRequest,
-			mockErr:  `{"error":"Amount must be more than 0"}`,
+			mockErr:  `{"type":"urn:wallet:problem:VALIDATION_FAILED","title":"Validation failed","status":400,"code":"VALIDATION_FAILED","detail":"Amount must be more than 0","instance":"/api/v1/wallet","errors":[{"field":"amount","message":"Amount must be more than 0"}]}`,
 		},
 		{
 			name:        "negative amount",
@@ -118,7 +166,7 @@ func TestWalletOperationHandler(t *testing.T) {
 			},
 			mockServ: func() {},
 			wantCode: http.StatusBadRequest,
-			mockErr:  `{"error":"Amount must be more than 0"}`,
+			mockErr:  `{"type":"urn:wallet:problem:VALIDATION_FAILED","title":"Validation failed","status":400,"code":"VALIDATION_FAILED","detail":"Amount must be more than 0","instance":"/api/v1/wallet","errors":[{"field":"amount","message":"Amount must be more than 0"}]}`,
 		},
 		{
 			name:        "insufficient funds",
@@ -129,10 +177,40 @@ func TestWalletOperationHandler(t *testing.T) {
 				Amount:        5000,
 			},
 			mockServ: func() {
-				mockWallet.EXPECT().ProcessTransaction(gomock.Any(), "123e4567-e89b-12d3-a456-426614174000", domain.WITHDRAW, int64(5000)).Return(appErrors.ErrInsufficientFunds)
+				mockWallet.EXPECT().ProcessTransaction(gomock.Any(), domain.TransactionRequest{WalletID: "123e4567-e89b-12d3-a456-426614174000", OperationType: domain.WITHDRAW, Amount: 5000}).Return(domain.TransactionResult{}, appErrors.ErrInsufficientFunds)
 			},
 			wantCode: http.StatusBadRequest,
-			mockErr:  `{"error":"insufficient funds"}`,
+			mockErr:  `{"type":"urn:wallet:problem:INSUFFICIENT_FUNDS","title":"Insufficient funds","status":400,"code":"INSUFFICIENT_FUNDS","detail":"insufficient funds","instance":"/api/v1/wallet"}`,
+		},
+		{
+			name:        "reference already used",
+			contentType: "application/json",
+			body:        `{"valletId":"123e4567-e89b-12d3-a456-426614174000","operationType":"DEPOSIT","amount":1000,"reference":"invoice-17","description":"April rent","metadata":{"unit":4},"tags":["rent"]}`,
+			mockServ: func() {
+				mockWallet.EXPECT().ProcessTransaction(gomock.Any(), domain.TransactionRequest{
+					WalletID: "123e4567-e89b-12d3-a456-426614174000", OperationType: domain.DEPOSIT, Amount: 1000,
+					Reference: "invoice-17", Description: "April rent", Metadata: map[string]any{"unit": float64(4)}, Tags: []string{"rent"},
+				}).Return(domain.TransactionResult{}, appErrors.ErrDuplicateReference)
+			},
+			wantCode: http.StatusConflict,
+			mockErr:  `{"type":"urn:wallet:problem:DUPLICATE_REFERENCE","title":"Reference already used","status":409,"code":"DUPLICATE_REFERENCE","detail":"reference was already used for a different operation","instance":"/api/v1/wallet"}`,
+		},
+		{
+			name:        "replayed reference",
+			contentType: "application/json",
+			body:        `{"valletId":"123e4567-e89b-12d3-a456-426614174000","operationType":"DEPOSIT","amount":1000,"reference":"invoice-17"}`,
+			mockServ: func() {
+				mockWallet.EXPECT().ProcessTransaction(gomock.Any(), domain.TransactionRequest{
+					WalletID: "123e4567-e89b-12d3-a456-426614174000", OperationType: domain.DEPOSIT, Amount: 1000, Reference: "invoice-17",
+				}).Return(domain.TransactionResult{Transaction: domain.Transaction{
+					ID: 7, WalletID: "123e4567-e89b-12d3-a456-426614174000", OperationType: domain.DEPOSIT, Amount: 1000, Balance: 1500,
+					Reference: "invoice-17", CreatedAt: time.Date(2025, 4, 1, 10, 0, 0, 0, time.UTC),
+				}, Replayed: true}, nil)
+			},
+			wantCode:     http.StatusOK,
+			wantHeader:   "/api/v1/transactions/7",
+			wantReplayed: "true",
+			mockErr:      `{"id":7,"wallet_id":"123e4567-e89b-12d3-a456-426614174000","operation_type":"DEPOSIT","amount":1000,"balance":1500,"reference":"invoice-17","created_at":"2025-04-01T10:00:00Z"}`,
 		},
 		{
 			name:        "internal server error",
@@ -143,10 +221,10 @@ func TestWalletOperationHandler(t *testing.T) {
 				Amount:        1000,
 			},
 			mockServ: func() {
-				mockWallet.EXPECT().ProcessTransaction(gomock.Any(), "123e4567-e89b-12d3-a456-426614174000", domain.DEPOSIT, int64(1000)).Return(appErrors.ErrWalletNotFound)
+				mockWallet.EXPECT().ProcessTransaction(gomock.Any(), domain.TransactionRequest{WalletID: "123e4567-e89b-12d3-a456-426614174000", OperationType: domain.DEPOSIT, Amount: 1000}).Return(domain.TransactionResult{}, errors.New("connection refused"))
 			},
 			wantCode: http.StatusInternalServerError,
-			mockErr:  `{"error":"Internal server error"}`,
+			mockErr:  `{"type":"urn:wallet:problem:INTERNAL_ERROR","title":"Internal server error","status":500,"code":"INTERNAL_ERROR","instance":"/api/v1/wallet"}`,
 		},
 	}
 
@@ -166,9 +244,11 @@ func TestWalletOperationHandler(t *testing.T) {
 			tc.mockServ()
 
 			w := httptest.NewRecorder()
-			handler.WalletOperationHandler(w, req)
+			h.ServeHTTP(w, req)
 
 			require.Equal(t, tc.wantCode, w.Code)
+			require.Equal(t, tc.wantHeader, w.Header().Get("Location"))
+			require.Equal(t, tc.wantReplayed, w.Header().Get(IdempotentReplayedHeader))
 			if tc.mockErr != "" {
 				require.JSONEq(t, tc.mockErr, w.Body.String())
 			}
@@ -176,6 +256,46 @@ func TestWalletOperationHandler(t *testing.T) {
 	}
 }
 
+func TestWalletOperationHandlerWithoutValidation(t *testing.T) {
+	ctrl, _, handler := setupTestHandler(t)
+	defer ctrl.Finish()
+
+	testCases := []struct {
+		name    string
+		body    string
+		wantErr string
+	}{
+		{
+			name:    "missing wallet ID",
+			body:    `{"operationType":"DEPOSIT","amount":1000}`,
+			wantErr: `{"type":"urn:wallet:problem:VALIDATION_FAILED","title":"Validation failed","status":400,"code":"VALIDATION_FAILED","detail":"Wallet ID is required","instance":"/api/v1/wallet","errors":[{"field":"valletId","message":"Wallet ID is required"}]}`,
+		},
+		{
+			name:    "invalid operation type",
+			body:    `{"valletId":"123e4567-e89b-12d3-a456-426614174000","operationType":"DEPOSITT","amount":1000}`,
+			wantErr: `{"type":"urn:wallet:problem:VALIDATION_FAILED","title":"Validation failed","status":400,"code":"VALIDATION_FAILED","detail":"Operation type must be DEPOSIT or WITHDRAW","instance":"/api/v1/wallet","errors":[{"field":"operationType","message":"Operation type must be DEPOSIT or WITHDRAW"}]}`,
+		},
+		{
+			name:    "zero amount",
+			body:    `{"valletId":"123e4567-e89b-12d3-a456-426614174000","operationType":"DEPOSIT","amount":0}`,
+			wantErr: `{"type":"urn:wallet:problem:VALIDATION_FAILED","title":"Validation failed","status":400,"code":"VALIDATION_FAILED","detail":"Amount must be more than 0","instance":"/api/v1/wallet","errors":[{"field":"amount","message":"Amount must be more than 0"}]}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/wallet", bytes.NewBufferString(tc.body))
+			req.Header.Set("Content-Type", "application/json")
+
+			w := httptest.NewRecorder()
+			handler.WalletOperationHandler(w, req)
+
+			require.Equal(t, http.StatusBadRequest, w.Code)
+			require.JSONEq(t, tc.wantErr, w.Body.String())
+		})
+	}
+}
+
 func TestGetBalanceHandler(t *testing.T) {
 	ctrl, mockWallet, handler := setupTestHandler(t)
 	defer ctrl.Finish()
@@ -183,6 +303,7 @@ func TestGetBalanceHandler(t *testing.T) {
 	testCases := []struct {
 		name     string
 		walletID string
+		query    string
 		mockServ func()
 		wantCode int
 		mockErr  string
@@ -191,41 +312,63 @@ func TestGetBalanceHandler(t *testing.T) {
 			name:     "successful",
 			walletID: "123e4567-e89b-12d3-a456-426614174000",
 			mockServ: func() {
-				mockWallet.EXPECT().GetBalance(gomock.Any(), "123e4567-e89b-12d3-a456-426614174000").Return(int64(1500), nil)
+				mockWallet.EXPECT().GetBalance(gomock.Any(), "123e4567-e89b-12d3-a456-426614174000").Return(domain.PocketBalances{
+					WalletID: "123e4567-e89b-12d3-a456-426614174000", Balance: 1500, MainBalance: 1200,
+					Pockets: []domain.Pocket{{WalletID: "123e4567-e89b-12d3-a456-426614174000", Name: "vacation", Balance: 300}},
+				}, nil)
 			},
 			wantCode: http.StatusOK,
-			mockErr:  `{"wallet_id":"123e4567-e89b-12d3-a456-426614174000","balance":1500}`,
+			mockErr:  `{"wallet_id":"123e4567-e89b-12d3-a456-426614174000","balance":1500,"main_balance":1200,"pockets":[{"name":"vacation","balance":300}]}`,
 		},
 		{
 			name:     "wallet not found",
 			walletID: "123e4567-e89b-12d3-a456-426614174000",
 			mockServ: func() {
-				mockWallet.EXPECT().GetBalance(gomock.Any(), "123e4567-e89b-12d3-a456-426614174000").Return(int64(0), appErrors.ErrWalletNotFound)
+				mockWallet.EXPECT().GetBalance(gomock.Any(), "123e4567-e89b-12d3-a456-426614174000").Return(domain.PocketBalances{}, appErrors.ErrWalletNotFound)
 			},
 			wantCode: http.StatusNotFound,
-			mockErr:  `{"error":"Wallet not found"}`,
+			mockErr:  `{"type":"urn:wallet:problem:WALLET_NOT_FOUND","title":"Wallet not found","status":404,"code":"WALLET_NOT_FOUND","detail":"wallet not found","instance":"/api/v1/wallets/123e4567-e89b-12d3-a456-426614174000"}`,
+		},
+		{
+			name:     "balance as of",
+			walletID: "123e4567-e89b-12d3-a456-426614174000",
+			query:    "?as_of=2025-03-31T23:59:00Z",
+			mockServ: func() {
+				mockWallet.EXPECT().GetBalanceAsOf(gomock.Any(), "123e4567-e89b-12d3-a456-426614174000",
+					time.Date(2025, 3, 31, 23, 59, 0, 0, time.UTC)).Return(int64(700), nil)
+			},
+			wantCode: http.StatusOK,
+			mockErr:  `{"wallet_id":"123e4567-e89b-12d3-a456-426614174000","balance":700,"as_of":"2025-03-31T23:59:00Z"}`,
+		},
+		{
+			name:     "invalid as_of",
+			walletID: "123e4567-e89b-12d3-a456-426614174000",
+			query:    "?as_of=2025-03-31",
+			mockServ: func() {},
+			wantCode: http.StatusBadRequest,
+			mockErr:  `{"type":"urn:wallet:problem:VALIDATION_FAILED","title":"Validation failed","status":400,"code":"VALIDATION_FAILED","detail":"as_of must be an RFC 3339 time","instance":"/api/v1/wallets/123e4567-e89b-12d3-a456-426614174000","errors":[{"field":"as_of","message":"as_of must be an RFC 3339 time"}]}`,
 		},
 		{
 			name:     "empty wallet ID",
 			walletID: "",
 			mockServ: func() {},
 			wantCode: http.StatusBadRequest,
-			mockErr:  `{"error":"Wallet ID is required"}`,
+			mockErr:  `{"type":"urn:wallet:problem:VALIDATION_FAILED","title":"Validation failed","status":400,"code":"VALIDATION_FAILED","detail":"Wallet ID is required","instance":"/api/v1/wallets/","errors":[{"field":"walletId","message":"Wallet ID is required"}]}`,
 		},
 		{
 			name:     "internal server error",
 			walletID: "123e4567-e89b-12d3-a456-426614174000",
 			mockServ: func() {
-				mockWallet.EXPECT().GetBalance(gomock.Any(), "123e4567-e89b-12d3-a456-426614174000").Return(int64(0), appErrors.ErrInsufficientFunds)
+				mockWallet.EXPECT().GetBalance(gomock.Any(), "123e4567-e89b-12d3-a456-426614174000").Return(domain.PocketBalances{}, errors.New("connection refused"))
 			},
 			wantCode: http.StatusInternalServerError,
-			mockErr:  `{"error":"Internal server error"}`,
+			mockErr:  `{"type":"urn:wallet:problem:INTERNAL_ERROR","title":"Internal server error","status":500,"code":"INTERNAL_ERROR","instance":"/api/v1/wallets/123e4567-e89b-12d3-a456-426614174000"}`,
 		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			req := httptest.NewRequest(http.MethodGet, "/api/v1/wallets/"+tc.walletID, nil)
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/wallets/"+tc.walletID+tc.query, nil)
 
 			if tc.walletID != "" {
 				rctx := chi.NewRouteContext()
@@ -243,3 +386,146 @@ func TestGetBalanceHandler(t *testing.T) {
 		})
 	}
 }
+
+func TestGetTransactionHandler(t *testing.T) {
+	ctrl, mockWallet, handler := setupTestHandler(t)
+	defer ctrl.Finish()
+
+	testCases := []struct {
+		name          string
+		transactionID string
+		mockServ      func()
+		wantCode      int
+		mockErr       string
+	}{
+		{
+			name:          "successful",
+			transactionID: "7",
+			mockServ: func() {
+				mockWallet.EXPECT().GetTransaction(gomock.Any(), int64(7)).Return(domain.Transaction{
+					ID: 7, WalletID: "123e4567-e89b-12d3-a456-426614174000", OperationType: domain.WITHDRAW, Amount: -500, Balance: 1000,
+					CreatedAt: time.Date(2025, 4, 1, 10, 0, 0, 0, time.UTC),
+				}, nil)
+			},
+			wantCode: http.StatusOK,
+			mockErr:  `{"id":7,"wallet_id":"123e4567-e89b-12d3-a456-426614174000","operation_type":"WITHDRAW","amount":-500,"balance":1000,"created_at":"2025-04-01T10:00:00Z"}`,
+		},
+		{
+			name:          "transaction not found",
+			transactionID: "7",
+			mockServ: func() {
+				mockWallet.EXPECT().GetTransaction(gomock.Any(), int64(7)).Return(domain.Transaction{}, appErrors.ErrTransactionNotFound)
+			},
+			wantCode: http.StatusNotFound,
+			mockErr:  `{"type":"urn:wallet:problem:TRANSACTION_NOT_FOUND","title":"Transaction not found","status":404,"code":"TRANSACTION_NOT_FOUND","detail":"transaction not found","instance":"/api/v1/transactions/7"}`,
+		},
+		{
+			name:          "invalid transaction ID",
+			transactionID: "abc",
+			mockServ:      func() {},
+			wantCode:      http.StatusBadRequest,
+			mockErr:       `{"type":"urn:wallet:problem:VALIDATION_FAILED","title":"Validation failed","status":400,"code":"VALIDATION_FAILED","detail":"Invalid transaction ID","instance":"/api/v1/transactions/abc","errors":[{"field":"transactionId","message":"Invalid transaction ID"}]}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/transactions/"+tc.transactionID, nil)
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("transactionId", tc.transactionID)
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+			tc.mockServ()
+
+			w := httptest.NewRecorder()
+			handler.GetTransactionHandler(w, req)
+
+			require.Equal(t, tc.wantCode, w.Code)
+			require.JSONEq(t, tc.mockErr, w.Body.String())
+		})
+	}
+}
+
+func TestListTransactionsHandler(t *testing.T) {
+	ctrl, mockWallet, handler := setupTestHandler(t)
+	defer ctrl.Finish()
+
+	walletID := "123e4567-e89b-12d3-a456-426614174000"
+	from := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name     string
+		query    string
+		mockServ func()
+		wantCode int
+		mockErr  string
+	}{
+		{
+			name:  "by reference and tags",
+			query: "?reference=invoice-17&tag=rent&tag=april&from=2025-04-01T00:00:00Z&limit=1",
+			mockServ: func() {
+				mockWallet.EXPECT().SearchTransactions(gomock.Any(), domain.TransactionFilter{
+					WalletID:  walletID,
+					Reference: "invoice-17",
+					Tags:      []string{"rent", "april"},
+					From:      &from,
+					Limit:     1,
+				}).Return(domain.TransactionHistory{
+					Transactions: []domain.Transaction{{
+						ID: 9, WalletID: walletID, OperationType: domain.DEPOSIT, Amount: 1000, Balance: 1000,
+						Reference: "invoice-17", Description: "April rent", Metadata: map[string]any{"unit": float64(4)},
+						Tags: []string{"rent", "april"}, CreatedAt: time.Date(2025, 4, 2, 9, 0, 0, 0, time.UTC),
+					}},
+					NextBefore: 9,
+				}, nil)
+			},
+			wantCode: http.StatusOK,
+			mockErr:  `{"transactions":[{"id":9,"wallet_id":"123e4567-e89b-12d3-a456-426614174000","operation_type":"DEPOSIT","amount":1000,"balance":1000,"reference":"invoice-17","description":"April rent","metadata":{"unit":4},"tags":["rent","april"],"created_at":"2025-04-02T09:00:00Z"}],"next_before":9}`,
+		},
+		{
+			name:  "default limit",
+			query: "?before=9",
+			mockServ: func() {
+				mockWallet.EXPECT().SearchTransactions(gomock.Any(), domain.TransactionFilter{WalletID: walletID, Before: 9, Limit: 50}).
+					Return(domain.TransactionHistory{Transactions: []domain.Transaction{}}, nil)
+			},
+			wantCode: http.StatusOK,
+			mockErr:  `{"transactions":[]}`,
+		},
+		{
+			name:     "invalid limit",
+			query:    "?limit=501",
+			mockServ: func() {},
+			wantCode: http.StatusBadRequest,
+			mockErr:  `{"type":"urn:wallet:problem:VALIDATION_FAILED","title":"Validation failed","status":400,"code":"VALIDATION_FAILED","detail":"limit must be between 1 and 500","instance":"/api/v1/wallets/123e4567-e89b-12d3-a456-426614174000/transactions","errors":[{"field":"limit","message":"limit must be between 1 and 500"}]}`,
+		},
+		{
+			name: "wallet not found",
+			mockServ: func() {
+				mockWallet.EXPECT().SearchTransactions(gomock.Any(), domain.TransactionFilter{WalletID: walletID, Limit: 50}).
+					Return(domain.TransactionHistory{}, appErrors.ErrWalletNotFound)
+			},
+			wantCode: http.StatusNotFound,
+			mockErr:  `{"type":"urn:wallet:problem:WALLET_NOT_FOUND","title":"Wallet not found","status":404,"code":"WALLET_NOT_FOUND","detail":"wallet not found","instance":"/api/v1/wallets/123e4567-e89b-12d3-a456-426614174000/transactions"}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/wallets/"+walletID+"/transactions"+tc.query, nil)
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("walletId", walletID)
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+			tc.mockServ()
+
+			w := httptest.NewRecorder()
+			handler.ListTransactionsHandler(w, req)
+
+			require.Equal(t, tc.wantCode, w.Code)
+			require.JSONEq(t, tc.mockErr, w.Body.String())
+		})
+	}
+}
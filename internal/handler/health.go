@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/Te8va/wallet/internal/domain"
+)
+
+//go:generate mockgen -source=health.go -destination=mocks/health_mock.gen.go -package=mocks
+type Health interface {
+	Ready(ctx context.Context) domain.HealthReport
+}
+
+type HealthHandler struct {
+	srv Health
+}
+
+func NewHealthHandler(srv Health) *HealthHandler {
+	return &HealthHandler{srv: srv}
+}
+
+// LivenessHandler reports that the process is up and serving requests. It
+// checks no dependencies, so a database outage does not get it restarted.
+func (h *HealthHandler) LivenessHandler(w http.ResponseWriter, r *http.Request) {
+	sendJSONResponse(w, domain.HealthReport{Status: domain.HealthOK}, http.StatusOK)
+}
+
+// ReadinessHandler reports whether the instance should receive traffic.
+// With ?verbose=true the response lists every check with its latency.
+func (h *HealthHandler) ReadinessHandler(w http.ResponseWriter, r *http.Request) {
+	report := h.srv.Ready(r.Context())
+
+	status := http.StatusOK
+	if report.Status != domain.HealthOK {
+		status = http.StatusServiceUnavailable
+	}
+
+	if verbose, _ := strconv.ParseBool(r.URL.Query().Get("verbose")); !verbose {
+		report.Checks = nil
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	sendJSONResponse(w, report, status)
+}
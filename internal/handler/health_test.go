@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Te8va/wallet/internal/domain"
+	"github.com/Te8va/wallet/internal/handler/mocks"
+)
+
+func TestReadinessHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockHealth := mocks.NewMockHealth(ctrl)
+	handler := NewHealthHandler(mockHealth)
+
+	ready := domain.HealthReport{Status: domain.HealthOK, Checks: []domain.HealthCheck{
+		{Name: "database", Status: domain.HealthOK, LatencyMs: 1.5},
+	}}
+	notReady := domain.HealthReport{Status: domain.HealthFail, Checks: []domain.HealthCheck{
+		{Name: "shutdown", Status: domain.HealthFail, Error: "shutting down"},
+	}}
+
+	testCases := []struct {
+		name     string
+		query    string
+		report   domain.HealthReport
+		wantCode int
+		wantBody string
+	}{
+		{
+			name:     "ready",
+			report:   ready,
+			wantCode: http.StatusOK,
+			wantBody: `{"status":"ok"}`,
+		},
+		{
+			name:     "ready with details",
+			query:    "?verbose=true",
+			report:   ready,
+			wantCode: http.StatusOK,
+			wantBody: `{"status":"ok","checks":[{"name":"database","status":"ok","latency_ms":1.5}]}`,
+		},
+		{
+			name:     "not ready",
+			query:    "?verbose=1",
+			report:   notReady,
+			wantCode: http.StatusServiceUnavailable,
+			wantBody: `{"status":"fail","checks":[{"name":"shutdown","status":"fail","latency_ms":0,"error":"shutting down"}]}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockHealth.EXPECT().Ready(gomock.Any()).Return(tc.report)
+
+			w := httptest.NewRecorder()
+			handler.ReadinessHandler(w, httptest.NewRequest(http.MethodGet, "/readyz"+tc.query, nil))
+
+			require.Equal(t, tc.wantCode, w.Code)
+			require.JSONEq(t, tc.wantBody, w.Body.String())
+		})
+	}
+}
+
+func TestLivenessHandler(t *testing.T) {
+	handler := NewHealthHandler(nil)
+
+	w := httptest.NewRecorder()
+	handler.LivenessHandler(w, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.JSONEq(t, `{"status":"ok"}`, w.Body.String())
+}
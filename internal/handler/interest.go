@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/Te8va/wallet/internal/domain"
+	"github.com/Te8va/wallet/internal/problem"
+)
+
+//go:generate mockgen -source=interest.go -destination=mocks/interest_mock.gen.go -package=mocks
+type Interest interface {
+	SaveSavingsAccount(ctx context.Context, account domain.SavingsAccount) (domain.SavingsAccount, error)
+	GetSavingsAccount(ctx context.Context, walletID string) (domain.SavingsAccount, error)
+}
+
+type InterestHandler struct {
+	srv Interest
+}
+
+func NewInterestHandler(srv Interest) *InterestHandler {
+	return &InterestHandler{srv: srv}
+}
+
+func (h *InterestHandler) SaveSavingsHandler(w http.ResponseWriter, r *http.Request) {
+	walletID := chi.URLParam(r, "walletId")
+
+	if walletID == "" {
+		sendErrorResponse(w, r, problem.Invalid("walletId", "Wallet ID is required"))
+		return
+	}
+
+	var req domain.SavingsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		sendErrorResponse(w, r, problem.New(problem.CodeMalformedRequest, "Invalid request body"))
+		return
+	}
+
+	if req.AnnualRateBps < 0 {
+		sendErrorResponse(w, r, problem.Invalid("annualRateBps", "Annual rate must not be negative"))
+		return
+	}
+
+	if req.DayCount == "" {
+		req.DayCount = domain.ACT365
+	}
+
+	if req.DayCount != domain.ACT365 && req.DayCount != domain.ACT360 && req.DayCount != domain.ACTACT {
+		sendErrorResponse(w, r, problem.Invalid("dayCount", "Day count must be ACT/365, ACT/360 or ACT/ACT"))
+		return
+	}
+
+	account, err := h.srv.SaveSavingsAccount(r.Context(), domain.SavingsAccount{
+		WalletID:      walletID,
+		AnnualRateBps: req.AnnualRateBps,
+		DayCount:      req.DayCount,
+	})
+	if err != nil {
+		sendError(w, r, err)
+		return
+	}
+
+	sendJSONResponse(w, account, http.StatusOK)
+}
+
+func (h *InterestHandler) GetSavingsHandler(w http.ResponseWriter, r *http.Request) {
+	walletID := chi.URLParam(r, "walletId")
+
+	if walletID == "" {
+		sendErrorResponse(w, r, problem.Invalid("walletId", "Wallet ID is required"))
+		return
+	}
+
+	account, err := h.srv.GetSavingsAccount(r.Context(), walletID)
+	if err != nil {
+		sendError(w, r, err)
+		return
+	}
+
+	sendJSONResponse(w, account, http.StatusOK)
+}
@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Te8va/wallet/internal/domain"
+	appErrors "github.com/Te8va/wallet/internal/errors"
+	"github.com/Te8va/wallet/internal/handler/mocks"
+)
+
+func TestSaveSavingsHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockInterest := mocks.NewMockInterest(ctrl)
+	handler := NewInterestHandler(mockInterest)
+
+	walletID := "123e4567-e89b-12d3-a456-426614174000"
+
+	testCases := []struct {
+		name     string
+		body     string
+		mockServ func()
+		wantCode int
+		wantBody string
+	}{
+		{
+			name: "successful with default day count",
+			body: `{"annualRateBps":525}`,
+			mockServ: func() {
+				mockInterest.EXPECT().SaveSavingsAccount(gomock.Any(), domain.SavingsAccount{
+					WalletID: walletID, AnnualRateBps: 525, DayCount: domain.ACT365,
+				}).Return(domain.SavingsAccount{WalletID: walletID, AnnualRateBps: 525, DayCount: domain.ACT365}, nil)
+			},
+			wantCode: http.StatusOK,
+			wantBody: `{"wallet_id":"123e4567-e89b-12d3-a456-426614174000","annual_rate_bps":525,"day_count":"ACT/365","accrued_nano":0}`,
+		},
+		{
+			name:     "negative rate",
+			body:     `{"annualRateBps":-1}`,
+			mockServ: func() {},
+			wantCode: http.StatusBadRequest,
+			wantBody: `{"type":"urn:wallet:problem:VALIDATION_FAILED","title":"Validation failed","status":400,"code":"VALIDATION_FAILED","detail":"Annual rate must not be negative","instance":"/api/v1/wallets/123e4567-e89b-12d3-a456-426614174000/savings","errors":[{"field":"annualRateBps","message":"Annual rate must not be negative"}]}`,
+		},
+		{
+			name:     "unknown day count",
+			body:     `{"annualRateBps":525,"dayCount":"30/360"}`,
+			mockServ: func() {},
+			wantCode: http.StatusBadRequest,
+			wantBody: `{"type":"urn:wallet:problem:VALIDATION_FAILED","title":"Validation failed","status":400,"code":"VALIDATION_FAILED","detail":"Day count must be ACT/365, ACT/360 or ACT/ACT","instance":"/api/v1/wallets/123e4567-e89b-12d3-a456-426614174000/savings","errors":[{"field":"dayCount","message":"Day count must be ACT/365, ACT/360 or ACT/ACT"}]}`,
+		},
+		{
+			name: "wallet not found",
+			body: `{"annualRateBps":525,"dayCount":"ACT/360"}`,
+			mockServ: func() {
+				mockInterest.EXPECT().SaveSavingsAccount(gomock.Any(), gomock.Any()).Return(domain.SavingsAccount{}, appErrors.ErrWalletNotFound)
+			},
+			wantCode: http.StatusNotFound,
+			wantBody: `{"type":"urn:wallet:problem:WALLET_NOT_FOUND","title":"Wallet not found","status":404,"code":"WALLET_NOT_FOUND","detail":"wallet not found","instance":"/api/v1/wallets/123e4567-e89b-12d3-a456-426614174000/savings"}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPut, "/api/v1/wallets/"+walletID+"/savings", bytes.NewReader([]byte(tc.body)))
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("walletId", walletID)
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+			tc.mockServ()
+
+			w := httptest.NewRecorder()
+			handler.SaveSavingsHandler(w, req)
+
+			require.Equal(t, tc.wantCode, w.Code)
+			require.JSONEq(t, tc.wantBody, w.Body.String())
+		})
+	}
+}
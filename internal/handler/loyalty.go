@@ -0,0 +1,138 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/Te8va/wallet/internal/domain"
+	"github.com/Te8va/wallet/internal/problem"
+)
+
+const (
+	maxLoyaltyRuleNameLength = 128
+	maxLoyaltyRuleTagLength  = 64
+)
+
+//go:generate mockgen -source=loyalty.go -destination=mocks/loyalty_mock.gen.go -package=mocks
+type Loyalty interface {
+	CreateRule(ctx context.Context, params domain.LoyaltyRuleParams) (domain.LoyaltyRule, error)
+	ListRules(ctx context.Context) ([]domain.LoyaltyRule, error)
+	GetPoints(ctx context.Context, walletID string) (domain.PointsBalance, error)
+	RedeemPoints(ctx context.Context, walletID string, req domain.PointsRedeemRequest) (domain.PointsRedemptionResult, error)
+}
+
+type LoyaltyHandler struct {
+	srv Loyalty
+}
+
+func NewLoyaltyHandler(srv Loyalty) *LoyaltyHandler {
+	return &LoyaltyHandler{srv: srv}
+}
+
+func (h *LoyaltyHandler) CreateRuleHandler(w http.ResponseWriter, r *http.Request) {
+	var params domain.LoyaltyRuleParams
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		sendErrorResponse(w, r, problem.New(problem.CodeMalformedRequest, "Invalid request body"))
+		return
+	}
+
+	switch {
+	case params.Name == "":
+		sendErrorResponse(w, r, problem.Invalid("name", "Name is required"))
+		return
+	case len(params.Name) > maxLoyaltyRuleNameLength:
+		sendErrorResponse(w, r, problem.Invalid("name", "Name must be at most 128 characters"))
+		return
+	case params.RateBps <= 0 || params.RateBps > 10000:
+		sendErrorResponse(w, r, problem.Invalid("rateBps", "rateBps must be between 1 and 10000"))
+		return
+	case params.MinAmount < 0:
+		sendErrorResponse(w, r, problem.Invalid("minAmount", "minAmount must not be negative"))
+		return
+	case len(params.Tag) > maxLoyaltyRuleTagLength:
+		sendErrorResponse(w, r, problem.Invalid("tag", "Tag must be at most 64 characters"))
+		return
+	}
+
+	rule, err := h.srv.CreateRule(r.Context(), params)
+	if err != nil {
+		sendError(w, r, err)
+		return
+	}
+
+	sendJSONResponse(w, rule, http.StatusCreated)
+}
+
+func (h *LoyaltyHandler) ListRulesHandler(w http.ResponseWriter, r *http.Request) {
+	rules, err := h.srv.ListRules(r.Context())
+	if err != nil {
+		sendError(w, r, err)
+		return
+	}
+
+	if rules == nil {
+		rules = []domain.LoyaltyRule{}
+	}
+
+	sendJSONResponse(w, rules, http.StatusOK)
+}
+
+// GetPointsHandler returns the valid points of the wallet with the lots
+// they expire in.
+func (h *LoyaltyHandler) GetPointsHandler(w http.ResponseWriter, r *http.Request) {
+	walletID := chi.URLParam(r, "walletId")
+
+	if walletID == "" {
+		sendErrorResponse(w, r, problem.Invalid("walletId", "Wallet ID is required"))
+		return
+	}
+
+	balance, err := h.srv.GetPoints(r.Context(), walletID)
+	if err != nil {
+		sendError(w, r, err)
+		return
+	}
+
+	sendJSONResponse(w, balance, http.StatusOK)
+}
+
+func (h *LoyaltyHandler) RedeemPointsHandler(w http.ResponseWriter, r *http.Request) {
+	walletID := chi.URLParam(r, "walletId")
+
+	if walletID == "" {
+		sendErrorResponse(w, r, problem.Invalid("walletId", "Wallet ID is required"))
+		return
+	}
+
+	var req domain.PointsRedeemRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		sendErrorResponse(w, r, problem.New(problem.CodeMalformedRequest, "Invalid request body"))
+		return
+	}
+
+	switch {
+	case req.Points <= 0:
+		sendErrorResponse(w, r, problem.Invalid("points", "Points must be more than 0"))
+		return
+	case len(req.IdempotencyKey) > maxIdempotencyKeyLength:
+		sendErrorResponse(w, r, problem.Invalid("idempotencyKey", "Idempotency key must be at most 128 characters"))
+		return
+	}
+
+	res, err := h.srv.RedeemPoints(r.Context(), walletID, req)
+	if err != nil {
+		sendError(w, r, err)
+		return
+	}
+
+	status := http.StatusCreated
+	if res.Replayed {
+		w.Header().Set(IdempotentReplayedHeader, "true")
+		status = http.StatusOK
+	}
+
+	sendJSONResponse(w, res, status)
+}
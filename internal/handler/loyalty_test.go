@@ -0,0 +1,193 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Te8va/wallet/internal/domain"
+	appErrors "github.com/Te8va/wallet/internal/errors"
+	"github.com/Te8va/wallet/internal/handler/mocks"
+)
+
+func TestCreateRuleHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockLoyalty := mocks.NewMockLoyalty(ctrl)
+	handler := NewLoyaltyHandler(mockLoyalty)
+
+	startsAt := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+	params := domain.LoyaltyRuleParams{Name: "groceries", RateBps: 10000, Tag: "groceries", StartsAt: startsAt}
+
+	testCases := []struct {
+		name     string
+		body     string
+		mockServ func()
+		wantCode int
+		wantBody string
+	}{
+		{
+			name: "successful",
+			body: `{"name":"groceries","rateBps":10000,"tag":"groceries","startsAt":"2025-04-01T00:00:00Z"}`,
+			mockServ: func() {
+				mockLoyalty.EXPECT().CreateRule(gomock.Any(), params).Return(domain.LoyaltyRule{
+					ID: 1, Name: "groceries", RateBps: 10000, Tag: "groceries", StartsAt: startsAt, CreatedAt: startsAt,
+				}, nil)
+			},
+			wantCode: http.StatusCreated,
+			wantBody: `{"id":1,"name":"groceries","rate_bps":10000,"min_amount":0,"tag":"groceries","starts_at":"2025-04-01T00:00:00Z","created_at":"2025-04-01T00:00:00Z"}`,
+		},
+		{
+			name:     "missing rate",
+			body:     `{"name":"groceries"}`,
+			mockServ: func() {},
+			wantCode: http.StatusBadRequest,
+			wantBody: `{"type":"urn:wallet:problem:VALIDATION_FAILED","title":"Validation failed","status":400,"code":"VALIDATION_FAILED","detail":"rateBps must be between 1 and 10000","instance":"/api/v1/loyalty-rules","errors":[{"field":"rateBps","message":"rateBps must be between 1 and 10000"}]}`,
+		},
+		{
+			name: "ends before it starts",
+			body: `{"name":"groceries","rateBps":10000,"tag":"groceries","startsAt":"2025-04-01T00:00:00Z"}`,
+			mockServ: func() {
+				mockLoyalty.EXPECT().CreateRule(gomock.Any(), params).Return(domain.LoyaltyRule{}, appErrors.ErrInvalidLoyaltyRule)
+			},
+			wantCode: http.StatusBadRequest,
+			wantBody: `{"type":"urn:wallet:problem:INVALID_LOYALTY_RULE","title":"Invalid loyalty rule","status":400,"code":"INVALID_LOYALTY_RULE","detail":"loyalty rule must end after it starts","instance":"/api/v1/loyalty-rules"}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/loyalty-rules", bytes.NewBufferString(tc.body))
+
+			tc.mockServ()
+
+			w := httptest.NewRecorder()
+			handler.CreateRuleHandler(w, req)
+
+			require.Equal(t, tc.wantCode, w.Code)
+			require.JSONEq(t, tc.wantBody, w.Body.String())
+		})
+	}
+}
+
+func TestRedeemPointsHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockLoyalty := mocks.NewMockLoyalty(ctrl)
+	handler := NewLoyaltyHandler(mockLoyalty)
+
+	walletID := "123e4567-e89b-12d3-a456-426614174000"
+	createdAt := time.Date(2025, 4, 1, 10, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name       string
+		body       string
+		mockServ   func()
+		wantCode   int
+		wantBody   string
+		wantHeader string
+	}{
+		{
+			name: "successful",
+			body: `{"points":2500}`,
+			mockServ: func() {
+				mockLoyalty.EXPECT().RedeemPoints(gomock.Any(), walletID, domain.PointsRedeemRequest{Points: 2500}).
+					Return(domain.PointsRedemptionResult{
+						Points: 2500,
+						Amount: 25,
+						Debit: domain.Transaction{
+							ID: 20, WalletID: "points", OperationType: domain.POINTS_REDEEM, Amount: -2500, Balance: 500,
+							Reference: "points-redeem:" + walletID, CreatedAt: createdAt,
+						},
+						Credit: domain.Transaction{
+							ID: 22, WalletID: walletID, OperationType: domain.POINTS_PAYOUT, Amount: 25, Balance: 125,
+							ParentID: 21, Reference: "points-redeem:" + walletID, CreatedAt: createdAt,
+						},
+					}, nil)
+			},
+			wantCode: http.StatusCreated,
+			wantBody: `{"points":2500,"amount":25,` +
+				`"debit":{"id":20,"wallet_id":"points","operation_type":"POINTS_REDEEM","amount":-2500,"balance":500,"reference":"points-redeem:123e4567-e89b-12d3-a456-426614174000","created_at":"2025-04-01T10:00:00Z"},` +
+				`"credit":{"id":22,"wallet_id":"123e4567-e89b-12d3-a456-426614174000","operation_type":"POINTS_PAYOUT","amount":25,"balance":125,"parent_id":21,"reference":"points-redeem:123e4567-e89b-12d3-a456-426614174000","created_at":"2025-04-01T10:00:00Z"}}`,
+		},
+		{
+			name: "repeated idempotency key",
+			body: `{"points":2500,"idempotencyKey":"redeem-1"}`,
+			mockServ: func() {
+				mockLoyalty.EXPECT().RedeemPoints(gomock.Any(), walletID, domain.PointsRedeemRequest{Points: 2500, IdempotencyKey: "redeem-1"}).
+					Return(domain.PointsRedemptionResult{
+						Points: 2500,
+						Amount: 25,
+						Debit: domain.Transaction{
+							ID: 20, WalletID: "points", OperationType: domain.POINTS_REDEEM, Amount: -2500, Balance: 500,
+							IdempotencyKey: "redeem-1", Reference: "points-redeem:" + walletID, CreatedAt: createdAt,
+						},
+						Credit: domain.Transaction{
+							ID: 22, WalletID: walletID, OperationType: domain.POINTS_PAYOUT, Amount: 25, Balance: 125,
+							ParentID: 21, Reference: "points-redeem:" + walletID, CreatedAt: createdAt,
+						},
+						Replayed: true,
+					}, nil)
+			},
+			wantCode: http.StatusOK,
+			wantBody: `{"points":2500,"amount":25,` +
+				`"debit":{"id":20,"wallet_id":"points","operation_type":"POINTS_REDEEM","amount":-2500,"balance":500,"idempotency_key":"redeem-1","reference":"points-redeem:123e4567-e89b-12d3-a456-426614174000","created_at":"2025-04-01T10:00:00Z"},` +
+				`"credit":{"id":22,"wallet_id":"123e4567-e89b-12d3-a456-426614174000","operation_type":"POINTS_PAYOUT","amount":25,"balance":125,"parent_id":21,"reference":"points-redeem:123e4567-e89b-12d3-a456-426614174000","created_at":"2025-04-01T10:00:00Z"}}`,
+			wantHeader: "true",
+		},
+		{
+			name:     "zero points",
+			body:     `{"points":0}`,
+			mockServ: func() {},
+			wantCode: http.StatusBadRequest,
+			wantBody: `{"type":"urn:wallet:problem:VALIDATION_FAILED","title":"Validation failed","status":400,"code":"VALIDATION_FAILED","detail":"Points must be more than 0","instance":"/api/v1/wallets/123e4567-e89b-12d3-a456-426614174000/points/redeem","errors":[{"field":"points","message":"Points must be more than 0"}]}`,
+		},
+		{
+			name: "not a multiple of the rate",
+			body: `{"points":150}`,
+			mockServ: func() {
+				mockLoyalty.EXPECT().RedeemPoints(gomock.Any(), walletID, domain.PointsRedeemRequest{Points: 150}).
+					Return(domain.PointsRedemptionResult{}, appErrors.ErrInvalidPointsAmount)
+			},
+			wantCode: http.StatusBadRequest,
+			wantBody: `{"type":"urn:wallet:problem:INVALID_POINTS_AMOUNT","title":"Invalid points amount","status":400,"code":"INVALID_POINTS_AMOUNT","detail":"points must be a positive multiple of the conversion rate","instance":"/api/v1/wallets/123e4567-e89b-12d3-a456-426614174000/points/redeem"}`,
+		},
+		{
+			name: "redemption disabled",
+			body: `{"points":100}`,
+			mockServ: func() {
+				mockLoyalty.EXPECT().RedeemPoints(gomock.Any(), walletID, domain.PointsRedeemRequest{Points: 100}).
+					Return(domain.PointsRedemptionResult{}, appErrors.ErrRedemptionDisabled)
+			},
+			wantCode: http.StatusServiceUnavailable,
+			wantBody: `{"type":"urn:wallet:problem:REDEMPTION_DISABLED","title":"Redemption disabled","status":503,"code":"REDEMPTION_DISABLED","detail":"points redemption is not configured","instance":"/api/v1/wallets/123e4567-e89b-12d3-a456-426614174000/points/redeem"}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/wallets/"+walletID+"/points/redeem", bytes.NewBufferString(tc.body))
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("walletId", walletID)
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+			tc.mockServ()
+
+			w := httptest.NewRecorder()
+			handler.RedeemPointsHandler(w, req)
+
+			require.Equal(t, tc.wantCode, w.Code)
+			require.JSONEq(t, tc.wantBody, w.Body.String())
+			require.Equal(t, tc.wantHeader, w.Header().Get(IdempotentReplayedHeader))
+		})
+	}
+}
@@ -0,0 +1,67 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: batch.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+
+	domain "github.com/Te8va/wallet/internal/domain"
+)
+
+// MockBatcher is a mock of Batcher interface.
+type MockBatcher struct {
+	ctrl     *gomock.Controller
+	recorder *MockBatcherMockRecorder
+}
+
+// MockBatcherMockRecorder is the mock recorder for MockBatcher.
+type MockBatcherMockRecorder struct {
+	mock *MockBatcher
+}
+
+// NewMockBatcher creates a new mock instance.
+func NewMockBatcher(ctrl *gomock.Controller) *MockBatcher {
+	mock := &MockBatcher{ctrl: ctrl}
+	mock.recorder = &MockBatcherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBatcher) EXPECT() *MockBatcherMockRecorder {
+	return m.recorder
+}
+
+// GetBatch mocks base method.
+func (m *MockBatcher) GetBatch(ctx context.Context, id int64) (domain.Batch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBatch", ctx, id)
+	ret0, _ := ret[0].(domain.Batch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBatch indicates an expected call of GetBatch.
+func (mr *MockBatcherMockRecorder) GetBatch(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBatch", reflect.TypeOf((*MockBatcher)(nil).GetBatch), ctx, id)
+}
+
+// SubmitBatch mocks base method.
+func (m *MockBatcher) SubmitBatch(ctx context.Context, mode domain.BatchMode, items []domain.BatchItem) (domain.Batch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubmitBatch", ctx, mode, items)
+	ret0, _ := ret[0].(domain.Batch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SubmitBatch indicates an expected call of SubmitBatch.
+func (mr *MockBatcherMockRecorder) SubmitBatch(ctx, mode, items interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubmitBatch", reflect.TypeOf((*MockBatcher)(nil).SubmitBatch), ctx, mode, items)
+}
@@ -0,0 +1,82 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: campaign.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+
+	domain "github.com/Te8va/wallet/internal/domain"
+)
+
+// MockCampaign is a mock of Campaign interface.
+type MockCampaign struct {
+	ctrl     *gomock.Controller
+	recorder *MockCampaignMockRecorder
+}
+
+// MockCampaignMockRecorder is the mock recorder for MockCampaign.
+type MockCampaignMockRecorder struct {
+	mock *MockCampaign
+}
+
+// NewMockCampaign creates a new mock instance.
+func NewMockCampaign(ctrl *gomock.Controller) *MockCampaign {
+	mock := &MockCampaign{ctrl: ctrl}
+	mock.recorder = &MockCampaignMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCampaign) EXPECT() *MockCampaignMockRecorder {
+	return m.recorder
+}
+
+// CreateCampaign mocks base method.
+func (m *MockCampaign) CreateCampaign(ctx context.Context, params domain.CampaignParams) (domain.Campaign, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCampaign", ctx, params)
+	ret0, _ := ret[0].(domain.Campaign)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCampaign indicates an expected call of CreateCampaign.
+func (mr *MockCampaignMockRecorder) CreateCampaign(ctx, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCampaign", reflect.TypeOf((*MockCampaign)(nil).CreateCampaign), ctx, params)
+}
+
+// GetCampaign mocks base method.
+func (m *MockCampaign) GetCampaign(ctx context.Context, id int64) (domain.Campaign, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCampaign", ctx, id)
+	ret0, _ := ret[0].(domain.Campaign)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCampaign indicates an expected call of GetCampaign.
+func (mr *MockCampaignMockRecorder) GetCampaign(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCampaign", reflect.TypeOf((*MockCampaign)(nil).GetCampaign), ctx, id)
+}
+
+// ListCampaigns mocks base method.
+func (m *MockCampaign) ListCampaigns(ctx context.Context) ([]domain.Campaign, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCampaigns", ctx)
+	ret0, _ := ret[0].([]domain.Campaign)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCampaigns indicates an expected call of ListCampaigns.
+func (mr *MockCampaignMockRecorder) ListCampaigns(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCampaigns", reflect.TypeOf((*MockCampaign)(nil).ListCampaigns), ctx)
+}
@@ -0,0 +1,127 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: escrow.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+
+	domain "github.com/Te8va/wallet/internal/domain"
+)
+
+// MockEscrow is a mock of Escrow interface.
+type MockEscrow struct {
+	ctrl     *gomock.Controller
+	recorder *MockEscrowMockRecorder
+}
+
+// MockEscrowMockRecorder is the mock recorder for MockEscrow.
+type MockEscrowMockRecorder struct {
+	mock *MockEscrow
+}
+
+// NewMockEscrow creates a new mock instance.
+func NewMockEscrow(ctrl *gomock.Controller) *MockEscrow {
+	mock := &MockEscrow{ctrl: ctrl}
+	mock.recorder = &MockEscrowMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEscrow) EXPECT() *MockEscrowMockRecorder {
+	return m.recorder
+}
+
+// CreateEscrow mocks base method.
+func (m *MockEscrow) CreateEscrow(ctx context.Context, req domain.EscrowRequest) (domain.Escrow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateEscrow", ctx, req)
+	ret0, _ := ret[0].(domain.Escrow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateEscrow indicates an expected call of CreateEscrow.
+func (mr *MockEscrowMockRecorder) CreateEscrow(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEscrow", reflect.TypeOf((*MockEscrow)(nil).CreateEscrow), ctx, req)
+}
+
+// DisputeEscrow mocks base method.
+func (m *MockEscrow) DisputeEscrow(ctx context.Context, dealID, reason string) (domain.Escrow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisputeEscrow", ctx, dealID, reason)
+	ret0, _ := ret[0].(domain.Escrow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DisputeEscrow indicates an expected call of DisputeEscrow.
+func (mr *MockEscrowMockRecorder) DisputeEscrow(ctx, dealID, reason interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisputeEscrow", reflect.TypeOf((*MockEscrow)(nil).DisputeEscrow), ctx, dealID, reason)
+}
+
+// GetEscrow mocks base method.
+func (m *MockEscrow) GetEscrow(ctx context.Context, dealID string) (domain.Escrow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEscrow", ctx, dealID)
+	ret0, _ := ret[0].(domain.Escrow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEscrow indicates an expected call of GetEscrow.
+func (mr *MockEscrowMockRecorder) GetEscrow(ctx, dealID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEscrow", reflect.TypeOf((*MockEscrow)(nil).GetEscrow), ctx, dealID)
+}
+
+// RefundEscrow mocks base method.
+func (m *MockEscrow) RefundEscrow(ctx context.Context, dealID, reason string) (domain.Escrow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefundEscrow", ctx, dealID, reason)
+	ret0, _ := ret[0].(domain.Escrow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RefundEscrow indicates an expected call of RefundEscrow.
+func (mr *MockEscrowMockRecorder) RefundEscrow(ctx, dealID, reason interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefundEscrow", reflect.TypeOf((*MockEscrow)(nil).RefundEscrow), ctx, dealID, reason)
+}
+
+// ReleaseEscrow mocks base method.
+func (m *MockEscrow) ReleaseEscrow(ctx context.Context, dealID, reason string) (domain.Escrow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseEscrow", ctx, dealID, reason)
+	ret0, _ := ret[0].(domain.Escrow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReleaseEscrow indicates an expected call of ReleaseEscrow.
+func (mr *MockEscrowMockRecorder) ReleaseEscrow(ctx, dealID, reason interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseEscrow", reflect.TypeOf((*MockEscrow)(nil).ReleaseEscrow), ctx, dealID, reason)
+}
+
+// ResolveEscrow mocks base method.
+func (m *MockEscrow) ResolveEscrow(ctx context.Context, dealID string, sellerAmount int64, reason string) (domain.Escrow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolveEscrow", ctx, dealID, sellerAmount, reason)
+	ret0, _ := ret[0].(domain.Escrow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResolveEscrow indicates an expected call of ResolveEscrow.
+func (mr *MockEscrowMockRecorder) ResolveEscrow(ctx, dealID, sellerAmount, reason interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveEscrow", reflect.TypeOf((*MockEscrow)(nil).ResolveEscrow), ctx, dealID, sellerAmount, reason)
+}
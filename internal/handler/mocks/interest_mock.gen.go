@@ -0,0 +1,67 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: interest.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+
+	domain "github.com/Te8va/wallet/internal/domain"
+)
+
+// MockInterest is a mock of Interest interface.
+type MockInterest struct {
+	ctrl     *gomock.Controller
+	recorder *MockInterestMockRecorder
+}
+
+// MockInterestMockRecorder is the mock recorder for MockInterest.
+type MockInterestMockRecorder struct {
+	mock *MockInterest
+}
+
+// NewMockInterest creates a new mock instance.
+func NewMockInterest(ctrl *gomock.Controller) *MockInterest {
+	mock := &MockInterest{ctrl: ctrl}
+	mock.recorder = &MockInterestMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockInterest) EXPECT() *MockInterestMockRecorder {
+	return m.recorder
+}
+
+// GetSavingsAccount mocks base method.
+func (m *MockInterest) GetSavingsAccount(ctx context.Context, walletID string) (domain.SavingsAccount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSavingsAccount", ctx, walletID)
+	ret0, _ := ret[0].(domain.SavingsAccount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSavingsAccount indicates an expected call of GetSavingsAccount.
+func (mr *MockInterestMockRecorder) GetSavingsAccount(ctx, walletID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSavingsAccount", reflect.TypeOf((*MockInterest)(nil).GetSavingsAccount), ctx, walletID)
+}
+
+// SaveSavingsAccount mocks base method.
+func (m *MockInterest) SaveSavingsAccount(ctx context.Context, account domain.SavingsAccount) (domain.SavingsAccount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveSavingsAccount", ctx, account)
+	ret0, _ := ret[0].(domain.SavingsAccount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SaveSavingsAccount indicates an expected call of SaveSavingsAccount.
+func (mr *MockInterestMockRecorder) SaveSavingsAccount(ctx, account interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveSavingsAccount", reflect.TypeOf((*MockInterest)(nil).SaveSavingsAccount), ctx, account)
+}
@@ -0,0 +1,97 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: loyalty.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+
+	domain "github.com/Te8va/wallet/internal/domain"
+)
+
+// MockLoyalty is a mock of Loyalty interface.
+type MockLoyalty struct {
+	ctrl     *gomock.Controller
+	recorder *MockLoyaltyMockRecorder
+}
+
+// MockLoyaltyMockRecorder is the mock recorder for MockLoyalty.
+type MockLoyaltyMockRecorder struct {
+	mock *MockLoyalty
+}
+
+// NewMockLoyalty creates a new mock instance.
+func NewMockLoyalty(ctrl *gomock.Controller) *MockLoyalty {
+	mock := &MockLoyalty{ctrl: ctrl}
+	mock.recorder = &MockLoyaltyMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLoyalty) EXPECT() *MockLoyaltyMockRecorder {
+	return m.recorder
+}
+
+// CreateRule mocks base method.
+func (m *MockLoyalty) CreateRule(ctx context.Context, params domain.LoyaltyRuleParams) (domain.LoyaltyRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRule", ctx, params)
+	ret0, _ := ret[0].(domain.LoyaltyRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateRule indicates an expected call of CreateRule.
+func (mr *MockLoyaltyMockRecorder) CreateRule(ctx, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRule", reflect.TypeOf((*MockLoyalty)(nil).CreateRule), ctx, params)
+}
+
+// GetPoints mocks base method.
+func (m *MockLoyalty) GetPoints(ctx context.Context, walletID string) (domain.PointsBalance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPoints", ctx, walletID)
+	ret0, _ := ret[0].(domain.PointsBalance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPoints indicates an expected call of GetPoints.
+func (mr *MockLoyaltyMockRecorder) GetPoints(ctx, walletID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPoints", reflect.TypeOf((*MockLoyalty)(nil).GetPoints), ctx, walletID)
+}
+
+// ListRules mocks base method.
+func (m *MockLoyalty) ListRules(ctx context.Context) ([]domain.LoyaltyRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRules", ctx)
+	ret0, _ := ret[0].([]domain.LoyaltyRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRules indicates an expected call of ListRules.
+func (mr *MockLoyaltyMockRecorder) ListRules(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRules", reflect.TypeOf((*MockLoyalty)(nil).ListRules), ctx)
+}
+
+// RedeemPoints mocks base method.
+func (m *MockLoyalty) RedeemPoints(ctx context.Context, walletID string, req domain.PointsRedeemRequest) (domain.PointsRedemptionResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RedeemPoints", ctx, walletID, req)
+	ret0, _ := ret[0].(domain.PointsRedemptionResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RedeemPoints indicates an expected call of RedeemPoints.
+func (mr *MockLoyaltyMockRecorder) RedeemPoints(ctx, walletID, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RedeemPoints", reflect.TypeOf((*MockLoyalty)(nil).RedeemPoints), ctx, walletID, req)
+}
@@ -0,0 +1,112 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: paymentrequest.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+
+	domain "github.com/Te8va/wallet/internal/domain"
+)
+
+// MockPaymentRequest is a mock of PaymentRequest interface.
+type MockPaymentRequest struct {
+	ctrl     *gomock.Controller
+	recorder *MockPaymentRequestMockRecorder
+}
+
+// MockPaymentRequestMockRecorder is the mock recorder for MockPaymentRequest.
+type MockPaymentRequestMockRecorder struct {
+	mock *MockPaymentRequest
+}
+
+// NewMockPaymentRequest creates a new mock instance.
+func NewMockPaymentRequest(ctrl *gomock.Controller) *MockPaymentRequest {
+	mock := &MockPaymentRequest{ctrl: ctrl}
+	mock.recorder = &MockPaymentRequestMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPaymentRequest) EXPECT() *MockPaymentRequestMockRecorder {
+	return m.recorder
+}
+
+// AcceptPaymentRequest mocks base method.
+func (m *MockPaymentRequest) AcceptPaymentRequest(ctx context.Context, id int64) (domain.PaymentRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AcceptPaymentRequest", ctx, id)
+	ret0, _ := ret[0].(domain.PaymentRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AcceptPaymentRequest indicates an expected call of AcceptPaymentRequest.
+func (mr *MockPaymentRequestMockRecorder) AcceptPaymentRequest(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcceptPaymentRequest", reflect.TypeOf((*MockPaymentRequest)(nil).AcceptPaymentRequest), ctx, id)
+}
+
+// CreatePaymentRequest mocks base method.
+func (m *MockPaymentRequest) CreatePaymentRequest(ctx context.Context, params domain.PaymentRequestParams) (domain.PaymentRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePaymentRequest", ctx, params)
+	ret0, _ := ret[0].(domain.PaymentRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePaymentRequest indicates an expected call of CreatePaymentRequest.
+func (mr *MockPaymentRequestMockRecorder) CreatePaymentRequest(ctx, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePaymentRequest", reflect.TypeOf((*MockPaymentRequest)(nil).CreatePaymentRequest), ctx, params)
+}
+
+// DeclinePaymentRequest mocks base method.
+func (m *MockPaymentRequest) DeclinePaymentRequest(ctx context.Context, id int64) (domain.PaymentRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeclinePaymentRequest", ctx, id)
+	ret0, _ := ret[0].(domain.PaymentRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeclinePaymentRequest indicates an expected call of DeclinePaymentRequest.
+func (mr *MockPaymentRequestMockRecorder) DeclinePaymentRequest(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeclinePaymentRequest", reflect.TypeOf((*MockPaymentRequest)(nil).DeclinePaymentRequest), ctx, id)
+}
+
+// GetPaymentRequest mocks base method.
+func (m *MockPaymentRequest) GetPaymentRequest(ctx context.Context, id int64) (domain.PaymentRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPaymentRequest", ctx, id)
+	ret0, _ := ret[0].(domain.PaymentRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPaymentRequest indicates an expected call of GetPaymentRequest.
+func (mr *MockPaymentRequestMockRecorder) GetPaymentRequest(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPaymentRequest", reflect.TypeOf((*MockPaymentRequest)(nil).GetPaymentRequest), ctx, id)
+}
+
+// ListPaymentRequests mocks base method.
+func (m *MockPaymentRequest) ListPaymentRequests(ctx context.Context, filter domain.PaymentRequestFilter) ([]domain.PaymentRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPaymentRequests", ctx, filter)
+	ret0, _ := ret[0].([]domain.PaymentRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPaymentRequests indicates an expected call of ListPaymentRequests.
+func (mr *MockPaymentRequestMockRecorder) ListPaymentRequests(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPaymentRequests", reflect.TypeOf((*MockPaymentRequest)(nil).ListPaymentRequests), ctx, filter)
+}
@@ -0,0 +1,82 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: pocket.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+
+	domain "github.com/Te8va/wallet/internal/domain"
+)
+
+// MockPocket is a mock of Pocket interface.
+type MockPocket struct {
+	ctrl     *gomock.Controller
+	recorder *MockPocketMockRecorder
+}
+
+// MockPocketMockRecorder is the mock recorder for MockPocket.
+type MockPocketMockRecorder struct {
+	mock *MockPocket
+}
+
+// NewMockPocket creates a new mock instance.
+func NewMockPocket(ctrl *gomock.Controller) *MockPocket {
+	mock := &MockPocket{ctrl: ctrl}
+	mock.recorder = &MockPocketMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPocket) EXPECT() *MockPocketMockRecorder {
+	return m.recorder
+}
+
+// CreatePocket mocks base method.
+func (m *MockPocket) CreatePocket(ctx context.Context, walletID, name string) (domain.Pocket, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePocket", ctx, walletID, name)
+	ret0, _ := ret[0].(domain.Pocket)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePocket indicates an expected call of CreatePocket.
+func (mr *MockPocketMockRecorder) CreatePocket(ctx, walletID, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePocket", reflect.TypeOf((*MockPocket)(nil).CreatePocket), ctx, walletID, name)
+}
+
+// MovePocket mocks base method.
+func (m *MockPocket) MovePocket(ctx context.Context, move domain.PocketMove) (domain.PocketMove, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MovePocket", ctx, move)
+	ret0, _ := ret[0].(domain.PocketMove)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MovePocket indicates an expected call of MovePocket.
+func (mr *MockPocketMockRecorder) MovePocket(ctx, move interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MovePocket", reflect.TypeOf((*MockPocket)(nil).MovePocket), ctx, move)
+}
+
+// PocketBalances mocks base method.
+func (m *MockPocket) PocketBalances(ctx context.Context, walletID string) (domain.PocketBalances, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PocketBalances", ctx, walletID)
+	ret0, _ := ret[0].(domain.PocketBalances)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PocketBalances indicates an expected call of PocketBalances.
+func (mr *MockPocketMockRecorder) PocketBalances(ctx, walletID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PocketBalances", reflect.TypeOf((*MockPocket)(nil).PocketBalances), ctx, walletID)
+}
@@ -0,0 +1,53 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: statement.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+
+	domain "github.com/Te8va/wallet/internal/domain"
+)
+
+// MockStatementer is a mock of Statementer interface.
+type MockStatementer struct {
+	ctrl     *gomock.Controller
+	recorder *MockStatementerMockRecorder
+}
+
+// MockStatementerMockRecorder is the mock recorder for MockStatementer.
+type MockStatementerMockRecorder struct {
+	mock *MockStatementer
+}
+
+// NewMockStatementer creates a new mock instance.
+func NewMockStatementer(ctrl *gomock.Controller) *MockStatementer {
+	mock := &MockStatementer{ctrl: ctrl}
+	mock.recorder = &MockStatementerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStatementer) EXPECT() *MockStatementerMockRecorder {
+	return m.recorder
+}
+
+// GetStatement mocks base method.
+func (m *MockStatementer) GetStatement(ctx context.Context, walletID string, from, to time.Time) (domain.Statement, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStatement", ctx, walletID, from, to)
+	ret0, _ := ret[0].(domain.Statement)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStatement indicates an expected call of GetStatement.
+func (mr *MockStatementerMockRecorder) GetStatement(ctx, walletID, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStatement", reflect.TypeOf((*MockStatementer)(nil).GetStatement), ctx, walletID, from, to)
+}
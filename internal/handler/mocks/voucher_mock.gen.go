@@ -0,0 +1,52 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: voucher.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+
+	domain "github.com/Te8va/wallet/internal/domain"
+)
+
+// MockVoucher is a mock of Voucher interface.
+type MockVoucher struct {
+	ctrl     *gomock.Controller
+	recorder *MockVoucherMockRecorder
+}
+
+// MockVoucherMockRecorder is the mock recorder for MockVoucher.
+type MockVoucherMockRecorder struct {
+	mock *MockVoucher
+}
+
+// NewMockVoucher creates a new mock instance.
+func NewMockVoucher(ctrl *gomock.Controller) *MockVoucher {
+	mock := &MockVoucher{ctrl: ctrl}
+	mock.recorder = &MockVoucherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockVoucher) EXPECT() *MockVoucherMockRecorder {
+	return m.recorder
+}
+
+// RedeemVoucher mocks base method.
+func (m *MockVoucher) RedeemVoucher(ctx context.Context, req domain.VoucherRedeemRequest) (domain.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RedeemVoucher", ctx, req)
+	ret0, _ := ret[0].(domain.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RedeemVoucher indicates an expected call of RedeemVoucher.
+func (mr *MockVoucherMockRecorder) RedeemVoucher(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RedeemVoucher", reflect.TypeOf((*MockVoucher)(nil).RedeemVoucher), ctx, req)
+}
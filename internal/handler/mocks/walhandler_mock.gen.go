@@ -7,6 +7,7 @@ package mocks
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 
@@ -37,10 +38,10 @@ func (m *MockWallet) EXPECT() *MockWalletMockRecorder {
 }
 
 // GetBalance mocks base method.
-func (m *MockWallet) GetBalance(ctx context.Context, walletID string) (int64, error) {
+func (m *MockWallet) GetBalance(ctx context.Context, walletID string) (domain.PocketBalances, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetBalance", ctx, walletID)
-	ret0, _ := ret[0].(int64)
+	ret0, _ := ret[0].(domain.PocketBalances)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -51,16 +52,77 @@ func (mr *MockWalletMockRecorder) GetBalance(ctx, walletID interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBalance", reflect.TypeOf((*MockWallet)(nil).GetBalance), ctx, walletID)
 }
 
+// GetBalanceAsOf mocks base method.
+func (m *MockWallet) GetBalanceAsOf(ctx context.Context, walletID string, asOf time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBalanceAsOf", ctx, walletID, asOf)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBalanceAsOf indicates an expected call of GetBalanceAsOf.
+func (mr *MockWalletMockRecorder) GetBalanceAsOf(ctx, walletID, asOf interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBalanceAsOf", reflect.TypeOf((*MockWallet)(nil).GetBalanceAsOf), ctx, walletID, asOf)
+}
+
+// GetTransaction mocks base method.
+func (m *MockWallet) GetTransaction(ctx context.Context, id int64) (domain.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransaction", ctx, id)
+	ret0, _ := ret[0].(domain.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransaction indicates an expected call of GetTransaction.
+func (mr *MockWalletMockRecorder) GetTransaction(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransaction", reflect.TypeOf((*MockWallet)(nil).GetTransaction), ctx, id)
+}
+
 // ProcessTransaction mocks base method.
-func (m *MockWallet) ProcessTransaction(ctx context.Context, walletID string, opType domain.OperationType, amount int64) error {
+func (m *MockWallet) ProcessTransaction(ctx context.Context, req domain.TransactionRequest) (domain.TransactionResult, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ProcessTransaction", ctx, walletID, opType, amount)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "ProcessTransaction", ctx, req)
+	ret0, _ := ret[0].(domain.TransactionResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // ProcessTransaction indicates an expected call of ProcessTransaction.
-func (mr *MockWalletMockRecorder) ProcessTransaction(ctx, walletID, opType, amount interface{}) *gomock.Call {
+func (mr *MockWalletMockRecorder) ProcessTransaction(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProcessTransaction", reflect.TypeOf((*MockWallet)(nil).ProcessTransaction), ctx, req)
+}
+
+// SearchTransactions mocks base method.
+func (m *MockWallet) SearchTransactions(ctx context.Context, filter domain.TransactionFilter) (domain.TransactionHistory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchTransactions", ctx, filter)
+	ret0, _ := ret[0].(domain.TransactionHistory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchTransactions indicates an expected call of SearchTransactions.
+func (mr *MockWalletMockRecorder) SearchTransactions(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchTransactions", reflect.TypeOf((*MockWallet)(nil).SearchTransactions), ctx, filter)
+}
+
+// Split mocks base method.
+func (m *MockWallet) Split(ctx context.Context, req domain.SplitRequest) (domain.SplitPayment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Split", ctx, req)
+	ret0, _ := ret[0].(domain.SplitPayment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Split indicates an expected call of Split.
+func (mr *MockWalletMockRecorder) Split(ctx, req interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProcessTransaction", reflect.TypeOf((*MockWallet)(nil).ProcessTransaction), ctx, walletID, opType, amount)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Split", reflect.TypeOf((*MockWallet)(nil).Split), ctx, req)
 }
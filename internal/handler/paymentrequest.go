@@ -0,0 +1,141 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/Te8va/wallet/internal/domain"
+	"github.com/Te8va/wallet/internal/problem"
+)
+
+const maxMemoLength = 500
+
+//go:generate mockgen -source=paymentrequest.go -destination=mocks/paymentrequest_mock.gen.go -package=mocks
+type PaymentRequest interface {
+	CreatePaymentRequest(ctx context.Context, params domain.PaymentRequestParams) (domain.PaymentRequest, error)
+	GetPaymentRequest(ctx context.Context, id int64) (domain.PaymentRequest, error)
+	ListPaymentRequests(ctx context.Context, filter domain.PaymentRequestFilter) ([]domain.PaymentRequest, error)
+	AcceptPaymentRequest(ctx context.Context, id int64) (domain.PaymentRequest, error)
+	DeclinePaymentRequest(ctx context.Context, id int64) (domain.PaymentRequest, error)
+}
+
+type PaymentRequestHandler struct {
+	srv PaymentRequest
+}
+
+func NewPaymentRequestHandler(srv PaymentRequest) *PaymentRequestHandler {
+	return &PaymentRequestHandler{srv: srv}
+}
+
+func (h *PaymentRequestHandler) CreatePaymentRequestHandler(w http.ResponseWriter, r *http.Request) {
+	var params domain.PaymentRequestParams
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		sendErrorResponse(w, r, problem.New(problem.CodeMalformedRequest, "Invalid request body"))
+		return
+	}
+
+	switch {
+	case params.PayeeWalletID == "":
+		sendErrorResponse(w, r, problem.Invalid("payeeWalletId", "Payee wallet ID is required"))
+		return
+	case params.PayerWalletID == "":
+		sendErrorResponse(w, r, problem.Invalid("payerWalletId", "Payer wallet ID is required"))
+		return
+	case len(params.PayeeWalletID) > maxWalletIDLength:
+		sendErrorResponse(w, r, problem.Invalid("payeeWalletId", "Wallet ID must be at most 36 characters"))
+		return
+	case len(params.PayerWalletID) > maxWalletIDLength:
+		sendErrorResponse(w, r, problem.Invalid("payerWalletId", "Wallet ID must be at most 36 characters"))
+		return
+	case params.Amount <= 0:
+		sendErrorResponse(w, r, problem.Invalid("amount", "Amount must be more than 0"))
+		return
+	case len(params.Memo) > maxMemoLength:
+		sendErrorResponse(w, r, problem.Invalid("memo", "Memo must be at most 500 characters"))
+		return
+	}
+
+	pr, err := h.srv.CreatePaymentRequest(r.Context(), params)
+	if err != nil {
+		sendError(w, r, err)
+		return
+	}
+
+	sendJSONResponse(w, pr, http.StatusCreated)
+}
+
+// ListIncomingHandler lists the requests the wallet is asked to pay.
+func (h *PaymentRequestHandler) ListIncomingHandler(w http.ResponseWriter, r *http.Request) {
+	h.list(w, r, domain.PaymentRequestIncoming)
+}
+
+// ListOutgoingHandler lists the requests the wallet has issued.
+func (h *PaymentRequestHandler) ListOutgoingHandler(w http.ResponseWriter, r *http.Request) {
+	h.list(w, r, domain.PaymentRequestOutgoing)
+}
+
+func (h *PaymentRequestHandler) GetPaymentRequestHandler(w http.ResponseWriter, r *http.Request) {
+	h.handlePaymentRequest(w, r, h.srv.GetPaymentRequest)
+}
+
+func (h *PaymentRequestHandler) AcceptPaymentRequestHandler(w http.ResponseWriter, r *http.Request) {
+	h.handlePaymentRequest(w, r, h.srv.AcceptPaymentRequest)
+}
+
+func (h *PaymentRequestHandler) DeclinePaymentRequestHandler(w http.ResponseWriter, r *http.Request) {
+	h.handlePaymentRequest(w, r, h.srv.DeclinePaymentRequest)
+}
+
+func (h *PaymentRequestHandler) list(w http.ResponseWriter, r *http.Request, direction domain.PaymentRequestDirection) {
+	walletID := chi.URLParam(r, "walletId")
+
+	if walletID == "" {
+		sendErrorResponse(w, r, problem.Invalid("walletId", "Wallet ID is required"))
+		return
+	}
+
+	status := domain.PaymentRequestStatus(r.URL.Query().Get("status"))
+	switch status {
+	case "", domain.PaymentRequestPending, domain.PaymentRequestAccepting, domain.PaymentRequestPaid,
+		domain.PaymentRequestDeclined, domain.PaymentRequestExpired:
+	default:
+		sendErrorResponse(w, r, problem.Invalid("status", "Status must be PENDING, ACCEPTING, PAID, DECLINED or EXPIRED"))
+		return
+	}
+
+	requests, err := h.srv.ListPaymentRequests(r.Context(), domain.PaymentRequestFilter{
+		WalletID:  walletID,
+		Direction: direction,
+		Status:    status,
+	})
+	if err != nil {
+		sendError(w, r, err)
+		return
+	}
+
+	if requests == nil {
+		requests = []domain.PaymentRequest{}
+	}
+
+	sendJSONResponse(w, requests, http.StatusOK)
+}
+
+func (h *PaymentRequestHandler) handlePaymentRequest(w http.ResponseWriter, r *http.Request, action func(context.Context, int64) (domain.PaymentRequest, error)) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "requestId"), 10, 64)
+	if err != nil {
+		sendErrorResponse(w, r, problem.Invalid("requestId", "Invalid payment request ID"))
+		return
+	}
+
+	pr, err := action(r.Context(), id)
+	if err != nil {
+		sendError(w, r, err)
+		return
+	}
+
+	sendJSONResponse(w, pr, http.StatusOK)
+}
@@ -0,0 +1,208 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Te8va/wallet/internal/domain"
+	appErrors "github.com/Te8va/wallet/internal/errors"
+	"github.com/Te8va/wallet/internal/handler/mocks"
+)
+
+func TestCreatePaymentRequestHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSrv := mocks.NewMockPaymentRequest(ctrl)
+	handler := NewPaymentRequestHandler(mockSrv)
+
+	at := time.Date(2025, 4, 1, 10, 0, 0, 0, time.UTC)
+	params := domain.PaymentRequestParams{PayeeWalletID: "payee", PayerWalletID: "payer", Amount: 500, Memo: "dinner"}
+
+	testCases := []struct {
+		name     string
+		body     string
+		mockServ func()
+		wantCode int
+		wantBody string
+	}{
+		{
+			name: "successful",
+			body: `{"payeeWalletId":"payee","payerWalletId":"payer","amount":500,"memo":"dinner"}`,
+			mockServ: func() {
+				mockSrv.EXPECT().CreatePaymentRequest(gomock.Any(), params).Return(domain.PaymentRequest{
+					ID: 1, PayeeWalletID: "payee", PayerWalletID: "payer", Amount: 500, Currency: "RUB", Memo: "dinner",
+					Status: domain.PaymentRequestPending, ExpiresAt: at.Add(72 * time.Hour), CreatedAt: at, UpdatedAt: at,
+					Events: []domain.PaymentRequestEvent{{ID: 1, ToStatus: domain.PaymentRequestPending, CreatedAt: at}},
+				}, nil)
+			},
+			wantCode: http.StatusCreated,
+			wantBody: `{"id":1,"payee_wallet_id":"payee","payer_wallet_id":"payer","amount":500,"currency":"RUB","memo":"dinner","status":"PENDING","expires_at":"2025-04-04T10:00:00Z","created_at":"2025-04-01T10:00:00Z","updated_at":"2025-04-01T10:00:00Z","events":[{"id":1,"to_status":"PENDING","created_at":"2025-04-01T10:00:00Z"}]}`,
+		},
+		{
+			name:     "zero amount",
+			body:     `{"payeeWalletId":"payee","payerWalletId":"payer","amount":0}`,
+			mockServ: func() {},
+			wantCode: http.StatusBadRequest,
+			wantBody: `{"type":"urn:wallet:problem:VALIDATION_FAILED","title":"Validation failed","status":400,"code":"VALIDATION_FAILED","detail":"Amount must be more than 0","instance":"/api/v1/payment-requests","errors":[{"field":"amount","message":"Amount must be more than 0"}]}`,
+		},
+		{
+			name: "request to itself",
+			body: `{"payeeWalletId":"payee","payerWalletId":"payer","amount":500,"memo":"dinner"}`,
+			mockServ: func() {
+				mockSrv.EXPECT().CreatePaymentRequest(gomock.Any(), params).Return(domain.PaymentRequest{}, appErrors.ErrSameWallet)
+			},
+			wantCode: http.StatusBadRequest,
+			wantBody: `{"type":"urn:wallet:problem:SAME_WALLET","title":"Same source and destination wallet","status":400,"code":"SAME_WALLET","detail":"source and destination wallets must differ","instance":"/api/v1/payment-requests"}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/payment-requests", bytes.NewBufferString(tc.body))
+
+			tc.mockServ()
+
+			w := httptest.NewRecorder()
+			handler.CreatePaymentRequestHandler(w, req)
+
+			require.Equal(t, tc.wantCode, w.Code)
+			require.JSONEq(t, tc.wantBody, w.Body.String())
+		})
+	}
+}
+
+func TestPaymentRequestActionHandlers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSrv := mocks.NewMockPaymentRequest(ctrl)
+	handler := NewPaymentRequestHandler(mockSrv)
+
+	testCases := []struct {
+		name      string
+		requestID string
+		handler   http.HandlerFunc
+		mockServ  func()
+		wantCode  int
+		wantBody  string
+	}{
+		{
+			name:      "accept",
+			requestID: "7",
+			handler:   handler.AcceptPaymentRequestHandler,
+			mockServ: func() {
+				mockSrv.EXPECT().AcceptPaymentRequest(gomock.Any(), int64(7)).Return(domain.PaymentRequest{
+					ID: 7, PayeeWalletID: "payee", PayerWalletID: "payer", Amount: 500, Currency: "RUB", Status: domain.PaymentRequestPaid,
+				}, nil)
+			},
+			wantCode: http.StatusOK,
+			wantBody: `{"id":7,"payee_wallet_id":"payee","payer_wallet_id":"payer","amount":500,"currency":"RUB","status":"PAID","expires_at":"0001-01-01T00:00:00Z","created_at":"0001-01-01T00:00:00Z","updated_at":"0001-01-01T00:00:00Z"}`,
+		},
+		{
+			name:      "accept expired",
+			requestID: "7",
+			handler:   handler.AcceptPaymentRequestHandler,
+			mockServ: func() {
+				mockSrv.EXPECT().AcceptPaymentRequest(gomock.Any(), int64(7)).Return(domain.PaymentRequest{}, appErrors.ErrPaymentRequestExpired)
+			},
+			wantCode: http.StatusConflict,
+			wantBody: `{"type":"urn:wallet:problem:PAYMENT_REQUEST_EXPIRED","title":"Payment request expired","status":409,"code":"PAYMENT_REQUEST_EXPIRED","detail":"payment request has expired","instance":"/api/v1/payment-requests/7"}`,
+		},
+		{
+			name:      "decline paid request",
+			requestID: "7",
+			handler:   handler.DeclinePaymentRequestHandler,
+			mockServ: func() {
+				mockSrv.EXPECT().DeclinePaymentRequest(gomock.Any(), int64(7)).Return(domain.PaymentRequest{}, appErrors.ErrPaymentRequestState)
+			},
+			wantCode: http.StatusConflict,
+			wantBody: `{"type":"urn:wallet:problem:INVALID_PAYMENT_REQUEST_STATE","title":"Invalid payment request state","status":409,"code":"INVALID_PAYMENT_REQUEST_STATE","detail":"payment request state does not allow this action","instance":"/api/v1/payment-requests/7"}`,
+		},
+		{
+			name:      "invalid id",
+			requestID: "abc",
+			handler:   handler.GetPaymentRequestHandler,
+			mockServ:  func() {},
+			wantCode:  http.StatusBadRequest,
+			wantBody:  `{"type":"urn:wallet:problem:VALIDATION_FAILED","title":"Validation failed","status":400,"code":"VALIDATION_FAILED","detail":"Invalid payment request ID","instance":"/api/v1/payment-requests/abc","errors":[{"field":"requestId","message":"Invalid payment request ID"}]}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/payment-requests/"+tc.requestID, nil)
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("requestId", tc.requestID)
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+			tc.mockServ()
+
+			w := httptest.NewRecorder()
+			tc.handler(w, req)
+
+			require.Equal(t, tc.wantCode, w.Code)
+			require.JSONEq(t, tc.wantBody, w.Body.String())
+		})
+	}
+}
+
+func TestListIncomingHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSrv := mocks.NewMockPaymentRequest(ctrl)
+	handler := NewPaymentRequestHandler(mockSrv)
+
+	testCases := []struct {
+		name     string
+		query    string
+		mockServ func()
+		wantCode int
+		wantBody string
+	}{
+		{
+			name:  "pending",
+			query: "?status=PENDING",
+			mockServ: func() {
+				mockSrv.EXPECT().ListPaymentRequests(gomock.Any(), domain.PaymentRequestFilter{
+					WalletID: "payer", Direction: domain.PaymentRequestIncoming, Status: domain.PaymentRequestPending,
+				}).Return(nil, nil)
+			},
+			wantCode: http.StatusOK,
+			wantBody: `[]`,
+		},
+		{
+			name:     "unknown status",
+			query:    "?status=LOST",
+			mockServ: func() {},
+			wantCode: http.StatusBadRequest,
+			wantBody: `{"type":"urn:wallet:problem:VALIDATION_FAILED","title":"Validation failed","status":400,"code":"VALIDATION_FAILED","detail":"Status must be PENDING, ACCEPTING, PAID, DECLINED or EXPIRED","instance":"/api/v1/wallets/payer/payment-requests/incoming","errors":[{"field":"status","message":"Status must be PENDING, ACCEPTING, PAID, DECLINED or EXPIRED"}]}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/wallets/payer/payment-requests/incoming"+tc.query, nil)
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("walletId", "payer")
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+			tc.mockServ()
+
+			w := httptest.NewRecorder()
+			handler.ListIncomingHandler(w, req)
+
+			require.Equal(t, tc.wantCode, w.Code)
+			require.JSONEq(t, tc.wantBody, w.Body.String())
+		})
+	}
+}
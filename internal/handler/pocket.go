@@ -0,0 +1,121 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/Te8va/wallet/internal/domain"
+	"github.com/Te8va/wallet/internal/problem"
+)
+
+const maxPocketNameLength = 64
+
+//go:generate mockgen -source=pocket.go -destination=mocks/pocket_mock.gen.go -package=mocks
+type Pocket interface {
+	CreatePocket(ctx context.Context, walletID, name string) (domain.Pocket, error)
+	PocketBalances(ctx context.Context, walletID string) (domain.PocketBalances, error)
+	MovePocket(ctx context.Context, move domain.PocketMove) (domain.PocketMove, error)
+}
+
+type PocketHandler struct {
+	srv Pocket
+}
+
+func NewPocketHandler(srv Pocket) *PocketHandler {
+	return &PocketHandler{srv: srv}
+}
+
+func (h *PocketHandler) CreatePocketHandler(w http.ResponseWriter, r *http.Request) {
+	walletID := chi.URLParam(r, "walletId")
+
+	if walletID == "" {
+		sendErrorResponse(w, r, problem.Invalid("walletId", "Wallet ID is required"))
+		return
+	}
+
+	var req domain.PocketRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		sendErrorResponse(w, r, problem.New(problem.CodeMalformedRequest, "Invalid request body"))
+		return
+	}
+
+	if msg := validatePocketName(req.Name); msg != "" {
+		sendErrorResponse(w, r, problem.Invalid("name", msg))
+		return
+	}
+
+	pocket, err := h.srv.CreatePocket(r.Context(), walletID, req.Name)
+	if err != nil {
+		sendError(w, r, err)
+		return
+	}
+
+	sendJSONResponse(w, pocket, http.StatusCreated)
+}
+
+// GetPocketsHandler returns the wallet total with its breakdown into the
+// main balance and pockets.
+func (h *PocketHandler) GetPocketsHandler(w http.ResponseWriter, r *http.Request) {
+	walletID := chi.URLParam(r, "walletId")
+
+	if walletID == "" {
+		sendErrorResponse(w, r, problem.Invalid("walletId", "Wallet ID is required"))
+		return
+	}
+
+	balances, err := h.srv.PocketBalances(r.Context(), walletID)
+	if err != nil {
+		sendError(w, r, err)
+		return
+	}
+
+	sendJSONResponse(w, balances, http.StatusOK)
+}
+
+func (h *PocketHandler) MovePocketHandler(w http.ResponseWriter, r *http.Request) {
+	walletID := chi.URLParam(r, "walletId")
+
+	if walletID == "" {
+		sendErrorResponse(w, r, problem.Invalid("walletId", "Wallet ID is required"))
+		return
+	}
+
+	var req domain.PocketMoveRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		sendErrorResponse(w, r, problem.New(problem.CodeMalformedRequest, "Invalid request body"))
+		return
+	}
+
+	if req.Amount <= 0 {
+		sendErrorResponse(w, r, problem.Invalid("amount", "Amount must be more than 0"))
+		return
+	}
+
+	move, err := h.srv.MovePocket(r.Context(), domain.PocketMove{
+		WalletID: walletID,
+		From:     req.From,
+		To:       req.To,
+		Amount:   req.Amount,
+	})
+	if err != nil {
+		sendError(w, r, err)
+		return
+	}
+
+	sendJSONResponse(w, move, http.StatusCreated)
+}
+
+func validatePocketName(name string) string {
+	if name == "" {
+		return "Pocket name is required"
+	}
+
+	if len(name) > maxPocketNameLength {
+		return "Pocket name must be at most 64 characters"
+	}
+
+	return ""
+}
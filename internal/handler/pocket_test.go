@@ -0,0 +1,174 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Te8va/wallet/internal/domain"
+	appErrors "github.com/Te8va/wallet/internal/errors"
+	"github.com/Te8va/wallet/internal/handler/mocks"
+)
+
+func TestCreatePocketHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPocket := mocks.NewMockPocket(ctrl)
+	handler := NewPocketHandler(mockPocket)
+
+	walletID := "123e4567-e89b-12d3-a456-426614174000"
+	createdAt := time.Date(2025, 4, 1, 10, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name     string
+		body     string
+		mockServ func()
+		wantCode int
+		wantBody string
+	}{
+		{
+			name: "successful",
+			body: `{"name":"vacation"}`,
+			mockServ: func() {
+				mockPocket.EXPECT().CreatePocket(gomock.Any(), walletID, "vacation").
+					Return(domain.Pocket{WalletID: walletID, Name: "vacation", CreatedAt: createdAt}, nil)
+			},
+			wantCode: http.StatusCreated,
+			wantBody: `{"wallet_id":"123e4567-e89b-12d3-a456-426614174000","name":"vacation","balance":0,"created_at":"2025-04-01T10:00:00Z"}`,
+		},
+		{
+			name:     "missing name",
+			body:     `{}`,
+			mockServ: func() {},
+			wantCode: http.StatusBadRequest,
+			wantBody: `{"type":"urn:wallet:problem:VALIDATION_FAILED","title":"Validation failed","status":400,"code":"VALIDATION_FAILED","detail":"Pocket name is required","instance":"/api/v1/wallets/123e4567-e89b-12d3-a456-426614174000/pockets","errors":[{"field":"name","message":"Pocket name is required"}]}`,
+		},
+		{
+			name: "pocket exists",
+			body: `{"name":"vacation"}`,
+			mockServ: func() {
+				mockPocket.EXPECT().CreatePocket(gomock.Any(), walletID, "vacation").Return(domain.Pocket{}, appErrors.ErrPocketExists)
+			},
+			wantCode: http.StatusConflict,
+			wantBody: `{"type":"urn:wallet:problem:POCKET_EXISTS","title":"Pocket already exists","status":409,"code":"POCKET_EXISTS","detail":"pocket already exists","instance":"/api/v1/wallets/123e4567-e89b-12d3-a456-426614174000/pockets"}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/wallets/"+walletID+"/pockets", bytes.NewBufferString(tc.body))
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("walletId", walletID)
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+			tc.mockServ()
+
+			w := httptest.NewRecorder()
+			handler.CreatePocketHandler(w, req)
+
+			require.Equal(t, tc.wantCode, w.Code)
+			require.JSONEq(t, tc.wantBody, w.Body.String())
+		})
+	}
+}
+
+func TestGetPocketsHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPocket := mocks.NewMockPocket(ctrl)
+	handler := NewPocketHandler(mockPocket)
+
+	walletID := "123e4567-e89b-12d3-a456-426614174000"
+
+	mockPocket.EXPECT().PocketBalances(gomock.Any(), walletID).Return(domain.PocketBalances{
+		WalletID:    walletID,
+		Balance:     1500,
+		MainBalance: 1200,
+		Pockets: []domain.Pocket{{
+			WalletID: walletID, Name: "vacation", Balance: 300, CreatedAt: time.Date(2025, 4, 1, 10, 0, 0, 0, time.UTC),
+		}},
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/wallets/"+walletID+"/pockets", nil)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("walletId", walletID)
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+	w := httptest.NewRecorder()
+	handler.GetPocketsHandler(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.JSONEq(t, `{"wallet_id":"123e4567-e89b-12d3-a456-426614174000","balance":1500,"main_balance":1200,"pockets":[{"wallet_id":"123e4567-e89b-12d3-a456-426614174000","name":"vacation","balance":300,"created_at":"2025-04-01T10:00:00Z"}]}`, w.Body.String())
+}
+
+func TestMovePocketHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPocket := mocks.NewMockPocket(ctrl)
+	handler := NewPocketHandler(mockPocket)
+
+	walletID := "123e4567-e89b-12d3-a456-426614174000"
+
+	testCases := []struct {
+		name     string
+		body     string
+		mockServ func()
+		wantCode int
+		wantBody string
+	}{
+		{
+			name: "main to pocket",
+			body: `{"to":"vacation","amount":300}`,
+			mockServ: func() {
+				mockPocket.EXPECT().MovePocket(gomock.Any(), domain.PocketMove{WalletID: walletID, To: "vacation", Amount: 300}).
+					Return(domain.PocketMove{ID: 5, WalletID: walletID, To: "vacation", Amount: 300, CreatedAt: time.Date(2025, 4, 1, 10, 0, 0, 0, time.UTC)}, nil)
+			},
+			wantCode: http.StatusCreated,
+			wantBody: `{"id":5,"wallet_id":"123e4567-e89b-12d3-a456-426614174000","to":"vacation","amount":300,"created_at":"2025-04-01T10:00:00Z"}`,
+		},
+		{
+			name: "insufficient funds",
+			body: `{"from":"vacation","amount":300}`,
+			mockServ: func() {
+				mockPocket.EXPECT().MovePocket(gomock.Any(), domain.PocketMove{WalletID: walletID, From: "vacation", Amount: 300}).
+					Return(domain.PocketMove{}, appErrors.ErrInsufficientFunds)
+			},
+			wantCode: http.StatusBadRequest,
+			wantBody: `{"type":"urn:wallet:problem:INSUFFICIENT_FUNDS","title":"Insufficient funds","status":400,"code":"INSUFFICIENT_FUNDS","detail":"insufficient funds","instance":"/api/v1/wallets/123e4567-e89b-12d3-a456-426614174000/pocket-moves"}`,
+		},
+		{
+			name:     "zero amount",
+			body:     `{"to":"vacation","amount":0}`,
+			mockServ: func() {},
+			wantCode: http.StatusBadRequest,
+			wantBody: `{"type":"urn:wallet:problem:VALIDATION_FAILED","title":"Validation failed","status":400,"code":"VALIDATION_FAILED","detail":"Amount must be more than 0","instance":"/api/v1/wallets/123e4567-e89b-12d3-a456-426614174000/pocket-moves","errors":[{"field":"amount","message":"Amount must be more than 0"}]}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/wallets/"+walletID+"/pocket-moves", bytes.NewBufferString(tc.body))
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("walletId", walletID)
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+			tc.mockServ()
+
+			w := httptest.NewRecorder()
+			handler.MovePocketHandler(w, req)
+
+			require.Equal(t, tc.wantCode, w.Code)
+			require.JSONEq(t, tc.wantBody, w.Body.String())
+		})
+	}
+}
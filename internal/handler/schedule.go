@@ -0,0 +1,133 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/Te8va/wallet/internal/domain"
+	appErrors "github.com/Te8va/wallet/internal/errors"
+	"github.com/Te8va/wallet/internal/problem"
+)
+
+//go:generate mockgen -source=schedule.go -destination=mocks/schedule_mock.gen.go -package=mocks
+type Scheduler interface {
+	CreateSchedule(ctx context.Context, req domain.ScheduleRequest) (domain.Schedule, error)
+	GetSchedule(ctx context.Context, id int64) (domain.Schedule, error)
+	ListSchedules(ctx context.Context, walletID string) ([]domain.Schedule, error)
+	PauseSchedule(ctx context.Context, id int64) (domain.Schedule, error)
+	ResumeSchedule(ctx context.Context, id int64) (domain.Schedule, error)
+	CancelSchedule(ctx context.Context, id int64) (domain.Schedule, error)
+}
+
+type ScheduleHandler struct {
+	srv Scheduler
+}
+
+func NewScheduleHandler(srv Scheduler) *ScheduleHandler {
+	return &ScheduleHandler{srv: srv}
+}
+
+func (h *ScheduleHandler) CreateScheduleHandler(w http.ResponseWriter, r *http.Request) {
+	var req domain.ScheduleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		sendErrorResponse(w, r, problem.New(problem.CodeMalformedRequest, "Invalid request body"))
+		return
+	}
+
+	if req.FromWalletID == "" {
+		sendErrorResponse(w, r, problem.Invalid("fromWalletId", "Source and destination wallet IDs are required"))
+		return
+	}
+
+	if req.ToWalletID == "" {
+		sendErrorResponse(w, r, problem.Invalid("toWalletId", "Source and destination wallet IDs are required"))
+		return
+	}
+
+	if req.FromWalletID == req.ToWalletID {
+		sendError(w, r, appErrors.ErrSameWallet)
+		return
+	}
+
+	if req.Amount <= 0 {
+		sendErrorResponse(w, r, problem.Invalid("amount", "Amount must be more than 0"))
+		return
+	}
+
+	switch req.Frequency {
+	case domain.ONCE, domain.DAILY, domain.WEEKLY, domain.MONTHLY:
+	case domain.CRON:
+		if req.Cron == "" {
+			sendErrorResponse(w, r, problem.Invalid("cron", "Cron expression is required"))
+			return
+		}
+	default:
+		sendErrorResponse(w, r, problem.Invalid("frequency", "Frequency must be ONCE, DAILY, WEEKLY, MONTHLY or CRON"))
+		return
+	}
+
+	schedule, err := h.srv.CreateSchedule(r.Context(), req)
+	if err != nil {
+		sendError(w, r, err)
+		return
+	}
+
+	sendJSONResponse(w, schedule, http.StatusCreated)
+}
+
+func (h *ScheduleHandler) ListSchedulesHandler(w http.ResponseWriter, r *http.Request) {
+	walletID := r.URL.Query().Get("walletId")
+
+	if walletID == "" {
+		sendErrorResponse(w, r, problem.Invalid("walletId", "Wallet ID is required"))
+		return
+	}
+
+	schedules, err := h.srv.ListSchedules(r.Context(), walletID)
+	if err != nil {
+		sendError(w, r, err)
+		return
+	}
+
+	if schedules == nil {
+		schedules = []domain.Schedule{}
+	}
+
+	sendJSONResponse(w, schedules, http.StatusOK)
+}
+
+func (h *ScheduleHandler) GetScheduleHandler(w http.ResponseWriter, r *http.Request) {
+	h.handleSchedule(w, r, h.srv.GetSchedule)
+}
+
+func (h *ScheduleHandler) PauseScheduleHandler(w http.ResponseWriter, r *http.Request) {
+	h.handleSchedule(w, r, h.srv.PauseSchedule)
+}
+
+func (h *ScheduleHandler) ResumeScheduleHandler(w http.ResponseWriter, r *http.Request) {
+	h.handleSchedule(w, r, h.srv.ResumeSchedule)
+}
+
+func (h *ScheduleHandler) CancelScheduleHandler(w http.ResponseWriter, r *http.Request) {
+	h.handleSchedule(w, r, h.srv.CancelSchedule)
+}
+
+func (h *ScheduleHandler) handleSchedule(w http.ResponseWriter, r *http.Request, action func(context.Context, int64) (domain.Schedule, error)) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "scheduleId"), 10, 64)
+	if err != nil {
+		sendErrorResponse(w, r, problem.Invalid("scheduleId", "Invalid schedule ID"))
+		return
+	}
+
+	schedule, err := action(r.Context(), id)
+	if err != nil {
+		sendError(w, r, err)
+		return
+	}
+
+	sendJSONResponse(w, schedule, http.StatusOK)
+}
@@ -0,0 +1,152 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Te8va/wallet/internal/domain"
+	appErrors "github.com/Te8va/wallet/internal/errors"
+	"github.com/Te8va/wallet/internal/handler/mocks"
+)
+
+func TestCreateScheduleHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockScheduler := mocks.NewMockScheduler(ctrl)
+	handler := NewScheduleHandler(mockScheduler)
+
+	testCases := []struct {
+		name     string
+		body     string
+		mockServ func()
+		wantCode int
+		wantBody string
+	}{
+		{
+			name: "successful",
+			body: `{"fromWalletId":"a","toWalletId":"b","amount":100,"frequency":"DAILY"}`,
+			mockServ: func() {
+				mockScheduler.EXPECT().CreateSchedule(gomock.Any(), domain.ScheduleRequest{
+					FromWalletID: "a", ToWalletID: "b", Amount: 100, Frequency: domain.DAILY,
+				}).Return(domain.Schedule{ID: 1, FromWalletID: "a", ToWalletID: "b", Amount: 100, Frequency: domain.DAILY, Status: domain.ScheduleActive}, nil)
+			},
+			wantCode: http.StatusCreated,
+		},
+		{
+			name:     "same wallet",
+			body:     `{"fromWalletId":"a","toWalletId":"a","amount":100,"frequency":"DAILY"}`,
+			mockServ: func() {},
+			wantCode: http.StatusBadRequest,
+			wantBody: `{"type":"urn:wallet:problem:SAME_WALLET","title":"Same source and destination wallet","status":400,"code":"SAME_WALLET","detail":"source and destination wallets must differ","instance":"/api/v1/schedules"}`,
+		},
+		{
+			name:     "unknown frequency",
+			body:     `{"fromWalletId":"a","toWalletId":"b","amount":100,"frequency":"HOURLY"}`,
+			mockServ: func() {},
+			wantCode: http.StatusBadRequest,
+			wantBody: `{"type":"urn:wallet:problem:VALIDATION_FAILED","title":"Validation failed","status":400,"code":"VALIDATION_FAILED","detail":"Frequency must be ONCE, DAILY, WEEKLY, MONTHLY or CRON","instance":"/api/v1/schedules","errors":[{"field":"frequency","message":"Frequency must be ONCE, DAILY, WEEKLY, MONTHLY or CRON"}]}`,
+		},
+		{
+			name:     "cron without expression",
+			body:     `{"fromWalletId":"a","toWalletId":"b","amount":100,"frequency":"CRON"}`,
+			mockServ: func() {},
+			wantCode: http.StatusBadRequest,
+			wantBody: `{"type":"urn:wallet:problem:VALIDATION_FAILED","title":"Validation failed","status":400,"code":"VALIDATION_FAILED","detail":"Cron expression is required","instance":"/api/v1/schedules","errors":[{"field":"cron","message":"Cron expression is required"}]}`,
+		},
+		{
+			name: "invalid cron expression",
+			body: `{"fromWalletId":"a","toWalletId":"b","amount":100,"frequency":"CRON","cron":"* *"}`,
+			mockServ: func() {
+				mockScheduler.EXPECT().CreateSchedule(gomock.Any(), gomock.Any()).Return(domain.Schedule{}, appErrors.ErrInvalidCron)
+			},
+			wantCode: http.StatusBadRequest,
+			wantBody: `{"type":"urn:wallet:problem:INVALID_CRON","title":"Invalid cron expression","status":400,"code":"INVALID_CRON","detail":"invalid cron expression","instance":"/api/v1/schedules"}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/schedules", bytes.NewReader([]byte(tc.body)))
+
+			tc.mockServ()
+
+			w := httptest.NewRecorder()
+			handler.CreateScheduleHandler(w, req)
+
+			require.Equal(t, tc.wantCode, w.Code)
+			if tc.wantBody != "" {
+				require.JSONEq(t, tc.wantBody, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestPauseScheduleHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockScheduler := mocks.NewMockScheduler(ctrl)
+	handler := NewScheduleHandler(mockScheduler)
+
+	testCases := []struct {
+		name       string
+		scheduleID string
+		mockServ   func()
+		wantCode   int
+	}{
+		{
+			name:       "successful",
+			scheduleID: "1",
+			mockServ: func() {
+				mockScheduler.EXPECT().PauseSchedule(gomock.Any(), int64(1)).Return(domain.Schedule{ID: 1, Status: domain.SchedulePaused}, nil)
+			},
+			wantCode: http.StatusOK,
+		},
+		{
+			name:       "not found",
+			scheduleID: "2",
+			mockServ: func() {
+				mockScheduler.EXPECT().PauseSchedule(gomock.Any(), int64(2)).Return(domain.Schedule{}, appErrors.ErrScheduleNotFound)
+			},
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name:       "already canceled",
+			scheduleID: "3",
+			mockServ: func() {
+				mockScheduler.EXPECT().PauseSchedule(gomock.Any(), int64(3)).Return(domain.Schedule{}, appErrors.ErrInvalidScheduleState)
+			},
+			wantCode: http.StatusConflict,
+		},
+		{
+			name:       "invalid id",
+			scheduleID: "abc",
+			mockServ:   func() {},
+			wantCode:   http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/schedules/"+tc.scheduleID+"/pause", nil)
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("scheduleId", tc.scheduleID)
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+			tc.mockServ()
+
+			w := httptest.NewRecorder()
+			handler.PauseScheduleHandler(w, req)
+
+			require.Equal(t, tc.wantCode, w.Code)
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/Te8va/wallet/internal/domain"
+	"github.com/Te8va/wallet/internal/problem"
+)
+
+const (
+	maxSplitRecipients      = 100
+	maxIdempotencyKeyLength = 128
+	maxReferenceLength      = 128
+	maxDescriptionLength    = 500
+)
+
+// SplitPaymentHandler debits one wallet and credits several recipients in
+// one transaction. Each recipient gets either a fixed amount or a share in
+// basis points of what is left after the fixed amounts. It responds 201 when
+// the payment is posted and 200 when the idempotency key has already been
+// used for the same split.
+func (h *WalletHandler) SplitPaymentHandler(w http.ResponseWriter, r *http.Request) {
+	var req domain.SplitRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		sendErrorResponse(w, r, problem.New(problem.CodeMalformedRequest, "Invalid request body"))
+		return
+	}
+
+	if field, msg := validateSplit(req); msg != "" {
+		sendErrorResponse(w, r, problem.Invalid(field, msg))
+		return
+	}
+
+	payment, err := h.srv.Split(r.Context(), req)
+	if err != nil {
+		sendError(w, r, err)
+		return
+	}
+
+	status := http.StatusCreated
+	if payment.Replayed {
+		w.Header().Set(IdempotentReplayedHeader, "true")
+		status = http.StatusOK
+	}
+
+	sendJSONResponse(w, payment, status)
+}
+
+// validateSplit returns the offending field and a message, or an empty
+// message when the request is well formed. Whether the amounts add up is
+// checked by the service.
+func validateSplit(req domain.SplitRequest) (string, string) {
+	switch {
+	case req.WalletID == "":
+		return "walletId", "Wallet ID is required"
+	case len(req.WalletID) > maxWalletIDLength:
+		return "walletId", "Wallet ID must be at most 36 characters"
+	case req.Amount <= 0:
+		return "amount", "Amount must be more than 0"
+	case len(req.IdempotencyKey) > maxIdempotencyKeyLength:
+		return "idempotencyKey", "Idempotency key must be at most 128 characters"
+	case len(req.Reference) > maxReferenceLength:
+		return "reference", "Reference must be at most 128 characters"
+	case len(req.Description) > maxDescriptionLength:
+		return "description", "Description must be at most 500 characters"
+	case len(req.Recipients) == 0:
+		return "recipients", "At least one recipient is required"
+	case len(req.Recipients) > maxSplitRecipients:
+		return "recipients", "At most 100 recipients are allowed"
+	}
+
+	seen := make(map[string]bool, len(req.Recipients))
+	for i, rcpt := range req.Recipients {
+		field := fmt.Sprintf("recipients.%d", i)
+		switch {
+		case rcpt.WalletID == "":
+			return field + ".walletId", "Wallet ID is required"
+		case len(rcpt.WalletID) > maxWalletIDLength:
+			return field + ".walletId", "Wallet ID must be at most 36 characters"
+		case seen[rcpt.WalletID]:
+			return field + ".walletId", "Recipients must be different wallets"
+		case rcpt.Amount < 0 || rcpt.ShareBps < 0 || (rcpt.Amount > 0) == (rcpt.ShareBps > 0):
+			return field, "Either amount or shareBps must be more than 0"
+		case rcpt.ShareBps > 10000:
+			return field + ".shareBps", "shareBps must be at most 10000"
+		}
+		seen[rcpt.WalletID] = true
+	}
+
+	return "", ""
+}
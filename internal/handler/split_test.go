@@ -0,0 +1,134 @@
+package handler
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Te8va/wallet/internal/domain"
+	appErrors "github.com/Te8va/wallet/internal/errors"
+)
+
+func TestSplitPaymentHandler(t *testing.T) {
+	ctrl, mockWallet, handler := setupTestHandler(t)
+	defer ctrl.Finish()
+
+	createdAt := time.Date(2025, 4, 1, 10, 0, 0, 0, time.UTC)
+	request := domain.SplitRequest{
+		WalletID:  "buyer",
+		Amount:    1000,
+		Reference: "order-7",
+		Recipients: []domain.SplitRecipient{
+			{WalletID: "platform", ShareBps: 1000},
+			{WalletID: "seller", ShareBps: 9000},
+		},
+	}
+
+	testCases := []struct {
+		name       string
+		body       string
+		mockServ   func()
+		wantCode   int
+		wantHeader string
+		wantBody   string
+	}{
+		{
+			name: "successful",
+			body: `{"walletId":"buyer","amount":1000,"reference":"order-7","recipients":[{"walletId":"platform","shareBps":1000},{"walletId":"seller","shareBps":9000}]}`,
+			mockServ: func() {
+				mockWallet.EXPECT().Split(gomock.Any(), request).Return(domain.SplitPayment{
+					Debit: domain.Transaction{ID: 10, WalletID: "buyer", OperationType: domain.SPLIT_OUT, Amount: -1000,
+						Balance: 500, Reference: "order-7", CreatedAt: createdAt},
+					Credits: []domain.Transaction{
+						{ID: 11, WalletID: "platform", OperationType: domain.SPLIT_IN, Amount: 100, Balance: 100,
+							ParentID: 10, Reference: "order-7", CreatedAt: createdAt},
+						{ID: 12, WalletID: "seller", OperationType: domain.SPLIT_IN, Amount: 900, Balance: 900,
+							ParentID: 10, Reference: "order-7", CreatedAt: createdAt},
+					},
+				}, nil)
+			},
+			wantCode: http.StatusCreated,
+			wantBody: `{"debit":{"id":10,"wallet_id":"buyer","operation_type":"SPLIT_OUT","amount":-1000,"balance":500,"reference":"order-7","created_at":"2025-04-01T10:00:00Z"},
+				"credits":[{"id":11,"wallet_id":"platform","operation_type":"SPLIT_IN","amount":100,"balance":100,"parent_id":10,"reference":"order-7","created_at":"2025-04-01T10:00:00Z"},
+				{"id":12,"wallet_id":"seller","operation_type":"SPLIT_IN","amount":900,"balance":900,"parent_id":10,"reference":"order-7","created_at":"2025-04-01T10:00:00Z"}]}`,
+		},
+		{
+			name: "replayed",
+			body: `{"walletId":"buyer","amount":1000,"idempotencyKey":"order-7","reference":"order-7","recipients":[{"walletId":"platform","shareBps":1000},{"walletId":"seller","shareBps":9000}]}`,
+			mockServ: func() {
+				replay := request
+				replay.IdempotencyKey = "order-7"
+				mockWallet.EXPECT().Split(gomock.Any(), replay).Return(domain.SplitPayment{
+					Debit: domain.Transaction{ID: 10, WalletID: "buyer", OperationType: domain.SPLIT_OUT, Amount: -1000,
+						Balance: 500, IdempotencyKey: "order-7", Reference: "order-7", CreatedAt: createdAt},
+					Credits: []domain.Transaction{
+						{ID: 11, WalletID: "platform", OperationType: domain.SPLIT_IN, Amount: 100, Balance: 100,
+							ParentID: 10, Reference: "order-7", CreatedAt: createdAt},
+						{ID: 12, WalletID: "seller", OperationType: domain.SPLIT_IN, Amount: 900, Balance: 900,
+							ParentID: 10, Reference: "order-7", CreatedAt: createdAt},
+					},
+					Replayed: true,
+				}, nil)
+			},
+			wantCode:   http.StatusOK,
+			wantHeader: "true",
+			wantBody: `{"debit":{"id":10,"wallet_id":"buyer","operation_type":"SPLIT_OUT","amount":-1000,"balance":500,"idempotency_key":"order-7","reference":"order-7","created_at":"2025-04-01T10:00:00Z"},
+				"credits":[{"id":11,"wallet_id":"platform","operation_type":"SPLIT_IN","amount":100,"balance":100,"parent_id":10,"reference":"order-7","created_at":"2025-04-01T10:00:00Z"},
+				{"id":12,"wallet_id":"seller","operation_type":"SPLIT_IN","amount":900,"balance":900,"parent_id":10,"reference":"order-7","created_at":"2025-04-01T10:00:00Z"}]}`,
+		},
+		{
+			name: "idempotency key reused",
+			body: `{"walletId":"buyer","amount":1000,"idempotencyKey":"order-7","reference":"order-7","recipients":[{"walletId":"platform","shareBps":1000},{"walletId":"seller","shareBps":9000}]}`,
+			mockServ: func() {
+				replay := request
+				replay.IdempotencyKey = "order-7"
+				mockWallet.EXPECT().Split(gomock.Any(), replay).Return(domain.SplitPayment{}, appErrors.ErrIdempotencyKeyReused)
+			},
+			wantCode: http.StatusConflict,
+			wantBody: `{"type":"urn:wallet:problem:IDEMPOTENCY_KEY_REUSED","title":"Idempotency key reused","status":409,"code":"IDEMPOTENCY_KEY_REUSED","detail":"idempotency key was used for a different operation","instance":"/api/v1/splits"}`,
+		},
+		{
+			name:     "amount and share",
+			body:     `{"walletId":"buyer","amount":1000,"recipients":[{"walletId":"seller","amount":900,"shareBps":9000}]}`,
+			mockServ: func() {},
+			wantCode: http.StatusBadRequest,
+			wantBody: `{"type":"urn:wallet:problem:VALIDATION_FAILED","title":"Validation failed","status":400,"code":"VALIDATION_FAILED","detail":"Either amount or shareBps must be more than 0","instance":"/api/v1/splits","errors":[{"field":"recipients.0","message":"Either amount or shareBps must be more than 0"}]}`,
+		},
+		{
+			name:     "duplicate recipient",
+			body:     `{"walletId":"buyer","amount":1000,"recipients":[{"walletId":"seller","amount":500},{"walletId":"seller","amount":500}]}`,
+			mockServ: func() {},
+			wantCode: http.StatusBadRequest,
+			wantBody: `{"type":"urn:wallet:problem:VALIDATION_FAILED","title":"Validation failed","status":400,"code":"VALIDATION_FAILED","detail":"Recipients must be different wallets","instance":"/api/v1/splits","errors":[{"field":"recipients.1.walletId","message":"Recipients must be different wallets"}]}`,
+		},
+		{
+			name: "shares do not add up",
+			body: `{"walletId":"buyer","amount":1000,"reference":"order-7","recipients":[{"walletId":"platform","shareBps":1000},{"walletId":"seller","shareBps":9000}]}`,
+			mockServ: func() {
+				mockWallet.EXPECT().Split(gomock.Any(), request).Return(domain.SplitPayment{}, appErrors.ErrInvalidSplit)
+			},
+			wantCode: http.StatusBadRequest,
+			wantBody: `{"type":"urn:wallet:problem:INVALID_SPLIT","title":"Invalid split","status":400,"code":"INVALID_SPLIT","detail":"split amounts do not add up to the payment amount","instance":"/api/v1/splits"}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/splits", bytes.NewBufferString(tc.body))
+
+			tc.mockServ()
+
+			w := httptest.NewRecorder()
+			handler.SplitPaymentHandler(w, req)
+
+			require.Equal(t, tc.wantCode, w.Code)
+			require.Equal(t, tc.wantHeader, w.Header().Get(IdempotentReplayedHeader))
+			require.JSONEq(t, tc.wantBody, w.Body.String())
+		})
+	}
+}
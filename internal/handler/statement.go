@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/Te8va/wallet/internal/domain"
+	"github.com/Te8va/wallet/internal/problem"
+	"github.com/Te8va/wallet/internal/statement"
+)
+
+//go:generate mockgen -source=statement.go -destination=mocks/statement_mock.gen.go -package=mocks
+type Statementer interface {
+	GetStatement(ctx context.Context, walletID string, from, to time.Time) (domain.Statement, error)
+}
+
+type StatementHandler struct {
+	srv  Statementer
+	opts statement.Options
+}
+
+func NewStatementHandler(srv Statementer, opts statement.Options) *StatementHandler {
+	return &StatementHandler{srv: srv, opts: opts}
+}
+
+func (h *StatementHandler) GetStatementHandler(w http.ResponseWriter, r *http.Request) {
+	walletID := chi.URLParam(r, "walletId")
+
+	if walletID == "" {
+		sendErrorResponse(w, r, problem.Invalid("walletId", "Wallet ID is required"))
+		return
+	}
+
+	query := r.URL.Query()
+
+	format, err := statement.ParseFormat(query.Get("format"))
+	if err != nil {
+		sendErrorResponse(w, r, problem.Invalid("format", "Format must be json, csv, text, camt053 or ofx"))
+		return
+	}
+
+	from, to, err := statement.ParsePeriod(query.Get("from"), query.Get("to"))
+	if err != nil {
+		field := "from"
+		var periodErr *statement.PeriodError
+		if errors.As(err, &periodErr) {
+			field = periodErr.Bound
+		}
+		sendErrorResponse(w, r, problem.Invalid(field, "Period must be set with from and to as YYYY-MM-DD or RFC 3339 time, from before to"))
+		return
+	}
+
+	st, err := h.srv.GetStatement(r.Context(), walletID, from, to)
+	if err != nil {
+		sendError(w, r, err)
+		return
+	}
+
+	var buf bytes.Buffer
+	if err = statement.Write(&buf, format, st, h.opts); err != nil {
+		sendError(w, r, err)
+		return
+	}
+
+	if format != statement.FormatJSON {
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="statement-%s-%s.%s"`,
+			walletID, from.Format(time.DateOnly), format.Extension()))
+	}
+	w.Header().Set("Content-Type", format.ContentType())
+	w.WriteHeader(http.StatusOK)
+	w.Write(buf.Bytes())
+}
@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Te8va/wallet/internal/handler/mocks"
+	"github.com/Te8va/wallet/internal/statement"
+)
+
+func TestGetStatementHandlerPeriod(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler := NewStatementHandler(mocks.NewMockStatementer(ctrl), statement.Options{})
+
+	walletID := "123e4567-e89b-12d3-a456-426614174000"
+
+	testCases := []struct {
+		name      string
+		query     string
+		wantField string
+	}{
+		{
+			name:      "malformed from",
+			query:     "from=01.03.2025&to=2025-03-31",
+			wantField: "from",
+		},
+		{
+			name:      "malformed to",
+			query:     "from=2025-03-01&to=31.03.2025",
+			wantField: "to",
+		},
+		{
+			name:      "to before from",
+			query:     "from=2025-04-01&to=2025-03-01",
+			wantField: "to",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			target := "/api/v1/wallets/" + walletID + "/statement?" + tc.query
+			req := httptest.NewRequest(http.MethodGet, target, nil)
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("walletId", walletID)
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+			w := httptest.NewRecorder()
+			handler.GetStatementHandler(w, req)
+
+			require.Equal(t, http.StatusBadRequest, w.Code)
+			require.JSONEq(t, `{"type":"urn:wallet:problem:VALIDATION_FAILED","title":"Validation failed","status":400,"code":"VALIDATION_FAILED",`+
+				`"detail":"Period must be set with from and to as YYYY-MM-DD or RFC 3339 time, from before to",`+
+				`"instance":"/api/v1/wallets/`+walletID+`/statement",`+
+				`"errors":[{"field":"`+tc.wantField+`","message":"Period must be set with from and to as YYYY-MM-DD or RFC 3339 time, from before to"}]}`,
+				w.Body.String())
+		})
+	}
+}
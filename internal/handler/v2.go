@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/Te8va/wallet/internal/domain"
+	"github.com/Te8va/wallet/internal/problem"
+)
+
+// IdempotentReplayedHeader marks a response that returns the transaction
+// posted by an earlier request with the same idempotency key.
+const IdempotentReplayedHeader = "Idempotent-Replayed"
+
+// CreateTransactionHandler posts a deposit or withdrawal and returns the
+// transaction with the new balance: 201 when it is posted, 200 when the
+// idempotency key has already been used for the same operation.
+func (h *WalletHandler) CreateTransactionHandler(w http.ResponseWriter, r *http.Request) {
+	var req domain.TransactionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		sendErrorResponse(w, r, problem.New(problem.CodeMalformedRequest, "Invalid request body"))
+		return
+	}
+
+	res, err := h.processTransaction(r.Context(), req)
+	if err != nil {
+		sendError(w, r, err)
+		return
+	}
+
+	status := http.StatusCreated
+	if res.Replayed {
+		w.Header().Set(IdempotentReplayedHeader, "true")
+		status = http.StatusOK
+	}
+
+	w.Header().Set("Location", fmt.Sprintf("/api/v2/transactions/%d", res.Transaction.ID))
+	sendJSONResponse(w, transactionResponse(res.Transaction, h.currency), status)
+}
+
+func (h *WalletHandler) GetTransactionV2Handler(w http.ResponseWriter, r *http.Request) {
+	t, ok := h.transaction(w, r)
+	if !ok {
+		return
+	}
+
+	sendJSONResponse(w, transactionResponse(t, h.currency), http.StatusOK)
+}
+
+func (h *WalletHandler) GetWalletHandler(w http.ResponseWriter, r *http.Request) {
+	walletID := chi.URLParam(r, "walletId")
+
+	if walletID == "" {
+		sendErrorResponse(w, r, problem.Invalid("walletId", "Wallet ID is required"))
+		return
+	}
+
+	var asOf *time.Time
+	if v := r.URL.Query().Get("asOf"); v != "" {
+		at, err := time.Parse(time.RFC3339Nano, v)
+		if err != nil {
+			sendErrorResponse(w, r, problem.Invalid("asOf", "asOf must be an RFC 3339 time"))
+			return
+		}
+		asOf = &at
+	}
+
+	wallet, err := h.wallet(r.Context(), walletID, asOf)
+	if err != nil {
+		sendError(w, r, err)
+		return
+	}
+
+	sendJSONResponse(w, wallet, http.StatusOK)
+}
+
+func (h *WalletHandler) ListTransactionsV2Handler(w http.ResponseWriter, r *http.Request) {
+	filter, ok := transactionFilter(w, r)
+	if !ok {
+		return
+	}
+
+	history, err := h.srv.SearchTransactions(r.Context(), filter)
+	if err != nil {
+		sendError(w, r, err)
+		return
+	}
+
+	resp := domain.TransactionHistoryResponse{
+		Transactions: make([]domain.TransactionResponse, 0, len(history.Transactions)),
+		NextBefore:   history.NextBefore,
+	}
+	for _, t := range history.Transactions {
+		resp.Transactions = append(resp.Transactions, transactionResponse(t, h.currency))
+	}
+
+	sendJSONResponse(w, resp, http.StatusOK)
+}
+
+func transactionResponse(t domain.Transaction, currency string) domain.TransactionResponse {
+	return domain.TransactionResponse{
+		ID:             t.ID,
+		WalletID:       t.WalletID,
+		OperationType:  t.OperationType,
+		Amount:         abs(t.Amount),
+		Currency:       currency,
+		Balance:        t.Balance,
+		IdempotencyKey: t.IdempotencyKey,
+		Reference:      t.Reference,
+		Description:    t.Description,
+		Metadata:       t.Metadata,
+		Tags:           t.Tags,
+		CreatedAt:      t.CreatedAt,
+	}
+}
+
+func abs(n int64) int64 {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,215 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Te8va/wallet/internal/domain"
+	appErrors "github.com/Te8va/wallet/internal/errors"
+	"github.com/Te8va/wallet/internal/middleware"
+	"github.com/Te8va/wallet/internal/openapi"
+)
+
+func TestCreateTransactionHandler(t *testing.T) {
+	ctrl, mockWallet, handler := setupTestHandler(t)
+	defer ctrl.Finish()
+
+	doc, err := openapi.Load()
+	require.NoError(t, err)
+	validate, err := middleware.WithValidation(doc)
+	require.NoError(t, err)
+	h := validate(http.HandlerFunc(handler.CreateTransactionHandler))
+
+	createdAt := time.Date(2025, 4, 1, 10, 0, 0, 0, time.UTC)
+	posted := domain.Transaction{
+		ID:             42,
+		WalletID:       "123e4567-e89b-12d3-a456-426614174000",
+		OperationType:  domain.WITHDRAW,
+		Amount:         -500,
+		Balance:        1000,
+		IdempotencyKey: "order-1",
+		Metadata:       map[string]any{"order": "1"},
+		CreatedAt:      createdAt,
+	}
+	request := domain.TransactionRequest{
+		WalletID:       "123e4567-e89b-12d3-a456-426614174000",
+		OperationType:  domain.WITHDRAW,
+		Amount:         500,
+		Currency:       "RUB",
+		IdempotencyKey: "order-1",
+		Metadata:       map[string]any{"order": "1"},
+	}
+	response := `{"id":42,"walletId":"123e4567-e89b-12d3-a456-426614174000","operationType":"WITHDRAW","amount":500,"currency":"RUB","balance":1000,"idempotencyKey":"order-1","metadata":{"order":"1"},"createdAt":"2025-04-01T10:00:00Z"}`
+
+	testCases := []struct {
+		name         string
+		body         string
+		mockServ     func()
+		wantCode     int
+		wantReplayed string
+		wantBody     string
+	}{
+		{
+			name: "created",
+			body: `{"walletId":"123e4567-e89b-12d3-a456-426614174000","operationType":"WITHDRAW","amount":500,"currency":"RUB","idempotencyKey":"order-1","metadata":{"order":"1"}}`,
+			mockServ: func() {
+				mockWallet.EXPECT().ProcessTransaction(gomock.Any(), request).Return(domain.TransactionResult{Transaction: posted}, nil)
+			},
+			wantCode: http.StatusCreated,
+			wantBody: response,
+		},
+		{
+			name: "replayed",
+			body: `{"walletId":"123e4567-e89b-12d3-a456-426614174000","operationType":"WITHDRAW","amount":500,"currency":"RUB","idempotencyKey":"order-1","metadata":{"order":"1"}}`,
+			mockServ: func() {
+				mockWallet.EXPECT().ProcessTransaction(gomock.Any(), request).Return(domain.TransactionResult{Transaction: posted, Replayed: true}, nil)
+			},
+			wantCode:     http.StatusOK,
+			wantReplayed: "true",
+			wantBody:     response,
+		},
+		{
+			name: "idempotency key reused",
+			body: `{"walletId":"123e4567-e89b-12d3-a456-426614174000","operationType":"WITHDRAW","amount":500,"currency":"RUB","idempotencyKey":"order-1","metadata":{"order":"1"}}`,
+			mockServ: func() {
+				mockWallet.EXPECT().ProcessTransaction(gomock.Any(), request).Return(domain.TransactionResult{}, appErrors.ErrIdempotencyKeyReused)
+			},
+			wantCode: http.StatusConflict,
+			wantBody: `{"type":"urn:wallet:problem:IDEMPOTENCY_KEY_REUSED","title":"Idempotency key reused","status":409,"code":"IDEMPOTENCY_KEY_REUSED","detail":"idempotency key was used for a different operation","instance":"/api/v2/transactions"}`,
+		},
+		{
+			name:     "currency mismatch",
+			body:     `{"walletId":"123e4567-e89b-12d3-a456-426614174000","operationType":"DEPOSIT","amount":500,"currency":"USD"}`,
+			mockServ: func() {},
+			wantCode: http.StatusBadRequest,
+			wantBody: `{"type":"urn:wallet:problem:CURRENCY_MISMATCH","title":"Currency mismatch","status":400,"code":"CURRENCY_MISMATCH","detail":"currency is not supported by this wallet","instance":"/api/v2/transactions"}`,
+		},
+		{
+			name:     "invalid currency",
+			body:     `{"walletId":"123e4567-e89b-12d3-a456-426614174000","operationType":"DEPOSIT","amount":500,"currency":"rub"}`,
+			mockServ: func() {},
+			wantCode: http.StatusBadRequest,
+			wantBody: `{"type":"urn:wallet:problem:VALIDATION_FAILED","title":"Validation failed","status":400,"code":"VALIDATION_FAILED","detail":"Currency must be a three-letter ISO 4217 code","instance":"/api/v2/transactions","errors":[{"field":"currency","message":"Currency must be a three-letter ISO 4217 code"}]}`,
+		},
+		{
+			name:     "v1 wallet field is rejected",
+			body:     `{"valletId":"123e4567-e89b-12d3-a456-426614174000","operationType":"DEPOSIT","amount":500}`,
+			mockServ: func() {},
+			wantCode: http.StatusBadRequest,
+			wantBody: `{"type":"urn:wallet:problem:VALIDATION_FAILED","title":"Validation failed","status":400,"code":"VALIDATION_FAILED","detail":"Wallet ID is required","instance":"/api/v2/transactions","errors":[{"field":"walletId","message":"Wallet ID is required"}]}`,
+		},
+		{
+			name:     "too many tags",
+			body:     `{"walletId":"123e4567-e89b-12d3-a456-426614174000","operationType":"DEPOSIT","amount":500,"tags":[` + strings.Repeat(`"t",`, 20) + `"t"]}`,
+			mockServ: func() {},
+			wantCode: http.StatusBadRequest,
+			wantBody: `{"type":"urn:wallet:problem:VALIDATION_FAILED","title":"Validation failed","status":400,"code":"VALIDATION_FAILED","detail":"At most 20 tags are allowed","instance":"/api/v2/transactions","errors":[{"field":"tags","message":"At most 20 tags are allowed"}]}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/v2/transactions", bytes.NewReader([]byte(tc.body)))
+			req.Header.Set("Content-Type", "application/json")
+
+			tc.mockServ()
+
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, req)
+
+			require.Equal(t, tc.wantCode, w.Code)
+			require.Equal(t, tc.wantReplayed, w.Header().Get(IdempotentReplayedHeader))
+			if tc.wantCode == http.StatusCreated || tc.wantCode == http.StatusOK {
+				require.Equal(t, "/api/v2/transactions/42", w.Header().Get("Location"))
+			}
+			require.JSONEq(t, tc.wantBody, w.Body.String())
+		})
+	}
+}
+
+func TestGetWalletHandler(t *testing.T) {
+	ctrl, mockWallet, handler := setupTestHandler(t)
+	defer ctrl.Finish()
+
+	testCases := []struct {
+		name     string
+		query    string
+		mockServ func()
+		wantCode int
+		wantBody string
+	}{
+		{
+			name: "successful",
+			mockServ: func() {
+				mockWallet.EXPECT().GetBalance(gomock.Any(), "123e4567-e89b-12d3-a456-426614174000").Return(domain.PocketBalances{
+					WalletID: "123e4567-e89b-12d3-a456-426614174000", Balance: 1500, MainBalance: 1200,
+					Pockets: []domain.Pocket{{WalletID: "123e4567-e89b-12d3-a456-426614174000", Name: "vacation", Balance: 300}},
+				}, nil)
+			},
+			wantCode: http.StatusOK,
+			wantBody: `{"walletId":"123e4567-e89b-12d3-a456-426614174000","balance":1500,"mainBalance":1200,"pockets":[{"name":"vacation","balance":300}],"currency":"RUB"}`,
+		},
+		{
+			name: "no pockets",
+			mockServ: func() {
+				mockWallet.EXPECT().GetBalance(gomock.Any(), "123e4567-e89b-12d3-a456-426614174000").Return(domain.PocketBalances{
+					WalletID: "123e4567-e89b-12d3-a456-426614174000", Balance: 1500, MainBalance: 1500, Pockets: []domain.Pocket{},
+				}, nil)
+			},
+			wantCode: http.StatusOK,
+			wantBody: `{"walletId":"123e4567-e89b-12d3-a456-426614174000","balance":1500,"mainBalance":1500,"pockets":[],"currency":"RUB"}`,
+		},
+		{
+			name:  "balance as of",
+			query: "?asOf=2025-03-31T23:59:00Z",
+			mockServ: func() {
+				mockWallet.EXPECT().GetBalanceAsOf(gomock.Any(), "123e4567-e89b-12d3-a456-426614174000",
+					time.Date(2025, 3, 31, 23, 59, 0, 0, time.UTC)).Return(int64(700), nil)
+			},
+			wantCode: http.StatusOK,
+			wantBody: `{"walletId":"123e4567-e89b-12d3-a456-426614174000","balance":700,"currency":"RUB","asOf":"2025-03-31T23:59:00Z"}`,
+		},
+		{
+			name:     "invalid asOf",
+			query:    "?asOf=2025-03-31",
+			mockServ: func() {},
+			wantCode: http.StatusBadRequest,
+			wantBody: `{"type":"urn:wallet:problem:VALIDATION_FAILED","title":"Validation failed","status":400,"code":"VALIDATION_FAILED","detail":"asOf must be an RFC 3339 time","instance":"/api/v2/wallets/123e4567-e89b-12d3-a456-426614174000","errors":[{"field":"asOf","message":"asOf must be an RFC 3339 time"}]}`,
+		},
+		{
+			name: "wallet not found",
+			mockServ: func() {
+				mockWallet.EXPECT().GetBalance(gomock.Any(), "123e4567-e89b-12d3-a456-426614174000").Return(domain.PocketBalances{}, appErrors.ErrWalletNotFound)
+			},
+			wantCode: http.StatusNotFound,
+			wantBody: `{"type":"urn:wallet:problem:WALLET_NOT_FOUND","title":"Wallet not found","status":404,"code":"WALLET_NOT_FOUND","detail":"wallet not found","instance":"/api/v2/wallets/123e4567-e89b-12d3-a456-426614174000"}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v2/wallets/123e4567-e89b-12d3-a456-426614174000"+tc.query, nil)
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("walletId", "123e4567-e89b-12d3-a456-426614174000")
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+			tc.mockServ()
+
+			w := httptest.NewRecorder()
+			handler.GetWalletHandler(w, req)
+
+			require.Equal(t, tc.wantCode, w.Code)
+			require.JSONEq(t, tc.wantBody, w.Body.String())
+		})
+	}
+}
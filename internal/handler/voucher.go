@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/Te8va/wallet/internal/domain"
+	"github.com/Te8va/wallet/internal/problem"
+)
+
+const maxVoucherCodeLength = 64
+
+//go:generate mockgen -source=voucher.go -destination=mocks/voucher_mock.gen.go -package=mocks
+type Voucher interface {
+	RedeemVoucher(ctx context.Context, req domain.VoucherRedeemRequest) (domain.Transaction, error)
+}
+
+type VoucherHandler struct {
+	srv Voucher
+}
+
+func NewVoucherHandler(srv Voucher) *VoucherHandler {
+	return &VoucherHandler{srv: srv}
+}
+
+// RedeemVoucherHandler credits the value of a voucher to the wallet and
+// returns the posted credit.
+func (h *VoucherHandler) RedeemVoucherHandler(w http.ResponseWriter, r *http.Request) {
+	var req domain.VoucherRedeemRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		sendErrorResponse(w, r, problem.New(problem.CodeMalformedRequest, "Invalid request body"))
+		return
+	}
+
+	switch {
+	case req.Code == "":
+		sendErrorResponse(w, r, problem.Invalid("code", "Code is required"))
+		return
+	case len(req.Code) > maxVoucherCodeLength:
+		sendErrorResponse(w, r, problem.Invalid("code", "Code must be at most 64 characters"))
+		return
+	case req.WalletID == "":
+		sendErrorResponse(w, r, problem.Invalid("walletId", "Wallet ID is required"))
+		return
+	case len(req.WalletID) > maxWalletIDLength:
+		sendErrorResponse(w, r, problem.Invalid("walletId", "Wallet ID must be at most 36 characters"))
+		return
+	}
+
+	t, err := h.srv.RedeemVoucher(r.Context(), req)
+	if err != nil {
+		sendError(w, r, err)
+		return
+	}
+
+	sendJSONResponse(w, t, http.StatusCreated)
+}
@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Te8va/wallet/internal/domain"
+	appErrors "github.com/Te8va/wallet/internal/errors"
+	"github.com/Te8va/wallet/internal/handler/mocks"
+)
+
+func TestRedeemVoucherHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockVoucher := mocks.NewMockVoucher(ctrl)
+	handler := NewVoucherHandler(mockVoucher)
+
+	createdAt := time.Date(2025, 4, 1, 10, 0, 0, 0, time.UTC)
+	request := domain.VoucherRedeemRequest{Code: "ABCD-2345-EFGH-6789", WalletID: "wallet"}
+
+	testCases := []struct {
+		name     string
+		body     string
+		mockServ func()
+		wantCode int
+		wantBody string
+	}{
+		{
+			name: "successful",
+			body: `{"code":"ABCD-2345-EFGH-6789","walletId":"wallet"}`,
+			mockServ: func() {
+				mockVoucher.EXPECT().RedeemVoucher(gomock.Any(), request).Return(domain.Transaction{
+					ID: 12, WalletID: "wallet", OperationType: domain.VOUCHER_REDEEM, Amount: 500, Balance: 500,
+					ParentID: 11, Reference: "voucher-batch:3", CreatedAt: createdAt,
+				}, nil)
+			},
+			wantCode: http.StatusCreated,
+			wantBody: `{"id":12,"wallet_id":"wallet","operation_type":"VOUCHER_REDEEM","amount":500,"balance":500,"parent_id":11,"reference":"voucher-batch:3","created_at":"2025-04-01T10:00:00Z"}`,
+		},
+		{
+			name:     "missing wallet",
+			body:     `{"code":"ABCD-2345-EFGH-6789"}`,
+			mockServ: func() {},
+			wantCode: http.StatusBadRequest,
+			wantBody: `{"type":"urn:wallet:problem:VALIDATION_FAILED","title":"Validation failed","status":400,"code":"VALIDATION_FAILED","detail":"Wallet ID is required","instance":"/api/v1/vouchers/redeem","errors":[{"field":"walletId","message":"Wallet ID is required"}]}`,
+		},
+		{
+			name: "already redeemed",
+			body: `{"code":"ABCD-2345-EFGH-6789","walletId":"wallet"}`,
+			mockServ: func() {
+				mockVoucher.EXPECT().RedeemVoucher(gomock.Any(), request).Return(domain.Transaction{}, appErrors.ErrVoucherRedeemed)
+			},
+			wantCode: http.StatusConflict,
+			wantBody: `{"type":"urn:wallet:problem:VOUCHER_REDEEMED","title":"Voucher already redeemed","status":409,"code":"VOUCHER_REDEEMED","detail":"voucher has already been redeemed","instance":"/api/v1/vouchers/redeem"}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/vouchers/redeem", bytes.NewBufferString(tc.body))
+
+			tc.mockServ()
+
+			w := httptest.NewRecorder()
+			handler.RedeemVoucherHandler(w, req)
+
+			require.Equal(t, tc.wantCode, w.Code)
+			require.JSONEq(t, tc.wantBody, w.Body.String())
+		})
+	}
+}
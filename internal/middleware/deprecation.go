@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Deprecated marks every response as coming from a deprecated API version
+// (the Deprecation header), points to its successor with a Link header and,
+// when sunset is set, announces when the version is removed (RFC 8594).
+func Deprecated(successor string, sunset time.Time) func(http.Handler) http.Handler {
+	link := fmt.Sprintf(`<%s>; rel="successor-version"`, successor)
+
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			header := w.Header()
+			header.Set("Deprecation", "true")
+			header.Add("Link", link)
+			if !sunset.IsZero() {
+				header.Set("Sunset", sunset.UTC().Format(http.TimeFormat))
+			}
+
+			h.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeprecated(t *testing.T) {
+	h := Deprecated("/api/v2", time.Date(2026, 6, 30, 0, 0, 0, 0, time.UTC))(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}))
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/wallets/a", nil))
+
+	require.Equal(t, http.StatusNotFound, w.Code)
+	require.Equal(t, "true", w.Header().Get("Deprecation"))
+	require.Equal(t, `</api/v2>; rel="successor-version"`, w.Header().Get("Link"))
+	require.Equal(t, "Tue, 30 Jun 2026 00:00:00 GMT", w.Header().Get("Sunset"))
+
+	w = httptest.NewRecorder()
+	Deprecated("/api/v2", time.Time{})(http.NotFoundHandler()).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	require.Empty(t, w.Header().Get("Sunset"))
+}
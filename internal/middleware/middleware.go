@@ -1,10 +1,15 @@
 package middleware
 
 import (
+	"net"
 	"net/http"
 	"time"
 
+	"github.com/go-chi/chi/v5"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
+
+	"github.com/Te8va/wallet/internal/logging"
 )
 
 var Log *zap.Logger = zap.NewNop()
@@ -49,17 +54,18 @@ func (r *loggingResponseWriter) WriteHeader(statusCode int) {
 	r.responseData.status = statusCode
 }
 
+// WithLogging writes a single access log line per request once the handler
+// has finished, and gives the handler a logger tagged with the request id
+// and trace id through the request context.
 func WithLogging(h http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		start := time.Now()
 
-		duration := time.Since(start)
-
-		Log.Info("Incoming request",
-			zap.String("uri", r.RequestURI),
-			zap.String("method", r.Method),
-			zap.Duration("duration", duration),
-		)
+		fields := []zap.Field{zap.String("request_id", logging.RequestID(r.Context()))}
+		if sc := trace.SpanContextFromContext(r.Context()); sc.IsValid() {
+			fields = append(fields, zap.String("trace_id", sc.TraceID().String()))
+		}
+		reqLog := Log.With(fields...)
 
 		responseData := &responseData{
 			status: http.StatusOK,
@@ -69,11 +75,31 @@ func WithLogging(h http.Handler) http.Handler {
 			ResponseWriter: w,
 			responseData:   responseData,
 		}
-		h.ServeHTTP(&lw, r)
+		h.ServeHTTP(&lw, r.WithContext(logging.WithLogger(r.Context(), reqLog)))
+
+		route := unmatchedRoute
+		if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+			route = rctx.RoutePattern()
+		}
 
-		Log.Info("HTTP request",
+		reqLog.Info("HTTP request",
+			zap.String("method", r.Method),
+			zap.String("route", route),
+			zap.String("uri", r.RequestURI),
 			zap.Int("status", responseData.status),
 			zap.Int("size", responseData.size),
+			zap.Duration("duration", time.Since(start)),
+			zap.String("remote_ip", remoteIP(r)),
 		)
 	})
 }
+
+// remoteIP returns the address of the direct peer. Forwarding headers are
+// not trusted, since any client can set them.
+func remoteIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -0,0 +1,87 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/Te8va/wallet/internal/logging"
+)
+
+func TestWithRequestID(t *testing.T) {
+	testCases := []struct {
+		name     string
+		header   string
+		wantSame bool
+	}{
+		{name: "accepted from header", header: "req-42", wantSame: true},
+		{name: "generated when missing", header: ""},
+		{name: "generated when invalid", header: "bad id\n"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var got string
+			h := WithRequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = logging.RequestID(r.Context())
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tc.header != "" {
+				req.Header.Set(RequestIDHeader, tc.header)
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, req)
+
+			require.NotEmpty(t, got)
+			require.Equal(t, got, w.Header().Get(RequestIDHeader))
+			if tc.wantSame {
+				require.Equal(t, tc.header, got)
+			} else {
+				require.Len(t, got, 32)
+			}
+		})
+	}
+}
+
+func TestWithLogging(t *testing.T) {
+	core, logs := observer.New(zap.InfoLevel)
+	prev := Log
+	Log = zap.New(core)
+	t.Cleanup(func() { Log = prev })
+
+	r := chi.NewRouter()
+	r.Use(WithRequestID)
+	r.Use(WithLogging)
+	r.Get("/api/v1/wallets/{walletId}", func(w http.ResponseWriter, r *http.Request) {
+		logging.FromContext(r.Context()).Info("from handler")
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("not found"))
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/wallets/123", nil)
+	req.Header.Set(RequestIDHeader, "req-42")
+	req.RemoteAddr = "192.0.2.1:53211"
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	entries := logs.AllUntimed()
+	require.Len(t, entries, 2)
+
+	require.Equal(t, "from handler", entries[0].Message)
+	require.Equal(t, "req-42", entries[0].ContextMap()["request_id"])
+
+	access := entries[1].ContextMap()
+	require.Equal(t, "HTTP request", entries[1].Message)
+	require.Equal(t, "req-42", access["request_id"])
+	require.Equal(t, http.MethodGet, access["method"])
+	require.Equal(t, "/api/v1/wallets/{walletId}", access["route"])
+	require.EqualValues(t, http.StatusNotFound, access["status"])
+	require.EqualValues(t, len("not found"), access["size"])
+	require.Equal(t, "192.0.2.1", access["remote_ip"])
+	require.Contains(t, access, "duration")
+}
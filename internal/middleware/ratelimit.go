@@ -0,0 +1,223 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+
+	"github.com/Te8va/wallet/internal/domain"
+	"github.com/Te8va/wallet/internal/logging"
+	"github.com/Te8va/wallet/internal/problem"
+	"github.com/Te8va/wallet/internal/ratelimit"
+)
+
+const APIKeyHeader = "X-API-Key"
+
+// maxWalletPeek bounds the part of a write body read to find its wallet.
+// Larger bodies, like batches, are not keyed by wallet.
+const maxWalletPeek = 64 << 10
+
+// bodyWalletFields are the body fields that name the wallet a write is made
+// for, in the order they are looked up: the wallet of a v1 operation, of a
+// v2 transaction, split or voucher redemption, the payee asking for a
+// payment, the source of a schedule and the buyer of an escrow.
+var bodyWalletFields = []string{"valletId", "walletId", "payeeWalletId", "fromWalletId", "buyerWalletId"}
+
+// RateLimitKey names what a client is identified by. Every configured key
+// that is present in a request gets its own bucket, and the request has to
+// pass all of them.
+type RateLimitKey string
+
+const (
+	KeyAPIKey RateLimitKey = "api_key"
+	KeyIP     RateLimitKey = "ip"
+	KeyWallet RateLimitKey = "wallet"
+)
+
+// ParseRateLimitKeys parses a comma-separated list of keys.
+func ParseRateLimitKeys(s string) ([]RateLimitKey, error) {
+	var keys []RateLimitKey
+	for _, part := range strings.Split(s, ",") {
+		key := RateLimitKey(strings.TrimSpace(part))
+		switch key {
+		case "":
+			continue
+		case KeyAPIKey, KeyIP, KeyWallet:
+			keys = append(keys, key)
+		default:
+			return nil, fmt.Errorf("unknown rate limit key: %s", key)
+		}
+	}
+	return keys, nil
+}
+
+type RateLimitOptions struct {
+	Store ratelimit.Store
+	Keys  []RateLimitKey
+	// Read applies to GET, HEAD and OPTIONS requests, Write to the rest.
+	Read  domain.RateLimit
+	Write domain.RateLimit
+}
+
+// WithRateLimit enforces token bucket limits per client. The wallet key is
+// read from the walletId route parameter, so the middleware has to run after
+// routing, e.g. in a chi group; writes that name the wallet in their JSON
+// body are keyed by that wallet. Buckets are taken in the order of the keys
+// and the first one that denies the request stops the rest from being
+// drained. If the store fails the request is let through.
+func WithRateLimit(opts RateLimitOptions) func(http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			class, limit := "write", opts.Write
+			switch r.Method {
+			case http.MethodGet, http.MethodHead, http.MethodOptions:
+				class, limit = "read", opts.Read
+			}
+
+			var (
+				res   domain.RateLimitResult
+				found bool
+			)
+			for _, key := range opts.Keys {
+				id := rateLimitID(r, key)
+				if id == "" {
+					continue
+				}
+
+				kr, err := opts.Store.Take(r.Context(), class+":"+string(key)+":"+id, limit)
+				if err != nil {
+					logging.FromContext(r.Context()).Error("Failed to check rate limit", zap.Error(err))
+					h.ServeHTTP(w, r)
+					return
+				}
+
+				if !found || stricter(kr, res) {
+					res = kr
+				}
+				found = true
+
+				if !kr.Allowed {
+					break
+				}
+			}
+
+			if !found {
+				h.ServeHTTP(w, r)
+				return
+			}
+
+			header := w.Header()
+			header.Set("RateLimit-Limit", strconv.Itoa(limit.Burst))
+			header.Set("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+			header.Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(res.Reset)))
+			header.Set("RateLimit-Policy", fmt.Sprintf("%d;w=%d", limit.Burst, window(limit)))
+
+			if !res.Allowed {
+				retryAfter := max(ceilSeconds(res.RetryAfter), 1)
+				header.Set("Retry-After", strconv.Itoa(retryAfter))
+				problem.Write(w, r, problem.New(problem.CodeRateLimited, fmt.Sprintf("Rate limit exceeded, retry in %d s", retryAfter)))
+				return
+			}
+
+			h.ServeHTTP(w, r)
+		})
+	}
+}
+
+// rateLimitID returns the client identifier for key, or "" when the request
+// does not carry it. API keys are hashed so they are not stored in clear.
+func rateLimitID(r *http.Request, key RateLimitKey) string {
+	switch key {
+	case KeyAPIKey:
+		apiKey := r.Header.Get(APIKeyHeader)
+		if apiKey == "" {
+			return ""
+		}
+		sum := sha256.Sum256([]byte(apiKey))
+		return hex.EncodeToString(sum[:])
+	case KeyIP:
+		return remoteIP(r)
+	case KeyWallet:
+		if walletID := chi.URLParam(r, "walletId"); walletID != "" {
+			return walletID
+		}
+		return bodyWalletID(r)
+	}
+	return ""
+}
+
+// bodyWalletID returns the wallet named in the JSON body of a write, or ""
+// when there is none. The body is put back for the handler.
+func bodyWalletID(r *http.Request) string {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return ""
+	}
+	if r.Body == nil || r.Body == http.NoBody {
+		return ""
+	}
+	if contentType := r.Header.Get("Content-Type"); contentType != "" {
+		if mediaType, _, err := mime.ParseMediaType(contentType); err != nil || mediaType != "application/json" {
+			return ""
+		}
+	}
+
+	peeked, err := io.ReadAll(io.LimitReader(r.Body, maxWalletPeek+1))
+	r.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(peeked), r.Body), r.Body}
+	if err != nil || len(peeked) > maxWalletPeek {
+		return ""
+	}
+
+	var body map[string]json.RawMessage
+	if json.Unmarshal(peeked, &body) != nil {
+		return ""
+	}
+
+	for _, field := range bodyWalletFields {
+		var walletID string
+		if json.Unmarshal(body[field], &walletID) == nil && walletID != "" {
+			return walletID
+		}
+	}
+
+	return ""
+}
+
+// stricter reports whether a is the more restrictive bucket state of the two,
+// the one the response headers should describe.
+func stricter(a, b domain.RateLimitResult) bool {
+	if a.Allowed != b.Allowed {
+		return !a.Allowed
+	}
+	if !a.Allowed {
+		return a.RetryAfter > b.RetryAfter
+	}
+	return a.Remaining < b.Remaining
+}
+
+// window is the time in seconds an empty bucket takes to refill.
+func window(limit domain.RateLimit) int {
+	if limit.Rate <= 0 {
+		return 0
+	}
+	return int(math.Ceil(float64(limit.Burst) / limit.Rate))
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,157 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Te8va/wallet/internal/domain"
+	"github.com/Te8va/wallet/internal/problem"
+	"github.com/Te8va/wallet/internal/ratelimit"
+)
+
+func TestWithRateLimit(t *testing.T) {
+	r := chi.NewRouter()
+	r.Use(WithRateLimit(RateLimitOptions{
+		Store: ratelimit.NewMemoryStore(),
+		Keys:  []RateLimitKey{KeyAPIKey, KeyIP},
+		Read:  domain.RateLimit{Rate: 1, Burst: 2},
+		Write: domain.RateLimit{Rate: 0.5, Burst: 1},
+	}))
+	r.Get("/wallets", func(w http.ResponseWriter, r *http.Request) {})
+	r.Post("/wallets", func(w http.ResponseWriter, r *http.Request) {})
+
+	do := func(method, apiKey string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/wallets", nil)
+		if apiKey != "" {
+			req.Header.Set(APIKeyHeader, apiKey)
+		}
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := do(http.MethodPost, "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, "1", rec.Header().Get("RateLimit-Limit"))
+	require.Equal(t, "0", rec.Header().Get("RateLimit-Remaining"))
+	require.Equal(t, "1;w=2", rec.Header().Get("RateLimit-Policy"))
+
+	rec = do(http.MethodPost, "")
+	require.Equal(t, http.StatusTooManyRequests, rec.Code)
+	require.Equal(t, "2", rec.Header().Get("Retry-After"))
+	require.Equal(t, problem.ContentType, rec.Header().Get("Content-Type"))
+	require.JSONEq(t, `{"type":"urn:wallet:problem:RATE_LIMITED","title":"Too many requests","status":429,"code":"RATE_LIMITED","detail":"Rate limit exceeded, retry in 2 s","instance":"/wallets"}`, rec.Body.String())
+
+	// Reads have their own bucket.
+	rec = do(http.MethodGet, "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, "1", rec.Header().Get("RateLimit-Remaining"))
+
+	// The client IP is still exhausted for writes whatever API key is sent.
+	rec = do(http.MethodPost, "key")
+	require.Equal(t, http.StatusTooManyRequests, rec.Code)
+}
+
+func TestWithRateLimitWalletKey(t *testing.T) {
+	r := chi.NewRouter()
+	r.Group(func(r chi.Router) {
+		r.Use(WithRateLimit(RateLimitOptions{
+			Store: ratelimit.NewMemoryStore(),
+			Keys:  []RateLimitKey{KeyWallet},
+			Read:  domain.RateLimit{Rate: 1, Burst: 1},
+		}))
+		r.Get("/wallets/{walletId}", func(w http.ResponseWriter, r *http.Request) {})
+	})
+
+	for _, tc := range []struct {
+		path string
+		code int
+	}{
+		{"/wallets/a", http.StatusOK},
+		{"/wallets/a", http.StatusTooManyRequests},
+		{"/wallets/b", http.StatusOK},
+	} {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tc.path, nil))
+		require.Equal(t, tc.code, rec.Code, tc.path)
+	}
+}
+
+func TestWithRateLimitBodyWallet(t *testing.T) {
+	r := chi.NewRouter()
+	r.Group(func(r chi.Router) {
+		r.Use(WithRateLimit(RateLimitOptions{
+			Store: ratelimit.NewMemoryStore(),
+			Keys:  []RateLimitKey{KeyWallet},
+			Write: domain.RateLimit{Rate: 1, Burst: 1},
+		}))
+		r.Post("/transactions", func(w http.ResponseWriter, r *http.Request) {
+			_, _ = io.Copy(w, r.Body)
+		})
+	})
+
+	for _, tc := range []struct {
+		body string
+		code int
+	}{
+		{`{"walletId":"a","amount":100}`, http.StatusOK},
+		{`{"walletId":"a","amount":200}`, http.StatusTooManyRequests},
+		{`{"valletId":"b","amount":100}`, http.StatusOK},
+		{`{"walletId":"c","amount":100}`, http.StatusOK},
+	} {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/transactions", strings.NewReader(tc.body))
+		req.Header.Set("Content-Type", "application/json")
+		r.ServeHTTP(rec, req)
+		require.Equal(t, tc.code, rec.Code, tc.body)
+		if tc.code == http.StatusOK {
+			require.Equal(t, tc.body, rec.Body.String())
+		}
+	}
+}
+
+func TestWithRateLimitDeniedKeepsOtherBuckets(t *testing.T) {
+	r := chi.NewRouter()
+	r.Use(WithRateLimit(RateLimitOptions{
+		Store: ratelimit.NewMemoryStore(),
+		Keys:  []RateLimitKey{KeyAPIKey, KeyIP},
+		Write: domain.RateLimit{Rate: 0.001, Burst: 2},
+	}))
+	r.Post("/wallets", func(w http.ResponseWriter, r *http.Request) {})
+
+	for _, tc := range []struct {
+		apiKey string
+		ip     string
+		code   int
+	}{
+		{"a", "192.0.2.1", http.StatusOK},
+		{"a", "192.0.2.2", http.StatusOK},
+		// The API key is exhausted, so the address is not charged.
+		{"a", "192.0.2.3", http.StatusTooManyRequests},
+		{"b", "192.0.2.3", http.StatusOK},
+		{"c", "192.0.2.3", http.StatusOK},
+		{"d", "192.0.2.3", http.StatusTooManyRequests},
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/wallets", nil)
+		req.Header.Set(APIKeyHeader, tc.apiKey)
+		req.RemoteAddr = tc.ip + ":1234"
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		require.Equal(t, tc.code, rec.Code, tc.apiKey+" "+tc.ip)
+	}
+}
+
+func TestParseRateLimitKeys(t *testing.T) {
+	keys, err := ParseRateLimitKeys("api_key, wallet,")
+	require.NoError(t, err)
+	require.Equal(t, []RateLimitKey{KeyAPIKey, KeyWallet}, keys)
+
+	_, err = ParseRateLimitKeys("user")
+	require.Error(t, err)
+}
@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers/legacy"
+
+	"github.com/Te8va/wallet/internal/openapi"
+	"github.com/Te8va/wallet/internal/problem"
+)
+
+// WithValidation rejects requests that do not match the operation described
+// for them in doc with a 400 problem, or a 415 one when a body that can be
+// sent in several formats is in none of them. Requests to paths or methods
+// the document does not describe are passed through unchecked.
+func WithValidation(doc *openapi3.T) (func(http.Handler) http.Handler, error) {
+	router, err := legacy.NewRouter(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OpenAPI router: %w", err)
+	}
+
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			route, pathParams, err := router.FindRoute(r)
+			if err != nil {
+				h.ServeHTTP(w, r)
+				return
+			}
+
+			err = openapi3filter.ValidateRequest(r.Context(), &openapi3filter.RequestValidationInput{
+				Request:    r,
+				PathParams: pathParams,
+				Route:      route,
+			})
+			if err != nil {
+				field, message := openapi.Field(err), openapi.Message(err)
+				if openapi.UnsupportedMediaType(err) {
+					problem.Write(w, r, problem.New(problem.CodeUnsupportedMediaType, message))
+					return
+				}
+				if field == "" {
+					problem.Write(w, r, problem.New(problem.CodeMalformedRequest, message))
+					return
+				}
+				problem.Write(w, r, problem.Invalid(field, message))
+				return
+			}
+
+			h.ServeHTTP(w, r)
+		})
+	}, nil
+}
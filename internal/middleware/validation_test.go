@@ -0,0 +1,92 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/Te8va/wallet/internal/openapi"
+)
+
+func TestWithValidation(t *testing.T) {
+	doc, err := openapi.Load()
+	require.NoError(t, err)
+	validate, err := WithValidation(doc)
+	require.NoError(t, err)
+
+	h := validate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	testCases := []struct {
+		name        string
+		method      string
+		path        string
+		contentType string
+		body        string
+		wantCode    int
+		wantBody    string
+	}{
+		{
+			name:     "valid",
+			path:     "/api/v1/wallets/123e4567-e89b-12d3-a456-426614174000?as_of=2025-03-31T23:59:00Z",
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "invalid as_of",
+			path:     "/api/v1/wallets/123e4567-e89b-12d3-a456-426614174000?as_of=yesterday",
+			wantCode: http.StatusBadRequest,
+			wantBody: `{"type":"urn:wallet:problem:VALIDATION_FAILED","title":"Validation failed","status":400,"code":"VALIDATION_FAILED","detail":"as_of must be an RFC 3339 time","instance":"/api/v1/wallets/123e4567-e89b-12d3-a456-426614174000","errors":[{"field":"as_of","message":"as_of must be an RFC 3339 time"}]}`,
+		},
+		{
+			name:     "missing required parameter",
+			path:     "/api/v1/wallets/a/statement?to=2025-03-31",
+			wantCode: http.StatusBadRequest,
+			wantBody: `{"type":"urn:wallet:problem:VALIDATION_FAILED","title":"Validation failed","status":400,"code":"VALIDATION_FAILED","detail":"Period must be set with from and to as YYYY-MM-DD or RFC 3339 time, from before to","instance":"/api/v1/wallets/a/statement","errors":[{"field":"from","message":"Period must be set with from and to as YYYY-MM-DD or RFC 3339 time, from before to"}]}`,
+		},
+		{
+			name:        "batch body not validated",
+			method:      http.MethodPost,
+			path:        "/api/v1/batches",
+			contentType: "text/csv",
+			body:        "walletId,operationType,amount\na,DEPOSIT,-1\n",
+			wantCode:    http.StatusOK,
+		},
+		{
+			name:        "unsupported content type",
+			method:      http.MethodPost,
+			path:        "/api/v1/batches",
+			contentType: "application/xml",
+			body:        "<batch/>",
+			wantCode:    http.StatusUnsupportedMediaType,
+			wantBody:    `{"type":"urn:wallet:problem:UNSUPPORTED_MEDIA_TYPE","title":"Unsupported media type","status":415,"code":"UNSUPPORTED_MEDIA_TYPE","detail":"Content type must be application/json, application/x-ndjson, text/csv or multipart/form-data","instance":"/api/v1/batches"}`,
+		},
+		{
+			name:     "path not described",
+			path:     "/healthz",
+			wantCode: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			method := tc.method
+			if method == "" {
+				method = http.MethodGet
+			}
+			req := httptest.NewRequest(method, tc.path, strings.NewReader(tc.body))
+			if tc.contentType != "" {
+				req.Header.Set("Content-Type", tc.contentType)
+			}
+
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, req)
+
+			require.Equal(t, tc.wantCode, w.Code)
+			if tc.wantBody != "" {
+				require.JSONEq(t, tc.wantBody, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,189 @@
+// Package openapi holds the OpenAPI 3 document of the HTTP API. The document
+// is the single source of the request validation rules: the validation
+// middleware checks requests against it, and client-facing messages are
+// taken from the x-error-messages extension of the failing schema.
+package openapi
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+)
+
+// messagesExtension maps a schema keyword, e.g. "maxLength" or "required", to
+// the message returned when a value fails it. On a request body it maps
+// "contentType" to the message for a body of a content type not described.
+const messagesExtension = "x-error-messages"
+
+// invalidContentType is the reason openapi3filter gives for a request body of
+// a content type the operation does not describe.
+const invalidContentType = "header Content-Type has unexpected value"
+
+//go:embed openapi.json
+var spec []byte
+
+var load = sync.OnceValues(func() (*openapi3.T, error) {
+	doc, err := openapi3.NewLoader().LoadFromData(spec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load OpenAPI document: %w", err)
+	}
+
+	if err = doc.Validate(context.Background()); err != nil {
+		return nil, fmt.Errorf("invalid OpenAPI document: %w", err)
+	}
+
+	return doc, nil
+})
+
+// Load returns the parsed document. It is parsed once and shared, so callers
+// must not modify it.
+func Load() (*openapi3.T, error) {
+	return load()
+}
+
+// Handler serves the document.
+func Handler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(spec)
+}
+
+// ValidateSchema checks v against the named component schema.
+func ValidateSchema(name string, v any) error {
+	doc, err := Load()
+	if err != nil {
+		return err
+	}
+
+	ref := doc.Components.Schemas[name]
+	if ref == nil || ref.Value == nil {
+		return fmt.Errorf("unknown schema: %s", name)
+	}
+
+	// The schema validates JSON values, so v is converted to one first.
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to encode value: %w", err)
+	}
+	var value any
+	if err = json.Unmarshal(b, &value); err != nil {
+		return fmt.Errorf("failed to decode value: %w", err)
+	}
+
+	return ref.Value.VisitJSON(value)
+}
+
+// Message returns the client-facing message for a validation error.
+func Message(err error) string {
+	var schemaErr *openapi3.SchemaError
+	if errors.As(err, &schemaErr) {
+		if msg := schemaMessage(schemaErr); msg != "" {
+			return msg
+		}
+	}
+
+	var reqErr *openapi3filter.RequestError
+	if errors.As(err, &reqErr) {
+		if reqErr.Parameter != nil {
+			if msg := parameterMessage(reqErr); msg != "" {
+				return msg
+			}
+			return fmt.Sprintf("Invalid %s parameter %s", reqErr.Parameter.In, reqErr.Parameter.Name)
+		}
+		if UnsupportedMediaType(err) {
+			return extensionMessage(reqErr.RequestBody.Extensions, "contentType")
+		}
+		return "Invalid request body"
+	}
+
+	if schemaErr != nil {
+		return fmt.Sprintf("Invalid %s: %s", strings.Join(schemaErr.JSONPointer(), "."), schemaErr.Reason)
+	}
+
+	return "Invalid request"
+}
+
+// UnsupportedMediaType reports whether a validation error is about the
+// content type of a body that can be sent in several formats. Such request
+// bodies name the formats in a contentType message; for the others a body of
+// another type is reported as malformed.
+func UnsupportedMediaType(err error) bool {
+	var reqErr *openapi3filter.RequestError
+	if !errors.As(err, &reqErr) || reqErr.RequestBody == nil ||
+		!strings.HasPrefix(reqErr.Reason, invalidContentType) {
+		return false
+	}
+
+	return extensionMessage(reqErr.RequestBody.Extensions, "contentType") != ""
+}
+
+// Field names the body field or parameter a validation error is about, or
+// returns "" when it is about the request as a whole.
+func Field(err error) string {
+	var schemaErr *openapi3.SchemaError
+	if errors.As(err, &schemaErr) && schemaErr.SchemaField != "type" {
+		if path := schemaErr.JSONPointer(); len(path) > 0 {
+			return strings.Join(path, ".")
+		}
+	}
+
+	var reqErr *openapi3filter.RequestError
+	if errors.As(err, &reqErr) && reqErr.Parameter != nil {
+		return reqErr.Parameter.Name
+	}
+
+	return ""
+}
+
+// schemaMessage looks the message up in the schema that failed. A missing
+// property is reported on the enclosing object, so its message is taken from
+// the property schema instead.
+func schemaMessage(err *openapi3.SchemaError) string {
+	schema := err.Schema
+	if err.SchemaField == "required" && schema != nil {
+		path := err.JSONPointer()
+		if len(path) == 0 {
+			return ""
+		}
+		prop := schema.Properties[path[len(path)-1]]
+		if prop == nil {
+			return ""
+		}
+		schema = prop.Value
+	}
+
+	if schema == nil {
+		return ""
+	}
+
+	return extensionMessage(schema.Extensions, err.SchemaField)
+}
+
+// parameterMessage looks the message for a missing required parameter up in
+// the parameter schema.
+func parameterMessage(err *openapi3filter.RequestError) string {
+	if !errors.Is(err.Err, openapi3filter.ErrInvalidRequired) {
+		return ""
+	}
+
+	schema := err.Parameter.Schema
+	if schema == nil || schema.Value == nil {
+		return ""
+	}
+
+	return extensionMessage(schema.Value.Extensions, "required")
+}
+
+func extensionMessage(extensions map[string]any, keyword string) string {
+	messages, _ := extensions[messagesExtension].(map[string]any)
+	msg, _ := messages[keyword].(string)
+	return msg
+}
@@ -0,0 +1,116 @@
+package openapi
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/Te8va/wallet/internal/domain"
+)
+
+func TestValidateSchema(t *testing.T) {
+	testCases := []struct {
+		name    string
+		req     domain.WalletRequest
+		wantMsg string
+	}{
+		{
+			name: "valid",
+			req:  domain.WalletRequest{WalletID: "a", OperationType: domain.DEPOSIT, Amount: 1},
+		},
+		{
+			name:    "empty wallet ID",
+			req:     domain.WalletRequest{OperationType: domain.DEPOSIT, Amount: 1},
+			wantMsg: "Wallet ID is required",
+		},
+		{
+			name:    "operation type",
+			req:     domain.WalletRequest{WalletID: "a", OperationType: domain.INTEREST, Amount: 1},
+			wantMsg: "Operation type must be DEPOSIT or WITHDRAW",
+		},
+		{
+			name:    "negative amount",
+			req:     domain.WalletRequest{WalletID: "a", OperationType: domain.WITHDRAW, Amount: -1},
+			wantMsg: "Amount must be more than 0",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateSchema("WalletRequest", tc.req)
+			if tc.wantMsg == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			require.Equal(t, tc.wantMsg, Message(err))
+		})
+	}
+}
+
+func TestValidateSchemaUnknown(t *testing.T) {
+	require.Error(t, ValidateSchema("Unknown", struct{}{}))
+}
+
+func TestDocumentDescribesEveryRoute(t *testing.T) {
+	doc, err := Load()
+	require.NoError(t, err)
+
+	routes := []struct {
+		method string
+		path   string
+	}{
+		{http.MethodPost, "/api/v1/wallet"},
+		{http.MethodGet, "/api/v1/transactions/{transactionId}"},
+		{http.MethodGet, "/api/v1/wallets/{walletId}"},
+		{http.MethodGet, "/api/v1/wallets/{walletId}/transactions"},
+		{http.MethodGet, "/api/v1/wallets/{walletId}/statement"},
+		{http.MethodGet, "/api/v1/wallets/{walletId}/pockets"},
+		{http.MethodPost, "/api/v1/wallets/{walletId}/pockets"},
+		{http.MethodPost, "/api/v1/wallets/{walletId}/pocket-moves"},
+		{http.MethodGet, "/api/v1/wallets/{walletId}/savings"},
+		{http.MethodPut, "/api/v1/wallets/{walletId}/savings"},
+		{http.MethodPost, "/api/v1/schedules"},
+		{http.MethodGet, "/api/v1/schedules"},
+		{http.MethodGet, "/api/v1/schedules/{scheduleId}"},
+		{http.MethodPost, "/api/v1/schedules/{scheduleId}/pause"},
+		{http.MethodPost, "/api/v1/schedules/{scheduleId}/resume"},
+		{http.MethodDelete, "/api/v1/schedules/{scheduleId}"},
+		{http.MethodPost, "/api/v1/batches"},
+		{http.MethodGet, "/api/v1/batches/{batchId}"},
+		{http.MethodPost, "/api/v1/splits"},
+		{http.MethodGet, "/api/v1/wallets/{walletId}/payment-requests/incoming"},
+		{http.MethodGet, "/api/v1/wallets/{walletId}/payment-requests/outgoing"},
+		{http.MethodPost, "/api/v1/payment-requests"},
+		{http.MethodGet, "/api/v1/payment-requests/{requestId}"},
+		{http.MethodPost, "/api/v1/payment-requests/{requestId}/accept"},
+		{http.MethodPost, "/api/v1/payment-requests/{requestId}/decline"},
+		{http.MethodPost, "/api/v1/vouchers/redeem"},
+		{http.MethodPost, "/api/v1/campaigns"},
+		{http.MethodGet, "/api/v1/campaigns"},
+		{http.MethodGet, "/api/v1/campaigns/{campaignId}"},
+		{http.MethodPost, "/api/v1/loyalty-rules"},
+		{http.MethodGet, "/api/v1/loyalty-rules"},
+		{http.MethodGet, "/api/v1/wallets/{walletId}/points"},
+		{http.MethodPost, "/api/v1/wallets/{walletId}/points/redeem"},
+		{http.MethodPost, "/api/v1/escrows"},
+		{http.MethodGet, "/api/v1/escrows/{dealId}"},
+		{http.MethodPost, "/api/v1/escrows/{dealId}/release"},
+		{http.MethodPost, "/api/v1/escrows/{dealId}/refund"},
+		{http.MethodPost, "/api/v1/escrows/{dealId}/dispute"},
+		{http.MethodPost, "/api/v1/escrows/{dealId}/resolve"},
+		{http.MethodPost, "/api/v2/transactions"},
+		{http.MethodGet, "/api/v2/transactions/{transactionId}"},
+		{http.MethodGet, "/api/v2/wallets/{walletId}"},
+		{http.MethodGet, "/api/v2/wallets/{walletId}/transactions"},
+	}
+
+	for _, route := range routes {
+		t.Run(route.method+" "+route.path, func(t *testing.T) {
+			item := doc.Paths.Value(route.path)
+			require.NotNil(t, item)
+			require.NotNil(t, item.GetOperation(route.method))
+		})
+	}
+}
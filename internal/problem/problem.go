@@ -0,0 +1,220 @@
+// Package problem builds and writes RFC 7807 problem details responses.
+package problem
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/Te8va/wallet/internal/domain"
+	appErrors "github.com/Te8va/wallet/internal/errors"
+	"github.com/Te8va/wallet/internal/logging"
+)
+
+const ContentType = "application/problem+json"
+
+// typePrefix makes a problem type URI out of a code.
+const typePrefix = "urn:wallet:problem:"
+
+// Codes are part of the API: clients match on them, so existing ones must
+// not be renamed.
+const (
+	CodeMalformedRequest       = "MALFORMED_REQUEST"
+	CodeValidationFailed       = "VALIDATION_FAILED"
+	CodeUnsupportedMediaType   = "UNSUPPORTED_MEDIA_TYPE"
+	CodeBatchTooLarge          = "BATCH_TOO_LARGE"
+	CodeRateLimited            = "RATE_LIMITED"
+	CodeNotFound               = "NOT_FOUND"
+	CodeMethodNotAllowed       = "METHOD_NOT_ALLOWED"
+	CodeWalletNotFound         = "WALLET_NOT_FOUND"
+	CodeInsufficientFunds      = "INSUFFICIENT_FUNDS"
+	CodeSavingsAccountNotFound = "SAVINGS_ACCOUNT_NOT_FOUND"
+	CodeDuplicateOperation     = "DUPLICATE_OPERATION"
+	CodeIdempotencyKeyReused   = "IDEMPOTENCY_KEY_REUSED"
+	CodeDuplicateReference     = "DUPLICATE_REFERENCE"
+	CodeCurrencyMismatch       = "CURRENCY_MISMATCH"
+	CodeTransactionNotFound    = "TRANSACTION_NOT_FOUND"
+	CodeSameWallet             = "SAME_WALLET"
+	CodePocketNotFound         = "POCKET_NOT_FOUND"
+	CodePocketExists           = "POCKET_EXISTS"
+	CodeSamePocket             = "SAME_POCKET"
+	CodeInvalidSplit           = "INVALID_SPLIT"
+	CodePaymentRequestNotFound = "PAYMENT_REQUEST_NOT_FOUND"
+	CodePaymentRequestExpired  = "PAYMENT_REQUEST_EXPIRED"
+	CodePaymentRequestState    = "INVALID_PAYMENT_REQUEST_STATE"
+	CodeWalletKindNotAllowed   = "WALLET_KIND_NOT_ALLOWED"
+	CodeEscrowNotFound         = "ESCROW_NOT_FOUND"
+	CodeEscrowExists           = "ESCROW_EXISTS"
+	CodeInvalidEscrowState     = "INVALID_ESCROW_STATE"
+	CodeInvalidEscrowSplit     = "INVALID_ESCROW_SPLIT"
+	CodeVoucherNotFound        = "VOUCHER_NOT_FOUND"
+	CodeVoucherExpired         = "VOUCHER_EXPIRED"
+	CodeVoucherRedeemed        = "VOUCHER_REDEEMED"
+	CodeInvalidVoucherBatch    = "INVALID_VOUCHER_BATCH"
+	CodeCampaignNotFound       = "CAMPAIGN_NOT_FOUND"
+	CodeInvalidCampaign        = "INVALID_CAMPAIGN"
+	CodeInvalidLoyaltyRule     = "INVALID_LOYALTY_RULE"
+	CodeInvalidPointsAmount    = "INVALID_POINTS_AMOUNT"
+	CodeRedemptionDisabled     = "REDEMPTION_DISABLED"
+	CodeScheduleNotFound       = "SCHEDULE_NOT_FOUND"
+	CodeInvalidScheduleState   = "INVALID_SCHEDULE_STATE"
+	CodeInvalidCron            = "INVALID_CRON"
+	CodeBatchNotFound          = "BATCH_NOT_FOUND"
+	CodeInconsistentEvent      = "INCONSISTENT_EVENT"
+	CodeUnknownProjection      = "UNKNOWN_PROJECTION"
+	CodeInternal               = "INTERNAL_ERROR"
+)
+
+type definition struct {
+	status int
+	title  string
+}
+
+var definitions = map[string]definition{
+	CodeMalformedRequest:       {http.StatusBadRequest, "Malformed request"},
+	CodeValidationFailed:       {http.StatusBadRequest, "Validation failed"},
+	CodeUnsupportedMediaType:   {http.StatusUnsupportedMediaType, "Unsupported media type"},
+	CodeBatchTooLarge:          {http.StatusRequestEntityTooLarge, "Batch too large"},
+	CodeRateLimited:            {http.StatusTooManyRequests, "Too many requests"},
+	CodeNotFound:               {http.StatusNotFound, "Not found"},
+	CodeMethodNotAllowed:       {http.StatusMethodNotAllowed, "Method not allowed"},
+	CodeWalletNotFound:         {http.StatusNotFound, "Wallet not found"},
+	CodeInsufficientFunds:      {http.StatusBadRequest, "Insufficient funds"},
+	CodeSavingsAccountNotFound: {http.StatusNotFound, "Savings account not found"},
+	CodeDuplicateOperation:     {http.StatusConflict, "Operation already processed"},
+	CodeIdempotencyKeyReused:   {http.StatusConflict, "Idempotency key reused"},
+	CodeDuplicateReference:     {http.StatusConflict, "Reference already used"},
+	CodeCurrencyMismatch:       {http.StatusBadRequest, "Currency mismatch"},
+	CodeTransactionNotFound:    {http.StatusNotFound, "Transaction not found"},
+	CodeSameWallet:             {http.StatusBadRequest, "Same source and destination wallet"},
+	CodePocketNotFound:         {http.StatusNotFound, "Pocket not found"},
+	CodePocketExists:           {http.StatusConflict, "Pocket already exists"},
+	CodeSamePocket:             {http.StatusBadRequest, "Same source and destination pocket"},
+	CodeInvalidSplit:           {http.StatusBadRequest, "Invalid split"},
+	CodePaymentRequestNotFound: {http.StatusNotFound, "Payment request not found"},
+	CodePaymentRequestExpired:  {http.StatusConflict, "Payment request expired"},
+	CodePaymentRequestState:    {http.StatusConflict, "Invalid payment request state"},
+	CodeWalletKindNotAllowed:   {http.StatusBadRequest, "Operation not allowed on wallet"},
+	CodeEscrowNotFound:         {http.StatusNotFound, "Escrow not found"},
+	CodeEscrowExists:           {http.StatusConflict, "Escrow already exists"},
+	CodeInvalidEscrowState:     {http.StatusConflict, "Invalid escrow state"},
+	CodeInvalidEscrowSplit:     {http.StatusBadRequest, "Invalid escrow split"},
+	CodeVoucherNotFound:        {http.StatusNotFound, "Voucher not found"},
+	CodeVoucherExpired:         {http.StatusConflict, "Voucher expired"},
+	CodeVoucherRedeemed:        {http.StatusConflict, "Voucher already redeemed"},
+	CodeInvalidVoucherBatch:    {http.StatusBadRequest, "Invalid voucher batch"},
+	CodeCampaignNotFound:       {http.StatusNotFound, "Campaign not found"},
+	CodeInvalidCampaign:        {http.StatusBadRequest, "Invalid campaign"},
+	CodeInvalidLoyaltyRule:     {http.StatusBadRequest, "Invalid loyalty rule"},
+	CodeInvalidPointsAmount:    {http.StatusBadRequest, "Invalid points amount"},
+	CodeRedemptionDisabled:     {http.StatusServiceUnavailable, "Redemption disabled"},
+	CodeScheduleNotFound:       {http.StatusNotFound, "Schedule not found"},
+	CodeInvalidScheduleState:   {http.StatusConflict, "Invalid schedule state"},
+	CodeInvalidCron:            {http.StatusBadRequest, "Invalid cron expression"},
+	CodeBatchNotFound:          {http.StatusNotFound, "Batch not found"},
+	CodeInconsistentEvent:      {http.StatusInternalServerError, "Inconsistent event"},
+	CodeUnknownProjection:      {http.StatusBadRequest, "Unknown projection"},
+	CodeInternal:               {http.StatusInternalServerError, "Internal server error"},
+}
+
+// sentinels maps the errors of internal/errors to their codes.
+var sentinels = []struct {
+	err  error
+	code string
+}{
+	{appErrors.ErrWalletNotFound, CodeWalletNotFound},
+	{appErrors.ErrInsufficientFunds, CodeInsufficientFunds},
+	{appErrors.ErrSavingsAccountNotFound, CodeSavingsAccountNotFound},
+	{appErrors.ErrDuplicateOperation, CodeDuplicateOperation},
+	{appErrors.ErrIdempotencyKeyReused, CodeIdempotencyKeyReused},
+	{appErrors.ErrDuplicateReference, CodeDuplicateReference},
+	{appErrors.ErrCurrencyMismatch, CodeCurrencyMismatch},
+	{appErrors.ErrTransactionNotFound, CodeTransactionNotFound},
+	{appErrors.ErrSameWallet, CodeSameWallet},
+	{appErrors.ErrPocketNotFound, CodePocketNotFound},
+	{appErrors.ErrPocketExists, CodePocketExists},
+	{appErrors.ErrSamePocket, CodeSamePocket},
+	{appErrors.ErrInvalidSplit, CodeInvalidSplit},
+	{appErrors.ErrPaymentRequestNotFound, CodePaymentRequestNotFound},
+	{appErrors.ErrPaymentRequestExpired, CodePaymentRequestExpired},
+	{appErrors.ErrPaymentRequestState, CodePaymentRequestState},
+	{appErrors.ErrWalletKindNotAllowed, CodeWalletKindNotAllowed},
+	{appErrors.ErrEscrowNotFound, CodeEscrowNotFound},
+	{appErrors.ErrEscrowExists, CodeEscrowExists},
+	{appErrors.ErrInvalidEscrowState, CodeInvalidEscrowState},
+	{appErrors.ErrInvalidEscrowSplit, CodeInvalidEscrowSplit},
+	{appErrors.ErrVoucherNotFound, CodeVoucherNotFound},
+	{appErrors.ErrVoucherExpired, CodeVoucherExpired},
+	{appErrors.ErrVoucherRedeemed, CodeVoucherRedeemed},
+	{appErrors.ErrInvalidVoucherBatch, CodeInvalidVoucherBatch},
+	{appErrors.ErrCampaignNotFound, CodeCampaignNotFound},
+	{appErrors.ErrInvalidCampaign, CodeInvalidCampaign},
+	{appErrors.ErrInvalidLoyaltyRule, CodeInvalidLoyaltyRule},
+	{appErrors.ErrInvalidPointsAmount, CodeInvalidPointsAmount},
+	{appErrors.ErrRedemptionDisabled, CodeRedemptionDisabled},
+	{appErrors.ErrScheduleNotFound, CodeScheduleNotFound},
+	{appErrors.ErrInvalidScheduleState, CodeInvalidScheduleState},
+	{appErrors.ErrInvalidCron, CodeInvalidCron},
+	{appErrors.ErrBatchNotFound, CodeBatchNotFound},
+	{appErrors.ErrInconsistentEvent, CodeInconsistentEvent},
+	{appErrors.ErrUnknownProjection, CodeUnknownProjection},
+}
+
+// New returns the problem for code with the status and title registered for
+// it. An unknown code is reported as an internal error.
+func New(code, detail string) domain.Problem {
+	def, ok := definitions[code]
+	if !ok {
+		code, def = CodeInternal, definitions[CodeInternal]
+	}
+
+	return domain.Problem{
+		Type:   typePrefix + code,
+		Title:  def.title,
+		Status: def.status,
+		Code:   code,
+		Detail: detail,
+	}
+}
+
+// Invalid reports a request that failed validation. field names the
+// offending body field or parameter, if there is one.
+func Invalid(field, message string) domain.Problem {
+	p := New(CodeValidationFailed, message)
+	if field != "" {
+		p.Errors = []domain.FieldError{{Field: field, Message: message}}
+	}
+	return p
+}
+
+// FromError maps a sentinel error to its problem. Any other error is an
+// internal error, and its text is not exposed.
+func FromError(err error) domain.Problem {
+	for _, s := range sentinels {
+		if errors.Is(err, s.err) {
+			return New(s.code, s.err.Error())
+		}
+	}
+	return New(CodeInternal, "")
+}
+
+// Write sends p, filling in the request path and id.
+func Write(w http.ResponseWriter, r *http.Request, p domain.Problem) {
+	if p.Instance == "" {
+		p.Instance = r.URL.Path
+	}
+	p.RequestID = logging.RequestID(r.Context())
+
+	w.Header().Set("Content-Type", ContentType)
+	w.WriteHeader(p.Status)
+	json.NewEncoder(w).Encode(p)
+}
+
+// NotFound and MethodNotAllowed answer requests the router has no route for.
+func NotFound(w http.ResponseWriter, r *http.Request) {
+	Write(w, r, New(CodeNotFound, ""))
+}
+
+func MethodNotAllowed(w http.ResponseWriter, r *http.Request) {
+	Write(w, r, New(CodeMethodNotAllowed, ""))
+}
@@ -0,0 +1,52 @@
+package problem
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	appErrors "github.com/Te8va/wallet/internal/errors"
+	"github.com/Te8va/wallet/internal/logging"
+)
+
+func TestFromError(t *testing.T) {
+	for _, s := range sentinels {
+		_, ok := definitions[s.code]
+		require.True(t, ok, "no definition for %s", s.code)
+	}
+
+	p := FromError(fmt.Errorf("service.GetBalance: %w", appErrors.ErrWalletNotFound))
+	require.Equal(t, CodeWalletNotFound, p.Code)
+	require.Equal(t, http.StatusNotFound, p.Status)
+	require.Equal(t, "wallet not found", p.Detail)
+
+	p = FromError(errors.New("connection refused"))
+	require.Equal(t, CodeInternal, p.Code)
+	require.Equal(t, http.StatusInternalServerError, p.Status)
+	require.Empty(t, p.Detail)
+}
+
+func TestWrite(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/api/v1/wallet?x=1", nil)
+	r = r.WithContext(logging.WithRequestID(r.Context(), "req-1"))
+	w := httptest.NewRecorder()
+
+	Write(w, r, Invalid("amount", "Amount must be more than 0"))
+
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	require.Equal(t, ContentType, w.Header().Get("Content-Type"))
+	require.JSONEq(t, `{
+		"type": "urn:wallet:problem:VALIDATION_FAILED",
+		"title": "Validation failed",
+		"status": 400,
+		"code": "VALIDATION_FAILED",
+		"detail": "Amount must be more than 0",
+		"instance": "/api/v1/wallet",
+		"request_id": "req-1",
+		"errors": [{"field": "amount", "message": "Amount must be more than 0"}]
+	}`, w.Body.String())
+}
@@ -0,0 +1,60 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/Te8va/wallet/internal/domain"
+)
+
+func TestMemoryStoreTake(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	s := NewMemoryStore()
+	s.now = func() time.Time { return now }
+
+	limit := domain.RateLimit{Rate: 2, Burst: 3}
+	ctx := context.Background()
+
+	for i := 2; i >= 0; i-- {
+		res, err := s.Take(ctx, "a", limit)
+		require.NoError(t, err)
+		require.True(t, res.Allowed)
+		require.Equal(t, i, res.Remaining)
+	}
+
+	res, err := s.Take(ctx, "a", limit)
+	require.NoError(t, err)
+	require.False(t, res.Allowed)
+	require.Equal(t, 500*time.Millisecond, res.RetryAfter)
+	require.Equal(t, 1500*time.Millisecond, res.Reset)
+
+	res, err = s.Take(ctx, "b", limit)
+	require.NoError(t, err)
+	require.True(t, res.Allowed, "buckets are per key")
+
+	now = now.Add(500 * time.Millisecond)
+	res, err = s.Take(ctx, "a", limit)
+	require.NoError(t, err)
+	require.True(t, res.Allowed)
+	require.Equal(t, 0, res.Remaining)
+}
+
+func TestMemoryStoreSweep(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	s := NewMemoryStore()
+	s.now = func() time.Time { return now }
+
+	limit := domain.RateLimit{Rate: 1, Burst: 10}
+	_, err := s.Take(context.Background(), "idle", limit)
+	require.NoError(t, err)
+	require.Len(t, s.buckets, 1)
+
+	now = now.Add(sweepInterval)
+	_, err = s.Take(context.Background(), "active", limit)
+	require.NoError(t, err)
+	require.Len(t, s.buckets, 1)
+	require.Contains(t, s.buckets, "active")
+}
@@ -0,0 +1,395 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/Te8va/wallet/internal/domain"
+	appErrors "github.com/Te8va/wallet/internal/errors"
+)
+
+type BatchRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewBatchRepository(db *pgxpool.Pool) (*BatchRepository, error) {
+	if db == nil {
+		return nil, fmt.Errorf("database connection is nil")
+	}
+	return &BatchRepository{db: db}, nil
+}
+
+func (r *BatchRepository) CreateBatch(ctx context.Context, mode domain.BatchMode, items []domain.BatchItem) (domain.Batch, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return domain.Batch{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer rollback(ctx, tx)
+
+	batch := domain.Batch{Mode: mode, Status: domain.BatchPending, Total: len(items)}
+	err = tx.QueryRow(ctx,
+		`INSERT INTO batch (mode, status, total) VALUES ($1, $2, $3) RETURNING id, created_at`,
+		batch.Mode, batch.Status, batch.Total,
+	).Scan(&batch.ID, &batch.CreatedAt)
+	if err != nil {
+		return domain.Batch{}, fmt.Errorf("failed to create batch: %w", err)
+	}
+
+	_, err = tx.CopyFrom(ctx,
+		pgx.Identifier{"batch_item"},
+		[]string{"batch_id", "line", "wallet_id", "operation_type", "amount", "status", "error"},
+		pgx.CopyFromSlice(len(items), func(i int) ([]any, error) {
+			it := items[i]
+			return []any{batch.ID, it.Line, it.WalletID, string(it.OperationType), it.Amount, string(it.Status), it.Error}, nil
+		}),
+	)
+	if err != nil {
+		return domain.Batch{}, fmt.Errorf("failed to create batch items: %w", err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return domain.Batch{}, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return batch, nil
+}
+
+func (r *BatchRepository) GetBatch(ctx context.Context, id int64) (domain.Batch, error) {
+	batch := domain.Batch{ID: id}
+	err := r.db.QueryRow(ctx,
+		`SELECT mode, status, total, succeeded, failed, created_at, completed_at FROM batch WHERE id = $1`,
+		id,
+	).Scan(&batch.Mode, &batch.Status, &batch.Total, &batch.Succeeded, &batch.Failed, &batch.CreatedAt, &batch.CompletedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.Batch{}, appErrors.ErrBatchNotFound
+		}
+		return domain.Batch{}, fmt.Errorf("failed to get batch: %w", err)
+	}
+
+	rows, err := r.db.Query(ctx,
+		`SELECT line, wallet_id, operation_type, amount, status, error, COALESCE(transaction_id, 0)
+		 FROM batch_item WHERE batch_id = $1 ORDER BY line`,
+		id,
+	)
+	if err != nil {
+		return domain.Batch{}, fmt.Errorf("failed to get batch items: %w", err)
+	}
+
+	batch.Items, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (domain.BatchItem, error) {
+		var it domain.BatchItem
+		err := row.Scan(&it.Line, &it.WalletID, &it.OperationType, &it.Amount, &it.Status, &it.Error, &it.TransactionID)
+		return it, err
+	})
+	if err != nil {
+		return domain.Batch{}, fmt.Errorf("failed to get batch items: %w", err)
+	}
+
+	return batch, nil
+}
+
+// ClaimBatch leases the oldest unfinished batch, skipping batches that are
+// being processed by another instance. It reports false when there is
+// nothing to do.
+func (r *BatchRepository) ClaimBatch(ctx context.Context, now, leaseUntil time.Time) (domain.Batch, bool, error) {
+	var batch domain.Batch
+	err := r.db.QueryRow(ctx,
+		`UPDATE batch SET status = 'PROCESSING', locked_until = $2
+		 WHERE id = (
+		     SELECT id FROM batch
+		     WHERE status IN ('PENDING', 'PROCESSING')
+		       AND (locked_until IS NULL OR locked_until < $1)
+		     ORDER BY id
+		     LIMIT 1
+		     FOR UPDATE SKIP LOCKED
+		 )
+		 RETURNING id, mode, status, total, created_at`,
+		now, leaseUntil,
+	).Scan(&batch.ID, &batch.Mode, &batch.Status, &batch.Total, &batch.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.Batch{}, false, nil
+		}
+		return domain.Batch{}, false, fmt.Errorf("failed to claim batch: %w", err)
+	}
+
+	return batch, true, nil
+}
+
+func (r *BatchRepository) ListPendingLines(ctx context.Context, batchID int64) ([]int, error) {
+	rows, err := r.db.Query(ctx,
+		`SELECT line FROM batch_item WHERE batch_id = $1 AND status = 'PENDING' ORDER BY line`,
+		batchID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pending batch items: %w", err)
+	}
+
+	lines, err := pgx.CollectRows(rows, pgx.RowTo[int])
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pending batch items: %w", err)
+	}
+
+	return lines, nil
+}
+
+// ApplyBatchItem executes a single pending item in its own transaction and
+// records the outcome on the item. It returns the posted entry, or a zero
+// transaction when nothing was posted. Items that are no longer pending are
+// left untouched, which makes the call safe to repeat.
+func (r *BatchRepository) ApplyBatchItem(ctx context.Context, batchID int64, line int) (domain.Transaction, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return domain.Transaction{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer rollback(ctx, tx)
+
+	var it domain.BatchItem
+	err = tx.QueryRow(ctx,
+		`SELECT wallet_id, operation_type, amount, status FROM batch_item
+		 WHERE batch_id = $1 AND line = $2 FOR UPDATE`,
+		batchID, line,
+	).Scan(&it.WalletID, &it.OperationType, &it.Amount, &it.Status)
+	if err != nil {
+		return domain.Transaction{}, fmt.Errorf("failed to get batch item: %w", err)
+	}
+
+	if it.Status != domain.BatchItemPending {
+		return domain.Transaction{}, nil
+	}
+
+	balances, err := lockWallets(ctx, tx, []string{it.WalletID}, map[string]bool{it.WalletID: it.OperationType == domain.DEPOSIT})
+	if err == nil {
+		err = checkUserWallets(ctx, tx, it.WalletID)
+	}
+	switch {
+	case errors.Is(err, appErrors.ErrWalletNotFound), errors.Is(err, appErrors.ErrWalletKindNotAllowed):
+		return domain.Transaction{}, finishBatchItem(ctx, tx, batchID, line, domain.BatchItemFailed, err.Error(), 0)
+	case err != nil:
+		return domain.Transaction{}, err
+	}
+
+	delta := it.Amount
+	if it.OperationType == domain.WITHDRAW {
+		if balances[it.WalletID] < it.Amount {
+			return domain.Transaction{}, finishBatchItem(ctx, tx, batchID, line, domain.BatchItemFailed,
+				appErrors.ErrInsufficientFunds.Error(), 0)
+		}
+		delta = -it.Amount
+	}
+
+	t, err := postEntry(ctx, tx, entry{walletID: it.WalletID, opType: it.OperationType, delta: delta})
+	if err != nil {
+		return domain.Transaction{}, err
+	}
+
+	if err = finishBatchItem(ctx, tx, batchID, line, domain.BatchItemSucceeded, "", t.ID); err != nil {
+		return domain.Transaction{}, err
+	}
+
+	return t, nil
+}
+
+func finishBatchItem(ctx context.Context, tx pgx.Tx, batchID int64, line int, status domain.BatchItemStatus, reason string, transactionID int64) error {
+	_, err := tx.Exec(ctx,
+		`UPDATE batch_item SET status = $3, error = $4, transaction_id = NULLIF($5, 0)
+		 WHERE batch_id = $1 AND line = $2`,
+		batchID, line, status, reason, transactionID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update batch item: %w", err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// ApplyBatch executes every item of an all-or-nothing batch in a single
+// transaction and returns the posted entries. When an item fails, nothing is
+// posted: the failing item is marked FAILED, the rest SKIPPED, and the batch
+// FAILED.
+func (r *BatchRepository) ApplyBatch(ctx context.Context, batchID int64) ([]domain.Transaction, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer rollback(ctx, tx)
+
+	var status domain.BatchStatus
+	err = tx.QueryRow(ctx, `SELECT status FROM batch WHERE id = $1 FOR UPDATE`, batchID).Scan(&status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock batch: %w", err)
+	}
+
+	if status == domain.BatchCompleted || status == domain.BatchFailed {
+		return nil, nil
+	}
+
+	rows, err := tx.Query(ctx,
+		`SELECT line, wallet_id, operation_type, amount, status, error FROM batch_item
+		 WHERE batch_id = $1 ORDER BY line`,
+		batchID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get batch items: %w", err)
+	}
+
+	items, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (domain.BatchItem, error) {
+		var it domain.BatchItem
+		err := row.Scan(&it.Line, &it.WalletID, &it.OperationType, &it.Amount, &it.Status, &it.Error)
+		return it, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get batch items: %w", err)
+	}
+
+	posted, failedLine, reason, err := r.postBatchItems(ctx, tx, batchID, items)
+	if err != nil {
+		return nil, err
+	}
+
+	if failedLine == 0 {
+		if err = finishBatch(ctx, tx, batchID); err != nil {
+			return nil, err
+		}
+		return posted, nil
+	}
+
+	// Discard everything posted so far and record why the batch failed.
+	if err = tx.Rollback(ctx); err != nil {
+		return nil, fmt.Errorf("failed to rollback transaction: %w", err)
+	}
+
+	tx, err = r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer rollback(ctx, tx)
+
+	_, err = tx.Exec(ctx,
+		`UPDATE batch_item
+		 SET status = CASE WHEN line = $2 THEN 'FAILED' ELSE 'SKIPPED' END,
+		     error = CASE WHEN line = $2 THEN $3 ELSE '' END
+		 WHERE batch_id = $1 AND (status = 'PENDING' OR line = $2)`,
+		batchID, failedLine, reason,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update batch items: %w", err)
+	}
+
+	return nil, finishBatch(ctx, tx, batchID)
+}
+
+// postBatchItems posts all items within tx and returns the posted entries.
+// It returns the first line that cannot be applied together with the
+// reason, or zero when all succeeded.
+func (r *BatchRepository) postBatchItems(ctx context.Context, tx pgx.Tx, batchID int64, items []domain.BatchItem) ([]domain.Transaction, int, string, error) {
+	// Every referenced wallet is created up front: a withdrawal from a wallet
+	// that does not exist fails on its zero balance, and a failed batch rolls
+	// the created wallets back.
+	walletIDs := make([]string, 0, len(items))
+	create := make(map[string]bool)
+	for _, it := range items {
+		if it.Status == domain.BatchItemFailed {
+			return nil, it.Line, it.Error, nil
+		}
+		walletIDs = append(walletIDs, it.WalletID)
+		create[it.WalletID] = true
+	}
+
+	balances, err := lockWallets(ctx, tx, walletIDs, create)
+	if err != nil {
+		return nil, 0, "", err
+	}
+
+	posted := make([]domain.Transaction, 0, len(items))
+	updates := &pgx.Batch{}
+	for _, it := range items {
+		err = checkUserWallets(ctx, tx, it.WalletID)
+		if errors.Is(err, appErrors.ErrWalletKindNotAllowed) {
+			return nil, it.Line, err.Error(), nil
+		}
+		if err != nil {
+			return nil, 0, "", err
+		}
+
+		delta := it.Amount
+		if it.OperationType == domain.WITHDRAW {
+			if balances[it.WalletID] < it.Amount {
+				return nil, it.Line, appErrors.ErrInsufficientFunds.Error(), nil
+			}
+			delta = -it.Amount
+		}
+
+		t, err := postEntry(ctx, tx, entry{walletID: it.WalletID, opType: it.OperationType, delta: delta})
+		if err != nil {
+			return nil, 0, "", err
+		}
+		balances[it.WalletID] += delta
+		posted = append(posted, t)
+
+		updates.Queue(
+			`UPDATE batch_item SET status = 'SUCCEEDED', transaction_id = $3 WHERE batch_id = $1 AND line = $2`,
+			batchID, it.Line, t.ID,
+		)
+	}
+
+	if err = tx.SendBatch(ctx, updates).Close(); err != nil {
+		return nil, 0, "", fmt.Errorf("failed to update batch items: %w", err)
+	}
+
+	return posted, 0, "", nil
+}
+
+// FinishBatch recounts the item outcomes and closes the batch once no
+// pending items remain.
+func (r *BatchRepository) FinishBatch(ctx context.Context, batchID int64) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer rollback(ctx, tx)
+
+	return finishBatch(ctx, tx, batchID)
+}
+
+func finishBatch(ctx context.Context, tx pgx.Tx, batchID int64) error {
+	_, err := tx.Exec(ctx,
+		`WITH counts AS (
+		     SELECT count(*) FILTER (WHERE status = 'SUCCEEDED') AS succeeded,
+		            count(*) FILTER (WHERE status IN ('FAILED', 'SKIPPED')) AS failed,
+		            count(*) FILTER (WHERE status = 'PENDING') AS pending
+		     FROM batch_item WHERE batch_id = $1
+		 )
+		 UPDATE batch
+		 SET succeeded = counts.succeeded,
+		     failed = counts.failed,
+		     status = CASE
+		         WHEN counts.pending > 0 THEN batch.status
+		         WHEN batch.mode = 'all_or_nothing' AND counts.failed > 0 THEN 'FAILED'
+		         ELSE 'COMPLETED'
+		     END,
+		     completed_at = CASE WHEN counts.pending > 0 THEN NULL ELSE now() END,
+		     locked_until = NULL
+		 FROM counts
+		 WHERE batch.id = $1`,
+		batchID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to finish batch: %w", err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
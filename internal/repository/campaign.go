@@ -0,0 +1,223 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/Te8va/wallet/internal/domain"
+	appErrors "github.com/Te8va/wallet/internal/errors"
+)
+
+const campaignColumns = `id, name, funding_wallet_id, rate_bps, min_amount, per_wallet_cap, budget, spent,
+	starts_at, ends_at, created_at`
+
+// CampaignRepository keeps cashback campaigns and the cashback they paid.
+// Every cashback posting carries the reference "campaign:<campaign id>".
+type CampaignRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewCampaignRepository(db *pgxpool.Pool) (*CampaignRepository, error) {
+	if db == nil {
+		return nil, fmt.Errorf("database connection is nil")
+	}
+	return &CampaignRepository{db: db}, nil
+}
+
+func scanCampaign(row pgx.Row) (domain.Campaign, error) {
+	var c domain.Campaign
+	err := row.Scan(&c.ID, &c.Name, &c.FundingWalletID, &c.RateBps, &c.MinAmount, &c.PerWalletCap, &c.Budget,
+		&c.Spent, &c.StartsAt, &c.EndsAt, &c.CreatedAt)
+	return c, err
+}
+
+// CreateCampaign stores the campaign. Only a user wallet can fund it: the
+// wallets the service holds money in for others are not spent on cashback.
+func (r *CampaignRepository) CreateCampaign(ctx context.Context, params domain.CampaignParams) (domain.Campaign, error) {
+	if err := checkUserWallets(ctx, r.db, params.FundingWalletID); err != nil {
+		return domain.Campaign{}, err
+	}
+
+	c, err := scanCampaign(r.db.QueryRow(ctx,
+		`INSERT INTO campaign (name, funding_wallet_id, rate_bps, min_amount, per_wallet_cap, budget, starts_at, ends_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		 RETURNING `+campaignColumns,
+		params.Name, params.FundingWalletID, params.RateBps, params.MinAmount, params.PerWalletCap, params.Budget,
+		params.StartsAt, params.EndsAt,
+	))
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgForeignKeyViolation {
+			return domain.Campaign{}, appErrors.ErrWalletNotFound
+		}
+		return domain.Campaign{}, fmt.Errorf("failed to create campaign: %w", err)
+	}
+
+	return c, nil
+}
+
+func (r *CampaignRepository) GetCampaign(ctx context.Context, id int64) (domain.Campaign, error) {
+	c, err := scanCampaign(r.db.QueryRow(ctx,
+		`SELECT `+campaignColumns+` FROM campaign WHERE id = $1`,
+		id,
+	))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.Campaign{}, appErrors.ErrCampaignNotFound
+		}
+		return domain.Campaign{}, fmt.Errorf("failed to get campaign: %w", err)
+	}
+
+	return c, nil
+}
+
+// ListCampaigns returns all campaigns, newest first.
+func (r *CampaignRepository) ListCampaigns(ctx context.Context) ([]domain.Campaign, error) {
+	return r.listCampaigns(ctx, `SELECT `+campaignColumns+` FROM campaign ORDER BY id DESC`)
+}
+
+// ListActiveCampaigns returns the campaigns running at the given time that
+// still have budget left.
+func (r *CampaignRepository) ListActiveCampaigns(ctx context.Context, at time.Time) ([]domain.Campaign, error) {
+	return r.listCampaigns(ctx,
+		`SELECT `+campaignColumns+` FROM campaign
+		 WHERE starts_at <= $1 AND ends_at > $1 AND spent < budget
+		 ORDER BY id`,
+		at,
+	)
+}
+
+func (r *CampaignRepository) listCampaigns(ctx context.Context, sql string, args ...any) ([]domain.Campaign, error) {
+	rows, err := r.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list campaigns: %w", err)
+	}
+
+	campaigns, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (domain.Campaign, error) {
+		return scanCampaign(row)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list campaigns: %w", err)
+	}
+
+	return campaigns, nil
+}
+
+// CreditCashback pays the cashback from the funding wallet of the campaign
+// and returns the amount paid. The campaign row is locked first, so the
+// budget and the per-wallet cap hold under concurrent credits; the amount is
+// cut down to what is left of both, and 0 is returned when nothing is left.
+// A second credit for the same source transaction returns
+// ErrDuplicateOperation.
+func (r *CampaignRepository) CreditCashback(ctx context.Context, cr domain.CashbackCredit) (int64, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer rollback(ctx, tx)
+
+	c, err := scanCampaign(tx.QueryRow(ctx,
+		`SELECT `+campaignColumns+` FROM campaign WHERE id = $1 FOR UPDATE`,
+		cr.CampaignID,
+	))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, appErrors.ErrCampaignNotFound
+		}
+		return 0, fmt.Errorf("failed to get campaign: %w", err)
+	}
+
+	var (
+		credited bool
+		earned   int64
+	)
+	err = tx.QueryRow(ctx,
+		`SELECT
+		     EXISTS (SELECT 1 FROM cashback WHERE campaign_id = $1 AND source_transaction_id = $2),
+		     COALESCE((SELECT SUM(amount) FROM cashback WHERE campaign_id = $1 AND wallet_id = $3), 0)`,
+		c.ID, cr.SourceTransactionID, cr.WalletID,
+	).Scan(&credited, &earned)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get cashback: %w", err)
+	}
+	if credited {
+		return 0, appErrors.ErrDuplicateOperation
+	}
+
+	amount := min(cr.Amount, c.Budget-c.Spent)
+	if c.PerWalletCap > 0 {
+		amount = min(amount, c.PerWalletCap-earned)
+	}
+	if amount <= 0 {
+		return 0, nil
+	}
+
+	balances, err := lockWallets(ctx, tx, []string{c.FundingWalletID, cr.WalletID}, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	if err = checkUserWallets(ctx, tx, c.FundingWalletID, cr.WalletID); err != nil {
+		return 0, err
+	}
+
+	if balances[c.FundingWalletID] < amount {
+		return 0, appErrors.ErrInsufficientFunds
+	}
+
+	reference := "campaign:" + strconv.FormatInt(c.ID, 10)
+	debit, err := postEntry(ctx, tx, entry{
+		walletID:  c.FundingWalletID,
+		opType:    domain.CASHBACK,
+		delta:     -amount,
+		reference: reference,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	credit, err := postEntry(ctx, tx, entry{
+		walletID:       cr.WalletID,
+		opType:         domain.CASHBACK,
+		delta:          amount,
+		parentID:       debit.ID,
+		idempotencyKey: fmt.Sprintf("cashback:%d:%d", c.ID, cr.SourceTransactionID),
+		reference:      reference,
+	})
+	if errors.Is(err, appErrors.ErrDuplicateOperation) {
+		// Credits of the campaign are serialized by its row lock and no
+		// cashback has been recorded for the source, so whatever holds the
+		// key is not this cashback.
+		return 0, appErrors.ErrIdempotencyKeyReused
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	_, err = tx.Exec(ctx,
+		`INSERT INTO cashback (campaign_id, source_transaction_id, wallet_id, amount, transaction_id)
+		 VALUES ($1, $2, $3, $4, $5)`,
+		c.ID, cr.SourceTransactionID, cr.WalletID, amount, credit.ID,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to record cashback: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `UPDATE campaign SET spent = spent + $2 WHERE id = $1`, c.ID, amount)
+	if err != nil {
+		return 0, fmt.Errorf("failed to update campaign: %w", err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return amount, nil
+}
@@ -0,0 +1,191 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/Te8va/wallet/internal/domain"
+)
+
+type CheckRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewCheckRepository(db *pgxpool.Pool) (*CheckRepository, error) {
+	if db == nil {
+		return nil, fmt.Errorf("database connection is nil")
+	}
+	return &CheckRepository{db: db}, nil
+}
+
+type ledgerCheck struct {
+	kind  domain.CheckKind
+	query string
+}
+
+// ledgerChecks select the rows that break a ledger invariant as
+// (wallet_id, transaction_id, expected, actual, detail). A points payout
+// is checked from its funding debit, which hangs off the points debit of
+// the redemption.
+var ledgerChecks = []ledgerCheck{
+	{
+		kind: domain.CheckNegativeBalance,
+		query: `SELECT id, 0::BIGINT, 0::BIGINT, balance::BIGINT, ''
+			FROM wallet
+			WHERE balance < 0
+			ORDER BY id`,
+	},
+	{
+		kind: domain.CheckBalanceMismatch,
+		query: `SELECT w.id, 0::BIGINT, COALESCE(SUM(t.amount), 0)::BIGINT, w.balance::BIGINT, ''
+			FROM wallet w
+			LEFT JOIN wallet_transaction t ON t.wallet_id = w.id
+			GROUP BY w.id, w.balance
+			HAVING w.balance <> COALESCE(SUM(t.amount), 0)
+			ORDER BY w.id`,
+	},
+	{
+		kind: domain.CheckJournalChain,
+		query: `SELECT wallet_id, id, running, balance_after, ''
+			FROM (
+				SELECT id, wallet_id, balance_after,
+					SUM(amount) OVER (PARTITION BY wallet_id ORDER BY id)::BIGINT AS running
+				FROM wallet_transaction
+			) j
+			WHERE balance_after <> running
+			ORDER BY id`,
+	},
+	{
+		kind: domain.CheckUnbalancedPosting,
+		query: `SELECT o.wallet_id, o.id, 0::BIGINT, SUM(g.amount)::BIGINT, COUNT(*) || ' legs'
+			FROM wallet_transaction o
+			JOIN wallet_transaction g ON g.id = o.id OR g.parent_id = o.id
+			WHERE (o.parent_id IS NULL AND (
+				o.operation_type IN ('TRANSFER_OUT', 'SPLIT_OUT')
+				OR (o.operation_type IN ('ESCROW_HOLD', 'ESCROW_RELEASE', 'ESCROW_REFUND',
+					'VOUCHER_FUND', 'VOUCHER_REDEEM', 'VOUCHER_RECLAIM', 'CASHBACK') AND o.amount < 0)
+			))
+			OR (o.operation_type = 'POINTS_PAYOUT' AND o.amount < 0)
+			GROUP BY o.id, o.wallet_id, o.operation_type
+			HAVING SUM(g.amount) <> 0 OR COUNT(*) < 2 OR (o.operation_type <> 'SPLIT_OUT' AND COUNT(*) <> 2)
+			ORDER BY o.id`,
+	},
+	{
+		kind: domain.CheckOrphanRecord,
+		query: `SELECT wallet_id, id, 0::BIGINT, 0::BIGINT, 'transfer credit without debit leg'
+			FROM wallet_transaction
+			WHERE parent_id IS NULL AND (
+				operation_type IN ('TRANSFER_IN', 'SPLIT_IN', 'POINTS_PAYOUT')
+				OR (operation_type IN ('ESCROW_HOLD', 'ESCROW_RELEASE', 'ESCROW_REFUND',
+					'VOUCHER_FUND', 'VOUCHER_REDEEM', 'VOUCHER_RECLAIM', 'CASHBACK') AND amount > 0)
+			)
+			UNION ALL
+			SELECT wallet_id, 0, amount, 0, 'interest credit for ' || to_char(period, 'YYYY-MM') || ' has no journal entry'
+			FROM interest_credit
+			WHERE amount <> 0 AND transaction_id IS NULL
+			UNION ALL
+			SELECT s.from_wallet_id, 0, 0, 0, 'schedule ' || s.id || ' debits a missing wallet'
+			FROM schedule s
+			LEFT JOIN wallet w ON w.id = s.from_wallet_id
+			WHERE s.status IN ('ACTIVE', 'PAUSED') AND w.id IS NULL
+			UNION ALL
+			SELECT wallet_id, 0, 0, 0, 'batch ' || batch_id || ' line ' || line || ' succeeded without journal entry'
+			FROM batch_item
+			WHERE status = 'SUCCEEDED' AND transaction_id IS NULL`,
+	},
+	{
+		kind: domain.CheckPocketMismatch,
+		query: `SELECT w.id, 0::BIGINT, COALESCE(SUM(p.balance), 0)::BIGINT, w.pocketed::BIGINT, 'pocketed differs from the pockets'
+			FROM wallet w
+			LEFT JOIN wallet_pocket p ON p.wallet_id = w.id
+			GROUP BY w.id, w.pocketed
+			HAVING w.pocketed <> COALESCE(SUM(p.balance), 0)
+			UNION ALL
+			SELECT id, 0, balance, pocketed, 'pockets exceed the balance'
+			FROM wallet
+			WHERE pocketed > balance`,
+	},
+	{
+		kind: domain.CheckPointsMismatch,
+		query: `SELECT w.id, 0::BIGINT, COALESCE(SUM(l.remaining), 0)::BIGINT, w.balance::BIGINT, 'balance differs from the points left in lots'
+			FROM wallet w
+			LEFT JOIN points_lot l ON l.points_wallet_id = w.id
+			WHERE w.kind = 'POINTS'
+			GROUP BY w.id, w.balance
+			HAVING w.balance <> COALESCE(SUM(l.remaining), 0)
+			ORDER BY w.id`,
+	},
+}
+
+// CheckLedger runs every invariant check against a single consistent
+// snapshot of the database.
+func (r *CheckRepository) CheckLedger(ctx context.Context) (domain.LedgerReport, error) {
+	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return domain.LedgerReport{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer rollback(ctx, tx)
+
+	report := domain.LedgerReport{Discrepancies: []domain.Discrepancy{}}
+
+	err = tx.QueryRow(ctx,
+		`SELECT (SELECT COUNT(*) FROM wallet), (SELECT COUNT(*) FROM wallet_transaction)`,
+	).Scan(&report.Wallets, &report.Transactions)
+	if err != nil {
+		return domain.LedgerReport{}, fmt.Errorf("failed to count ledger rows: %w", err)
+	}
+
+	for _, c := range ledgerChecks {
+		rows, err := tx.Query(ctx, c.query)
+		if err != nil {
+			return domain.LedgerReport{}, fmt.Errorf("failed to run %s check: %w", c.kind, err)
+		}
+
+		found, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (domain.Discrepancy, error) {
+			d := domain.Discrepancy{Check: c.kind}
+			err := row.Scan(&d.WalletID, &d.TransactionID, &d.Expected, &d.Actual, &d.Detail)
+			return d, err
+		})
+		if err != nil {
+			return domain.LedgerReport{}, fmt.Errorf("failed to read %s check: %w", c.kind, err)
+		}
+
+		report.Discrepancies = append(report.Discrepancies, found...)
+	}
+
+	return report, nil
+}
+
+// WriteRepairScript renders the proposed repairs as a SQL script for review.
+// Every statement only applies while the stored value is still the one the
+// check saw, so a stale script cannot overwrite newer postings.
+func WriteRepairScript(w io.Writer, repairs []domain.Repair) error {
+	if _, err := fmt.Fprintln(w, "BEGIN;"); err != nil {
+		return err
+	}
+
+	for _, rp := range repairs {
+		var err error
+		switch rp.Check {
+		case domain.CheckBalanceMismatch:
+			_, err = fmt.Fprintf(w, "\n-- %s: wallet %q\nUPDATE wallet SET balance = %d WHERE id = '%s' AND balance = %d;\n",
+				rp.Check, rp.WalletID, rp.To, strings.ReplaceAll(rp.WalletID, "'", "''"), rp.From)
+		case domain.CheckJournalChain:
+			_, err = fmt.Fprintf(w, "\n-- %s: wallet %q, transaction %d\nUPDATE wallet_transaction SET balance_after = %d WHERE id = %d AND balance_after = %d;\n",
+				rp.Check, rp.WalletID, rp.TransactionID, rp.To, rp.TransactionID, rp.From)
+		default:
+			_, err = fmt.Fprintf(w, "\n-- %s: wallet %q needs manual review\n", rp.Check, rp.WalletID)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err := fmt.Fprintln(w, "\nCOMMIT;")
+	return err
+}
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/Te8va/wallet/internal/domain"
+)
+
+func ledgerCheckQuery(t *testing.T, kind domain.CheckKind) string {
+	t.Helper()
+
+	for _, c := range ledgerChecks {
+		if c.kind == kind {
+			return c.query
+		}
+	}
+
+	t.Fatalf("no %s check", kind)
+	return ""
+}
+
+func TestLedgerChecksCoverEveryKind(t *testing.T) {
+	for _, kind := range domain.CheckKinds {
+		t.Run(string(kind), func(t *testing.T) {
+			require.NotEmpty(t, ledgerCheckQuery(t, kind))
+		})
+	}
+}
+
+// TestLedgerChecksCoverPostings pins every posting made of a debit and its
+// credit legs to the checks that catch a missing or extra leg.
+func TestLedgerChecksCoverPostings(t *testing.T) {
+	testCases := []struct {
+		name   string
+		debit  domain.OperationType
+		credit domain.OperationType
+	}{
+		{name: "transfer", debit: domain.TRANSFER_OUT, credit: domain.TRANSFER_IN},
+		{name: "split", debit: domain.SPLIT_OUT, credit: domain.SPLIT_IN},
+		{name: "escrow hold", debit: domain.ESCROW_HOLD, credit: domain.ESCROW_HOLD},
+		{name: "escrow release", debit: domain.ESCROW_RELEASE, credit: domain.ESCROW_RELEASE},
+		{name: "escrow refund", debit: domain.ESCROW_REFUND, credit: domain.ESCROW_REFUND},
+		{name: "voucher fund", debit: domain.VOUCHER_FUND, credit: domain.VOUCHER_FUND},
+		{name: "voucher redeem", debit: domain.VOUCHER_REDEEM, credit: domain.VOUCHER_REDEEM},
+		{name: "voucher reclaim", debit: domain.VOUCHER_RECLAIM, credit: domain.VOUCHER_RECLAIM},
+		{name: "cashback", debit: domain.CASHBACK, credit: domain.CASHBACK},
+		{name: "points payout", debit: domain.POINTS_PAYOUT, credit: domain.POINTS_PAYOUT},
+	}
+
+	unbalanced := ledgerCheckQuery(t, domain.CheckUnbalancedPosting)
+	orphan := ledgerCheckQuery(t, domain.CheckOrphanRecord)
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Contains(t, unbalanced, "'"+string(tc.debit)+"'")
+			require.Contains(t, orphan, "'"+string(tc.credit)+"'")
+		})
+	}
+}
@@ -0,0 +1,292 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/Te8va/wallet/internal/domain"
+	appErrors "github.com/Te8va/wallet/internal/errors"
+)
+
+const escrowColumns = `deal_id, wallet_id, buyer_wallet_id, seller_wallet_id, amount, status, on_timeout,
+	expires_at, created_at, updated_at`
+
+// EscrowRepository keeps escrows. The funds of each escrow sit in a wallet
+// of kind ESCROW, and every posting to or from it carries the reference
+// "escrow:<deal id>", so the money trail of a deal can be read from the
+// journal.
+type EscrowRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewEscrowRepository(db *pgxpool.Pool) (*EscrowRepository, error) {
+	if db == nil {
+		return nil, fmt.Errorf("database connection is nil")
+	}
+	return &EscrowRepository{db: db}, nil
+}
+
+func escrowReference(dealID string) string {
+	return "escrow:" + dealID
+}
+
+func scanEscrow(row pgx.Row) (domain.Escrow, error) {
+	var e domain.Escrow
+	err := row.Scan(&e.DealID, &e.WalletID, &e.BuyerWalletID, &e.SellerWalletID, &e.Amount, &e.Status,
+		&e.OnTimeout, &e.ExpiresAt, &e.CreatedAt, &e.UpdatedAt)
+	return e, err
+}
+
+// CreateEscrow opens an escrow wallet for the deal and moves the amount
+// into it from the buyer wallet.
+func (r *EscrowRepository) CreateEscrow(ctx context.Context, req domain.EscrowRequest) (domain.Escrow, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return domain.Escrow{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer rollback(ctx, tx)
+
+	// The party wallets are locked before the escrow row is inserted: its
+	// foreign keys take a share lock on them, which upgrading to FOR UPDATE
+	// later would turn into a deadlock with a concurrent operation.
+	balances, err := lockWallets(ctx, tx, []string{req.BuyerWalletID, req.SellerWalletID}, nil)
+	if err != nil {
+		return domain.Escrow{}, err
+	}
+
+	if err = checkUserWallets(ctx, tx, req.BuyerWalletID, req.SellerWalletID); err != nil {
+		return domain.Escrow{}, err
+	}
+
+	var walletID string
+	err = tx.QueryRow(ctx,
+		`INSERT INTO wallet (id, balance, kind) VALUES (gen_random_uuid()::text, 0, $1) RETURNING id`,
+		domain.WalletEscrow,
+	).Scan(&walletID)
+	if err != nil {
+		return domain.Escrow{}, fmt.Errorf("failed to create escrow wallet: %w", err)
+	}
+
+	e, err := scanEscrow(tx.QueryRow(ctx,
+		`INSERT INTO escrow (deal_id, wallet_id, buyer_wallet_id, seller_wallet_id, amount, status, on_timeout, expires_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		 RETURNING `+escrowColumns,
+		req.DealID, walletID, req.BuyerWalletID, req.SellerWalletID, req.Amount, domain.EscrowFunded,
+		req.OnTimeout, req.ExpiresAt,
+	))
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) {
+			switch pgErr.Code {
+			case pgForeignKeyViolation:
+				return domain.Escrow{}, appErrors.ErrWalletNotFound
+			case pgUniqueViolation:
+				return domain.Escrow{}, appErrors.ErrEscrowExists
+			}
+		}
+		return domain.Escrow{}, fmt.Errorf("failed to create escrow: %w", err)
+	}
+
+	if balances[req.BuyerWalletID] < req.Amount {
+		return domain.Escrow{}, appErrors.ErrInsufficientFunds
+	}
+
+	if err = postEscrowMove(ctx, tx, e, req.BuyerWalletID, walletID, domain.ESCROW_HOLD, req.Amount); err != nil {
+		return domain.Escrow{}, err
+	}
+
+	event, err := insertEscrowEvent(ctx, tx, e.DealID, domain.EscrowTransition{
+		Action: domain.EscrowFund,
+		To:     domain.EscrowFunded,
+	})
+	if err != nil {
+		return domain.Escrow{}, err
+	}
+	e.Events = []domain.EscrowEvent{event}
+
+	if err = tx.Commit(ctx); err != nil {
+		return domain.Escrow{}, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return e, nil
+}
+
+// GetEscrow returns the escrow with its events, oldest first.
+func (r *EscrowRepository) GetEscrow(ctx context.Context, dealID string) (domain.Escrow, error) {
+	e, err := scanEscrow(r.db.QueryRow(ctx,
+		`SELECT `+escrowColumns+` FROM escrow WHERE deal_id = $1`,
+		dealID,
+	))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.Escrow{}, appErrors.ErrEscrowNotFound
+		}
+		return domain.Escrow{}, fmt.Errorf("failed to get escrow: %w", err)
+	}
+
+	rows, err := r.db.Query(ctx,
+		`SELECT id, action, COALESCE(from_status, ''), to_status, seller_amount, buyer_amount, reason, created_at
+		 FROM escrow_event WHERE deal_id = $1 ORDER BY id`,
+		dealID,
+	)
+	if err != nil {
+		return domain.Escrow{}, fmt.Errorf("failed to get escrow events: %w", err)
+	}
+
+	e.Events, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (domain.EscrowEvent, error) {
+		var ev domain.EscrowEvent
+		err := row.Scan(&ev.ID, &ev.Action, &ev.FromStatus, &ev.ToStatus, &ev.SellerAmount, &ev.BuyerAmount,
+			&ev.Reason, &ev.CreatedAt)
+		return ev, err
+	})
+	if err != nil {
+		return domain.Escrow{}, fmt.Errorf("failed to get escrow events: %w", err)
+	}
+
+	return e, nil
+}
+
+// TransitionEscrow applies t in one transaction: it pays the seller and
+// buyer amounts out of the escrow wallet, moves the escrow to t.To and
+// records the event. The escrow row is locked first and the transition is
+// rejected with ErrInvalidEscrowState unless the escrow is still in t.From,
+// so of two concurrent transitions only one takes effect.
+func (r *EscrowRepository) TransitionEscrow(ctx context.Context, t domain.EscrowTransition) (domain.Escrow, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return domain.Escrow{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer rollback(ctx, tx)
+
+	e, err := scanEscrow(tx.QueryRow(ctx,
+		`SELECT `+escrowColumns+` FROM escrow WHERE deal_id = $1 FOR UPDATE`,
+		t.DealID,
+	))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.Escrow{}, appErrors.ErrEscrowNotFound
+		}
+		return domain.Escrow{}, fmt.Errorf("failed to get escrow: %w", err)
+	}
+
+	if e.Status != t.From {
+		return domain.Escrow{}, appErrors.ErrInvalidEscrowState
+	}
+
+	if t.SellerAmount+t.BuyerAmount > 0 {
+		_, err = lockWallets(ctx, tx, []string{e.WalletID, e.SellerWalletID, e.BuyerWalletID}, nil)
+		if err != nil {
+			return domain.Escrow{}, err
+		}
+	}
+
+	payouts := []struct {
+		walletID string
+		opType   domain.OperationType
+		amount   int64
+	}{
+		{e.SellerWalletID, domain.ESCROW_RELEASE, t.SellerAmount},
+		{e.BuyerWalletID, domain.ESCROW_REFUND, t.BuyerAmount},
+	}
+	for _, p := range payouts {
+		if p.amount == 0 {
+			continue
+		}
+		if err = postEscrowMove(ctx, tx, e, e.WalletID, p.walletID, p.opType, p.amount); err != nil {
+			return domain.Escrow{}, err
+		}
+	}
+
+	err = tx.QueryRow(ctx,
+		`UPDATE escrow SET status = $2, updated_at = now() WHERE deal_id = $1 RETURNING updated_at`,
+		e.DealID, t.To,
+	).Scan(&e.UpdatedAt)
+	if err != nil {
+		return domain.Escrow{}, fmt.Errorf("failed to update escrow: %w", err)
+	}
+	e.Status = t.To
+
+	if _, err = insertEscrowEvent(ctx, tx, e.DealID, t); err != nil {
+		return domain.Escrow{}, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return domain.Escrow{}, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return e, nil
+}
+
+// ListExpiredEscrows returns up to limit funded escrows that expired at now.
+func (r *EscrowRepository) ListExpiredEscrows(ctx context.Context, now time.Time, limit int) ([]domain.Escrow, error) {
+	rows, err := r.db.Query(ctx,
+		`SELECT `+escrowColumns+` FROM escrow
+		 WHERE status = $1 AND expires_at <= $2
+		 ORDER BY expires_at
+		 LIMIT $3`,
+		domain.EscrowFunded, now, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list expired escrows: %w", err)
+	}
+
+	escrows, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (domain.Escrow, error) {
+		return scanEscrow(row)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list expired escrows: %w", err)
+	}
+
+	return escrows, nil
+}
+
+// postEscrowMove posts a debit and its credit leg between two wallets of the
+// escrow. The caller holds the wallet row locks.
+func postEscrowMove(ctx context.Context, tx pgx.Tx, e domain.Escrow, fromWalletID, toWalletID string, opType domain.OperationType, amount int64) error {
+	debit, err := postEntry(ctx, tx, entry{
+		walletID:  fromWalletID,
+		opType:    opType,
+		delta:     -amount,
+		reference: escrowReference(e.DealID),
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = postEntry(ctx, tx, entry{
+		walletID:  toWalletID,
+		opType:    opType,
+		delta:     amount,
+		parentID:  debit.ID,
+		reference: escrowReference(e.DealID),
+	})
+	return err
+}
+
+func insertEscrowEvent(ctx context.Context, tx pgx.Tx, dealID string, t domain.EscrowTransition) (domain.EscrowEvent, error) {
+	ev := domain.EscrowEvent{
+		Action:       t.Action,
+		FromStatus:   t.From,
+		ToStatus:     t.To,
+		SellerAmount: t.SellerAmount,
+		BuyerAmount:  t.BuyerAmount,
+		Reason:       t.Reason,
+	}
+	err := tx.QueryRow(ctx,
+		`INSERT INTO escrow_event (deal_id, action, from_status, to_status, seller_amount, buyer_amount, reason)
+		 VALUES ($1, $2, NULLIF($3, ''), $4, $5, $6, $7)
+		 RETURNING id, created_at`,
+		dealID, t.Action, t.From, t.To, t.SellerAmount, t.BuyerAmount, t.Reason,
+	).Scan(&ev.ID, &ev.CreatedAt)
+	if err != nil {
+		return domain.EscrowEvent{}, fmt.Errorf("failed to record escrow event: %w", err)
+	}
+
+	return ev, nil
+}
@@ -0,0 +1,200 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/Te8va/wallet/internal/domain"
+	appErrors "github.com/Te8va/wallet/internal/errors"
+)
+
+const nanoPerUnit = 1_000_000_000
+
+type InterestRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewInterestRepository(db *pgxpool.Pool) (*InterestRepository, error) {
+	if db == nil {
+		return nil, fmt.Errorf("database connection is nil")
+	}
+	return &InterestRepository{db: db}, nil
+}
+
+func (r *InterestRepository) SaveSavingsAccount(ctx context.Context, account domain.SavingsAccount) (domain.SavingsAccount, error) {
+	err := r.db.QueryRow(ctx,
+		`INSERT INTO savings_account (wallet_id, annual_rate_bps, day_count)
+		 VALUES ($1, $2, $3)
+		 ON CONFLICT (wallet_id) DO UPDATE
+		 SET annual_rate_bps = EXCLUDED.annual_rate_bps, day_count = EXCLUDED.day_count
+		 RETURNING accrued_nano`,
+		account.WalletID, account.AnnualRateBps, account.DayCount,
+	).Scan(&account.AccruedNano)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgForeignKeyViolation {
+			return domain.SavingsAccount{}, appErrors.ErrWalletNotFound
+		}
+		return domain.SavingsAccount{}, fmt.Errorf("failed to save savings account: %w", err)
+	}
+
+	return account, nil
+}
+
+func (r *InterestRepository) GetSavingsAccount(ctx context.Context, walletID string) (domain.SavingsAccount, error) {
+	account := domain.SavingsAccount{WalletID: walletID}
+	err := r.db.QueryRow(ctx,
+		`SELECT annual_rate_bps, day_count, accrued_nano FROM savings_account WHERE wallet_id = $1`,
+		walletID,
+	).Scan(&account.AnnualRateBps, &account.DayCount, &account.AccruedNano)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.SavingsAccount{}, appErrors.ErrSavingsAccountNotFound
+		}
+		return domain.SavingsAccount{}, fmt.Errorf("failed to get savings account: %w", err)
+	}
+
+	return account, nil
+}
+
+func (r *InterestRepository) ListSavingsAccounts(ctx context.Context) ([]domain.SavingsAccount, error) {
+	rows, err := r.db.Query(ctx,
+		`SELECT wallet_id, annual_rate_bps, day_count, accrued_nano FROM savings_account ORDER BY wallet_id`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list savings accounts: %w", err)
+	}
+
+	accounts, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (domain.SavingsAccount, error) {
+		var a domain.SavingsAccount
+		err := row.Scan(&a.WalletID, &a.AnnualRateBps, &a.DayCount, &a.AccruedNano)
+		return a, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list savings accounts: %w", err)
+	}
+
+	return accounts, nil
+}
+
+func (r *InterestRepository) BalanceAt(ctx context.Context, walletID string, at time.Time) (int64, error) {
+	return balanceAt(ctx, r.db, walletID, at)
+}
+
+// RecordAccrual stores the interest accrued for a single day and adds it to
+// the running fractional total. It reports false when the day was already
+// accrued, so re-running a date has no effect.
+func (r *InterestRepository) RecordAccrual(ctx context.Context, walletID string, date time.Time, balance, amountNano int64) (bool, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer rollback(ctx, tx)
+
+	tag, err := tx.Exec(ctx,
+		`INSERT INTO interest_accrual (wallet_id, accrual_date, balance, amount_nano)
+		 VALUES ($1, $2, $3, $4)
+		 ON CONFLICT (wallet_id, accrual_date) DO NOTHING`,
+		walletID, date, balance, amountNano,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to record accrual: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return false, nil
+	}
+
+	_, err = tx.Exec(ctx,
+		`UPDATE savings_account SET accrued_nano = accrued_nano + $1 WHERE wallet_id = $2`,
+		amountNano, walletID,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to update accrued interest: %w", err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return true, nil
+}
+
+// CreditInterest moves the whole units of accrued interest to the wallet
+// balance as an INTEREST journal entry, keeping the fractional remainder for
+// the next period. A period is credited at most once.
+func (r *InterestRepository) CreditInterest(ctx context.Context, walletID string, period time.Time) (int64, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer rollback(ctx, tx)
+
+	var accrued int64
+	err = tx.QueryRow(ctx,
+		`SELECT accrued_nano FROM savings_account WHERE wallet_id = $1 FOR UPDATE`,
+		walletID,
+	).Scan(&accrued)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, appErrors.ErrSavingsAccountNotFound
+		}
+		return 0, fmt.Errorf("failed to get savings account: %w", err)
+	}
+
+	amount := accrued / nanoPerUnit
+
+	tag, err := tx.Exec(ctx,
+		`INSERT INTO interest_credit (wallet_id, period, amount)
+		 VALUES ($1, $2, $3)
+		 ON CONFLICT (wallet_id, period) DO NOTHING`,
+		walletID, period, amount,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to record interest credit: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return 0, nil
+	}
+
+	if amount > 0 {
+		_, err = tx.Exec(ctx, `SELECT 1 FROM wallet WHERE id = $1 FOR UPDATE`, walletID)
+		if err != nil {
+			return 0, fmt.Errorf("failed to lock wallet: %w", err)
+		}
+
+		credit, err := postEntry(ctx, tx, entry{walletID: walletID, opType: domain.INTEREST, delta: amount})
+		if err != nil {
+			return 0, err
+		}
+
+		_, err = tx.Exec(ctx,
+			`UPDATE interest_credit SET transaction_id = $1 WHERE wallet_id = $2 AND period = $3`,
+			credit.ID, walletID, period,
+		)
+		if err != nil {
+			return 0, fmt.Errorf("failed to record interest credit: %w", err)
+		}
+
+		_, err = tx.Exec(ctx,
+			`UPDATE savings_account SET accrued_nano = accrued_nano - $1 WHERE wallet_id = $2`,
+			amount*nanoPerUnit, walletID,
+		)
+		if err != nil {
+			return 0, fmt.Errorf("failed to update accrued interest: %w", err)
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return amount, nil
+}
@@ -0,0 +1,304 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.uber.org/zap"
+
+	"github.com/Te8va/wallet/internal/domain"
+	appErrors "github.com/Te8va/wallet/internal/errors"
+	"github.com/Te8va/wallet/internal/logging"
+)
+
+const (
+	pgForeignKeyViolation = "23503"
+	pgUniqueViolation     = "23505"
+)
+
+// rollback ends a transaction that was not committed. The caller is already
+// returning the original error, so a failed rollback is only logged.
+func rollback(ctx context.Context, tx pgx.Tx) {
+	if err := tx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) && ctx.Err() == nil {
+		logging.FromContext(ctx).Error("Failed to roll back transaction", zap.Error(err))
+	}
+}
+
+type querier interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// entry describes a single journal posting.
+type entry struct {
+	walletID       string
+	opType         domain.OperationType
+	delta          int64
+	parentID       int64
+	idempotencyKey string
+	reference      string
+	description    string
+	metadata       map[string]any
+	tags           []string
+}
+
+// postEntry appends a journal entry, the wallet event, and applies it to the
+// wallet table, which is the balance projection of the journal. Both happen
+// in the caller's transaction; the caller is expected to hold the wallet row
+// lock.
+func postEntry(ctx context.Context, tx pgx.Tx, e entry) (domain.Transaction, error) {
+	t := domain.Transaction{
+		WalletID:       e.walletID,
+		OperationType:  e.opType,
+		Amount:         e.delta,
+		ParentID:       e.parentID,
+		IdempotencyKey: sentKey(e.walletID, e.idempotencyKey),
+		Reference:      e.reference,
+		Description:    e.description,
+		Metadata:       e.metadata,
+		Tags:           e.tags,
+	}
+
+	// Empty metadata and tags are stored as NULL rather than as empty JSON.
+	var metadata, tags any
+	if len(e.metadata) > 0 {
+		metadata = e.metadata
+	}
+	if len(e.tags) > 0 {
+		tags = e.tags
+	}
+
+	err := tx.QueryRow(ctx,
+		`UPDATE wallet SET balance = balance + $1 WHERE id = $2 RETURNING balance`,
+		e.delta, e.walletID,
+	).Scan(&t.Balance)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.Transaction{}, appErrors.ErrWalletNotFound
+		}
+		return domain.Transaction{}, fmt.Errorf("failed to update balance: %w", err)
+	}
+
+	err = tx.QueryRow(ctx,
+		`INSERT INTO wallet_transaction (wallet_id, operation_type, amount, balance_after, parent_id, idempotency_key,
+			reference, description, metadata, tags)
+		 VALUES ($1, $2, $3, $4, NULLIF($5, 0), NULLIF($6, ''), NULLIF($7, ''), NULLIF($8, ''), $9, $10)
+		 RETURNING id, created_at`,
+		e.walletID, e.opType, e.delta, t.Balance, e.parentID, e.idempotencyKey,
+		e.reference, e.description, metadata, tags,
+	).Scan(&t.ID, &t.CreatedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgUniqueViolation {
+			return domain.Transaction{}, appErrors.ErrDuplicateOperation
+		}
+		return domain.Transaction{}, fmt.Errorf("failed to append journal entry: %w", err)
+	}
+
+	return t, nil
+}
+
+// lockWallets locks the given wallet rows in a stable order, so concurrent
+// multi-wallet operations cannot deadlock, and returns their spendable
+// balances, which exclude money set aside in pockets.
+// Wallets listed in create are inserted with a zero balance when missing.
+func lockWallets(ctx context.Context, tx pgx.Tx, walletIDs []string, create map[string]bool) (map[string]int64, error) {
+	ids := slices.Clone(walletIDs)
+	slices.Sort(ids)
+	ids = slices.Compact(ids)
+
+	balances := make(map[string]int64, len(ids))
+	for _, id := range ids {
+		if create[id] {
+			_, err := tx.Exec(ctx,
+				`INSERT INTO wallet (id, balance) VALUES ($1, 0) ON CONFLICT (id) DO NOTHING`,
+				id,
+			)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create wallet: %w", err)
+			}
+		}
+
+		var balance int64
+		err := tx.QueryRow(ctx,
+			`SELECT balance - pocketed FROM wallet WHERE id = $1 FOR UPDATE`,
+			id,
+		).Scan(&balance)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil, appErrors.ErrWalletNotFound
+			}
+			return nil, fmt.Errorf("failed to get wallet: %w", err)
+		}
+		balances[id] = balance
+	}
+
+	return balances, nil
+}
+
+// checkUserWallets rejects operations that would move money in or out of
+// internal wallets, such as the ones holding escrow funds, bypassing the
+// service that owns them.
+func checkUserWallets(ctx context.Context, q querier, walletIDs ...string) error {
+	var internal bool
+	err := q.QueryRow(ctx,
+		`SELECT EXISTS (SELECT 1 FROM wallet WHERE id = ANY($1) AND kind <> $2)`,
+		walletIDs, domain.WalletUser,
+	).Scan(&internal)
+	if err != nil {
+		return fmt.Errorf("failed to check wallet kind: %w", err)
+	}
+
+	if internal {
+		return appErrors.ErrWalletKindNotAllowed
+	}
+
+	return nil
+}
+
+// clientKeyPrefix starts the stored form of an idempotency key sent by a
+// client. Keys are stored as client:<wallet>:<key>, so each wallet has its
+// own keys and none of them can collide with the keys the service posts
+// under itself, such as schedule:<id>:<run>.
+const clientKeyPrefix = "client:"
+
+// clientKey returns the stored form of the key a client sent for an
+// operation of the wallet.
+func clientKey(walletID, key string) string {
+	if key == "" {
+		return ""
+	}
+
+	return clientKeyPrefix + walletID + ":" + key
+}
+
+// sentKey returns the key as the client sent it for an entry of the wallet.
+// Keys the service posted under itself are returned as stored.
+func sentKey(walletID, stored string) string {
+	return strings.TrimPrefix(stored, clientKeyPrefix+walletID+":")
+}
+
+// checkIdempotencyKey reports ErrDuplicateOperation when an operation with
+// the key has already been journaled.
+func checkIdempotencyKey(ctx context.Context, q querier, key string) error {
+	if key == "" {
+		return nil
+	}
+
+	var exists bool
+	err := q.QueryRow(ctx,
+		`SELECT EXISTS (SELECT 1 FROM wallet_transaction WHERE idempotency_key = $1)`,
+		key,
+	).Scan(&exists)
+	if err != nil {
+		return fmt.Errorf("failed to check idempotency key: %w", err)
+	}
+
+	if exists {
+		return appErrors.ErrDuplicateOperation
+	}
+
+	return nil
+}
+
+// transactionColumns are the journal columns read by scanFullTransaction.
+const transactionColumns = `id, wallet_id, operation_type, amount, balance_after, COALESCE(parent_id, 0),
+	COALESCE(idempotency_key, ''), COALESCE(reference, ''), COALESCE(description, ''), COALESCE(metadata, '{}'),
+	COALESCE(tags, '[]'), created_at`
+
+// transactionByIdempotencyKey returns the journal entry posted with the key.
+func transactionByIdempotencyKey(ctx context.Context, q querier, key string) (domain.Transaction, bool, error) {
+	t, err := scanFullTransaction(q.QueryRow(ctx,
+		`SELECT `+transactionColumns+` FROM wallet_transaction WHERE idempotency_key = $1`,
+		key,
+	))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.Transaction{}, false, nil
+		}
+		return domain.Transaction{}, false, fmt.Errorf("failed to get transaction by idempotency key: %w", err)
+	}
+
+	return t, true, nil
+}
+
+// transactionByReference returns the first journal entry of the wallet
+// posted with the reference.
+func transactionByReference(ctx context.Context, q querier, walletID, reference string) (domain.Transaction, bool, error) {
+	t, err := scanFullTransaction(q.QueryRow(ctx,
+		`SELECT `+transactionColumns+` FROM wallet_transaction
+		 WHERE wallet_id = $1 AND reference = $2
+		 ORDER BY id
+		 LIMIT 1`,
+		walletID, reference,
+	))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.Transaction{}, false, nil
+		}
+		return domain.Transaction{}, false, fmt.Errorf("failed to get transaction by reference: %w", err)
+	}
+
+	return t, true, nil
+}
+
+// scanFullTransaction reads a row selected with transactionColumns.
+func scanFullTransaction(row pgx.Row) (domain.Transaction, error) {
+	var t domain.Transaction
+	err := row.Scan(&t.ID, &t.WalletID, &t.OperationType, &t.Amount, &t.Balance, &t.ParentID, &t.IdempotencyKey,
+		&t.Reference, &t.Description, &t.Metadata, &t.Tags, &t.CreatedAt)
+	if err != nil {
+		return domain.Transaction{}, err
+	}
+
+	t.IdempotencyKey = sentKey(t.WalletID, t.IdempotencyKey)
+
+	if len(t.Metadata) == 0 {
+		t.Metadata = nil
+	}
+	if len(t.Tags) == 0 {
+		t.Tags = nil
+	}
+
+	return t, nil
+}
+
+func scanTransaction(row pgx.CollectableRow) (domain.Transaction, error) {
+	var t domain.Transaction
+	err := row.Scan(&t.ID, &t.WalletID, &t.OperationType, &t.Amount, &t.Balance, &t.ParentID, &t.CreatedAt)
+	return t, err
+}
+
+// balanceAt returns the wallet balance right before the given moment,
+// reconstructed from the journal.
+func balanceAt(ctx context.Context, q querier, walletID string, at time.Time) (int64, error) {
+	var balance int64
+	err := q.QueryRow(ctx,
+		`SELECT balance_after FROM wallet_transaction
+		 WHERE wallet_id = $1 AND created_at < $2
+		 ORDER BY created_at DESC, id DESC
+		 LIMIT 1`,
+		walletID, at,
+	).Scan(&balance)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("failed to get balance from journal: %w", err)
+	}
+
+	return balance, nil
+}
+
+func abs(n int64) int64 {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,522 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/Te8va/wallet/internal/domain"
+	appErrors "github.com/Te8va/wallet/internal/errors"
+)
+
+const loyaltyRuleColumns = `id, name, rate_bps, min_amount, tag, starts_at, ends_at, created_at`
+
+// LoyaltyRepository keeps loyalty rules and the points of each wallet.
+// Points live in a wallet of kind POINTS linked to the wallet through
+// points_account, so they are journaled like money but can't be moved by
+// money operations. The balance of a points wallet is split into lots, one
+// per earning, that are spent and expire oldest first.
+type LoyaltyRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewLoyaltyRepository(db *pgxpool.Pool) (*LoyaltyRepository, error) {
+	if db == nil {
+		return nil, fmt.Errorf("database connection is nil")
+	}
+	return &LoyaltyRepository{db: db}, nil
+}
+
+func scanLoyaltyRule(row pgx.Row) (domain.LoyaltyRule, error) {
+	var lr domain.LoyaltyRule
+	err := row.Scan(&lr.ID, &lr.Name, &lr.RateBps, &lr.MinAmount, &lr.Tag, &lr.StartsAt, &lr.EndsAt, &lr.CreatedAt)
+	return lr, err
+}
+
+func (r *LoyaltyRepository) CreateRule(ctx context.Context, params domain.LoyaltyRuleParams) (domain.LoyaltyRule, error) {
+	lr, err := scanLoyaltyRule(r.db.QueryRow(ctx,
+		`INSERT INTO loyalty_rule (name, rate_bps, min_amount, tag, starts_at, ends_at)
+		 VALUES ($1, $2, $3, $4, $5, $6)
+		 RETURNING `+loyaltyRuleColumns,
+		params.Name, params.RateBps, params.MinAmount, params.Tag, params.StartsAt, params.EndsAt,
+	))
+	if err != nil {
+		return domain.LoyaltyRule{}, fmt.Errorf("failed to create loyalty rule: %w", err)
+	}
+
+	return lr, nil
+}
+
+// ListRules returns all loyalty rules, newest first.
+func (r *LoyaltyRepository) ListRules(ctx context.Context) ([]domain.LoyaltyRule, error) {
+	return r.listRules(ctx, `SELECT `+loyaltyRuleColumns+` FROM loyalty_rule ORDER BY id DESC`)
+}
+
+// ListActiveRules returns the rules in effect at the given time.
+func (r *LoyaltyRepository) ListActiveRules(ctx context.Context, at time.Time) ([]domain.LoyaltyRule, error) {
+	return r.listRules(ctx,
+		`SELECT `+loyaltyRuleColumns+` FROM loyalty_rule
+		 WHERE starts_at <= $1 AND (ends_at IS NULL OR ends_at > $1)
+		 ORDER BY id`,
+		at,
+	)
+}
+
+func (r *LoyaltyRepository) listRules(ctx context.Context, sql string, args ...any) ([]domain.LoyaltyRule, error) {
+	rows, err := r.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list loyalty rules: %w", err)
+	}
+
+	rules, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (domain.LoyaltyRule, error) {
+		return scanLoyaltyRule(row)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list loyalty rules: %w", err)
+	}
+
+	return rules, nil
+}
+
+// EarnPoints credits the points of an earning to the points wallet of the
+// wallet, opening it on the first earning, and stores them as a new lot.
+// The wallet row is locked first, so concurrent earnings open one points
+// wallet. A second earning for the same rule and source transaction returns
+// ErrDuplicateOperation.
+func (r *LoyaltyRepository) EarnPoints(ctx context.Context, pe domain.PointsEarning) (domain.Transaction, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return domain.Transaction{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer rollback(ctx, tx)
+
+	if _, err = lockWallets(ctx, tx, []string{pe.WalletID}, nil); err != nil {
+		return domain.Transaction{}, err
+	}
+
+	var earned bool
+	err = tx.QueryRow(ctx,
+		`SELECT EXISTS (SELECT 1 FROM points_lot WHERE rule_id = $1 AND source_transaction_id = $2)`,
+		pe.RuleID, pe.SourceTransactionID,
+	).Scan(&earned)
+	if err != nil {
+		return domain.Transaction{}, fmt.Errorf("failed to check points lot: %w", err)
+	}
+	if earned {
+		return domain.Transaction{}, appErrors.ErrDuplicateOperation
+	}
+
+	pointsWalletID, found, err := pointsWalletOf(ctx, tx, pe.WalletID)
+	if err != nil {
+		return domain.Transaction{}, err
+	}
+	if !found {
+		if pointsWalletID, err = openPointsWallet(ctx, tx, pe.WalletID); err != nil {
+			return domain.Transaction{}, err
+		}
+	}
+
+	if _, err = lockWallets(ctx, tx, []string{pointsWalletID}, nil); err != nil {
+		return domain.Transaction{}, err
+	}
+
+	credit, err := postEntry(ctx, tx, entry{
+		walletID:       pointsWalletID,
+		opType:         domain.POINTS_EARN,
+		delta:          pe.Points,
+		idempotencyKey: fmt.Sprintf("points:%d:%d", pe.RuleID, pe.SourceTransactionID),
+		reference:      "loyalty-rule:" + strconv.FormatInt(pe.RuleID, 10),
+	})
+	if errors.Is(err, appErrors.ErrDuplicateOperation) {
+		// Earnings of the wallet are serialized by its row lock and no lot
+		// has been recorded for the rule and source, so whatever holds the
+		// key is not this earning.
+		return domain.Transaction{}, appErrors.ErrIdempotencyKeyReused
+	}
+	if err != nil {
+		return domain.Transaction{}, err
+	}
+
+	_, err = tx.Exec(ctx,
+		`INSERT INTO points_lot (points_wallet_id, rule_id, source_transaction_id, points, remaining, expires_at,
+			transaction_id)
+		 VALUES ($1, $2, $3, $4, $4, $5, $6)`,
+		pointsWalletID, pe.RuleID, pe.SourceTransactionID, pe.Points, pe.ExpiresAt, credit.ID,
+	)
+	if err != nil {
+		return domain.Transaction{}, fmt.Errorf("failed to create points lot: %w", err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return domain.Transaction{}, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return credit, nil
+}
+
+// GetPoints returns the points of the wallet that are still valid at now,
+// with the lots they are kept in, the first to expire first.
+func (r *LoyaltyRepository) GetPoints(ctx context.Context, walletID string, now time.Time) (domain.PointsBalance, error) {
+	var exists bool
+	err := r.db.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM wallet WHERE id = $1)`, walletID).Scan(&exists)
+	if err != nil {
+		return domain.PointsBalance{}, fmt.Errorf("failed to get wallet: %w", err)
+	}
+	if !exists {
+		return domain.PointsBalance{}, appErrors.ErrWalletNotFound
+	}
+
+	pb := domain.PointsBalance{WalletID: walletID, Lots: []domain.PointsLot{}}
+
+	pointsWalletID, found, err := pointsWalletOf(ctx, r.db, walletID)
+	if err != nil || !found {
+		return pb, err
+	}
+	pb.PointsWalletID = pointsWalletID
+
+	rows, err := r.db.Query(ctx,
+		`SELECT id, points, remaining, expires_at, created_at FROM points_lot
+		 WHERE points_wallet_id = $1 AND remaining > 0 AND expires_at > $2
+		 ORDER BY expires_at, id`,
+		pointsWalletID, now,
+	)
+	if err != nil {
+		return domain.PointsBalance{}, fmt.Errorf("failed to list points lots: %w", err)
+	}
+
+	pb.Lots, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (domain.PointsLot, error) {
+		var l domain.PointsLot
+		err := row.Scan(&l.ID, &l.Points, &l.Remaining, &l.ExpiresAt, &l.CreatedAt)
+		return l, err
+	})
+	if err != nil {
+		return domain.PointsBalance{}, fmt.Errorf("failed to list points lots: %w", err)
+	}
+
+	for _, l := range pb.Lots {
+		pb.Balance += l.Remaining
+	}
+
+	return pb, nil
+}
+
+// RedeemPoints spends the points of the wallet, taking them from the lots
+// that expire first, and pays the amount from the funding wallet. The points
+// debit carries the idempotency key; the funding debit and the credit of the
+// wallet follow it as its children. A repeated key returns the stored
+// redemption with Replayed set, or ErrIdempotencyKeyReused when it was used
+// for a different one.
+func (r *LoyaltyRepository) RedeemPoints(ctx context.Context, pr domain.PointsRedemption, now time.Time) (domain.PointsRedemptionResult, error) {
+	res, err := r.redeemPoints(ctx, pr, now)
+	if errors.Is(err, appErrors.ErrDuplicateOperation) && pr.IdempotencyKey != "" {
+		// A concurrent redemption with the same key committed first.
+		if replayed, replayErr := replayRedemption(ctx, r.db, pr); !errors.Is(replayErr, errNotReplayed) {
+			return replayed, replayErr
+		}
+	}
+
+	return res, err
+}
+
+func (r *LoyaltyRepository) redeemPoints(ctx context.Context, pr domain.PointsRedemption, now time.Time) (domain.PointsRedemptionResult, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return domain.PointsRedemptionResult{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer rollback(ctx, tx)
+
+	if pr.IdempotencyKey != "" {
+		res, err := replayRedemption(ctx, tx, pr)
+		if !errors.Is(err, errNotReplayed) {
+			return res, err
+		}
+	}
+
+	balances, err := lockWallets(ctx, tx, []string{pr.WalletID, pr.FundingWalletID}, nil)
+	if err != nil {
+		return domain.PointsRedemptionResult{}, err
+	}
+
+	if err = checkUserWallets(ctx, tx, pr.WalletID, pr.FundingWalletID); err != nil {
+		return domain.PointsRedemptionResult{}, err
+	}
+
+	pointsWalletID, found, err := pointsWalletOf(ctx, tx, pr.WalletID)
+	if err != nil {
+		return domain.PointsRedemptionResult{}, err
+	}
+	if !found {
+		return domain.PointsRedemptionResult{}, appErrors.ErrInsufficientFunds
+	}
+
+	if _, err = lockWallets(ctx, tx, []string{pointsWalletID}, nil); err != nil {
+		return domain.PointsRedemptionResult{}, err
+	}
+
+	if err = spendPointsLots(ctx, tx, pointsWalletID, pr.Points, now); err != nil {
+		return domain.PointsRedemptionResult{}, err
+	}
+
+	if balances[pr.FundingWalletID] < pr.Amount {
+		return domain.PointsRedemptionResult{}, appErrors.ErrInsufficientFunds
+	}
+
+	reference := "points-redeem:" + pr.WalletID
+	res := domain.PointsRedemptionResult{Points: pr.Points, Amount: pr.Amount}
+	res.Debit, err = postEntry(ctx, tx, entry{
+		walletID:       pointsWalletID,
+		opType:         domain.POINTS_REDEEM,
+		delta:          -pr.Points,
+		idempotencyKey: clientKey(pointsWalletID, pr.IdempotencyKey),
+		reference:      reference,
+	})
+	if err != nil {
+		return domain.PointsRedemptionResult{}, err
+	}
+
+	payout, err := postEntry(ctx, tx, entry{
+		walletID:  pr.FundingWalletID,
+		opType:    domain.POINTS_PAYOUT,
+		delta:     -pr.Amount,
+		parentID:  res.Debit.ID,
+		reference: reference,
+	})
+	if err != nil {
+		return domain.PointsRedemptionResult{}, err
+	}
+
+	res.Credit, err = postEntry(ctx, tx, entry{
+		walletID:  pr.WalletID,
+		opType:    domain.POINTS_PAYOUT,
+		delta:     pr.Amount,
+		parentID:  payout.ID,
+		reference: reference,
+	})
+	if err != nil {
+		return domain.PointsRedemptionResult{}, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return domain.PointsRedemptionResult{}, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return res, nil
+}
+
+// replayRedemption returns the redemption stored under the idempotency key
+// of pr, errNotReplayed when there is none, or ErrIdempotencyKeyReused when
+// the key holds a different operation.
+func replayRedemption(ctx context.Context, q querier, pr domain.PointsRedemption) (domain.PointsRedemptionResult, error) {
+	pointsWalletID, found, err := pointsWalletOf(ctx, q, pr.WalletID)
+	if err != nil {
+		return domain.PointsRedemptionResult{}, err
+	}
+	if !found {
+		return domain.PointsRedemptionResult{}, errNotReplayed
+	}
+
+	debit, found, err := transactionByIdempotencyKey(ctx, q, clientKey(pointsWalletID, pr.IdempotencyKey))
+	if err != nil {
+		return domain.PointsRedemptionResult{}, err
+	}
+	if !found {
+		return domain.PointsRedemptionResult{}, errNotReplayed
+	}
+
+	if debit.WalletID != pointsWalletID || debit.OperationType != domain.POINTS_REDEEM || debit.Amount != -pr.Points {
+		return domain.PointsRedemptionResult{}, appErrors.ErrIdempotencyKeyReused
+	}
+
+	payout, err := scanFullTransaction(q.QueryRow(ctx,
+		`SELECT `+transactionColumns+` FROM wallet_transaction WHERE parent_id = $1`,
+		debit.ID,
+	))
+	if err != nil {
+		return domain.PointsRedemptionResult{}, fmt.Errorf("failed to get points payout: %w", err)
+	}
+
+	credit, err := scanFullTransaction(q.QueryRow(ctx,
+		`SELECT `+transactionColumns+` FROM wallet_transaction WHERE parent_id = $1`,
+		payout.ID,
+	))
+	if err != nil {
+		return domain.PointsRedemptionResult{}, fmt.Errorf("failed to get points credit: %w", err)
+	}
+
+	if payout.WalletID != pr.FundingWalletID || credit.WalletID != pr.WalletID || credit.Amount != pr.Amount {
+		return domain.PointsRedemptionResult{}, appErrors.ErrIdempotencyKeyReused
+	}
+
+	return domain.PointsRedemptionResult{
+		Points:   pr.Points,
+		Amount:   pr.Amount,
+		Debit:    debit,
+		Credit:   credit,
+		Replayed: true,
+	}, nil
+}
+
+// ListExpiredPointsWallets returns up to limit points wallets that have
+// lots expired at now with points left in them.
+func (r *LoyaltyRepository) ListExpiredPointsWallets(ctx context.Context, now time.Time, limit int) ([]string, error) {
+	rows, err := r.db.Query(ctx,
+		`SELECT points_wallet_id FROM points_lot
+		 WHERE remaining > 0 AND expires_at <= $1
+		 GROUP BY points_wallet_id
+		 ORDER BY MIN(expires_at)
+		 LIMIT $2`,
+		now, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list expired points wallets: %w", err)
+	}
+
+	ids, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, fmt.Errorf("failed to list expired points wallets: %w", err)
+	}
+
+	return ids, nil
+}
+
+// ExpirePoints debits the points left in the lots of the points wallet that
+// expired at now and empties those lots. Lots spent in the meantime are
+// left out, so the job can run alongside redemptions.
+func (r *LoyaltyRepository) ExpirePoints(ctx context.Context, pointsWalletID string, now time.Time) (int64, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer rollback(ctx, tx)
+
+	if _, err = lockWallets(ctx, tx, []string{pointsWalletID}, nil); err != nil {
+		return 0, err
+	}
+
+	var expired int64
+	err = tx.QueryRow(ctx,
+		`WITH expired AS (
+		     UPDATE points_lot l SET remaining = 0
+		     FROM (SELECT id, remaining FROM points_lot
+		           WHERE points_wallet_id = $1 AND remaining > 0 AND expires_at <= $2
+		           FOR UPDATE) e
+		     WHERE l.id = e.id
+		     RETURNING e.remaining
+		 )
+		 SELECT COALESCE(SUM(remaining), 0) FROM expired`,
+		pointsWalletID, now,
+	).Scan(&expired)
+	if err != nil {
+		return 0, fmt.Errorf("failed to expire points lots: %w", err)
+	}
+
+	if expired == 0 {
+		return 0, nil
+	}
+
+	_, err = postEntry(ctx, tx, entry{
+		walletID: pointsWalletID,
+		opType:   domain.POINTS_EXPIRE,
+		delta:    -expired,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return expired, nil
+}
+
+// pointsWalletOf returns the points wallet of the wallet, if it has one.
+func pointsWalletOf(ctx context.Context, q querier, walletID string) (string, bool, error) {
+	var pointsWalletID string
+	err := q.QueryRow(ctx,
+		`SELECT points_wallet_id FROM points_account WHERE wallet_id = $1`,
+		walletID,
+	).Scan(&pointsWalletID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", false, nil
+		}
+		return "", false, fmt.Errorf("failed to get points account: %w", err)
+	}
+
+	return pointsWalletID, true, nil
+}
+
+// openPointsWallet creates the points wallet of the wallet. The caller is
+// expected to hold the wallet row lock.
+func openPointsWallet(ctx context.Context, tx pgx.Tx, walletID string) (string, error) {
+	var pointsWalletID string
+	err := tx.QueryRow(ctx,
+		`INSERT INTO wallet (id, balance, kind) VALUES (gen_random_uuid()::text, 0, $1) RETURNING id`,
+		domain.WalletPoints,
+	).Scan(&pointsWalletID)
+	if err != nil {
+		return "", fmt.Errorf("failed to create points wallet: %w", err)
+	}
+
+	_, err = tx.Exec(ctx,
+		`INSERT INTO points_account (wallet_id, points_wallet_id) VALUES ($1, $2)`,
+		walletID, pointsWalletID,
+	)
+	if err != nil {
+		return "", fmt.Errorf("failed to create points account: %w", err)
+	}
+
+	return pointsWalletID, nil
+}
+
+// spendPointsLots takes points from the valid lots of the points wallet,
+// the first to expire first, and returns ErrInsufficientFunds when they
+// don't hold enough.
+func spendPointsLots(ctx context.Context, tx pgx.Tx, pointsWalletID string, points int64, now time.Time) error {
+	rows, err := tx.Query(ctx,
+		`SELECT id, remaining FROM points_lot
+		 WHERE points_wallet_id = $1 AND remaining > 0 AND expires_at > $2
+		 ORDER BY expires_at, id
+		 FOR UPDATE`,
+		pointsWalletID, now,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to list points lots: %w", err)
+	}
+
+	type lot struct{ id, remaining int64 }
+	lots, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (lot, error) {
+		var l lot
+		err := row.Scan(&l.id, &l.remaining)
+		return l, err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list points lots: %w", err)
+	}
+
+	left := points
+	for _, l := range lots {
+		if left == 0 {
+			break
+		}
+
+		take := min(left, l.remaining)
+		_, err = tx.Exec(ctx, `UPDATE points_lot SET remaining = remaining - $2 WHERE id = $1`, l.id, take)
+		if err != nil {
+			return fmt.Errorf("failed to update points lot: %w", err)
+		}
+		left -= take
+	}
+
+	if left > 0 {
+		return appErrors.ErrInsufficientFunds
+	}
+
+	return nil
+}
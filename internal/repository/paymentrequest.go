@@ -0,0 +1,214 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/Te8va/wallet/internal/domain"
+	appErrors "github.com/Te8va/wallet/internal/errors"
+)
+
+const paymentRequestColumns = `id, payee_wallet_id, payer_wallet_id, amount, currency, memo, status, expires_at,
+	created_at, updated_at`
+
+type PaymentRequestRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPaymentRequestRepository(db *pgxpool.Pool) (*PaymentRequestRepository, error) {
+	if db == nil {
+		return nil, fmt.Errorf("database connection is nil")
+	}
+	return &PaymentRequestRepository{db: db}, nil
+}
+
+func scanPaymentRequest(row pgx.Row) (domain.PaymentRequest, error) {
+	var pr domain.PaymentRequest
+	err := row.Scan(&pr.ID, &pr.PayeeWalletID, &pr.PayerWalletID, &pr.Amount, &pr.Currency, &pr.Memo, &pr.Status,
+		&pr.ExpiresAt, &pr.CreatedAt, &pr.UpdatedAt)
+	return pr, err
+}
+
+func (r *PaymentRequestRepository) CreatePaymentRequest(ctx context.Context, params domain.PaymentRequestParams) (domain.PaymentRequest, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return domain.PaymentRequest{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer rollback(ctx, tx)
+
+	pr, err := scanPaymentRequest(tx.QueryRow(ctx,
+		`INSERT INTO payment_request (payee_wallet_id, payer_wallet_id, amount, currency, memo, status, expires_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7)
+		 RETURNING `+paymentRequestColumns,
+		params.PayeeWalletID, params.PayerWalletID, params.Amount, params.Currency, params.Memo,
+		domain.PaymentRequestPending, params.ExpiresAt,
+	))
+	if err != nil {
+		return domain.PaymentRequest{}, fmt.Errorf("failed to create payment request: %w", err)
+	}
+
+	event, err := insertPaymentRequestEvent(ctx, tx, pr.ID, "", pr.Status)
+	if err != nil {
+		return domain.PaymentRequest{}, err
+	}
+	pr.Events = []domain.PaymentRequestEvent{event}
+
+	if err = tx.Commit(ctx); err != nil {
+		return domain.PaymentRequest{}, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return pr, nil
+}
+
+// GetPaymentRequest returns the request with its events, oldest first.
+func (r *PaymentRequestRepository) GetPaymentRequest(ctx context.Context, id int64) (domain.PaymentRequest, error) {
+	pr, err := scanPaymentRequest(r.db.QueryRow(ctx,
+		`SELECT `+paymentRequestColumns+` FROM payment_request WHERE id = $1`,
+		id,
+	))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.PaymentRequest{}, appErrors.ErrPaymentRequestNotFound
+		}
+		return domain.PaymentRequest{}, fmt.Errorf("failed to get payment request: %w", err)
+	}
+
+	rows, err := r.db.Query(ctx,
+		`SELECT id, COALESCE(from_status, ''), to_status, created_at
+		 FROM payment_request_event WHERE request_id = $1 ORDER BY id`,
+		id,
+	)
+	if err != nil {
+		return domain.PaymentRequest{}, fmt.Errorf("failed to get payment request events: %w", err)
+	}
+
+	pr.Events, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (domain.PaymentRequestEvent, error) {
+		var ev domain.PaymentRequestEvent
+		err := row.Scan(&ev.ID, &ev.FromStatus, &ev.ToStatus, &ev.CreatedAt)
+		return ev, err
+	})
+	if err != nil {
+		return domain.PaymentRequest{}, fmt.Errorf("failed to get payment request events: %w", err)
+	}
+
+	return pr, nil
+}
+
+// ListPaymentRequests returns the requests of a wallet, newest first.
+func (r *PaymentRequestRepository) ListPaymentRequests(ctx context.Context, filter domain.PaymentRequestFilter) ([]domain.PaymentRequest, error) {
+	column := "payer_wallet_id"
+	if filter.Direction == domain.PaymentRequestOutgoing {
+		column = "payee_wallet_id"
+	}
+
+	rows, err := r.db.Query(ctx,
+		`SELECT `+paymentRequestColumns+` FROM payment_request
+		 WHERE `+column+` = $1 AND ($2 = '' OR status = $2)
+		 ORDER BY id DESC`,
+		filter.WalletID, filter.Status,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list payment requests: %w", err)
+	}
+
+	requests, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (domain.PaymentRequest, error) {
+		return scanPaymentRequest(row)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list payment requests: %w", err)
+	}
+
+	return requests, nil
+}
+
+// UpdatePaymentRequestStatus moves the request to status to if it is
+// currently in one of the from states, and records the event.
+func (r *PaymentRequestRepository) UpdatePaymentRequestStatus(ctx context.Context, id int64, from []domain.PaymentRequestStatus, to domain.PaymentRequestStatus) (domain.PaymentRequest, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return domain.PaymentRequest{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer rollback(ctx, tx)
+
+	pr, err := scanPaymentRequest(tx.QueryRow(ctx,
+		`SELECT `+paymentRequestColumns+` FROM payment_request WHERE id = $1 FOR UPDATE`,
+		id,
+	))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.PaymentRequest{}, appErrors.ErrPaymentRequestNotFound
+		}
+		return domain.PaymentRequest{}, fmt.Errorf("failed to get payment request: %w", err)
+	}
+
+	if !slices.Contains(from, pr.Status) {
+		return domain.PaymentRequest{}, appErrors.ErrPaymentRequestState
+	}
+
+	err = tx.QueryRow(ctx,
+		`UPDATE payment_request SET status = $2, updated_at = now() WHERE id = $1 RETURNING updated_at`,
+		id, to,
+	).Scan(&pr.UpdatedAt)
+	if err != nil {
+		return domain.PaymentRequest{}, fmt.Errorf("failed to update payment request: %w", err)
+	}
+
+	if _, err = insertPaymentRequestEvent(ctx, tx, id, pr.Status, to); err != nil {
+		return domain.PaymentRequest{}, err
+	}
+	pr.Status = to
+
+	if err = tx.Commit(ctx); err != nil {
+		return domain.PaymentRequest{}, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return pr, nil
+}
+
+// ExpirePaymentRequests moves up to limit pending requests that expired at
+// now to EXPIRED and returns how many were moved. Requests locked by a
+// concurrent accept or decline are left for the next run.
+func (r *PaymentRequestRepository) ExpirePaymentRequests(ctx context.Context, now time.Time, limit int) (int, error) {
+	tag, err := r.db.Exec(ctx,
+		`WITH expired AS (
+		     UPDATE payment_request SET status = $3, updated_at = now()
+		     WHERE id IN (
+		         SELECT id FROM payment_request
+		         WHERE status = $4 AND expires_at <= $1
+		         ORDER BY expires_at
+		         LIMIT $2
+		         FOR UPDATE SKIP LOCKED
+		     )
+		     RETURNING id
+		 )
+		 INSERT INTO payment_request_event (request_id, from_status, to_status)
+		 SELECT id, $4, $3 FROM expired`,
+		now, limit, domain.PaymentRequestExpired, domain.PaymentRequestPending,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to expire payment requests: %w", err)
+	}
+
+	return int(tag.RowsAffected()), nil
+}
+
+func insertPaymentRequestEvent(ctx context.Context, tx pgx.Tx, id int64, from, to domain.PaymentRequestStatus) (domain.PaymentRequestEvent, error) {
+	ev := domain.PaymentRequestEvent{FromStatus: from, ToStatus: to}
+	err := tx.QueryRow(ctx,
+		`INSERT INTO payment_request_event (request_id, from_status, to_status)
+		 VALUES ($1, NULLIF($2, ''), $3)
+		 RETURNING id, created_at`,
+		id, from, to,
+	).Scan(&ev.ID, &ev.CreatedAt)
+	if err != nil {
+		return domain.PaymentRequestEvent{}, fmt.Errorf("failed to record payment request event: %w", err)
+	}
+
+	return ev, nil
+}
@@ -0,0 +1,217 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/Te8va/wallet/internal/domain"
+	appErrors "github.com/Te8va/wallet/internal/errors"
+)
+
+// PocketRepository keeps pocket balances. Pockets only earmark part of the
+// wallet balance: moves do not touch wallet.balance or the journal, they
+// change wallet.pocketed, which withdrawals subtract from the balance they
+// may spend.
+type PocketRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPocketRepository(db *pgxpool.Pool) (*PocketRepository, error) {
+	if db == nil {
+		return nil, fmt.Errorf("database connection is nil")
+	}
+	return &PocketRepository{db: db}, nil
+}
+
+// CreatePocket adds an empty pocket to a user wallet. Internal wallets, such
+// as the ones holding escrow funds, cannot have pockets.
+func (r *PocketRepository) CreatePocket(ctx context.Context, walletID, name string) (domain.Pocket, error) {
+	if err := checkUserWallets(ctx, r.db, walletID); err != nil {
+		return domain.Pocket{}, err
+	}
+
+	pocket := domain.Pocket{WalletID: walletID, Name: name}
+	err := r.db.QueryRow(ctx,
+		`INSERT INTO wallet_pocket (wallet_id, name) VALUES ($1, $2) RETURNING balance, created_at`,
+		walletID, name,
+	).Scan(&pocket.Balance, &pocket.CreatedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) {
+			switch pgErr.Code {
+			case pgForeignKeyViolation:
+				return domain.Pocket{}, appErrors.ErrWalletNotFound
+			case pgUniqueViolation:
+				return domain.Pocket{}, appErrors.ErrPocketExists
+			}
+		}
+		return domain.Pocket{}, fmt.Errorf("failed to create pocket: %w", err)
+	}
+
+	return pocket, nil
+}
+
+// PocketBalances returns the wallet balance with its pockets.
+func (r *PocketRepository) PocketBalances(ctx context.Context, walletID string) (domain.PocketBalances, error) {
+	return pocketBalances(ctx, r.db, walletID)
+}
+
+// MovePocket moves money between the main balance and pockets. It locks the
+// wallet row like ProcessTransaction does, so a move cannot race with a
+// withdrawal spending the same money.
+func (r *PocketRepository) MovePocket(ctx context.Context, move domain.PocketMove) (domain.PocketMove, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return domain.PocketMove{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer rollback(ctx, tx)
+
+	var balance, pocketed int64
+	err = tx.QueryRow(ctx,
+		`SELECT balance, pocketed FROM wallet WHERE id = $1 FOR UPDATE`,
+		move.WalletID,
+	).Scan(&balance, &pocketed)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.PocketMove{}, appErrors.ErrWalletNotFound
+		}
+		return domain.PocketMove{}, fmt.Errorf("failed to get wallet: %w", err)
+	}
+
+	if err = checkUserWallets(ctx, tx, move.WalletID); err != nil {
+		return domain.PocketMove{}, err
+	}
+
+	if move.From == "" {
+		if balance-pocketed < move.Amount {
+			return domain.PocketMove{}, appErrors.ErrInsufficientFunds
+		}
+	} else if err = adjustPocket(ctx, tx, move.WalletID, move.From, -move.Amount); err != nil {
+		return domain.PocketMove{}, err
+	}
+
+	if move.To != "" {
+		if err = adjustPocket(ctx, tx, move.WalletID, move.To, move.Amount); err != nil {
+			return domain.PocketMove{}, err
+		}
+	}
+
+	var delta int64
+	if move.From != "" {
+		delta -= move.Amount
+	}
+	if move.To != "" {
+		delta += move.Amount
+	}
+	if delta != 0 {
+		_, err = tx.Exec(ctx, `UPDATE wallet SET pocketed = pocketed + $1 WHERE id = $2`, delta, move.WalletID)
+		if err != nil {
+			return domain.PocketMove{}, fmt.Errorf("failed to update pocketed balance: %w", err)
+		}
+	}
+
+	err = tx.QueryRow(ctx,
+		`INSERT INTO pocket_move (wallet_id, from_pocket, to_pocket, amount)
+		 VALUES ($1, NULLIF($2, ''), NULLIF($3, ''), $4)
+		 RETURNING id, created_at`,
+		move.WalletID, move.From, move.To, move.Amount,
+	).Scan(&move.ID, &move.CreatedAt)
+	if err != nil {
+		return domain.PocketMove{}, fmt.Errorf("failed to record pocket move: %w", err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return domain.PocketMove{}, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return move, nil
+}
+
+// adjustPocket adds delta to the pocket balance, which must not become
+// negative.
+func adjustPocket(ctx context.Context, tx pgx.Tx, walletID, name string, delta int64) error {
+	var balance int64
+	err := tx.QueryRow(ctx,
+		`SELECT balance FROM wallet_pocket WHERE wallet_id = $1 AND name = $2 FOR UPDATE`,
+		walletID, name,
+	).Scan(&balance)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return appErrors.ErrPocketNotFound
+		}
+		return fmt.Errorf("failed to get pocket: %w", err)
+	}
+
+	if balance+delta < 0 {
+		return appErrors.ErrInsufficientFunds
+	}
+
+	_, err = tx.Exec(ctx,
+		`UPDATE wallet_pocket SET balance = balance + $1 WHERE wallet_id = $2 AND name = $3`,
+		delta, walletID, name,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update pocket: %w", err)
+	}
+
+	return nil
+}
+
+// pocketBalances reads the wallet balance and its pockets in one statement,
+// so the breakdown adds up.
+func pocketBalances(ctx context.Context, q querier, walletID string) (domain.PocketBalances, error) {
+	balances := domain.PocketBalances{WalletID: walletID, Pockets: []domain.Pocket{}}
+
+	rows, err := q.Query(ctx,
+		`SELECT w.balance, w.pocketed, p.name, p.balance, p.created_at
+		 FROM wallet w
+		 LEFT JOIN wallet_pocket p ON p.wallet_id = w.id
+		 WHERE w.id = $1
+		 ORDER BY p.name`,
+		walletID,
+	)
+	if err != nil {
+		return domain.PocketBalances{}, fmt.Errorf("failed to get pockets: %w", err)
+	}
+	defer rows.Close()
+
+	found := false
+	for rows.Next() {
+		// The pocket columns are NULL for a wallet without pockets.
+		var (
+			pocketed int64
+			name     *string
+			balance  *int64
+			created  *time.Time
+		)
+		if err = rows.Scan(&balances.Balance, &pocketed, &name, &balance, &created); err != nil {
+			return domain.PocketBalances{}, fmt.Errorf("failed to get pockets: %w", err)
+		}
+		found = true
+		balances.MainBalance = balances.Balance - pocketed
+
+		if name != nil {
+			balances.Pockets = append(balances.Pockets, domain.Pocket{
+				WalletID:  walletID,
+				Name:      *name,
+				Balance:   *balance,
+				CreatedAt: *created,
+			})
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return domain.PocketBalances{}, fmt.Errorf("failed to get pockets: %w", err)
+	}
+
+	if !found {
+		return domain.PocketBalances{}, appErrors.ErrWalletNotFound
+	}
+
+	return balances, nil
+}
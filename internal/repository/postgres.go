@@ -7,6 +7,8 @@ import (
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/Te8va/wallet/internal/tracing"
 )
 
 type postgres struct {
@@ -22,6 +24,7 @@ func GetPgxPool(ctx context.Context, dsn string) (*pgxpool.Pool, error) {
 	if err != nil {
 		return nil, fmt.Errorf("repository.GetPgxPool: %w", err)
 	}
+	config.ConnConfig.Tracer = tracing.PgxTracer{}
 
 	pool, err := pgxpool.NewWithConfig(ctx, config)
 	if err != nil {
@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/Te8va/wallet/internal/domain"
+)
+
+// refilled is the token count of an existing bucket topped up for the time
+// since its last update; EXCLUDED.updated_at holds the current time.
+const refilled = `LEAST($3::DOUBLE PRECISION,
+	b.tokens + GREATEST(EXTRACT(EPOCH FROM (EXCLUDED.updated_at - b.updated_at))::DOUBLE PRECISION, 0) * $2)`
+
+// RateLimitRepository keeps token buckets in Postgres, so every instance
+// enforces the same limit.
+type RateLimitRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewRateLimitRepository(db *pgxpool.Pool) (*RateLimitRepository, error) {
+	if db == nil {
+		return nil, fmt.Errorf("database connection is nil")
+	}
+	return &RateLimitRepository{db: db}, nil
+}
+
+// Take refills the bucket and takes a token from it in one statement. The
+// row lock taken by the upsert serializes concurrent takes of the same key,
+// and the database clock is used so instances with skewed clocks agree.
+func (r *RateLimitRepository) Take(ctx context.Context, key string, limit domain.RateLimit) (domain.RateLimitResult, error) {
+	var (
+		tokens  float64
+		allowed bool
+	)
+	err := r.db.QueryRow(ctx,
+		`INSERT INTO rate_limit_bucket AS b (key, tokens, allowed, updated_at)
+		 VALUES ($1, $3::DOUBLE PRECISION - 1, $3 >= 1, clock_timestamp())
+		 ON CONFLICT (key) DO UPDATE SET
+			tokens = CASE WHEN `+refilled+` >= 1 THEN `+refilled+` - 1 ELSE `+refilled+` END,
+			allowed = `+refilled+` >= 1,
+			updated_at = GREATEST(b.updated_at, EXCLUDED.updated_at)
+		 RETURNING b.tokens, b.allowed`,
+		key, limit.Rate, float64(limit.Burst),
+	).Scan(&tokens, &allowed)
+	if err != nil {
+		return domain.RateLimitResult{}, fmt.Errorf("failed to take rate limit token: %w", err)
+	}
+
+	return limit.Result(tokens, allowed), nil
+}
+
+// DeleteIdleBuckets removes buckets untouched for longer than idle; a bucket
+// that has had time to refill is the same as a missing one.
+func (r *RateLimitRepository) DeleteIdleBuckets(ctx context.Context, idle time.Duration) (int, error) {
+	tag, err := r.db.Exec(ctx,
+		`DELETE FROM rate_limit_bucket WHERE updated_at < clock_timestamp() - $1::INTERVAL`,
+		idle,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete idle rate limit buckets: %w", err)
+	}
+
+	return int(tag.RowsAffected()), nil
+}
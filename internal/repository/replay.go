@@ -0,0 +1,186 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/Te8va/wallet/internal/domain"
+)
+
+const walletProjection = "wallet"
+
+type ReplayRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewReplayRepository(db *pgxpool.Pool) (*ReplayRepository, error) {
+	if db == nil {
+		return nil, fmt.Errorf("database connection is nil")
+	}
+	return &ReplayRepository{db: db}, nil
+}
+
+// ReplayWallets rebuilds the wallet table, the balance projection, by
+// folding every wallet's events. Writers are blocked for the duration of the
+// replay, so no event can be posted between reading the log and writing the
+// projection.
+func (r *ReplayRepository) ReplayWallets(ctx context.Context, opts domain.ReplayOptions) (domain.ReplayResult, error) {
+	result := domain.ReplayResult{
+		Projection:   walletProjection,
+		FromSnapshot: opts.FromSnapshot,
+		DryRun:       opts.DryRun,
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return result, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer rollback(ctx, tx)
+
+	if _, err = tx.Exec(ctx, `LOCK TABLE wallet IN EXCLUSIVE MODE`); err != nil {
+		return result, fmt.Errorf("failed to lock wallet table: %w", err)
+	}
+
+	states, err := loadStartStates(ctx, tx, opts.FromSnapshot)
+	if err != nil {
+		return result, err
+	}
+
+	rows, err := tx.Query(ctx,
+		`SELECT t.id, t.wallet_id, t.operation_type, t.amount, t.balance_after, COALESCE(t.parent_id, 0), t.created_at
+		 FROM wallet_transaction t
+		 LEFT JOIN LATERAL (
+			SELECT transaction_id
+			FROM wallet_balance_snapshot
+			WHERE wallet_id = t.wallet_id AND $1
+			ORDER BY transaction_id DESC
+			LIMIT 1
+		 ) s ON true
+		 WHERE t.id > COALESCE(s.transaction_id, 0)
+		 ORDER BY t.wallet_id, t.id`,
+		opts.FromSnapshot,
+	)
+	if err != nil {
+		return result, fmt.Errorf("failed to read events: %w", err)
+	}
+
+	for rows.Next() {
+		e, err := scanTransaction(rows)
+		if err != nil {
+			rows.Close()
+			return result, fmt.Errorf("failed to read events: %w", err)
+		}
+
+		state, ok := states[e.WalletID]
+		if !ok {
+			state = domain.WalletState{WalletID: e.WalletID}
+		}
+
+		if state, err = state.Apply(e); err != nil {
+			rows.Close()
+			return result, err
+		}
+		states[e.WalletID] = state
+
+		result.Events++
+		result.LastEventID = max(result.LastEventID, e.ID)
+	}
+	if err = rows.Err(); err != nil {
+		return result, fmt.Errorf("failed to read events: %w", err)
+	}
+
+	result.Wallets = len(states)
+
+	changed, err := writeWalletProjection(ctx, tx, states)
+	if err != nil {
+		return result, err
+	}
+	result.Changed = changed
+
+	if opts.DryRun {
+		return result, nil
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return result, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return result, nil
+}
+
+// loadStartStates returns the state every replay starts from: the latest
+// snapshot of each wallet, or nothing when replaying from scratch.
+func loadStartStates(ctx context.Context, tx pgx.Tx, fromSnapshot bool) (map[string]domain.WalletState, error) {
+	states := make(map[string]domain.WalletState)
+	if !fromSnapshot {
+		return states, nil
+	}
+
+	rows, err := tx.Query(ctx,
+		`SELECT DISTINCT ON (wallet_id) wallet_id, balance, transaction_id
+		 FROM wallet_balance_snapshot
+		 ORDER BY wallet_id, transaction_id DESC`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load snapshots: %w", err)
+	}
+
+	snapshots, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (domain.WalletState, error) {
+		var s domain.WalletState
+		err := row.Scan(&s.WalletID, &s.Balance, &s.Version)
+		return s, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load snapshots: %w", err)
+	}
+
+	for _, s := range snapshots {
+		states[s.WalletID] = s
+	}
+
+	return states, nil
+}
+
+// writeWalletProjection stores the folded balances in the wallet table and
+// reports how many cached balances changed. Wallets without events are reset
+// to zero.
+func writeWalletProjection(ctx context.Context, tx pgx.Tx, states map[string]domain.WalletState) (int, error) {
+	_, err := tx.Exec(ctx,
+		`CREATE TEMPORARY TABLE wallet_replay (id VARCHAR(36) PRIMARY KEY, balance BIGINT NOT NULL) ON COMMIT DROP`,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create replay table: %w", err)
+	}
+
+	list := make([]domain.WalletState, 0, len(states))
+	for _, s := range states {
+		list = append(list, s)
+	}
+
+	_, err = tx.CopyFrom(ctx,
+		pgx.Identifier{"wallet_replay"},
+		[]string{"id", "balance"},
+		pgx.CopyFromSlice(len(list), func(i int) ([]any, error) {
+			return []any{list[i].WalletID, list[i].Balance}, nil
+		}),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to copy replayed balances: %w", err)
+	}
+
+	tag, err := tx.Exec(ctx,
+		`UPDATE wallet w
+		 SET balance = COALESCE(p.balance, 0)
+		 FROM wallet x
+		 LEFT JOIN wallet_replay p ON p.id = x.id
+		 WHERE w.id = x.id AND w.balance <> COALESCE(p.balance, 0)`,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to update wallet projection: %w", err)
+	}
+
+	return int(tag.RowsAffected()), nil
+}
@@ -4,12 +4,15 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/Te8va/wallet/internal/domain"
 	appErrors "github.com/Te8va/wallet/internal/errors"
+	"github.com/Te8va/wallet/internal/metrics"
 )
 
 type WalletRepository struct {
@@ -23,62 +26,145 @@ func NewWalletRepository(db *pgxpool.Pool) (*WalletRepository, error) {
 	return &WalletRepository{db: db}, nil
 }
 
-func (r *WalletRepository) ProcessTransaction(ctx context.Context, walletID string, opType domain.OperationType, amount int64) error {
+// ProcessTransaction posts a deposit or withdrawal. A deposit creates the
+// wallet when it does not exist. A request repeating the idempotency key of
+// an earlier identical operation returns that operation, marked as replayed;
+// so does one repeating a reference of the wallet when req.UniqueReference
+// is set. A withdrawal can only spend the main balance: money in pockets has
+// to be moved back first.
+func (r *WalletRepository) ProcessTransaction(ctx context.Context, req domain.TransactionRequest) (domain.TransactionResult, error) {
+	defer prometheus.NewTimer(metrics.DBTxDuration.WithLabelValues("process_transaction")).ObserveDuration()
+
+	res, err := r.processTransaction(ctx, req)
+	if errors.Is(err, appErrors.ErrDuplicateOperation) && req.IdempotencyKey != "" {
+		// A concurrent request with the same key committed first.
+		if replayed, replayErr := r.replay(ctx, r.db, req); !errors.Is(replayErr, errNotReplayed) {
+			return replayed, replayErr
+		}
+	}
+
+	return res, err
+}
+
+func (r *WalletRepository) processTransaction(ctx context.Context, req domain.TransactionRequest) (domain.TransactionResult, error) {
 	tx, err := r.db.Begin(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
+		return domain.TransactionResult{}, fmt.Errorf("failed to begin transaction: %w", err)
 	}
-	defer tx.Rollback(ctx)
+	defer rollback(ctx, tx)
 
-	var balance int64
+	if req.IdempotencyKey != "" {
+		res, err := r.replay(ctx, tx, req)
+		if !errors.Is(err, errNotReplayed) {
+			return res, err
+		}
+	}
+
+	var available int64
 	err = tx.QueryRow(ctx,
-		`SELECT balance FROM wallet WHERE id = $1 FOR UPDATE`,
-		walletID,
-	).Scan(&balance)
+		`SELECT balance - pocketed FROM wallet WHERE id = $1 FOR UPDATE`,
+		req.WalletID,
+	).Scan(&available)
 
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			_, err = tx.Exec(ctx,
 				`INSERT INTO wallet (id, balance) VALUES ($1, $2)`,
-				walletID, 0,
+				req.WalletID, 0,
 			)
 			if err != nil {
-				return fmt.Errorf("failed to create wallet: %w", err)
+				return domain.TransactionResult{}, fmt.Errorf("failed to create wallet: %w", err)
 			}
-			balance = 0
+			available = 0
 		} else {
-			return fmt.Errorf("failed to get wallet: %w", err)
+			return domain.TransactionResult{}, fmt.Errorf("failed to get wallet: %w", err)
 		}
 	}
 
-	if opType == domain.WITHDRAW && balance < amount {
-		return appErrors.ErrInsufficientFunds
+	if err = checkUserWallets(ctx, tx, req.WalletID); err != nil {
+		return domain.TransactionResult{}, err
+	}
+
+	// The wallet row lock serializes operations on the wallet, so no other
+	// entry with the reference can be posted until this one commits.
+	if req.UniqueReference && req.Reference != "" {
+		res, err := r.replayReference(ctx, tx, req)
+		if !errors.Is(err, errNotReplayed) {
+			return res, err
+		}
+	}
+
+	if req.OperationType == domain.WITHDRAW && available < req.Amount {
+		return domain.TransactionResult{}, appErrors.ErrInsufficientFunds
 	}
 
 	var delta int64
-	if opType == domain.DEPOSIT {
-		delta = amount
+	if req.OperationType == domain.DEPOSIT {
+		delta = req.Amount
 	} else {
-		delta = -amount
+		delta = -req.Amount
 	}
 
-	tag, err := tx.Exec(ctx,
-		`UPDATE wallet SET balance = balance + $1 WHERE id = $2`,
-		delta, walletID,
-	)
+	t, err := postEntry(ctx, tx, entry{
+		walletID:       req.WalletID,
+		opType:         req.OperationType,
+		delta:          delta,
+		idempotencyKey: clientKey(req.WalletID, req.IdempotencyKey),
+		reference:      req.Reference,
+		description:    req.Description,
+		metadata:       req.Metadata,
+		tags:           req.Tags,
+	})
 	if err != nil {
-		return fmt.Errorf("failed to update balance: %w", err)
+		return domain.TransactionResult{}, err
 	}
 
-	if tag.RowsAffected() == 0 {
-		return appErrors.ErrWalletNotFound
+	if err = tx.Commit(ctx); err != nil {
+		return domain.TransactionResult{}, fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
-	if err = tx.Commit(ctx); err != nil {
-		return fmt.Errorf("failed to commit transaction: %w", err)
+	return domain.TransactionResult{Transaction: t}, nil
+}
+
+// errNotReplayed reports that no operation has been posted with the key or
+// reference yet.
+var errNotReplayed = errors.New("idempotency key not used yet")
+
+// replay returns the operation posted earlier with the idempotency key of
+// req. The key may only be reused for the same wallet, type and amount.
+func (r *WalletRepository) replay(ctx context.Context, q querier, req domain.TransactionRequest) (domain.TransactionResult, error) {
+	t, found, err := transactionByIdempotencyKey(ctx, q, clientKey(req.WalletID, req.IdempotencyKey))
+	if err != nil {
+		return domain.TransactionResult{}, err
+	}
+	if !found {
+		return domain.TransactionResult{}, errNotReplayed
 	}
 
-	return nil
+	return replayed(t, req, appErrors.ErrIdempotencyKeyReused)
+}
+
+// replayReference is replay for the reference of req within its wallet.
+func (r *WalletRepository) replayReference(ctx context.Context, q querier, req domain.TransactionRequest) (domain.TransactionResult, error) {
+	t, found, err := transactionByReference(ctx, q, req.WalletID, req.Reference)
+	if err != nil {
+		return domain.TransactionResult{}, err
+	}
+	if !found {
+		return domain.TransactionResult{}, errNotReplayed
+	}
+
+	return replayed(t, req, appErrors.ErrDuplicateReference)
+}
+
+// replayed returns t as the result of req when both describe the same
+// operation, and mismatch otherwise.
+func replayed(t domain.Transaction, req domain.TransactionRequest, mismatch error) (domain.TransactionResult, error) {
+	if t.WalletID != req.WalletID || t.OperationType != req.OperationType || abs(t.Amount) != req.Amount {
+		return domain.TransactionResult{}, mismatch
+	}
+
+	return domain.TransactionResult{Transaction: t, Replayed: true}, nil
 }
 
 func (r *WalletRepository) GetBalance(ctx context.Context, walletID string) (int64, error) {
@@ -94,3 +180,371 @@ func (r *WalletRepository) GetBalance(ctx context.Context, walletID string) (int
 	}
 	return balance, nil
 }
+
+// PocketBalances returns the wallet balance broken down into the main
+// balance and the pockets.
+func (r *WalletRepository) PocketBalances(ctx context.Context, walletID string) (domain.PocketBalances, error) {
+	return pocketBalances(ctx, r.db, walletID)
+}
+
+// GetTransaction returns the journal entry with the given id.
+func (r *WalletRepository) GetTransaction(ctx context.Context, id int64) (domain.Transaction, error) {
+	t, err := scanFullTransaction(r.db.QueryRow(ctx,
+		`SELECT `+transactionColumns+` FROM wallet_transaction WHERE id = $1`,
+		id,
+	))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.Transaction{}, appErrors.ErrTransactionNotFound
+		}
+		return domain.Transaction{}, fmt.Errorf("failed to get transaction: %w", err)
+	}
+
+	return t, nil
+}
+
+// Transfer moves amount between two wallets. It reports ErrDuplicateOperation
+// when the same transfer has already been posted with the idempotency key,
+// and ErrIdempotencyKeyReused when the key was used for anything else.
+func (r *WalletRepository) Transfer(ctx context.Context, fromWalletID, toWalletID string, amount int64, idempotencyKey string) error {
+	if fromWalletID == toWalletID {
+		return appErrors.ErrSameWallet
+	}
+
+	defer prometheus.NewTimer(metrics.DBTxDuration.WithLabelValues("transfer")).ObserveDuration()
+
+	err := r.transfer(ctx, fromWalletID, toWalletID, amount, idempotencyKey)
+	if errors.Is(err, appErrors.ErrDuplicateOperation) && idempotencyKey != "" {
+		return checkTransferPosted(ctx, r.db, fromWalletID, toWalletID, amount, idempotencyKey)
+	}
+
+	return err
+}
+
+func (r *WalletRepository) transfer(ctx context.Context, fromWalletID, toWalletID string, amount int64, idempotencyKey string) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer rollback(ctx, tx)
+
+	if err = checkIdempotencyKey(ctx, tx, idempotencyKey); err != nil {
+		return err
+	}
+
+	balances, err := lockWallets(ctx, tx, []string{fromWalletID, toWalletID}, map[string]bool{toWalletID: true})
+	if err != nil {
+		return err
+	}
+
+	if err = checkUserWallets(ctx, tx, fromWalletID, toWalletID); err != nil {
+		return err
+	}
+
+	if balances[fromWalletID] < amount {
+		return appErrors.ErrInsufficientFunds
+	}
+
+	debit, err := postEntry(ctx, tx, entry{
+		walletID:       fromWalletID,
+		opType:         domain.TRANSFER_OUT,
+		delta:          -amount,
+		idempotencyKey: idempotencyKey,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = postEntry(ctx, tx, entry{
+		walletID: toWalletID,
+		opType:   domain.TRANSFER_IN,
+		delta:    amount,
+		parentID: debit.ID,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// checkTransferPosted reports ErrDuplicateOperation when the entry posted
+// with the key is the debit of a transfer of amount from fromWalletID to
+// toWalletID, and ErrIdempotencyKeyReused otherwise.
+func checkTransferPosted(ctx context.Context, q querier, fromWalletID, toWalletID string, amount int64, key string) error {
+	debit, found, err := transactionByIdempotencyKey(ctx, q, key)
+	if err != nil {
+		return err
+	}
+	if !found || debit.WalletID != fromWalletID || debit.OperationType != domain.TRANSFER_OUT || debit.Amount != -amount {
+		return appErrors.ErrIdempotencyKeyReused
+	}
+
+	var credited bool
+	err = q.QueryRow(ctx,
+		`SELECT EXISTS (
+			SELECT 1 FROM wallet_transaction
+			WHERE parent_id = $1 AND wallet_id = $2 AND operation_type = $3 AND amount = $4
+		)`,
+		debit.ID, toWalletID, domain.TRANSFER_IN, amount,
+	).Scan(&credited)
+	if err != nil {
+		return fmt.Errorf("failed to check transfer credit: %w", err)
+	}
+
+	if !credited {
+		return appErrors.ErrIdempotencyKeyReused
+	}
+
+	return appErrors.ErrDuplicateOperation
+}
+
+// Split debits the payer once and credits every leg in one transaction. The
+// credits have the debit as their parent, so the payment reads as one
+// operation in the history of each wallet. A split repeating the idempotency
+// key of an earlier identical split returns that payment, marked as
+// replayed.
+func (r *WalletRepository) Split(ctx context.Context, split domain.Split) (domain.SplitPayment, error) {
+	defer prometheus.NewTimer(metrics.DBTxDuration.WithLabelValues("split")).ObserveDuration()
+
+	payment, err := r.split(ctx, split)
+	if errors.Is(err, appErrors.ErrDuplicateOperation) && split.IdempotencyKey != "" {
+		// A concurrent split with the same key committed first.
+		if replayed, replayErr := replaySplit(ctx, r.db, split); !errors.Is(replayErr, errNotReplayed) {
+			return replayed, replayErr
+		}
+	}
+
+	return payment, err
+}
+
+func (r *WalletRepository) split(ctx context.Context, split domain.Split) (domain.SplitPayment, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return domain.SplitPayment{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer rollback(ctx, tx)
+
+	if split.IdempotencyKey != "" {
+		payment, err := replaySplit(ctx, tx, split)
+		if !errors.Is(err, errNotReplayed) {
+			return payment, err
+		}
+	}
+
+	walletIDs := []string{split.WalletID}
+	create := make(map[string]bool, len(split.Legs))
+	for _, leg := range split.Legs {
+		walletIDs = append(walletIDs, leg.WalletID)
+		create[leg.WalletID] = true
+	}
+
+	balances, err := lockWallets(ctx, tx, walletIDs, create)
+	if err != nil {
+		return domain.SplitPayment{}, err
+	}
+
+	if err = checkUserWallets(ctx, tx, walletIDs...); err != nil {
+		return domain.SplitPayment{}, err
+	}
+
+	if balances[split.WalletID] < split.Amount {
+		return domain.SplitPayment{}, appErrors.ErrInsufficientFunds
+	}
+
+	payment := domain.SplitPayment{Credits: make([]domain.Transaction, 0, len(split.Legs))}
+	payment.Debit, err = postEntry(ctx, tx, entry{
+		walletID:       split.WalletID,
+		opType:         domain.SPLIT_OUT,
+		delta:          -split.Amount,
+		idempotencyKey: clientKey(split.WalletID, split.IdempotencyKey),
+		reference:      split.Reference,
+		description:    split.Description,
+	})
+	if err != nil {
+		return domain.SplitPayment{}, err
+	}
+
+	for _, leg := range split.Legs {
+		credit, err := postEntry(ctx, tx, entry{
+			walletID:    leg.WalletID,
+			opType:      domain.SPLIT_IN,
+			delta:       leg.Amount,
+			parentID:    payment.Debit.ID,
+			reference:   split.Reference,
+			description: split.Description,
+		})
+		if err != nil {
+			return domain.SplitPayment{}, err
+		}
+		payment.Credits = append(payment.Credits, credit)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return domain.SplitPayment{}, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return payment, nil
+}
+
+// replaySplit returns the payment posted earlier with the idempotency key of
+// split. The key may only be reused for the same payer, amount and legs.
+func replaySplit(ctx context.Context, q querier, split domain.Split) (domain.SplitPayment, error) {
+	debit, found, err := transactionByIdempotencyKey(ctx, q, clientKey(split.WalletID, split.IdempotencyKey))
+	if err != nil {
+		return domain.SplitPayment{}, err
+	}
+	if !found {
+		return domain.SplitPayment{}, errNotReplayed
+	}
+
+	if debit.WalletID != split.WalletID || debit.OperationType != domain.SPLIT_OUT || debit.Amount != -split.Amount {
+		return domain.SplitPayment{}, appErrors.ErrIdempotencyKeyReused
+	}
+
+	rows, err := q.Query(ctx,
+		`SELECT `+transactionColumns+` FROM wallet_transaction WHERE parent_id = $1 ORDER BY id`,
+		debit.ID,
+	)
+	if err != nil {
+		return domain.SplitPayment{}, fmt.Errorf("failed to get split credits: %w", err)
+	}
+
+	credits, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (domain.Transaction, error) {
+		return scanFullTransaction(row)
+	})
+	if err != nil {
+		return domain.SplitPayment{}, fmt.Errorf("failed to get split credits: %w", err)
+	}
+
+	if len(credits) != len(split.Legs) {
+		return domain.SplitPayment{}, appErrors.ErrIdempotencyKeyReused
+	}
+	for i, leg := range split.Legs {
+		if credits[i].WalletID != leg.WalletID || credits[i].Amount != leg.Amount {
+			return domain.SplitPayment{}, appErrors.ErrIdempotencyKeyReused
+		}
+	}
+
+	return domain.SplitPayment{Debit: debit, Credits: credits, Replayed: true}, nil
+}
+
+func (r *WalletRepository) BalanceAt(ctx context.Context, walletID string, at time.Time) (int64, error) {
+	return balanceAt(ctx, r.db, walletID, at)
+}
+
+// BalanceAsOf reconstructs the wallet balance including every entry posted
+// up to and including asOf. It starts from the latest balance snapshot taken
+// no later than asOf and adds the journal entries posted after it.
+func (r *WalletRepository) BalanceAsOf(ctx context.Context, walletID string, asOf time.Time) (int64, error) {
+	var (
+		exists  bool
+		balance int64
+	)
+	err := r.db.QueryRow(ctx,
+		`WITH snapshot AS (
+			SELECT transaction_id, balance, taken_at
+			FROM wallet_balance_snapshot
+			WHERE wallet_id = $1 AND taken_at <= $2
+			ORDER BY taken_at DESC, transaction_id DESC
+			LIMIT 1
+		)
+		SELECT
+			EXISTS (SELECT 1 FROM wallet WHERE id = $1),
+			COALESCE((SELECT balance FROM snapshot), 0) + COALESCE((
+				SELECT SUM(amount) FROM wallet_transaction
+				WHERE wallet_id = $1
+					AND created_at <= $2
+					AND created_at >= COALESCE((SELECT taken_at FROM snapshot), '-infinity')
+					AND id > COALESCE((SELECT transaction_id FROM snapshot), 0)
+			), 0)::BIGINT`,
+		walletID, asOf,
+	).Scan(&exists, &balance)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get balance from journal: %w", err)
+	}
+
+	if !exists {
+		return 0, appErrors.ErrWalletNotFound
+	}
+
+	return balance, nil
+}
+
+// ListTransactions returns the journal entries of the wallet created within
+// [from, to), oldest first.
+func (r *WalletRepository) ListTransactions(ctx context.Context, walletID string, from, to time.Time) ([]domain.Transaction, error) {
+	rows, err := r.db.Query(ctx,
+		`SELECT id, wallet_id, operation_type, amount, balance_after, COALESCE(parent_id, 0), created_at
+		 FROM wallet_transaction
+		 WHERE wallet_id = $1 AND created_at >= $2 AND created_at < $3
+		 ORDER BY created_at, id`,
+		walletID, from, to,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list transactions: %w", err)
+	}
+
+	transactions, err := pgx.CollectRows(rows, scanTransaction)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list transactions: %w", err)
+	}
+
+	return transactions, nil
+}
+
+// SearchTransactions returns a page of the wallet's journal entries matching
+// filter, newest first. NextBefore is set when there may be more entries.
+func (r *WalletRepository) SearchTransactions(ctx context.Context, filter domain.TransactionFilter) (domain.TransactionHistory, error) {
+	var tags any
+	if len(filter.Tags) > 0 {
+		tags = filter.Tags
+	}
+
+	rows, err := r.db.Query(ctx,
+		`SELECT `+transactionColumns+` FROM wallet_transaction
+		 WHERE wallet_id = $1
+			AND (NULLIF($2::TEXT, '') IS NULL OR reference = $2)
+			AND ($3::JSONB IS NULL OR tags @> $3)
+			AND ($4::TIMESTAMPTZ IS NULL OR created_at >= $4)
+			AND ($5::TIMESTAMPTZ IS NULL OR created_at < $5)
+			AND ($6::BIGINT = 0 OR id < $6)
+		 ORDER BY id DESC
+		 LIMIT $7`,
+		filter.WalletID, filter.Reference, tags, filter.From, filter.To, filter.Before, filter.Limit,
+	)
+	if err != nil {
+		return domain.TransactionHistory{}, fmt.Errorf("failed to search transactions: %w", err)
+	}
+
+	transactions, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (domain.Transaction, error) {
+		return scanFullTransaction(row)
+	})
+	if err != nil {
+		return domain.TransactionHistory{}, fmt.Errorf("failed to search transactions: %w", err)
+	}
+
+	if len(transactions) == 0 {
+		transactions = []domain.Transaction{}
+
+		var exists bool
+		err = r.db.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM wallet WHERE id = $1)`, filter.WalletID).Scan(&exists)
+		if err != nil {
+			return domain.TransactionHistory{}, fmt.Errorf("failed to get wallet: %w", err)
+		}
+		if !exists {
+			return domain.TransactionHistory{}, appErrors.ErrWalletNotFound
+		}
+	}
+
+	history := domain.TransactionHistory{Transactions: transactions}
+	if len(transactions) == filter.Limit {
+		history.NextBefore = transactions[len(transactions)-1].ID
+	}
+
+	return history, nil
+}
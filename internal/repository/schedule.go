@@ -0,0 +1,177 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/Te8va/wallet/internal/domain"
+	appErrors "github.com/Te8va/wallet/internal/errors"
+)
+
+const scheduleColumns = `id, from_wallet_id, to_wallet_id, amount, frequency, cron, start_at, next_run_at,
+	occurrence_at, status, attempts, last_error, last_run_at, created_at`
+
+type ScheduleRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewScheduleRepository(db *pgxpool.Pool) (*ScheduleRepository, error) {
+	if db == nil {
+		return nil, fmt.Errorf("database connection is nil")
+	}
+	return &ScheduleRepository{db: db}, nil
+}
+
+func scanSchedule(row pgx.Row) (domain.Schedule, error) {
+	var s domain.Schedule
+	err := row.Scan(&s.ID, &s.FromWalletID, &s.ToWalletID, &s.Amount, &s.Frequency, &s.Cron, &s.StartAt,
+		&s.NextRunAt, &s.OccurrenceAt, &s.Status, &s.Attempts, &s.LastError, &s.LastRunAt, &s.CreatedAt)
+	return s, err
+}
+
+func (r *ScheduleRepository) CreateSchedule(ctx context.Context, s domain.Schedule) (domain.Schedule, error) {
+	created, err := scanSchedule(r.db.QueryRow(ctx,
+		`INSERT INTO schedule (from_wallet_id, to_wallet_id, amount, frequency, cron, start_at, next_run_at,
+			occurrence_at, status)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $7, $8)
+		 RETURNING `+scheduleColumns,
+		s.FromWalletID, s.ToWalletID, s.Amount, s.Frequency, s.Cron, s.StartAt, s.NextRunAt, domain.ScheduleActive,
+	))
+	if err != nil {
+		return domain.Schedule{}, fmt.Errorf("failed to create schedule: %w", err)
+	}
+
+	return created, nil
+}
+
+func (r *ScheduleRepository) GetSchedule(ctx context.Context, id int64) (domain.Schedule, error) {
+	s, err := scanSchedule(r.db.QueryRow(ctx,
+		`SELECT `+scheduleColumns+` FROM schedule WHERE id = $1`,
+		id,
+	))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.Schedule{}, appErrors.ErrScheduleNotFound
+		}
+		return domain.Schedule{}, fmt.Errorf("failed to get schedule: %w", err)
+	}
+
+	return s, nil
+}
+
+func (r *ScheduleRepository) ListSchedules(ctx context.Context, walletID string) ([]domain.Schedule, error) {
+	rows, err := r.db.Query(ctx,
+		`SELECT `+scheduleColumns+` FROM schedule
+		 WHERE from_wallet_id = $1 OR to_wallet_id = $1
+		 ORDER BY id`,
+		walletID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list schedules: %w", err)
+	}
+
+	schedules, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (domain.Schedule, error) {
+		return scanSchedule(row)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list schedules: %w", err)
+	}
+
+	return schedules, nil
+}
+
+// UpdateScheduleStatus moves the schedule to status if it is currently in
+// one of the from states. A non-zero nextRunAt moves the schedule to that
+// occurrence; a zero one keeps the stored run and occurrence times.
+func (r *ScheduleRepository) UpdateScheduleStatus(ctx context.Context, id int64, from []domain.ScheduleStatus, to domain.ScheduleStatus, nextRunAt time.Time) (domain.Schedule, error) {
+	var next *time.Time
+	if !nextRunAt.IsZero() {
+		next = &nextRunAt
+	}
+
+	statuses := make([]string, 0, len(from))
+	for _, status := range from {
+		statuses = append(statuses, string(status))
+	}
+
+	s, err := scanSchedule(r.db.QueryRow(ctx,
+		`UPDATE schedule
+		 SET status = $3, next_run_at = COALESCE($4, next_run_at), occurrence_at = COALESCE($4, occurrence_at),
+		     attempts = 0, locked_until = NULL
+		 WHERE id = $1 AND status = ANY($2)
+		 RETURNING `+scheduleColumns,
+		id, statuses, to, next,
+	))
+	if err == nil {
+		return s, nil
+	}
+
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return domain.Schedule{}, fmt.Errorf("failed to update schedule: %w", err)
+	}
+
+	if _, err = r.GetSchedule(ctx, id); err != nil {
+		return domain.Schedule{}, err
+	}
+
+	return domain.Schedule{}, appErrors.ErrInvalidScheduleState
+}
+
+// ClaimDueSchedules leases up to limit active schedules that are due at now.
+// Rows locked by another instance are skipped, and a lease that has expired
+// (e.g. after a crash) makes the schedule claimable again. The lease value is
+// stored with microsecond precision and doubles as the claim token.
+func (r *ScheduleRepository) ClaimDueSchedules(ctx context.Context, now, leaseUntil time.Time, limit int) ([]domain.Schedule, error) {
+	leaseUntil = leaseUntil.Truncate(time.Microsecond)
+
+	rows, err := r.db.Query(ctx,
+		`UPDATE schedule SET locked_until = $2
+		 WHERE id IN (
+		     SELECT id FROM schedule
+		     WHERE status = 'ACTIVE' AND next_run_at <= $1
+		       AND (locked_until IS NULL OR locked_until < $1)
+		     ORDER BY next_run_at
+		     LIMIT $3
+		     FOR UPDATE SKIP LOCKED
+		 )
+		 RETURNING `+scheduleColumns,
+		now, leaseUntil, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim schedules: %w", err)
+	}
+
+	schedules, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (domain.Schedule, error) {
+		return scanSchedule(row)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim schedules: %w", err)
+	}
+
+	return schedules, nil
+}
+
+// CompleteScheduleRun stores the outcome of a run and releases the lease.
+// It is a no-op when the lease has been lost to another instance or the
+// schedule was paused or canceled in the meantime.
+func (r *ScheduleRepository) CompleteScheduleRun(ctx context.Context, s domain.Schedule, leaseUntil time.Time) error {
+	leaseUntil = leaseUntil.Truncate(time.Microsecond)
+
+	_, err := r.db.Exec(ctx,
+		`UPDATE schedule
+		 SET next_run_at = $3, occurrence_at = $4, status = $5, attempts = $6, last_error = $7, last_run_at = $8,
+		     locked_until = NULL
+		 WHERE id = $1 AND locked_until = $2 AND status = 'ACTIVE'`,
+		s.ID, leaseUntil, s.NextRunAt, s.OccurrenceAt, s.Status, s.Attempts, s.LastError, s.LastRunAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to complete schedule run: %w", err)
+	}
+
+	return nil
+}
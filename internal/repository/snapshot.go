@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type SnapshotRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewSnapshotRepository(db *pgxpool.Pool) (*SnapshotRepository, error) {
+	if db == nil {
+		return nil, fmt.Errorf("database connection is nil")
+	}
+	return &SnapshotRepository{db: db}, nil
+}
+
+// TakeSnapshots records the balance at the latest journal entry of every
+// wallet that has at least minEntries entries since its previous snapshot.
+// The balance is summed from the journal rather than read from the cache.
+//
+// Entries of one wallet are posted under its row lock, so a committed entry
+// never has a smaller id than one that is still in flight; a snapshot taken
+// at the highest visible id cannot skip entries committed later.
+func (r *SnapshotRepository) TakeSnapshots(ctx context.Context, minEntries int) (int, error) {
+	tag, err := r.db.Exec(ctx,
+		`INSERT INTO wallet_balance_snapshot (wallet_id, transaction_id, balance, taken_at)
+		 SELECT w.id, MAX(t.id), COALESCE(s.balance, 0) + SUM(t.amount), MAX(t.created_at)
+		 FROM wallet w
+		 LEFT JOIN LATERAL (
+			SELECT transaction_id, balance
+			FROM wallet_balance_snapshot
+			WHERE wallet_id = w.id
+			ORDER BY transaction_id DESC
+			LIMIT 1
+		 ) s ON true
+		 JOIN wallet_transaction t ON t.wallet_id = w.id AND t.id > COALESCE(s.transaction_id, 0)
+		 GROUP BY w.id, s.balance
+		 HAVING COUNT(*) >= $1
+		 ON CONFLICT (wallet_id, transaction_id) DO NOTHING`,
+		minEntries,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to take balance snapshots: %w", err)
+	}
+
+	return int(tag.RowsAffected()), nil
+}
@@ -0,0 +1,303 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/Te8va/wallet/internal/domain"
+	appErrors "github.com/Te8va/wallet/internal/errors"
+)
+
+const voucherBatchColumns = `id, wallet_id, treasury_wallet_id, value, max_redemptions, count, status, expires_at,
+	created_at, updated_at`
+
+// VoucherRepository keeps voucher batches and their codes. Every posting of
+// a batch carries the reference "voucher-batch:<batch id>".
+type VoucherRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewVoucherRepository(db *pgxpool.Pool) (*VoucherRepository, error) {
+	if db == nil {
+		return nil, fmt.Errorf("database connection is nil")
+	}
+	return &VoucherRepository{db: db}, nil
+}
+
+func voucherReference(batchID int64) string {
+	return "voucher-batch:" + strconv.FormatInt(batchID, 10)
+}
+
+func scanVoucherBatch(row pgx.Row) (domain.VoucherBatch, error) {
+	var b domain.VoucherBatch
+	err := row.Scan(&b.ID, &b.WalletID, &b.TreasuryWalletID, &b.Value, &b.MaxRedemptions, &b.Count, &b.Status,
+		&b.ExpiresAt, &b.CreatedAt, &b.UpdatedAt)
+	return b, err
+}
+
+// CreateVoucherBatch opens a voucher wallet for the batch, moves the value
+// of all its redemptions into it from the treasury wallet and stores the
+// code hashes.
+func (r *VoucherRepository) CreateVoucherBatch(ctx context.Context, params domain.VoucherBatchParams, codeHashes [][]byte) (domain.VoucherBatch, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return domain.VoucherBatch{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer rollback(ctx, tx)
+
+	// The treasury is locked before the batch row is inserted: its foreign
+	// key takes a share lock on the treasury, which upgrading to FOR UPDATE
+	// later would turn into a deadlock with a concurrent operation.
+	balances, err := lockWallets(ctx, tx, []string{params.TreasuryWalletID}, nil)
+	if err != nil {
+		return domain.VoucherBatch{}, err
+	}
+
+	if err = checkUserWallets(ctx, tx, params.TreasuryWalletID); err != nil {
+		return domain.VoucherBatch{}, err
+	}
+
+	var walletID string
+	err = tx.QueryRow(ctx,
+		`INSERT INTO wallet (id, balance, kind) VALUES (gen_random_uuid()::text, 0, $1) RETURNING id`,
+		domain.WalletVoucher,
+	).Scan(&walletID)
+	if err != nil {
+		return domain.VoucherBatch{}, fmt.Errorf("failed to create voucher wallet: %w", err)
+	}
+
+	b, err := scanVoucherBatch(tx.QueryRow(ctx,
+		`INSERT INTO voucher_batch (wallet_id, treasury_wallet_id, value, max_redemptions, count, status, expires_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7)
+		 RETURNING `+voucherBatchColumns,
+		walletID, params.TreasuryWalletID, params.Value, params.MaxRedemptions, params.Count,
+		domain.VoucherBatchActive, params.ExpiresAt,
+	))
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgForeignKeyViolation {
+			return domain.VoucherBatch{}, appErrors.ErrWalletNotFound
+		}
+		return domain.VoucherBatch{}, fmt.Errorf("failed to create voucher batch: %w", err)
+	}
+
+	total := b.Value * int64(b.MaxRedemptions) * int64(b.Count)
+	if balances[b.TreasuryWalletID] < total {
+		return domain.VoucherBatch{}, appErrors.ErrInsufficientFunds
+	}
+
+	if _, err = postVoucherMove(ctx, tx, b.ID, b.TreasuryWalletID, b.WalletID, domain.VOUCHER_FUND, total); err != nil {
+		return domain.VoucherBatch{}, err
+	}
+
+	_, err = tx.CopyFrom(ctx,
+		pgx.Identifier{"voucher"},
+		[]string{"batch_id", "code_hash"},
+		pgx.CopyFromSlice(len(codeHashes), func(i int) ([]any, error) {
+			return []any{b.ID, codeHashes[i]}, nil
+		}),
+	)
+	if err != nil {
+		return domain.VoucherBatch{}, fmt.Errorf("failed to create vouchers: %w", err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return domain.VoucherBatch{}, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return b, nil
+}
+
+// RedeemVoucher pays the value of the voucher with the code hash to the
+// wallet and returns the credit. The voucher row is locked first, so
+// concurrent redemptions of a code are applied one at a time, and the batch
+// row is share-locked, so the batch cannot expire in between. The wallet
+// is created when missing.
+func (r *VoucherRepository) RedeemVoucher(ctx context.Context, codeHash []byte, walletID string, now time.Time) (domain.Transaction, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return domain.Transaction{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer rollback(ctx, tx)
+
+	var (
+		voucherID   int64
+		redemptions int
+		b           domain.VoucherBatch
+	)
+	err = tx.QueryRow(ctx,
+		`SELECT v.id, v.redemptions, b.id, b.wallet_id, b.value, b.max_redemptions, b.status, b.expires_at
+		 FROM voucher v JOIN voucher_batch b ON b.id = v.batch_id
+		 WHERE v.code_hash = $1
+		 FOR UPDATE OF v FOR SHARE OF b`,
+		codeHash,
+	).Scan(&voucherID, &redemptions, &b.ID, &b.WalletID, &b.Value, &b.MaxRedemptions, &b.Status, &b.ExpiresAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.Transaction{}, appErrors.ErrVoucherNotFound
+		}
+		return domain.Transaction{}, fmt.Errorf("failed to get voucher: %w", err)
+	}
+
+	if b.Status != domain.VoucherBatchActive || !b.ExpiresAt.After(now) {
+		return domain.Transaction{}, appErrors.ErrVoucherExpired
+	}
+
+	if redemptions >= b.MaxRedemptions {
+		return domain.Transaction{}, appErrors.ErrVoucherRedeemed
+	}
+
+	var redeemed bool
+	err = tx.QueryRow(ctx,
+		`SELECT EXISTS (SELECT 1 FROM voucher_redemption WHERE voucher_id = $1 AND wallet_id = $2)`,
+		voucherID, walletID,
+	).Scan(&redeemed)
+	if err != nil {
+		return domain.Transaction{}, fmt.Errorf("failed to check voucher redemption: %w", err)
+	}
+	if redeemed {
+		return domain.Transaction{}, appErrors.ErrVoucherRedeemed
+	}
+
+	balances, err := lockWallets(ctx, tx, []string{b.WalletID, walletID}, map[string]bool{walletID: true})
+	if err != nil {
+		return domain.Transaction{}, err
+	}
+
+	if err = checkUserWallets(ctx, tx, walletID); err != nil {
+		return domain.Transaction{}, err
+	}
+
+	if balances[b.WalletID] < b.Value {
+		return domain.Transaction{}, fmt.Errorf("voucher batch %d is underfunded", b.ID)
+	}
+
+	credit, err := postVoucherMove(ctx, tx, b.ID, b.WalletID, walletID, domain.VOUCHER_REDEEM, b.Value)
+	if err != nil {
+		return domain.Transaction{}, err
+	}
+
+	_, err = tx.Exec(ctx,
+		`UPDATE voucher SET redemptions = redemptions + 1 WHERE id = $1`,
+		voucherID,
+	)
+	if err != nil {
+		return domain.Transaction{}, fmt.Errorf("failed to update voucher: %w", err)
+	}
+
+	_, err = tx.Exec(ctx,
+		`INSERT INTO voucher_redemption (voucher_id, wallet_id, transaction_id) VALUES ($1, $2, $3)`,
+		voucherID, walletID, credit.ID,
+	)
+	if err != nil {
+		return domain.Transaction{}, fmt.Errorf("failed to record voucher redemption: %w", err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return domain.Transaction{}, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return credit, nil
+}
+
+// ListExpiredVoucherBatches returns up to limit active batches that expired
+// at now.
+func (r *VoucherRepository) ListExpiredVoucherBatches(ctx context.Context, now time.Time, limit int) ([]domain.VoucherBatch, error) {
+	rows, err := r.db.Query(ctx,
+		`SELECT `+voucherBatchColumns+` FROM voucher_batch
+		 WHERE status = $1 AND expires_at <= $2
+		 ORDER BY expires_at
+		 LIMIT $3`,
+		domain.VoucherBatchActive, now, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list expired voucher batches: %w", err)
+	}
+
+	batches, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (domain.VoucherBatch, error) {
+		return scanVoucherBatch(row)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list expired voucher batches: %w", err)
+	}
+
+	return batches, nil
+}
+
+// ExpireVoucherBatch moves the unredeemed value of an expired batch back to
+// the treasury wallet and marks the batch EXPIRED. A batch that has already
+// expired is left as it is.
+func (r *VoucherRepository) ExpireVoucherBatch(ctx context.Context, id int64) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer rollback(ctx, tx)
+
+	b, err := scanVoucherBatch(tx.QueryRow(ctx,
+		`SELECT `+voucherBatchColumns+` FROM voucher_batch WHERE id = $1 FOR UPDATE`,
+		id,
+	))
+	if err != nil {
+		return fmt.Errorf("failed to get voucher batch: %w", err)
+	}
+
+	if b.Status != domain.VoucherBatchActive {
+		return nil
+	}
+
+	balances, err := lockWallets(ctx, tx, []string{b.WalletID, b.TreasuryWalletID}, nil)
+	if err != nil {
+		return err
+	}
+
+	if left := balances[b.WalletID]; left > 0 {
+		if _, err = postVoucherMove(ctx, tx, b.ID, b.WalletID, b.TreasuryWalletID, domain.VOUCHER_RECLAIM, left); err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.Exec(ctx,
+		`UPDATE voucher_batch SET status = $2, updated_at = now() WHERE id = $1`,
+		b.ID, domain.VoucherBatchExpired,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update voucher batch: %w", err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// postVoucherMove posts a debit and its credit leg between two wallets of a
+// voucher batch and returns the credit. The caller holds the wallet row
+// locks.
+func postVoucherMove(ctx context.Context, tx pgx.Tx, batchID int64, fromWalletID, toWalletID string, opType domain.OperationType, amount int64) (domain.Transaction, error) {
+	debit, err := postEntry(ctx, tx, entry{
+		walletID:  fromWalletID,
+		opType:    opType,
+		delta:     -amount,
+		reference: voucherReference(batchID),
+	})
+	if err != nil {
+		return domain.Transaction{}, err
+	}
+
+	return postEntry(ctx, tx, entry{
+		walletID:  toWalletID,
+		opType:    opType,
+		delta:     amount,
+		parentID:  debit.ID,
+		reference: voucherReference(batchID),
+	})
+}
@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Te8va/wallet/internal/domain"
+)
+
+//go:generate mockgen -source=batch.go -destination=mocks/batch_mock.gen.go -package=mocks
+type batchRepo interface {
+	CreateBatch(ctx context.Context, mode domain.BatchMode, items []domain.BatchItem) (domain.Batch, error)
+	GetBatch(ctx context.Context, id int64) (domain.Batch, error)
+	ClaimBatch(ctx context.Context, now, leaseUntil time.Time) (domain.Batch, bool, error)
+	ListPendingLines(ctx context.Context, batchID int64) ([]int, error)
+	ApplyBatchItem(ctx context.Context, batchID int64, line int) (domain.Transaction, error)
+	ApplyBatch(ctx context.Context, batchID int64) ([]domain.Transaction, error)
+	FinishBatch(ctx context.Context, batchID int64) error
+}
+
+// postRecorder counts an operation posted by a batch and runs what follows
+// it, like cashback and loyalty points.
+type postRecorder interface {
+	RecordPosted(ctx context.Context, t domain.Transaction)
+}
+
+// BatchPolicy controls how batches are processed in the background.
+type BatchPolicy struct {
+	Concurrency int
+	Lease       time.Duration
+}
+
+type BatchService struct {
+	repo     batchRepo
+	policy   BatchPolicy
+	recorder postRecorder
+	now      func() time.Time
+}
+
+func NewBatchService(repo batchRepo, policy BatchPolicy) *BatchService {
+	return &BatchService{
+		repo:   repo,
+		policy: policy,
+		now:    func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// SubmitBatch stores the batch for background processing. Items that failed
+// validation are expected to arrive already marked as FAILED.
+// SetRecorder makes the service pass every entry a batch posts to r once it
+// has committed.
+func (s *BatchService) SetRecorder(r postRecorder) {
+	s.recorder = r
+}
+
+func (s *BatchService) SubmitBatch(ctx context.Context, mode domain.BatchMode, items []domain.BatchItem) (domain.Batch, error) {
+	return s.repo.CreateBatch(ctx, mode, items)
+}
+
+func (s *BatchService) GetBatch(ctx context.Context, id int64) (domain.Batch, error) {
+	return s.repo.GetBatch(ctx, id)
+}
+
+// ProcessNext claims one unfinished batch and processes it. It returns the
+// number of batches processed, i.e. zero when there was nothing to do.
+func (s *BatchService) ProcessNext(ctx context.Context) (int, error) {
+	now := s.now()
+
+	batch, ok, err := s.repo.ClaimBatch(ctx, now, now.Add(s.policy.Lease))
+	if err != nil {
+		return 0, fmt.Errorf("service.ProcessNext: %w", err)
+	}
+
+	if !ok {
+		return 0, nil
+	}
+
+	if batch.Mode == domain.BatchAllOrNothing {
+		posted, err := s.repo.ApplyBatch(ctx, batch.ID)
+		if err != nil {
+			return 1, fmt.Errorf("service.ProcessNext: batch %d: %w", batch.ID, err)
+		}
+		for _, t := range posted {
+			s.recordPosted(ctx, t)
+		}
+		return 1, nil
+	}
+
+	if err = s.processBestEffort(ctx, batch.ID); err != nil {
+		return 1, fmt.Errorf("service.ProcessNext: batch %d: %w", batch.ID, err)
+	}
+
+	return 1, nil
+}
+
+// processBestEffort applies the pending items of a batch one by one with
+// bounded concurrency. Items hit by an unexpected error stay pending and are
+// picked up again the next time the batch is claimed.
+func (s *BatchService) processBestEffort(ctx context.Context, batchID int64) error {
+	lines, err := s.repo.ListPendingLines(ctx, batchID)
+	if err != nil {
+		return err
+	}
+
+	workers := max(1, min(s.policy.Concurrency, len(lines)))
+	queue := make(chan int)
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+	)
+
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for line := range queue {
+				t, err := s.repo.ApplyBatchItem(ctx, batchID, line)
+				if err != nil {
+					mu.Lock()
+					errs = append(errs, fmt.Errorf("line %d: %w", line, err))
+					mu.Unlock()
+					continue
+				}
+				s.recordPosted(ctx, t)
+			}
+		}()
+	}
+
+feed:
+	for _, line := range lines {
+		select {
+		case queue <- line:
+		case <-ctx.Done():
+			mu.Lock()
+			errs = append(errs, ctx.Err())
+			mu.Unlock()
+			break feed
+		}
+	}
+	close(queue)
+	wg.Wait()
+
+	if err = s.repo.FinishBatch(ctx, batchID); err != nil {
+		errs = append(errs, err)
+	}
+
+	return errors.Join(errs...)
+}
+
+// recordPosted passes an entry posted by a batch to the recorder. A zero
+// transaction means the item posted nothing.
+func (s *BatchService) recordPosted(ctx context.Context, t domain.Transaction) {
+	if s.recorder != nil && t.ID != 0 {
+		s.recorder.RecordPosted(ctx, t)
+	}
+}
@@ -0,0 +1,91 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Te8va/wallet/internal/domain"
+	"github.com/Te8va/wallet/internal/service"
+	"github.com/Te8va/wallet/internal/service/mocks"
+)
+
+func TestBatchService_ProcessNext(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockbatchRepo(ctrl)
+	mockRecorder := mocks.NewMockpostRecorder(ctrl)
+	svc := service.NewBatchService(mockRepo, service.BatchPolicy{Concurrency: 4, Lease: time.Minute})
+	svc.SetRecorder(mockRecorder)
+
+	t.Run("nothing to do", func(t *testing.T) {
+		mockRepo.EXPECT().ClaimBatch(gomock.Any(), gomock.Any(), gomock.Any()).Return(domain.Batch{}, false, nil)
+
+		n, err := svc.ProcessNext(context.Background())
+		require.NoError(t, err)
+		require.Zero(t, n)
+	})
+
+	t.Run("all or nothing batch is applied in one call", func(t *testing.T) {
+		posted := []domain.Transaction{
+			{ID: 10, WalletID: "a", OperationType: domain.DEPOSIT, Amount: 100},
+			{ID: 11, WalletID: "b", OperationType: domain.WITHDRAW, Amount: -50},
+		}
+
+		mockRepo.EXPECT().ClaimBatch(gomock.Any(), gomock.Any(), gomock.Any()).Return(domain.Batch{ID: 1, Mode: domain.BatchAllOrNothing}, true, nil)
+		mockRepo.EXPECT().ApplyBatch(gomock.Any(), int64(1)).Return(posted, nil)
+		mockRecorder.EXPECT().RecordPosted(gomock.Any(), posted[0])
+		mockRecorder.EXPECT().RecordPosted(gomock.Any(), posted[1])
+
+		n, err := svc.ProcessNext(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, 1, n)
+	})
+
+	t.Run("best effort batch applies every pending line", func(t *testing.T) {
+		lines := []int{1, 2, 3, 5, 8, 13}
+
+		mockRepo.EXPECT().ClaimBatch(gomock.Any(), gomock.Any(), gomock.Any()).Return(domain.Batch{ID: 2, Mode: domain.BatchBestEffort}, true, nil)
+		mockRepo.EXPECT().ListPendingLines(gomock.Any(), int64(2)).Return(lines, nil)
+
+		var (
+			mu      sync.Mutex
+			applied []int
+		)
+		mockRepo.EXPECT().ApplyBatchItem(gomock.Any(), int64(2), gomock.Any()).Times(len(lines)).DoAndReturn(
+			func(_ context.Context, _ int64, line int) (domain.Transaction, error) {
+				mu.Lock()
+				defer mu.Unlock()
+				applied = append(applied, line)
+				if line == 13 {
+					// The last line failed validation and posted nothing.
+					return domain.Transaction{}, nil
+				}
+				return domain.Transaction{ID: int64(100 + line), WalletID: "a", OperationType: domain.WITHDRAW, Amount: -10}, nil
+			})
+		mockRecorder.EXPECT().RecordPosted(gomock.Any(), gomock.Any()).Times(len(lines) - 1)
+		mockRepo.EXPECT().FinishBatch(gomock.Any(), int64(2)).Return(nil)
+
+		n, err := svc.ProcessNext(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, 1, n)
+		require.ElementsMatch(t, lines, applied)
+	})
+
+	t.Run("unexpected item error is reported and the batch is still finished", func(t *testing.T) {
+		mockRepo.EXPECT().ClaimBatch(gomock.Any(), gomock.Any(), gomock.Any()).Return(domain.Batch{ID: 3, Mode: domain.BatchBestEffort}, true, nil)
+		mockRepo.EXPECT().ListPendingLines(gomock.Any(), int64(3)).Return([]int{1, 2}, nil)
+		mockRepo.EXPECT().ApplyBatchItem(gomock.Any(), int64(3), 1).Return(domain.Transaction{}, nil)
+		mockRepo.EXPECT().ApplyBatchItem(gomock.Any(), int64(3), 2).Return(domain.Transaction{}, errors.New("database error"))
+		mockRepo.EXPECT().FinishBatch(gomock.Any(), int64(3)).Return(nil)
+
+		_, err := svc.ProcessNext(context.Background())
+		require.ErrorContains(t, err, "line 2: database error")
+	})
+}
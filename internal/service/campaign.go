@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/bits"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/Te8va/wallet/internal/domain"
+	appErrors "github.com/Te8va/wallet/internal/errors"
+	"github.com/Te8va/wallet/internal/logging"
+)
+
+//go:generate mockgen -source=campaign.go -destination=mocks/campaign_mock.gen.go -package=mocks
+type campaignRepo interface {
+	CreateCampaign(ctx context.Context, params domain.CampaignParams) (domain.Campaign, error)
+	GetCampaign(ctx context.Context, id int64) (domain.Campaign, error)
+	ListCampaigns(ctx context.Context) ([]domain.Campaign, error)
+	ListActiveCampaigns(ctx context.Context, at time.Time) ([]domain.Campaign, error)
+	CreditCashback(ctx context.Context, cr domain.CashbackCredit) (int64, error)
+}
+
+type CampaignService struct {
+	repo campaignRepo
+	now  func() time.Time
+}
+
+func NewCampaignService(repo campaignRepo) *CampaignService {
+	return &CampaignService{
+		repo: repo,
+		now:  func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// CreateCampaign starts a campaign. Without a start time it starts now.
+func (s *CampaignService) CreateCampaign(ctx context.Context, params domain.CampaignParams) (domain.Campaign, error) {
+	if params.StartsAt.IsZero() {
+		params.StartsAt = s.now()
+	}
+	params.StartsAt = params.StartsAt.UTC()
+	params.EndsAt = params.EndsAt.UTC()
+
+	if !params.EndsAt.After(params.StartsAt) {
+		return domain.Campaign{}, appErrors.ErrInvalidCampaign
+	}
+
+	return s.repo.CreateCampaign(ctx, params)
+}
+
+func (s *CampaignService) GetCampaign(ctx context.Context, id int64) (domain.Campaign, error) {
+	return s.repo.GetCampaign(ctx, id)
+}
+
+func (s *CampaignService) ListCampaigns(ctx context.Context) ([]domain.Campaign, error) {
+	return s.repo.ListCampaigns(ctx)
+}
+
+// ApplyCashback credits the cashback of every campaign running when t was
+// posted, if t is a qualifying withdrawal. Each campaign pays at most once
+// per transaction, so calling it again for the same transaction, for
+// example after a replayed request, only pays what was missed.
+func (s *CampaignService) ApplyCashback(ctx context.Context, t domain.Transaction) error {
+	if t.OperationType != domain.WITHDRAW {
+		return nil
+	}
+	spent := -t.Amount
+
+	campaigns, err := s.repo.ListActiveCampaigns(ctx, t.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("service.ApplyCashback: %w", err)
+	}
+
+	var errs []error
+	for _, c := range campaigns {
+		if c.FundingWalletID == t.WalletID || spent < c.MinAmount {
+			continue
+		}
+
+		amount := bpsOf(spent, c.RateBps)
+		if amount == 0 {
+			continue
+		}
+
+		credited, err := s.repo.CreditCashback(ctx, domain.CashbackCredit{
+			CampaignID:          c.ID,
+			SourceTransactionID: t.ID,
+			WalletID:            t.WalletID,
+			Amount:              amount,
+		})
+		switch {
+		case errors.Is(err, appErrors.ErrDuplicateOperation):
+		case err != nil:
+			errs = append(errs, fmt.Errorf("campaign %d: %w", c.ID, err))
+		case credited > 0:
+			logging.FromContext(ctx).Info("Cashback credited",
+				zap.Int64("campaign_id", c.ID),
+				zap.Int64("source_transaction_id", t.ID),
+				zap.String("wallet_id", t.WalletID),
+				zap.Int64("amount", credited),
+			)
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("service.ApplyCashback: %w", errors.Join(errs...))
+	}
+
+	return nil
+}
+
+// bpsOf is rateBps basis points of amount, rounded down.
+func bpsOf(amount, rateBps int64) int64 {
+	hi, lo := bits.Mul64(uint64(amount), uint64(rateBps))
+	q, _ := bits.Div64(hi, lo, fullShareBps)
+	return int64(q)
+}
@@ -0,0 +1,153 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Te8va/wallet/internal/domain"
+	appErrors "github.com/Te8va/wallet/internal/errors"
+	"github.com/Te8va/wallet/internal/service"
+	"github.com/Te8va/wallet/internal/service/mocks"
+)
+
+func TestCampaignService_CreateCampaign(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockcampaignRepo(ctrl)
+	svc := service.NewCampaignService(mockRepo)
+
+	startsAt := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+	params := domain.CampaignParams{
+		Name: "spring", FundingWalletID: "marketing", RateBps: 500, Budget: 100000,
+		StartsAt: startsAt, EndsAt: startsAt.AddDate(0, 1, 0),
+	}
+
+	mockRepo.EXPECT().CreateCampaign(gomock.Any(), params).Return(domain.Campaign{ID: 1}, nil)
+	_, err := svc.CreateCampaign(context.Background(), params)
+	require.NoError(t, err)
+
+	params.EndsAt = startsAt
+	_, err = svc.CreateCampaign(context.Background(), params)
+	require.ErrorIs(t, err, appErrors.ErrInvalidCampaign)
+}
+
+func TestCampaignService_ApplyCashback(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockcampaignRepo(ctrl)
+	svc := service.NewCampaignService(mockRepo)
+
+	postedAt := time.Date(2025, 4, 10, 12, 0, 0, 0, time.UTC)
+	withdrawal := domain.Transaction{ID: 42, WalletID: "wallet", OperationType: domain.WITHDRAW, Amount: -1999, CreatedAt: postedAt}
+
+	testCases := []struct {
+		name        string
+		t           domain.Transaction
+		mockRepo    func()
+		expectedErr error
+	}{
+		{
+			name: "rate rounded down and every campaign applied",
+			t:    withdrawal,
+			mockRepo: func() {
+				mockRepo.EXPECT().ListActiveCampaigns(gomock.Any(), postedAt).Return([]domain.Campaign{
+					{ID: 1, FundingWalletID: "marketing", RateBps: 500},
+					{ID: 2, FundingWalletID: "partner", RateBps: 100, MinAmount: 1000},
+				}, nil)
+				mockRepo.EXPECT().CreditCashback(gomock.Any(), domain.CashbackCredit{
+					CampaignID: 1, SourceTransactionID: 42, WalletID: "wallet", Amount: 99,
+				}).Return(int64(99), nil)
+				mockRepo.EXPECT().CreditCashback(gomock.Any(), domain.CashbackCredit{
+					CampaignID: 2, SourceTransactionID: 42, WalletID: "wallet", Amount: 19,
+				}).Return(int64(0), nil)
+			},
+		},
+		{
+			name: "below the minimum and from the funding wallet",
+			t:    withdrawal,
+			mockRepo: func() {
+				mockRepo.EXPECT().ListActiveCampaigns(gomock.Any(), postedAt).Return([]domain.Campaign{
+					{ID: 1, FundingWalletID: "marketing", RateBps: 500, MinAmount: 2000},
+					{ID: 2, FundingWalletID: "wallet", RateBps: 500},
+				}, nil)
+			},
+		},
+		{
+			name: "already credited",
+			t:    withdrawal,
+			mockRepo: func() {
+				mockRepo.EXPECT().ListActiveCampaigns(gomock.Any(), postedAt).Return([]domain.Campaign{
+					{ID: 1, FundingWalletID: "marketing", RateBps: 500},
+				}, nil)
+				mockRepo.EXPECT().CreditCashback(gomock.Any(), gomock.Any()).Return(int64(0), appErrors.ErrDuplicateOperation)
+			},
+		},
+		{
+			name: "key held by another operation",
+			t:    withdrawal,
+			mockRepo: func() {
+				mockRepo.EXPECT().ListActiveCampaigns(gomock.Any(), postedAt).Return([]domain.Campaign{
+					{ID: 1, FundingWalletID: "marketing", RateBps: 500},
+				}, nil)
+				mockRepo.EXPECT().CreditCashback(gomock.Any(), gomock.Any()).Return(int64(0), appErrors.ErrIdempotencyKeyReused)
+			},
+			expectedErr: appErrors.ErrIdempotencyKeyReused,
+		},
+		{
+			name: "funding wallet empty",
+			t:    withdrawal,
+			mockRepo: func() {
+				mockRepo.EXPECT().ListActiveCampaigns(gomock.Any(), postedAt).Return([]domain.Campaign{
+					{ID: 1, FundingWalletID: "marketing", RateBps: 500},
+				}, nil)
+				mockRepo.EXPECT().CreditCashback(gomock.Any(), gomock.Any()).Return(int64(0), appErrors.ErrInsufficientFunds)
+			},
+			expectedErr: appErrors.ErrInsufficientFunds,
+		},
+		{
+			name: "wallet not a user wallet",
+			t:    withdrawal,
+			mockRepo: func() {
+				mockRepo.EXPECT().ListActiveCampaigns(gomock.Any(), postedAt).Return([]domain.Campaign{
+					{ID: 1, FundingWalletID: "escrow", RateBps: 500},
+				}, nil)
+				mockRepo.EXPECT().CreditCashback(gomock.Any(), gomock.Any()).Return(int64(0), appErrors.ErrWalletKindNotAllowed)
+			},
+			expectedErr: appErrors.ErrWalletKindNotAllowed,
+		},
+		{
+			name:     "deposit",
+			t:        domain.Transaction{ID: 43, WalletID: "wallet", OperationType: domain.DEPOSIT, Amount: 1000},
+			mockRepo: func() {},
+		},
+		{
+			name: "repository error",
+			t:    withdrawal,
+			mockRepo: func() {
+				mockRepo.EXPECT().ListActiveCampaigns(gomock.Any(), postedAt).Return(nil, errors.New("connection reset"))
+			},
+			expectedErr: errors.New("connection reset"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mockRepo()
+
+			err := svc.ApplyCashback(context.Background(), tc.t)
+
+			if tc.expectedErr != nil {
+				require.ErrorContains(t, err, tc.expectedErr.Error())
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Te8va/wallet/internal/domain"
+)
+
+//go:generate mockgen -source=check.go -destination=mocks/check_mock.gen.go -package=mocks
+type checkRepo interface {
+	CheckLedger(ctx context.Context) (domain.LedgerReport, error)
+}
+
+type CheckService struct {
+	repo checkRepo
+}
+
+func NewCheckService(repo checkRepo) *CheckService {
+	return &CheckService{repo: repo}
+}
+
+// CheckLedger verifies the ledger invariants and proposes repairs for the
+// drift that can be derived from the journal. The journal is the source of
+// truth: cached balances and balance_after values are brought in line with
+// it, while negative balances, unbalanced postings and orphan records are
+// left for manual review.
+func (s *CheckService) CheckLedger(ctx context.Context) (domain.LedgerReport, error) {
+	report, err := s.repo.CheckLedger(ctx)
+	if err != nil {
+		return domain.LedgerReport{}, fmt.Errorf("service.CheckLedger: %w", err)
+	}
+
+	report.CheckedAt = time.Now().UTC()
+	report.Counts = make(map[domain.CheckKind]int, len(domain.CheckKinds))
+	for _, kind := range domain.CheckKinds {
+		report.Counts[kind] = 0
+	}
+
+	report.Repairs = []domain.Repair{}
+	for _, d := range report.Discrepancies {
+		report.Counts[d.Check]++
+
+		switch d.Check {
+		case domain.CheckBalanceMismatch, domain.CheckJournalChain:
+			report.Repairs = append(report.Repairs, domain.Repair{
+				Check:         d.Check,
+				WalletID:      d.WalletID,
+				TransactionID: d.TransactionID,
+				From:          d.Actual,
+				To:            d.Expected,
+			})
+		}
+	}
+	report.OK = len(report.Discrepancies) == 0
+
+	return report, nil
+}
@@ -0,0 +1,72 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Te8va/wallet/internal/domain"
+	"github.com/Te8va/wallet/internal/service"
+	"github.com/Te8va/wallet/internal/service/mocks"
+)
+
+func TestCheckService_CheckLedger(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockcheckRepo(ctrl)
+	svc := service.NewCheckService(mockRepo)
+
+	t.Run("consistent ledger", func(t *testing.T) {
+		mockRepo.EXPECT().CheckLedger(gomock.Any()).Return(domain.LedgerReport{
+			Wallets:       2,
+			Transactions:  5,
+			Discrepancies: []domain.Discrepancy{},
+		}, nil)
+
+		report, err := svc.CheckLedger(context.Background())
+		require.NoError(t, err)
+		require.True(t, report.OK)
+		require.Empty(t, report.Repairs)
+		require.Len(t, report.Counts, len(domain.CheckKinds))
+		require.False(t, report.CheckedAt.IsZero())
+	})
+
+	t.Run("drift is counted and repaired from the journal", func(t *testing.T) {
+		mockRepo.EXPECT().CheckLedger(gomock.Any()).Return(domain.LedgerReport{
+			Discrepancies: []domain.Discrepancy{
+				{Check: domain.CheckBalanceMismatch, WalletID: "w1", Expected: 1500, Actual: 1400},
+				{Check: domain.CheckJournalChain, WalletID: "w1", TransactionID: 7, Expected: 900, Actual: 800},
+				{Check: domain.CheckUnbalancedPosting, WalletID: "w2", TransactionID: 9, Actual: -100},
+				{Check: domain.CheckOrphanRecord, WalletID: "w3", TransactionID: 12},
+			},
+		}, nil)
+
+		report, err := svc.CheckLedger(context.Background())
+		require.NoError(t, err)
+		require.False(t, report.OK)
+		require.Equal(t, map[domain.CheckKind]int{
+			domain.CheckNegativeBalance:   0,
+			domain.CheckBalanceMismatch:   1,
+			domain.CheckJournalChain:      1,
+			domain.CheckUnbalancedPosting: 1,
+			domain.CheckOrphanRecord:      1,
+			domain.CheckPocketMismatch:    0,
+			domain.CheckPointsMismatch:    0,
+		}, report.Counts)
+		require.Equal(t, []domain.Repair{
+			{Check: domain.CheckBalanceMismatch, WalletID: "w1", From: 1400, To: 1500},
+			{Check: domain.CheckJournalChain, WalletID: "w1", TransactionID: 7, From: 800, To: 900},
+		}, report.Repairs)
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		mockRepo.EXPECT().CheckLedger(gomock.Any()).Return(domain.LedgerReport{}, errors.New("db error"))
+
+		_, err := svc.CheckLedger(context.Background())
+		require.Error(t, err)
+	})
+}
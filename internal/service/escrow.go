@@ -0,0 +1,174 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/Te8va/wallet/internal/domain"
+	appErrors "github.com/Te8va/wallet/internal/errors"
+)
+
+//go:generate mockgen -source=escrow.go -destination=mocks/escrow_mock.gen.go -package=mocks
+type escrowRepo interface {
+	CreateEscrow(ctx context.Context, req domain.EscrowRequest) (domain.Escrow, error)
+	GetEscrow(ctx context.Context, dealID string) (domain.Escrow, error)
+	TransitionEscrow(ctx context.Context, t domain.EscrowTransition) (domain.Escrow, error)
+	ListExpiredEscrows(ctx context.Context, now time.Time, limit int) ([]domain.Escrow, error)
+}
+
+// escrowTransitions is the escrow state machine: the statuses an escrow may
+// move to from each status. RELEASED, REFUNDED and SPLIT are final.
+var escrowTransitions = map[domain.EscrowStatus][]domain.EscrowStatus{
+	domain.EscrowFunded:   {domain.EscrowReleased, domain.EscrowRefunded, domain.EscrowDisputed},
+	domain.EscrowDisputed: {domain.EscrowReleased, domain.EscrowRefunded, domain.EscrowSplit},
+}
+
+// EscrowPolicy controls escrow defaults and the timeout job.
+type EscrowPolicy struct {
+	BatchSize      int
+	DefaultTimeout time.Duration
+}
+
+type EscrowService struct {
+	repo   escrowRepo
+	policy EscrowPolicy
+	now    func() time.Time
+}
+
+func NewEscrowService(repo escrowRepo, policy EscrowPolicy) *EscrowService {
+	return &EscrowService{
+		repo:   repo,
+		policy: policy,
+		now:    func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// CreateEscrow moves the amount from the buyer wallet into a new escrow for
+// the deal. Without an expiry the escrow times out after the policy default,
+// and without a timeout action it is released to the seller.
+func (s *EscrowService) CreateEscrow(ctx context.Context, req domain.EscrowRequest) (domain.Escrow, error) {
+	if req.BuyerWalletID == req.SellerWalletID {
+		return domain.Escrow{}, appErrors.ErrSameWallet
+	}
+
+	if req.ExpiresAt.IsZero() {
+		req.ExpiresAt = s.now().Add(s.policy.DefaultTimeout)
+	}
+	req.ExpiresAt = req.ExpiresAt.UTC()
+
+	if req.OnTimeout == "" {
+		req.OnTimeout = domain.EscrowReleased
+	}
+
+	return s.repo.CreateEscrow(ctx, req)
+}
+
+func (s *EscrowService) GetEscrow(ctx context.Context, dealID string) (domain.Escrow, error) {
+	return s.repo.GetEscrow(ctx, dealID)
+}
+
+// ReleaseEscrow pays the whole escrow to the seller.
+func (s *EscrowService) ReleaseEscrow(ctx context.Context, dealID, reason string) (domain.Escrow, error) {
+	return s.settle(ctx, dealID, domain.EscrowRelease, domain.EscrowReleased, reason)
+}
+
+// RefundEscrow returns the whole escrow to the buyer.
+func (s *EscrowService) RefundEscrow(ctx context.Context, dealID, reason string) (domain.Escrow, error) {
+	return s.settle(ctx, dealID, domain.EscrowRefund, domain.EscrowRefunded, reason)
+}
+
+// DisputeEscrow freezes a funded escrow: it no longer times out and can only
+// be released, refunded or split explicitly.
+func (s *EscrowService) DisputeEscrow(ctx context.Context, dealID, reason string) (domain.Escrow, error) {
+	e, err := s.repo.GetEscrow(ctx, dealID)
+	if err != nil {
+		return domain.Escrow{}, err
+	}
+
+	return s.transition(ctx, e, domain.EscrowDispute, domain.EscrowDisputed, 0, reason)
+}
+
+// ResolveEscrow settles a disputed escrow by paying sellerAmount to the
+// seller and refunding the rest to the buyer.
+func (s *EscrowService) ResolveEscrow(ctx context.Context, dealID string, sellerAmount int64, reason string) (domain.Escrow, error) {
+	e, err := s.repo.GetEscrow(ctx, dealID)
+	if err != nil {
+		return domain.Escrow{}, err
+	}
+
+	if sellerAmount < 0 || sellerAmount > e.Amount {
+		return domain.Escrow{}, appErrors.ErrInvalidEscrowSplit
+	}
+
+	return s.transition(ctx, e, domain.EscrowResolve, domain.EscrowSplit, sellerAmount, reason)
+}
+
+// RunExpired applies the timeout action of funded escrows that have expired.
+// It returns the number of escrows processed. An escrow settled or disputed
+// concurrently is skipped.
+func (s *EscrowService) RunExpired(ctx context.Context) (int, error) {
+	escrows, err := s.repo.ListExpiredEscrows(ctx, s.now(), s.policy.BatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("service.RunExpired: %w", err)
+	}
+
+	var errs []error
+	for _, e := range escrows {
+		var sellerAmount int64
+		if e.OnTimeout == domain.EscrowReleased {
+			sellerAmount = e.Amount
+		}
+
+		_, err := s.transition(ctx, e, domain.EscrowTimeout, e.OnTimeout, sellerAmount, "")
+		if err != nil && !errors.Is(err, appErrors.ErrInvalidEscrowState) {
+			errs = append(errs, fmt.Errorf("escrow %s: %w", e.DealID, err))
+		}
+	}
+
+	if len(errs) > 0 {
+		return len(escrows), fmt.Errorf("service.RunExpired: %w", errors.Join(errs...))
+	}
+
+	return len(escrows), nil
+}
+
+func (s *EscrowService) settle(ctx context.Context, dealID string, action domain.EscrowAction, to domain.EscrowStatus, reason string) (domain.Escrow, error) {
+	e, err := s.repo.GetEscrow(ctx, dealID)
+	if err != nil {
+		return domain.Escrow{}, err
+	}
+
+	var sellerAmount int64
+	if to == domain.EscrowReleased {
+		sellerAmount = e.Amount
+	}
+
+	return s.transition(ctx, e, action, to, sellerAmount, reason)
+}
+
+// transition checks the move against the state machine and hands it to the
+// repository, which applies it only if the escrow has not changed status in
+// the meantime. Money not paid to the seller is refunded to the buyer, except
+// on a dispute, which moves no money.
+func (s *EscrowService) transition(ctx context.Context, e domain.Escrow, action domain.EscrowAction, to domain.EscrowStatus, sellerAmount int64, reason string) (domain.Escrow, error) {
+	if !slices.Contains(escrowTransitions[e.Status], to) {
+		return domain.Escrow{}, appErrors.ErrInvalidEscrowState
+	}
+
+	t := domain.EscrowTransition{
+		DealID:       e.DealID,
+		Action:       action,
+		From:         e.Status,
+		To:           to,
+		SellerAmount: sellerAmount,
+		Reason:       reason,
+	}
+	if to != domain.EscrowDisputed {
+		t.BuyerAmount = e.Amount - sellerAmount
+	}
+
+	return s.repo.TransitionEscrow(ctx, t)
+}
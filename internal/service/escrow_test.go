@@ -0,0 +1,140 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Te8va/wallet/internal/domain"
+	appErrors "github.com/Te8va/wallet/internal/errors"
+	"github.com/Te8va/wallet/internal/service"
+	"github.com/Te8va/wallet/internal/service/mocks"
+)
+
+func TestEscrowService_Transitions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockescrowRepo(ctrl)
+	svc := service.NewEscrowService(mockRepo, service.EscrowPolicy{})
+
+	escrow := func(status domain.EscrowStatus) domain.Escrow {
+		return domain.Escrow{DealID: "deal-1", Amount: 1000, Status: status}
+	}
+
+	testCases := []struct {
+		name        string
+		status      domain.EscrowStatus
+		call        func() (domain.Escrow, error)
+		want        *domain.EscrowTransition
+		expectedErr error
+	}{
+		{
+			name:   "release funded",
+			status: domain.EscrowFunded,
+			call:   func() (domain.Escrow, error) { return svc.ReleaseEscrow(context.Background(), "deal-1", "delivered") },
+			want: &domain.EscrowTransition{DealID: "deal-1", Action: domain.EscrowRelease, From: domain.EscrowFunded,
+				To: domain.EscrowReleased, SellerAmount: 1000, Reason: "delivered"},
+		},
+		{
+			name:   "refund disputed",
+			status: domain.EscrowDisputed,
+			call:   func() (domain.Escrow, error) { return svc.RefundEscrow(context.Background(), "deal-1", "") },
+			want: &domain.EscrowTransition{DealID: "deal-1", Action: domain.EscrowRefund, From: domain.EscrowDisputed,
+				To: domain.EscrowRefunded, BuyerAmount: 1000},
+		},
+		{
+			name:   "dispute moves no money",
+			status: domain.EscrowFunded,
+			call: func() (domain.Escrow, error) {
+				return svc.DisputeEscrow(context.Background(), "deal-1", "not delivered")
+			},
+			want: &domain.EscrowTransition{DealID: "deal-1", Action: domain.EscrowDispute, From: domain.EscrowFunded,
+				To: domain.EscrowDisputed, Reason: "not delivered"},
+		},
+		{
+			name:   "resolve splits the amount",
+			status: domain.EscrowDisputed,
+			call: func() (domain.Escrow, error) {
+				return svc.ResolveEscrow(context.Background(), "deal-1", 300, "partial")
+			},
+			want: &domain.EscrowTransition{DealID: "deal-1", Action: domain.EscrowResolve, From: domain.EscrowDisputed,
+				To: domain.EscrowSplit, SellerAmount: 300, BuyerAmount: 700, Reason: "partial"},
+		},
+		{
+			name:        "resolve without dispute",
+			status:      domain.EscrowFunded,
+			call:        func() (domain.Escrow, error) { return svc.ResolveEscrow(context.Background(), "deal-1", 300, "") },
+			expectedErr: appErrors.ErrInvalidEscrowState,
+		},
+		{
+			name:        "resolve above amount",
+			status:      domain.EscrowDisputed,
+			call:        func() (domain.Escrow, error) { return svc.ResolveEscrow(context.Background(), "deal-1", 1001, "") },
+			expectedErr: appErrors.ErrInvalidEscrowSplit,
+		},
+		{
+			name:        "release released",
+			status:      domain.EscrowReleased,
+			call:        func() (domain.Escrow, error) { return svc.ReleaseEscrow(context.Background(), "deal-1", "") },
+			expectedErr: appErrors.ErrInvalidEscrowState,
+		},
+		{
+			name:        "dispute disputed",
+			status:      domain.EscrowDisputed,
+			call:        func() (domain.Escrow, error) { return svc.DisputeEscrow(context.Background(), "deal-1", "") },
+			expectedErr: appErrors.ErrInvalidEscrowState,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo.EXPECT().GetEscrow(gomock.Any(), "deal-1").Return(escrow(tc.status), nil)
+			if tc.want != nil {
+				mockRepo.EXPECT().TransitionEscrow(gomock.Any(), *tc.want).Return(escrow(tc.want.To), nil)
+			}
+
+			e, err := tc.call()
+
+			require.ErrorIs(t, err, tc.expectedErr)
+			if tc.want != nil {
+				require.Equal(t, tc.want.To, e.Status)
+			}
+		})
+	}
+}
+
+func TestEscrowService_RunExpired(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockescrowRepo(ctrl)
+	svc := service.NewEscrowService(mockRepo, service.EscrowPolicy{BatchSize: 10})
+
+	expired := []domain.Escrow{
+		{DealID: "deal-1", Amount: 1000, Status: domain.EscrowFunded, OnTimeout: domain.EscrowReleased},
+		{DealID: "deal-2", Amount: 500, Status: domain.EscrowFunded, OnTimeout: domain.EscrowRefunded},
+		{DealID: "deal-3", Amount: 200, Status: domain.EscrowFunded, OnTimeout: domain.EscrowReleased},
+	}
+
+	mockRepo.EXPECT().ListExpiredEscrows(gomock.Any(), gomock.Any(), 10).Return(expired, nil)
+	mockRepo.EXPECT().TransitionEscrow(gomock.Any(), domain.EscrowTransition{DealID: "deal-1", Action: domain.EscrowTimeout,
+		From: domain.EscrowFunded, To: domain.EscrowReleased, SellerAmount: 1000}).Return(domain.Escrow{}, nil)
+	mockRepo.EXPECT().TransitionEscrow(gomock.Any(), domain.EscrowTransition{DealID: "deal-2", Action: domain.EscrowTimeout,
+		From: domain.EscrowFunded, To: domain.EscrowRefunded, BuyerAmount: 500}).Return(domain.Escrow{}, nil)
+	// deal-3 was disputed after it was listed.
+	mockRepo.EXPECT().TransitionEscrow(gomock.Any(), gomock.Any()).Return(domain.Escrow{}, appErrors.ErrInvalidEscrowState)
+
+	n, err := svc.RunExpired(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 3, n)
+
+	mockRepo.EXPECT().ListExpiredEscrows(gomock.Any(), gomock.Any(), 10).Return(expired[:1], nil)
+	mockRepo.EXPECT().TransitionEscrow(gomock.Any(), gomock.Any()).Return(domain.Escrow{}, errors.New("database error"))
+
+	_, err = svc.RunExpired(context.Background())
+	require.Error(t, err)
+}
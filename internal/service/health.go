@@ -0,0 +1,132 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/Te8va/wallet/internal/domain"
+)
+
+//go:generate mockgen -source=health.go -destination=mocks/health_mock.gen.go -package=mocks
+type healthRepo interface {
+	Ping(ctx context.Context) error
+	MigrationVersion(ctx context.Context) (uint, bool, error)
+	PoolUsage() (acquired, total int32)
+}
+
+// HealthPolicy configures the readiness checks.
+type HealthPolicy struct {
+	// MigrationVersion is the schema version the binary was built for.
+	MigrationVersion uint
+	// MaxPoolUsage is the share of acquired pool connections at which the
+	// instance stops accepting traffic.
+	MaxPoolUsage float64
+	// Timeout bounds each check.
+	Timeout time.Duration
+}
+
+var errShuttingDown = errors.New("shutting down")
+
+type HealthService struct {
+	repo         healthRepo
+	policy       HealthPolicy
+	shuttingDown atomic.Bool
+}
+
+func NewHealthService(repo healthRepo, policy HealthPolicy) *HealthService {
+	return &HealthService{repo: repo, policy: policy}
+}
+
+// SetShuttingDown makes every following readiness check fail, so load
+// balancers drain the instance before the server stops.
+func (s *HealthService) SetShuttingDown() {
+	s.shuttingDown.Store(true)
+}
+
+// Ready runs the readiness checks. The report is ok only when all pass.
+func (s *HealthService) Ready(ctx context.Context) domain.HealthReport {
+	report := domain.HealthReport{Status: domain.HealthOK}
+
+	checks := []struct {
+		name string
+		run  func(ctx context.Context) error
+	}{
+		{name: "shutdown", run: s.checkShutdown},
+		{name: "database", run: s.repo.Ping},
+		{name: "migrations", run: s.checkMigrations},
+		{name: "pool", run: s.checkPool},
+	}
+
+	for _, c := range checks {
+		check := s.run(ctx, c.name, c.run)
+		if check.Status != domain.HealthOK {
+			report.Status = domain.HealthFail
+		}
+		report.Checks = append(report.Checks, check)
+	}
+
+	return report
+}
+
+func (s *HealthService) run(ctx context.Context, name string, fn func(ctx context.Context) error) domain.HealthCheck {
+	if s.policy.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.policy.Timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	err := fn(ctx)
+
+	check := domain.HealthCheck{
+		Name:      name,
+		Status:    domain.HealthOK,
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		check.Status = domain.HealthFail
+		check.Error = err.Error()
+	}
+
+	return check
+}
+
+func (s *HealthService) checkShutdown(context.Context) error {
+	if s.shuttingDown.Load() {
+		return errShuttingDown
+	}
+	return nil
+}
+
+func (s *HealthService) checkMigrations(ctx context.Context) error {
+	version, dirty, err := s.repo.MigrationVersion(ctx)
+	if err != nil {
+		return err
+	}
+
+	if dirty {
+		return fmt.Errorf("migration %d is dirty", version)
+	}
+
+	if version < s.policy.MigrationVersion {
+		return fmt.Errorf("schema version %d is behind %d", version, s.policy.MigrationVersion)
+	}
+
+	return nil
+}
+
+func (s *HealthService) checkPool(context.Context) error {
+	acquired, total := s.repo.PoolUsage()
+	if total == 0 || s.policy.MaxPoolUsage <= 0 {
+		return nil
+	}
+
+	if float64(acquired)/float64(total) >= s.policy.MaxPoolUsage {
+		return fmt.Errorf("%d of %d connections in use", acquired, total)
+	}
+
+	return nil
+}
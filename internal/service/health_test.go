@@ -0,0 +1,95 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Te8va/wallet/internal/domain"
+	"github.com/Te8va/wallet/internal/service"
+	"github.com/Te8va/wallet/internal/service/mocks"
+)
+
+func TestHealthService_Ready(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	policy := service.HealthPolicy{MigrationVersion: 7, MaxPoolUsage: 0.9}
+
+	testCases := []struct {
+		name       string
+		mockRepo   func(m *mocks.MockhealthRepo)
+		shutdown   bool
+		wantStatus domain.HealthStatus
+		wantFailed []string
+	}{
+		{
+			name: "all checks pass",
+			mockRepo: func(m *mocks.MockhealthRepo) {
+				m.EXPECT().Ping(gomock.Any()).Return(nil)
+				m.EXPECT().MigrationVersion(gomock.Any()).Return(uint(7), false, nil)
+				m.EXPECT().PoolUsage().Return(int32(2), int32(10))
+			},
+			wantStatus: domain.HealthOK,
+		},
+		{
+			name: "database down and dirty migration",
+			mockRepo: func(m *mocks.MockhealthRepo) {
+				m.EXPECT().Ping(gomock.Any()).Return(errors.New("connection refused"))
+				m.EXPECT().MigrationVersion(gomock.Any()).Return(uint(7), true, nil)
+				m.EXPECT().PoolUsage().Return(int32(2), int32(10))
+			},
+			wantStatus: domain.HealthFail,
+			wantFailed: []string{"database", "migrations"},
+		},
+		{
+			name: "schema behind and pool saturated",
+			mockRepo: func(m *mocks.MockhealthRepo) {
+				m.EXPECT().Ping(gomock.Any()).Return(nil)
+				m.EXPECT().MigrationVersion(gomock.Any()).Return(uint(6), false, nil)
+				m.EXPECT().PoolUsage().Return(int32(9), int32(10))
+			},
+			wantStatus: domain.HealthFail,
+			wantFailed: []string{"migrations", "pool"},
+		},
+		{
+			name: "shutting down",
+			mockRepo: func(m *mocks.MockhealthRepo) {
+				m.EXPECT().Ping(gomock.Any()).Return(nil)
+				m.EXPECT().MigrationVersion(gomock.Any()).Return(uint(7), false, nil)
+				m.EXPECT().PoolUsage().Return(int32(0), int32(10))
+			},
+			shutdown:   true,
+			wantStatus: domain.HealthFail,
+			wantFailed: []string{"shutdown"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := mocks.NewMockhealthRepo(ctrl)
+			tc.mockRepo(mockRepo)
+
+			svc := service.NewHealthService(mockRepo, policy)
+			if tc.shutdown {
+				svc.SetShuttingDown()
+			}
+
+			report := svc.Ready(context.Background())
+			require.Equal(t, tc.wantStatus, report.Status)
+			require.Len(t, report.Checks, 4)
+
+			var failed []string
+			for _, c := range report.Checks {
+				if c.Status == domain.HealthFail {
+					require.NotEmpty(t, c.Error)
+					failed = append(failed, c.Name)
+				}
+			}
+			require.Equal(t, tc.wantFailed, failed)
+		})
+	}
+}
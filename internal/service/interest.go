@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/Te8va/wallet/internal/domain"
+)
+
+const nanoPerUnit = 1_000_000_000
+
+//go:generate mockgen -source=interest.go -destination=mocks/interest_mock.gen.go -package=mocks
+type interestRepo interface {
+	SaveSavingsAccount(ctx context.Context, account domain.SavingsAccount) (domain.SavingsAccount, error)
+	GetSavingsAccount(ctx context.Context, walletID string) (domain.SavingsAccount, error)
+	ListSavingsAccounts(ctx context.Context) ([]domain.SavingsAccount, error)
+	BalanceAt(ctx context.Context, walletID string, at time.Time) (int64, error)
+	RecordAccrual(ctx context.Context, walletID string, date time.Time, balance, amountNano int64) (bool, error)
+	CreditInterest(ctx context.Context, walletID string, period time.Time) (int64, error)
+}
+
+type InterestService struct {
+	repo interestRepo
+}
+
+func NewInterestService(repo interestRepo) *InterestService {
+	return &InterestService{repo: repo}
+}
+
+func (s *InterestService) SaveSavingsAccount(ctx context.Context, account domain.SavingsAccount) (domain.SavingsAccount, error) {
+	return s.repo.SaveSavingsAccount(ctx, account)
+}
+
+func (s *InterestService) GetSavingsAccount(ctx context.Context, walletID string) (domain.SavingsAccount, error) {
+	return s.repo.GetSavingsAccount(ctx, walletID)
+}
+
+// RunForDate accrues one day of interest on the end-of-day balance of every
+// savings account and, on the last day of a month, credits the accumulated
+// interest. Re-running the same date is safe: accruals and credits that were
+// already recorded are skipped by the repository.
+func (s *InterestService) RunForDate(ctx context.Context, date time.Time) (domain.InterestRunResult, error) {
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	next := day.AddDate(0, 0, 1)
+	monthEnd := next.Day() == 1
+	period := time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	result := domain.InterestRunResult{Date: day}
+
+	accounts, err := s.repo.ListSavingsAccounts(ctx)
+	if err != nil {
+		return result, fmt.Errorf("service.RunForDate: %w", err)
+	}
+
+	var errs []error
+	for _, account := range accounts {
+		balance, err := s.repo.BalanceAt(ctx, account.WalletID, next)
+		if err != nil {
+			result.Failed++
+			errs = append(errs, fmt.Errorf("wallet %s: %w", account.WalletID, err))
+			continue
+		}
+
+		amount := DailyInterestNano(balance, account.AnnualRateBps, account.DayCount, day)
+
+		accrued, err := s.repo.RecordAccrual(ctx, account.WalletID, day, balance, amount)
+		if err != nil {
+			result.Failed++
+			errs = append(errs, fmt.Errorf("wallet %s: %w", account.WalletID, err))
+			continue
+		}
+		if accrued {
+			result.Accrued++
+		}
+
+		if !monthEnd {
+			continue
+		}
+
+		credited, err := s.repo.CreditInterest(ctx, account.WalletID, period)
+		if err != nil {
+			result.Failed++
+			errs = append(errs, fmt.Errorf("wallet %s: %w", account.WalletID, err))
+			continue
+		}
+		if credited > 0 {
+			result.Credited++
+		}
+	}
+
+	if len(errs) > 0 {
+		return result, fmt.Errorf("service.RunForDate: %w", errors.Join(errs...))
+	}
+
+	return result, nil
+}
+
+// DailyInterestNano returns the interest earned by balance over the given day
+// in billionths of the minor currency unit.
+func DailyInterestNano(balance, annualRateBps int64, dayCount domain.DayCountConvention, day time.Time) int64 {
+	if balance <= 0 || annualRateBps <= 0 {
+		return 0
+	}
+
+	var daysInYear int64
+	switch dayCount {
+	case domain.ACT360:
+		daysInYear = 360
+	case domain.ACTACT:
+		daysInYear = 365
+		if isLeapYear(day.Year()) {
+			daysInYear = 366
+		}
+	default:
+		daysInYear = 365
+	}
+
+	num := new(big.Int).Mul(big.NewInt(balance), big.NewInt(annualRateBps))
+	num.Mul(num, big.NewInt(nanoPerUnit))
+	den := big.NewInt(10_000 * daysInYear)
+
+	return num.Quo(num, den).Int64()
+}
+
+func isLeapYear(year int) bool {
+	return year%4 == 0 && (year%100 != 0 || year%400 == 0)
+}
@@ -0,0 +1,152 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Te8va/wallet/internal/domain"
+	"github.com/Te8va/wallet/internal/service"
+	"github.com/Te8va/wallet/internal/service/mocks"
+)
+
+func TestDailyInterestNano(t *testing.T) {
+	testCases := []struct {
+		name     string
+		balance  int64
+		rateBps  int64
+		dayCount domain.DayCountConvention
+		day      time.Time
+		expected int64
+	}{
+		{
+			name:     "act/365",
+			balance:  365_000,
+			rateBps:  1000,
+			dayCount: domain.ACT365,
+			day:      time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+			expected: 100 * 1_000_000_000,
+		},
+		{
+			name:     "act/360",
+			balance:  360_000,
+			rateBps:  1000,
+			dayCount: domain.ACT360,
+			day:      time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+			expected: 100 * 1_000_000_000,
+		},
+		{
+			name:     "act/act leap year",
+			balance:  366_000,
+			rateBps:  1000,
+			dayCount: domain.ACTACT,
+			day:      time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+			expected: 100 * 1_000_000_000,
+		},
+		{
+			name:     "fractional amount",
+			balance:  1000,
+			rateBps:  500,
+			dayCount: domain.ACT365,
+			day:      time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+			expected: 136_986_301,
+		},
+		{
+			name:     "zero balance",
+			balance:  0,
+			rateBps:  500,
+			dayCount: domain.ACT365,
+			day:      time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+			expected: 0,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, service.DailyInterestNano(tc.balance, tc.rateBps, tc.dayCount, tc.day))
+		})
+	}
+}
+
+func TestInterestService_RunForDate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockinterestRepo(ctrl)
+	svc := service.NewInterestService(mockRepo)
+
+	account := domain.SavingsAccount{WalletID: "123e4567-e89b-12d3-a456-426614174000", AnnualRateBps: 1000, DayCount: domain.ACT365}
+
+	testCases := []struct {
+		name           string
+		date           time.Time
+		mockRepo       func()
+		expectedResult domain.InterestRunResult
+		expectErr      bool
+	}{
+		{
+			name: "mid month accrual only",
+			date: time.Date(2025, 3, 15, 0, 0, 0, 0, time.UTC),
+			mockRepo: func() {
+				day := time.Date(2025, 3, 15, 0, 0, 0, 0, time.UTC)
+				mockRepo.EXPECT().ListSavingsAccounts(gomock.Any()).Return([]domain.SavingsAccount{account}, nil)
+				mockRepo.EXPECT().BalanceAt(gomock.Any(), account.WalletID, day.AddDate(0, 0, 1)).Return(int64(365_000), nil)
+				mockRepo.EXPECT().RecordAccrual(gomock.Any(), account.WalletID, day, int64(365_000), int64(100*1_000_000_000)).Return(true, nil)
+			},
+			expectedResult: domain.InterestRunResult{Date: time.Date(2025, 3, 15, 0, 0, 0, 0, time.UTC), Accrued: 1},
+		},
+		{
+			name: "month end credits interest",
+			date: time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC),
+			mockRepo: func() {
+				day := time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC)
+				mockRepo.EXPECT().ListSavingsAccounts(gomock.Any()).Return([]domain.SavingsAccount{account}, nil)
+				mockRepo.EXPECT().BalanceAt(gomock.Any(), account.WalletID, day.AddDate(0, 0, 1)).Return(int64(365_000), nil)
+				mockRepo.EXPECT().RecordAccrual(gomock.Any(), account.WalletID, day, int64(365_000), int64(100*1_000_000_000)).Return(true, nil)
+				mockRepo.EXPECT().CreditInterest(gomock.Any(), account.WalletID, time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)).Return(int64(3100), nil)
+			},
+			expectedResult: domain.InterestRunResult{Date: time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC), Accrued: 1, Credited: 1},
+		},
+		{
+			name: "re-run of an already processed date",
+			date: time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC),
+			mockRepo: func() {
+				day := time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC)
+				mockRepo.EXPECT().ListSavingsAccounts(gomock.Any()).Return([]domain.SavingsAccount{account}, nil)
+				mockRepo.EXPECT().BalanceAt(gomock.Any(), account.WalletID, day.AddDate(0, 0, 1)).Return(int64(365_000), nil)
+				mockRepo.EXPECT().RecordAccrual(gomock.Any(), account.WalletID, day, int64(365_000), int64(100*1_000_000_000)).Return(false, nil)
+				mockRepo.EXPECT().CreditInterest(gomock.Any(), account.WalletID, time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)).Return(int64(0), nil)
+			},
+			expectedResult: domain.InterestRunResult{Date: time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC)},
+		},
+		{
+			name: "accrual failure",
+			date: time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC),
+			mockRepo: func() {
+				mockRepo.EXPECT().ListSavingsAccounts(gomock.Any()).Return([]domain.SavingsAccount{account}, nil)
+				mockRepo.EXPECT().BalanceAt(gomock.Any(), account.WalletID, gomock.Any()).Return(int64(0), errors.New("database error"))
+			},
+			expectedResult: domain.InterestRunResult{Date: time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC), Failed: 1},
+			expectErr:      true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mockRepo()
+
+			result, err := svc.RunForDate(context.Background(), tc.date)
+
+			require.Equal(t, tc.expectedResult, result)
+			if tc.expectErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/Te8va/wallet/internal/domain"
+	appErrors "github.com/Te8va/wallet/internal/errors"
+	"github.com/Te8va/wallet/internal/logging"
+)
+
+//go:generate mockgen -source=loyalty.go -destination=mocks/loyalty_mock.gen.go -package=mocks
+type loyaltyRepo interface {
+	CreateRule(ctx context.Context, params domain.LoyaltyRuleParams) (domain.LoyaltyRule, error)
+	ListRules(ctx context.Context) ([]domain.LoyaltyRule, error)
+	ListActiveRules(ctx context.Context, at time.Time) ([]domain.LoyaltyRule, error)
+	EarnPoints(ctx context.Context, pe domain.PointsEarning) (domain.Transaction, error)
+	GetPoints(ctx context.Context, walletID string, now time.Time) (domain.PointsBalance, error)
+	RedeemPoints(ctx context.Context, pr domain.PointsRedemption, now time.Time) (domain.PointsRedemptionResult, error)
+	ListExpiredPointsWallets(ctx context.Context, now time.Time, limit int) ([]string, error)
+	ExpirePoints(ctx context.Context, pointsWalletID string, now time.Time) (int64, error)
+}
+
+// LoyaltyPolicy controls how points are redeemed and how long they last.
+// PointsPerUnit points are redeemed for one unit of money paid from the
+// funding wallet; without a funding wallet redemption is disabled.
+type LoyaltyPolicy struct {
+	FundingWalletID string
+	PointsPerUnit   int64
+	PointsTTL       time.Duration
+	BatchSize       int
+}
+
+type LoyaltyService struct {
+	repo   loyaltyRepo
+	policy LoyaltyPolicy
+	now    func() time.Time
+}
+
+func NewLoyaltyService(repo loyaltyRepo, policy LoyaltyPolicy) *LoyaltyService {
+	return &LoyaltyService{
+		repo:   repo,
+		policy: policy,
+		now:    func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// CreateRule starts a loyalty rule. Without a start time it starts now.
+func (s *LoyaltyService) CreateRule(ctx context.Context, params domain.LoyaltyRuleParams) (domain.LoyaltyRule, error) {
+	if params.StartsAt.IsZero() {
+		params.StartsAt = s.now()
+	}
+	params.StartsAt = params.StartsAt.UTC()
+
+	if params.EndsAt != nil {
+		endsAt := params.EndsAt.UTC()
+		if !endsAt.After(params.StartsAt) {
+			return domain.LoyaltyRule{}, appErrors.ErrInvalidLoyaltyRule
+		}
+		params.EndsAt = &endsAt
+	}
+
+	return s.repo.CreateRule(ctx, params)
+}
+
+func (s *LoyaltyService) ListRules(ctx context.Context) ([]domain.LoyaltyRule, error) {
+	return s.repo.ListRules(ctx)
+}
+
+// EarnPoints awards the points of every rule in effect when t was posted,
+// if t is a qualifying withdrawal. The points expire PointsTTL after t.
+// Each rule awards points at most once per transaction, so calling it
+// again for the same transaction only awards what was missed.
+func (s *LoyaltyService) EarnPoints(ctx context.Context, t domain.Transaction) error {
+	if t.OperationType != domain.WITHDRAW || t.WalletID == s.policy.FundingWalletID {
+		return nil
+	}
+	spent := -t.Amount
+
+	rules, err := s.repo.ListActiveRules(ctx, t.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("service.EarnPoints: %w", err)
+	}
+
+	var errs []error
+	for _, lr := range rules {
+		if spent < lr.MinAmount || (lr.Tag != "" && !slices.Contains(t.Tags, lr.Tag)) {
+			continue
+		}
+
+		points := bpsOf(spent, lr.RateBps)
+		if points == 0 {
+			continue
+		}
+
+		_, err := s.repo.EarnPoints(ctx, domain.PointsEarning{
+			RuleID:              lr.ID,
+			SourceTransactionID: t.ID,
+			WalletID:            t.WalletID,
+			Points:              points,
+			ExpiresAt:           t.CreatedAt.Add(s.policy.PointsTTL),
+		})
+		switch {
+		case errors.Is(err, appErrors.ErrDuplicateOperation):
+		case err != nil:
+			errs = append(errs, fmt.Errorf("loyalty rule %d: %w", lr.ID, err))
+		default:
+			logging.FromContext(ctx).Info("Loyalty points earned",
+				zap.Int64("loyalty_rule_id", lr.ID),
+				zap.Int64("source_transaction_id", t.ID),
+				zap.String("wallet_id", t.WalletID),
+				zap.Int64("points", points),
+			)
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("service.EarnPoints: %w", errors.Join(errs...))
+	}
+
+	return nil
+}
+
+func (s *LoyaltyService) GetPoints(ctx context.Context, walletID string) (domain.PointsBalance, error) {
+	return s.repo.GetPoints(ctx, walletID, s.now())
+}
+
+// RedeemPoints exchanges points of the wallet for money at the configured
+// rate. Only whole units are paid out, so the points must be a multiple of
+// PointsPerUnit.
+func (s *LoyaltyService) RedeemPoints(ctx context.Context, walletID string, req domain.PointsRedeemRequest) (domain.PointsRedemptionResult, error) {
+	if s.policy.FundingWalletID == "" || s.policy.PointsPerUnit <= 0 {
+		return domain.PointsRedemptionResult{}, appErrors.ErrRedemptionDisabled
+	}
+
+	if req.Points <= 0 || req.Points%s.policy.PointsPerUnit != 0 {
+		return domain.PointsRedemptionResult{}, appErrors.ErrInvalidPointsAmount
+	}
+
+	return s.repo.RedeemPoints(ctx, domain.PointsRedemption{
+		WalletID:        walletID,
+		FundingWalletID: s.policy.FundingWalletID,
+		Points:          req.Points,
+		Amount:          req.Points / s.policy.PointsPerUnit,
+		IdempotencyKey:  req.IdempotencyKey,
+	}, s.now())
+}
+
+// RunExpired debits the points left in expired lots. It returns the number
+// of points wallets processed.
+func (s *LoyaltyService) RunExpired(ctx context.Context) (int, error) {
+	now := s.now()
+
+	ids, err := s.repo.ListExpiredPointsWallets(ctx, now, s.policy.BatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("service.RunExpired: %w", err)
+	}
+
+	var errs []error
+	for _, id := range ids {
+		if _, err := s.repo.ExpirePoints(ctx, id, now); err != nil {
+			errs = append(errs, fmt.Errorf("points wallet %s: %w", id, err))
+		}
+	}
+
+	if len(errs) > 0 {
+		return len(ids), fmt.Errorf("service.RunExpired: %w", errors.Join(errs...))
+	}
+
+	return len(ids), nil
+}
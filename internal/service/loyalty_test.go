@@ -0,0 +1,205 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Te8va/wallet/internal/domain"
+	appErrors "github.com/Te8va/wallet/internal/errors"
+	"github.com/Te8va/wallet/internal/service"
+	"github.com/Te8va/wallet/internal/service/mocks"
+)
+
+func TestLoyaltyService_CreateRule(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockloyaltyRepo(ctrl)
+	svc := service.NewLoyaltyService(mockRepo, service.LoyaltyPolicy{})
+
+	startsAt := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+	params := domain.LoyaltyRuleParams{Name: "groceries", RateBps: 10000, Tag: "groceries", StartsAt: startsAt}
+
+	mockRepo.EXPECT().CreateRule(gomock.Any(), params).Return(domain.LoyaltyRule{ID: 1}, nil)
+	_, err := svc.CreateRule(context.Background(), params)
+	require.NoError(t, err)
+
+	params.EndsAt = &startsAt
+	_, err = svc.CreateRule(context.Background(), params)
+	require.ErrorIs(t, err, appErrors.ErrInvalidLoyaltyRule)
+}
+
+func TestLoyaltyService_EarnPoints(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockloyaltyRepo(ctrl)
+	svc := service.NewLoyaltyService(mockRepo, service.LoyaltyPolicy{
+		FundingWalletID: "loyalty",
+		PointsTTL:       24 * time.Hour,
+	})
+
+	postedAt := time.Date(2025, 4, 10, 12, 0, 0, 0, time.UTC)
+	withdrawal := domain.Transaction{
+		ID: 42, WalletID: "wallet", OperationType: domain.WITHDRAW, Amount: -1999, Tags: []string{"groceries"},
+		CreatedAt: postedAt,
+	}
+
+	testCases := []struct {
+		name        string
+		t           domain.Transaction
+		mockRepo    func()
+		expectedErr error
+	}{
+		{
+			name: "rate rounded down and every matching rule applied",
+			t:    withdrawal,
+			mockRepo: func() {
+				mockRepo.EXPECT().ListActiveRules(gomock.Any(), postedAt).Return([]domain.LoyaltyRule{
+					{ID: 1, RateBps: 10000},
+					{ID: 2, RateBps: 2500, Tag: "groceries"},
+					{ID: 3, RateBps: 5000, Tag: "travel"},
+					{ID: 4, RateBps: 5000, MinAmount: 2000},
+				}, nil)
+				mockRepo.EXPECT().EarnPoints(gomock.Any(), domain.PointsEarning{
+					RuleID: 1, SourceTransactionID: 42, WalletID: "wallet", Points: 1999, ExpiresAt: postedAt.Add(24 * time.Hour),
+				}).Return(domain.Transaction{ID: 50}, nil)
+				mockRepo.EXPECT().EarnPoints(gomock.Any(), domain.PointsEarning{
+					RuleID: 2, SourceTransactionID: 42, WalletID: "wallet", Points: 499, ExpiresAt: postedAt.Add(24 * time.Hour),
+				}).Return(domain.Transaction{ID: 51}, nil)
+			},
+		},
+		{
+			name: "already earned",
+			t:    withdrawal,
+			mockRepo: func() {
+				mockRepo.EXPECT().ListActiveRules(gomock.Any(), postedAt).Return([]domain.LoyaltyRule{{ID: 1, RateBps: 10000}}, nil)
+				mockRepo.EXPECT().EarnPoints(gomock.Any(), gomock.Any()).Return(domain.Transaction{}, appErrors.ErrDuplicateOperation)
+			},
+		},
+		{
+			name: "key held by another operation",
+			t:    withdrawal,
+			mockRepo: func() {
+				mockRepo.EXPECT().ListActiveRules(gomock.Any(), postedAt).Return([]domain.LoyaltyRule{{ID: 1, RateBps: 10000}}, nil)
+				mockRepo.EXPECT().EarnPoints(gomock.Any(), gomock.Any()).Return(domain.Transaction{}, appErrors.ErrIdempotencyKeyReused)
+			},
+			expectedErr: appErrors.ErrIdempotencyKeyReused,
+		},
+		{
+			name:     "from the funding wallet",
+			t:        domain.Transaction{ID: 43, WalletID: "loyalty", OperationType: domain.WITHDRAW, Amount: -1000},
+			mockRepo: func() {},
+		},
+		{
+			name:     "deposit",
+			t:        domain.Transaction{ID: 44, WalletID: "wallet", OperationType: domain.DEPOSIT, Amount: 1000},
+			mockRepo: func() {},
+		},
+		{
+			name: "repository error",
+			t:    withdrawal,
+			mockRepo: func() {
+				mockRepo.EXPECT().ListActiveRules(gomock.Any(), postedAt).Return([]domain.LoyaltyRule{{ID: 1, RateBps: 10000}}, nil)
+				mockRepo.EXPECT().EarnPoints(gomock.Any(), gomock.Any()).Return(domain.Transaction{}, errors.New("connection reset"))
+			},
+			expectedErr: errors.New("connection reset"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mockRepo()
+
+			err := svc.EarnPoints(context.Background(), tc.t)
+
+			if tc.expectedErr != nil {
+				require.ErrorContains(t, err, tc.expectedErr.Error())
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestLoyaltyService_RedeemPoints(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockloyaltyRepo(ctrl)
+	policy := service.LoyaltyPolicy{FundingWalletID: "loyalty", PointsPerUnit: 100}
+
+	testCases := []struct {
+		name        string
+		policy      service.LoyaltyPolicy
+		req         domain.PointsRedeemRequest
+		mockRepo    func()
+		expectedErr error
+	}{
+		{
+			name:   "successful",
+			policy: policy,
+			req:    domain.PointsRedeemRequest{Points: 2500, IdempotencyKey: "redeem-1"},
+			mockRepo: func() {
+				mockRepo.EXPECT().RedeemPoints(gomock.Any(), domain.PointsRedemption{
+					WalletID: "wallet", FundingWalletID: "loyalty", Points: 2500, Amount: 25, IdempotencyKey: "redeem-1",
+				}, gomock.Any()).Return(domain.PointsRedemptionResult{Points: 2500, Amount: 25}, nil)
+			},
+		},
+		{
+			name:        "not a multiple of the rate",
+			policy:      policy,
+			req:         domain.PointsRedeemRequest{Points: 2550},
+			mockRepo:    func() {},
+			expectedErr: appErrors.ErrInvalidPointsAmount,
+		},
+		{
+			name:   "not enough points",
+			policy: policy,
+			req:    domain.PointsRedeemRequest{Points: 100},
+			mockRepo: func() {
+				mockRepo.EXPECT().RedeemPoints(gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(domain.PointsRedemptionResult{}, appErrors.ErrInsufficientFunds)
+			},
+			expectedErr: appErrors.ErrInsufficientFunds,
+		},
+		{
+			name:        "no funding wallet",
+			policy:      service.LoyaltyPolicy{PointsPerUnit: 100},
+			req:         domain.PointsRedeemRequest{Points: 100},
+			mockRepo:    func() {},
+			expectedErr: appErrors.ErrRedemptionDisabled,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			svc := service.NewLoyaltyService(mockRepo, tc.policy)
+			tc.mockRepo()
+
+			_, err := svc.RedeemPoints(context.Background(), "wallet", tc.req)
+			require.ErrorIs(t, err, tc.expectedErr)
+		})
+	}
+}
+
+func TestLoyaltyService_RunExpired(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockloyaltyRepo(ctrl)
+	svc := service.NewLoyaltyService(mockRepo, service.LoyaltyPolicy{BatchSize: 10})
+
+	mockRepo.EXPECT().ListExpiredPointsWallets(gomock.Any(), gomock.Any(), 10).Return([]string{"points-1", "points-2"}, nil)
+	mockRepo.EXPECT().ExpirePoints(gomock.Any(), "points-1", gomock.Any()).Return(int64(300), nil)
+	mockRepo.EXPECT().ExpirePoints(gomock.Any(), "points-2", gomock.Any()).Return(int64(0), errors.New("connection reset"))
+
+	n, err := svc.RunExpired(context.Background())
+	require.ErrorContains(t, err, "points wallet points-2: connection reset")
+	require.Equal(t, 2, n)
+}
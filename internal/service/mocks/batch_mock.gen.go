@@ -0,0 +1,178 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: batch.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+
+	domain "github.com/Te8va/wallet/internal/domain"
+)
+
+// MockbatchRepo is a mock of batchRepo interface.
+type MockbatchRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockbatchRepoMockRecorder
+}
+
+// MockbatchRepoMockRecorder is the mock recorder for MockbatchRepo.
+type MockbatchRepoMockRecorder struct {
+	mock *MockbatchRepo
+}
+
+// NewMockbatchRepo creates a new mock instance.
+func NewMockbatchRepo(ctrl *gomock.Controller) *MockbatchRepo {
+	mock := &MockbatchRepo{ctrl: ctrl}
+	mock.recorder = &MockbatchRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockbatchRepo) EXPECT() *MockbatchRepoMockRecorder {
+	return m.recorder
+}
+
+// ApplyBatch mocks base method.
+func (m *MockbatchRepo) ApplyBatch(ctx context.Context, batchID int64) ([]domain.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApplyBatch", ctx, batchID)
+	ret0, _ := ret[0].([]domain.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ApplyBatch indicates an expected call of ApplyBatch.
+func (mr *MockbatchRepoMockRecorder) ApplyBatch(ctx, batchID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplyBatch", reflect.TypeOf((*MockbatchRepo)(nil).ApplyBatch), ctx, batchID)
+}
+
+// ApplyBatchItem mocks base method.
+func (m *MockbatchRepo) ApplyBatchItem(ctx context.Context, batchID int64, line int) (domain.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApplyBatchItem", ctx, batchID, line)
+	ret0, _ := ret[0].(domain.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ApplyBatchItem indicates an expected call of ApplyBatchItem.
+func (mr *MockbatchRepoMockRecorder) ApplyBatchItem(ctx, batchID, line interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplyBatchItem", reflect.TypeOf((*MockbatchRepo)(nil).ApplyBatchItem), ctx, batchID, line)
+}
+
+// ClaimBatch mocks base method.
+func (m *MockbatchRepo) ClaimBatch(ctx context.Context, now, leaseUntil time.Time) (domain.Batch, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimBatch", ctx, now, leaseUntil)
+	ret0, _ := ret[0].(domain.Batch)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ClaimBatch indicates an expected call of ClaimBatch.
+func (mr *MockbatchRepoMockRecorder) ClaimBatch(ctx, now, leaseUntil interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimBatch", reflect.TypeOf((*MockbatchRepo)(nil).ClaimBatch), ctx, now, leaseUntil)
+}
+
+// CreateBatch mocks base method.
+func (m *MockbatchRepo) CreateBatch(ctx context.Context, mode domain.BatchMode, items []domain.BatchItem) (domain.Batch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBatch", ctx, mode, items)
+	ret0, _ := ret[0].(domain.Batch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateBatch indicates an expected call of CreateBatch.
+func (mr *MockbatchRepoMockRecorder) CreateBatch(ctx, mode, items interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBatch", reflect.TypeOf((*MockbatchRepo)(nil).CreateBatch), ctx, mode, items)
+}
+
+// FinishBatch mocks base method.
+func (m *MockbatchRepo) FinishBatch(ctx context.Context, batchID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FinishBatch", ctx, batchID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FinishBatch indicates an expected call of FinishBatch.
+func (mr *MockbatchRepoMockRecorder) FinishBatch(ctx, batchID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinishBatch", reflect.TypeOf((*MockbatchRepo)(nil).FinishBatch), ctx, batchID)
+}
+
+// GetBatch mocks base method.
+func (m *MockbatchRepo) GetBatch(ctx context.Context, id int64) (domain.Batch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBatch", ctx, id)
+	ret0, _ := ret[0].(domain.Batch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBatch indicates an expected call of GetBatch.
+func (mr *MockbatchRepoMockRecorder) GetBatch(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBatch", reflect.TypeOf((*MockbatchRepo)(nil).GetBatch), ctx, id)
+}
+
+// ListPendingLines mocks base method.
+func (m *MockbatchRepo) ListPendingLines(ctx context.Context, batchID int64) ([]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPendingLines", ctx, batchID)
+	ret0, _ := ret[0].([]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPendingLines indicates an expected call of ListPendingLines.
+func (mr *MockbatchRepoMockRecorder) ListPendingLines(ctx, batchID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPendingLines", reflect.TypeOf((*MockbatchRepo)(nil).ListPendingLines), ctx, batchID)
+}
+
+// MockpostRecorder is a mock of postRecorder interface.
+type MockpostRecorder struct {
+	ctrl     *gomock.Controller
+	recorder *MockpostRecorderMockRecorder
+}
+
+// MockpostRecorderMockRecorder is the mock recorder for MockpostRecorder.
+type MockpostRecorderMockRecorder struct {
+	mock *MockpostRecorder
+}
+
+// NewMockpostRecorder creates a new mock instance.
+func NewMockpostRecorder(ctrl *gomock.Controller) *MockpostRecorder {
+	mock := &MockpostRecorder{ctrl: ctrl}
+	mock.recorder = &MockpostRecorderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockpostRecorder) EXPECT() *MockpostRecorderMockRecorder {
+	return m.recorder
+}
+
+// RecordPosted mocks base method.
+func (m *MockpostRecorder) RecordPosted(ctx context.Context, t domain.Transaction) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RecordPosted", ctx, t)
+}
+
+// RecordPosted indicates an expected call of RecordPosted.
+func (mr *MockpostRecorderMockRecorder) RecordPosted(ctx, t interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordPosted", reflect.TypeOf((*MockpostRecorder)(nil).RecordPosted), ctx, t)
+}
@@ -0,0 +1,113 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: campaign.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+
+	domain "github.com/Te8va/wallet/internal/domain"
+)
+
+// MockcampaignRepo is a mock of campaignRepo interface.
+type MockcampaignRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockcampaignRepoMockRecorder
+}
+
+// MockcampaignRepoMockRecorder is the mock recorder for MockcampaignRepo.
+type MockcampaignRepoMockRecorder struct {
+	mock *MockcampaignRepo
+}
+
+// NewMockcampaignRepo creates a new mock instance.
+func NewMockcampaignRepo(ctrl *gomock.Controller) *MockcampaignRepo {
+	mock := &MockcampaignRepo{ctrl: ctrl}
+	mock.recorder = &MockcampaignRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockcampaignRepo) EXPECT() *MockcampaignRepoMockRecorder {
+	return m.recorder
+}
+
+// CreateCampaign mocks base method.
+func (m *MockcampaignRepo) CreateCampaign(ctx context.Context, params domain.CampaignParams) (domain.Campaign, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCampaign", ctx, params)
+	ret0, _ := ret[0].(domain.Campaign)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCampaign indicates an expected call of CreateCampaign.
+func (mr *MockcampaignRepoMockRecorder) CreateCampaign(ctx, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCampaign", reflect.TypeOf((*MockcampaignRepo)(nil).CreateCampaign), ctx, params)
+}
+
+// CreditCashback mocks base method.
+func (m *MockcampaignRepo) CreditCashback(ctx context.Context, cr domain.CashbackCredit) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreditCashback", ctx, cr)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreditCashback indicates an expected call of CreditCashback.
+func (mr *MockcampaignRepoMockRecorder) CreditCashback(ctx, cr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreditCashback", reflect.TypeOf((*MockcampaignRepo)(nil).CreditCashback), ctx, cr)
+}
+
+// GetCampaign mocks base method.
+func (m *MockcampaignRepo) GetCampaign(ctx context.Context, id int64) (domain.Campaign, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCampaign", ctx, id)
+	ret0, _ := ret[0].(domain.Campaign)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCampaign indicates an expected call of GetCampaign.
+func (mr *MockcampaignRepoMockRecorder) GetCampaign(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCampaign", reflect.TypeOf((*MockcampaignRepo)(nil).GetCampaign), ctx, id)
+}
+
+// ListActiveCampaigns mocks base method.
+func (m *MockcampaignRepo) ListActiveCampaigns(ctx context.Context, at time.Time) ([]domain.Campaign, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListActiveCampaigns", ctx, at)
+	ret0, _ := ret[0].([]domain.Campaign)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListActiveCampaigns indicates an expected call of ListActiveCampaigns.
+func (mr *MockcampaignRepoMockRecorder) ListActiveCampaigns(ctx, at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListActiveCampaigns", reflect.TypeOf((*MockcampaignRepo)(nil).ListActiveCampaigns), ctx, at)
+}
+
+// ListCampaigns mocks base method.
+func (m *MockcampaignRepo) ListCampaigns(ctx context.Context) ([]domain.Campaign, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCampaigns", ctx)
+	ret0, _ := ret[0].([]domain.Campaign)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCampaigns indicates an expected call of ListCampaigns.
+func (mr *MockcampaignRepoMockRecorder) ListCampaigns(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCampaigns", reflect.TypeOf((*MockcampaignRepo)(nil).ListCampaigns), ctx)
+}
@@ -0,0 +1,52 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: check.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+
+	domain "github.com/Te8va/wallet/internal/domain"
+)
+
+// MockcheckRepo is a mock of checkRepo interface.
+type MockcheckRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockcheckRepoMockRecorder
+}
+
+// MockcheckRepoMockRecorder is the mock recorder for MockcheckRepo.
+type MockcheckRepoMockRecorder struct {
+	mock *MockcheckRepo
+}
+
+// NewMockcheckRepo creates a new mock instance.
+func NewMockcheckRepo(ctrl *gomock.Controller) *MockcheckRepo {
+	mock := &MockcheckRepo{ctrl: ctrl}
+	mock.recorder = &MockcheckRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockcheckRepo) EXPECT() *MockcheckRepoMockRecorder {
+	return m.recorder
+}
+
+// CheckLedger mocks base method.
+func (m *MockcheckRepo) CheckLedger(ctx context.Context) (domain.LedgerReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckLedger", ctx)
+	ret0, _ := ret[0].(domain.LedgerReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckLedger indicates an expected call of CheckLedger.
+func (mr *MockcheckRepoMockRecorder) CheckLedger(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckLedger", reflect.TypeOf((*MockcheckRepo)(nil).CheckLedger), ctx)
+}
@@ -0,0 +1,98 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: escrow.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+
+	domain "github.com/Te8va/wallet/internal/domain"
+)
+
+// MockescrowRepo is a mock of escrowRepo interface.
+type MockescrowRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockescrowRepoMockRecorder
+}
+
+// MockescrowRepoMockRecorder is the mock recorder for MockescrowRepo.
+type MockescrowRepoMockRecorder struct {
+	mock *MockescrowRepo
+}
+
+// NewMockescrowRepo creates a new mock instance.
+func NewMockescrowRepo(ctrl *gomock.Controller) *MockescrowRepo {
+	mock := &MockescrowRepo{ctrl: ctrl}
+	mock.recorder = &MockescrowRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockescrowRepo) EXPECT() *MockescrowRepoMockRecorder {
+	return m.recorder
+}
+
+// CreateEscrow mocks base method.
+func (m *MockescrowRepo) CreateEscrow(ctx context.Context, req domain.EscrowRequest) (domain.Escrow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateEscrow", ctx, req)
+	ret0, _ := ret[0].(domain.Escrow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateEscrow indicates an expected call of CreateEscrow.
+func (mr *MockescrowRepoMockRecorder) CreateEscrow(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEscrow", reflect.TypeOf((*MockescrowRepo)(nil).CreateEscrow), ctx, req)
+}
+
+// GetEscrow mocks base method.
+func (m *MockescrowRepo) GetEscrow(ctx context.Context, dealID string) (domain.Escrow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEscrow", ctx, dealID)
+	ret0, _ := ret[0].(domain.Escrow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEscrow indicates an expected call of GetEscrow.
+func (mr *MockescrowRepoMockRecorder) GetEscrow(ctx, dealID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEscrow", reflect.TypeOf((*MockescrowRepo)(nil).GetEscrow), ctx, dealID)
+}
+
+// ListExpiredEscrows mocks base method.
+func (m *MockescrowRepo) ListExpiredEscrows(ctx context.Context, now time.Time, limit int) ([]domain.Escrow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListExpiredEscrows", ctx, now, limit)
+	ret0, _ := ret[0].([]domain.Escrow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListExpiredEscrows indicates an expected call of ListExpiredEscrows.
+func (mr *MockescrowRepoMockRecorder) ListExpiredEscrows(ctx, now, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListExpiredEscrows", reflect.TypeOf((*MockescrowRepo)(nil).ListExpiredEscrows), ctx, now, limit)
+}
+
+// TransitionEscrow mocks base method.
+func (m *MockescrowRepo) TransitionEscrow(ctx context.Context, t domain.EscrowTransition) (domain.Escrow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TransitionEscrow", ctx, t)
+	ret0, _ := ret[0].(domain.Escrow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TransitionEscrow indicates an expected call of TransitionEscrow.
+func (mr *MockescrowRepoMockRecorder) TransitionEscrow(ctx, t interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransitionEscrow", reflect.TypeOf((*MockescrowRepo)(nil).TransitionEscrow), ctx, t)
+}
@@ -0,0 +1,80 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: health.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockhealthRepo is a mock of healthRepo interface.
+type MockhealthRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockhealthRepoMockRecorder
+}
+
+// MockhealthRepoMockRecorder is the mock recorder for MockhealthRepo.
+type MockhealthRepoMockRecorder struct {
+	mock *MockhealthRepo
+}
+
+// NewMockhealthRepo creates a new mock instance.
+func NewMockhealthRepo(ctrl *gomock.Controller) *MockhealthRepo {
+	mock := &MockhealthRepo{ctrl: ctrl}
+	mock.recorder = &MockhealthRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockhealthRepo) EXPECT() *MockhealthRepoMockRecorder {
+	return m.recorder
+}
+
+// MigrationVersion mocks base method.
+func (m *MockhealthRepo) MigrationVersion(ctx context.Context) (uint, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MigrationVersion", ctx)
+	ret0, _ := ret[0].(uint)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// MigrationVersion indicates an expected call of MigrationVersion.
+func (mr *MockhealthRepoMockRecorder) MigrationVersion(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MigrationVersion", reflect.TypeOf((*MockhealthRepo)(nil).MigrationVersion), ctx)
+}
+
+// Ping mocks base method.
+func (m *MockhealthRepo) Ping(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Ping", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Ping indicates an expected call of Ping.
+func (mr *MockhealthRepoMockRecorder) Ping(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ping", reflect.TypeOf((*MockhealthRepo)(nil).Ping), ctx)
+}
+
+// PoolUsage mocks base method.
+func (m *MockhealthRepo) PoolUsage() (int32, int32) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PoolUsage")
+	ret0, _ := ret[0].(int32)
+	ret1, _ := ret[1].(int32)
+	return ret0, ret1
+}
+
+// PoolUsage indicates an expected call of PoolUsage.
+func (mr *MockhealthRepoMockRecorder) PoolUsage() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PoolUsage", reflect.TypeOf((*MockhealthRepo)(nil).PoolUsage))
+}
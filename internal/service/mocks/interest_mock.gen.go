@@ -0,0 +1,128 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: interest.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+
+	domain "github.com/Te8va/wallet/internal/domain"
+)
+
+// MockinterestRepo is a mock of interestRepo interface.
+type MockinterestRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockinterestRepoMockRecorder
+}
+
+// MockinterestRepoMockRecorder is the mock recorder for MockinterestRepo.
+type MockinterestRepoMockRecorder struct {
+	mock *MockinterestRepo
+}
+
+// NewMockinterestRepo creates a new mock instance.
+func NewMockinterestRepo(ctrl *gomock.Controller) *MockinterestRepo {
+	mock := &MockinterestRepo{ctrl: ctrl}
+	mock.recorder = &MockinterestRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockinterestRepo) EXPECT() *MockinterestRepoMockRecorder {
+	return m.recorder
+}
+
+// BalanceAt mocks base method.
+func (m *MockinterestRepo) BalanceAt(ctx context.Context, walletID string, at time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BalanceAt", ctx, walletID, at)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BalanceAt indicates an expected call of BalanceAt.
+func (mr *MockinterestRepoMockRecorder) BalanceAt(ctx, walletID, at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BalanceAt", reflect.TypeOf((*MockinterestRepo)(nil).BalanceAt), ctx, walletID, at)
+}
+
+// CreditInterest mocks base method.
+func (m *MockinterestRepo) CreditInterest(ctx context.Context, walletID string, period time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreditInterest", ctx, walletID, period)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreditInterest indicates an expected call of CreditInterest.
+func (mr *MockinterestRepoMockRecorder) CreditInterest(ctx, walletID, period interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreditInterest", reflect.TypeOf((*MockinterestRepo)(nil).CreditInterest), ctx, walletID, period)
+}
+
+// GetSavingsAccount mocks base method.
+func (m *MockinterestRepo) GetSavingsAccount(ctx context.Context, walletID string) (domain.SavingsAccount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSavingsAccount", ctx, walletID)
+	ret0, _ := ret[0].(domain.SavingsAccount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSavingsAccount indicates an expected call of GetSavingsAccount.
+func (mr *MockinterestRepoMockRecorder) GetSavingsAccount(ctx, walletID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSavingsAccount", reflect.TypeOf((*MockinterestRepo)(nil).GetSavingsAccount), ctx, walletID)
+}
+
+// ListSavingsAccounts mocks base method.
+func (m *MockinterestRepo) ListSavingsAccounts(ctx context.Context) ([]domain.SavingsAccount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSavingsAccounts", ctx)
+	ret0, _ := ret[0].([]domain.SavingsAccount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSavingsAccounts indicates an expected call of ListSavingsAccounts.
+func (mr *MockinterestRepoMockRecorder) ListSavingsAccounts(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSavingsAccounts", reflect.TypeOf((*MockinterestRepo)(nil).ListSavingsAccounts), ctx)
+}
+
+// RecordAccrual mocks base method.
+func (m *MockinterestRepo) RecordAccrual(ctx context.Context, walletID string, date time.Time, balance, amountNano int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordAccrual", ctx, walletID, date, balance, amountNano)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecordAccrual indicates an expected call of RecordAccrual.
+func (mr *MockinterestRepoMockRecorder) RecordAccrual(ctx, walletID, date, balance, amountNano interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordAccrual", reflect.TypeOf((*MockinterestRepo)(nil).RecordAccrual), ctx, walletID, date, balance, amountNano)
+}
+
+// SaveSavingsAccount mocks base method.
+func (m *MockinterestRepo) SaveSavingsAccount(ctx context.Context, account domain.SavingsAccount) (domain.SavingsAccount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveSavingsAccount", ctx, account)
+	ret0, _ := ret[0].(domain.SavingsAccount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SaveSavingsAccount indicates an expected call of SaveSavingsAccount.
+func (mr *MockinterestRepoMockRecorder) SaveSavingsAccount(ctx, account interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveSavingsAccount", reflect.TypeOf((*MockinterestRepo)(nil).SaveSavingsAccount), ctx, account)
+}
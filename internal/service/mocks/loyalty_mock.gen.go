@@ -0,0 +1,158 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: loyalty.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+
+	domain "github.com/Te8va/wallet/internal/domain"
+)
+
+// MockloyaltyRepo is a mock of loyaltyRepo interface.
+type MockloyaltyRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockloyaltyRepoMockRecorder
+}
+
+// MockloyaltyRepoMockRecorder is the mock recorder for MockloyaltyRepo.
+type MockloyaltyRepoMockRecorder struct {
+	mock *MockloyaltyRepo
+}
+
+// NewMockloyaltyRepo creates a new mock instance.
+func NewMockloyaltyRepo(ctrl *gomock.Controller) *MockloyaltyRepo {
+	mock := &MockloyaltyRepo{ctrl: ctrl}
+	mock.recorder = &MockloyaltyRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockloyaltyRepo) EXPECT() *MockloyaltyRepoMockRecorder {
+	return m.recorder
+}
+
+// CreateRule mocks base method.
+func (m *MockloyaltyRepo) CreateRule(ctx context.Context, params domain.LoyaltyRuleParams) (domain.LoyaltyRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRule", ctx, params)
+	ret0, _ := ret[0].(domain.LoyaltyRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateRule indicates an expected call of CreateRule.
+func (mr *MockloyaltyRepoMockRecorder) CreateRule(ctx, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRule", reflect.TypeOf((*MockloyaltyRepo)(nil).CreateRule), ctx, params)
+}
+
+// EarnPoints mocks base method.
+func (m *MockloyaltyRepo) EarnPoints(ctx context.Context, pe domain.PointsEarning) (domain.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EarnPoints", ctx, pe)
+	ret0, _ := ret[0].(domain.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EarnPoints indicates an expected call of EarnPoints.
+func (mr *MockloyaltyRepoMockRecorder) EarnPoints(ctx, pe interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EarnPoints", reflect.TypeOf((*MockloyaltyRepo)(nil).EarnPoints), ctx, pe)
+}
+
+// ExpirePoints mocks base method.
+func (m *MockloyaltyRepo) ExpirePoints(ctx context.Context, pointsWalletID string, now time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpirePoints", ctx, pointsWalletID, now)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExpirePoints indicates an expected call of ExpirePoints.
+func (mr *MockloyaltyRepoMockRecorder) ExpirePoints(ctx, pointsWalletID, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpirePoints", reflect.TypeOf((*MockloyaltyRepo)(nil).ExpirePoints), ctx, pointsWalletID, now)
+}
+
+// GetPoints mocks base method.
+func (m *MockloyaltyRepo) GetPoints(ctx context.Context, walletID string, now time.Time) (domain.PointsBalance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPoints", ctx, walletID, now)
+	ret0, _ := ret[0].(domain.PointsBalance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPoints indicates an expected call of GetPoints.
+func (mr *MockloyaltyRepoMockRecorder) GetPoints(ctx, walletID, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPoints", reflect.TypeOf((*MockloyaltyRepo)(nil).GetPoints), ctx, walletID, now)
+}
+
+// ListActiveRules mocks base method.
+func (m *MockloyaltyRepo) ListActiveRules(ctx context.Context, at time.Time) ([]domain.LoyaltyRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListActiveRules", ctx, at)
+	ret0, _ := ret[0].([]domain.LoyaltyRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListActiveRules indicates an expected call of ListActiveRules.
+func (mr *MockloyaltyRepoMockRecorder) ListActiveRules(ctx, at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListActiveRules", reflect.TypeOf((*MockloyaltyRepo)(nil).ListActiveRules), ctx, at)
+}
+
+// ListExpiredPointsWallets mocks base method.
+func (m *MockloyaltyRepo) ListExpiredPointsWallets(ctx context.Context, now time.Time, limit int) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListExpiredPointsWallets", ctx, now, limit)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListExpiredPointsWallets indicates an expected call of ListExpiredPointsWallets.
+func (mr *MockloyaltyRepoMockRecorder) ListExpiredPointsWallets(ctx, now, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListExpiredPointsWallets", reflect.TypeOf((*MockloyaltyRepo)(nil).ListExpiredPointsWallets), ctx, now, limit)
+}
+
+// ListRules mocks base method.
+func (m *MockloyaltyRepo) ListRules(ctx context.Context) ([]domain.LoyaltyRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRules", ctx)
+	ret0, _ := ret[0].([]domain.LoyaltyRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRules indicates an expected call of ListRules.
+func (mr *MockloyaltyRepoMockRecorder) ListRules(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRules", reflect.TypeOf((*MockloyaltyRepo)(nil).ListRules), ctx)
+}
+
+// RedeemPoints mocks base method.
+func (m *MockloyaltyRepo) RedeemPoints(ctx context.Context, pr domain.PointsRedemption, now time.Time) (domain.PointsRedemptionResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RedeemPoints", ctx, pr, now)
+	ret0, _ := ret[0].(domain.PointsRedemptionResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RedeemPoints indicates an expected call of RedeemPoints.
+func (mr *MockloyaltyRepoMockRecorder) RedeemPoints(ctx, pr, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RedeemPoints", reflect.TypeOf((*MockloyaltyRepo)(nil).RedeemPoints), ctx, pr, now)
+}
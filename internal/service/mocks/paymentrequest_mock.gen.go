@@ -0,0 +1,113 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: paymentrequest.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+
+	domain "github.com/Te8va/wallet/internal/domain"
+)
+
+// MockpaymentRequestRepo is a mock of paymentRequestRepo interface.
+type MockpaymentRequestRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockpaymentRequestRepoMockRecorder
+}
+
+// MockpaymentRequestRepoMockRecorder is the mock recorder for MockpaymentRequestRepo.
+type MockpaymentRequestRepoMockRecorder struct {
+	mock *MockpaymentRequestRepo
+}
+
+// NewMockpaymentRequestRepo creates a new mock instance.
+func NewMockpaymentRequestRepo(ctrl *gomock.Controller) *MockpaymentRequestRepo {
+	mock := &MockpaymentRequestRepo{ctrl: ctrl}
+	mock.recorder = &MockpaymentRequestRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockpaymentRequestRepo) EXPECT() *MockpaymentRequestRepoMockRecorder {
+	return m.recorder
+}
+
+// CreatePaymentRequest mocks base method.
+func (m *MockpaymentRequestRepo) CreatePaymentRequest(ctx context.Context, params domain.PaymentRequestParams) (domain.PaymentRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePaymentRequest", ctx, params)
+	ret0, _ := ret[0].(domain.PaymentRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePaymentRequest indicates an expected call of CreatePaymentRequest.
+func (mr *MockpaymentRequestRepoMockRecorder) CreatePaymentRequest(ctx, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePaymentRequest", reflect.TypeOf((*MockpaymentRequestRepo)(nil).CreatePaymentRequest), ctx, params)
+}
+
+// ExpirePaymentRequests mocks base method.
+func (m *MockpaymentRequestRepo) ExpirePaymentRequests(ctx context.Context, now time.Time, limit int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpirePaymentRequests", ctx, now, limit)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExpirePaymentRequests indicates an expected call of ExpirePaymentRequests.
+func (mr *MockpaymentRequestRepoMockRecorder) ExpirePaymentRequests(ctx, now, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpirePaymentRequests", reflect.TypeOf((*MockpaymentRequestRepo)(nil).ExpirePaymentRequests), ctx, now, limit)
+}
+
+// GetPaymentRequest mocks base method.
+func (m *MockpaymentRequestRepo) GetPaymentRequest(ctx context.Context, id int64) (domain.PaymentRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPaymentRequest", ctx, id)
+	ret0, _ := ret[0].(domain.PaymentRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPaymentRequest indicates an expected call of GetPaymentRequest.
+func (mr *MockpaymentRequestRepoMockRecorder) GetPaymentRequest(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPaymentRequest", reflect.TypeOf((*MockpaymentRequestRepo)(nil).GetPaymentRequest), ctx, id)
+}
+
+// ListPaymentRequests mocks base method.
+func (m *MockpaymentRequestRepo) ListPaymentRequests(ctx context.Context, filter domain.PaymentRequestFilter) ([]domain.PaymentRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPaymentRequests", ctx, filter)
+	ret0, _ := ret[0].([]domain.PaymentRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPaymentRequests indicates an expected call of ListPaymentRequests.
+func (mr *MockpaymentRequestRepoMockRecorder) ListPaymentRequests(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPaymentRequests", reflect.TypeOf((*MockpaymentRequestRepo)(nil).ListPaymentRequests), ctx, filter)
+}
+
+// UpdatePaymentRequestStatus mocks base method.
+func (m *MockpaymentRequestRepo) UpdatePaymentRequestStatus(ctx context.Context, id int64, from []domain.PaymentRequestStatus, to domain.PaymentRequestStatus) (domain.PaymentRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePaymentRequestStatus", ctx, id, from, to)
+	ret0, _ := ret[0].(domain.PaymentRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdatePaymentRequestStatus indicates an expected call of UpdatePaymentRequestStatus.
+func (mr *MockpaymentRequestRepoMockRecorder) UpdatePaymentRequestStatus(ctx, id, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePaymentRequestStatus", reflect.TypeOf((*MockpaymentRequestRepo)(nil).UpdatePaymentRequestStatus), ctx, id, from, to)
+}
@@ -0,0 +1,82 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: pocket.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+
+	domain "github.com/Te8va/wallet/internal/domain"
+)
+
+// MockpocketRepo is a mock of pocketRepo interface.
+type MockpocketRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockpocketRepoMockRecorder
+}
+
+// MockpocketRepoMockRecorder is the mock recorder for MockpocketRepo.
+type MockpocketRepoMockRecorder struct {
+	mock *MockpocketRepo
+}
+
+// NewMockpocketRepo creates a new mock instance.
+func NewMockpocketRepo(ctrl *gomock.Controller) *MockpocketRepo {
+	mock := &MockpocketRepo{ctrl: ctrl}
+	mock.recorder = &MockpocketRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockpocketRepo) EXPECT() *MockpocketRepoMockRecorder {
+	return m.recorder
+}
+
+// CreatePocket mocks base method.
+func (m *MockpocketRepo) CreatePocket(ctx context.Context, walletID, name string) (domain.Pocket, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePocket", ctx, walletID, name)
+	ret0, _ := ret[0].(domain.Pocket)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePocket indicates an expected call of CreatePocket.
+func (mr *MockpocketRepoMockRecorder) CreatePocket(ctx, walletID, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePocket", reflect.TypeOf((*MockpocketRepo)(nil).CreatePocket), ctx, walletID, name)
+}
+
+// MovePocket mocks base method.
+func (m *MockpocketRepo) MovePocket(ctx context.Context, move domain.PocketMove) (domain.PocketMove, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MovePocket", ctx, move)
+	ret0, _ := ret[0].(domain.PocketMove)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MovePocket indicates an expected call of MovePocket.
+func (mr *MockpocketRepoMockRecorder) MovePocket(ctx, move interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MovePocket", reflect.TypeOf((*MockpocketRepo)(nil).MovePocket), ctx, move)
+}
+
+// PocketBalances mocks base method.
+func (m *MockpocketRepo) PocketBalances(ctx context.Context, walletID string) (domain.PocketBalances, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PocketBalances", ctx, walletID)
+	ret0, _ := ret[0].(domain.PocketBalances)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PocketBalances indicates an expected call of PocketBalances.
+func (mr *MockpocketRepoMockRecorder) PocketBalances(ctx, walletID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PocketBalances", reflect.TypeOf((*MockpocketRepo)(nil).PocketBalances), ctx, walletID)
+}
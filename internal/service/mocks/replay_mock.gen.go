@@ -0,0 +1,52 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: replay.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+
+	domain "github.com/Te8va/wallet/internal/domain"
+)
+
+// MockreplayRepo is a mock of replayRepo interface.
+type MockreplayRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockreplayRepoMockRecorder
+}
+
+// MockreplayRepoMockRecorder is the mock recorder for MockreplayRepo.
+type MockreplayRepoMockRecorder struct {
+	mock *MockreplayRepo
+}
+
+// NewMockreplayRepo creates a new mock instance.
+func NewMockreplayRepo(ctrl *gomock.Controller) *MockreplayRepo {
+	mock := &MockreplayRepo{ctrl: ctrl}
+	mock.recorder = &MockreplayRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockreplayRepo) EXPECT() *MockreplayRepoMockRecorder {
+	return m.recorder
+}
+
+// ReplayWallets mocks base method.
+func (m *MockreplayRepo) ReplayWallets(ctx context.Context, opts domain.ReplayOptions) (domain.ReplayResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplayWallets", ctx, opts)
+	ret0, _ := ret[0].(domain.ReplayResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReplayWallets indicates an expected call of ReplayWallets.
+func (mr *MockreplayRepoMockRecorder) ReplayWallets(ctx, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplayWallets", reflect.TypeOf((*MockreplayRepo)(nil).ReplayWallets), ctx, opts)
+}
@@ -0,0 +1,164 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: schedule.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+
+	domain "github.com/Te8va/wallet/internal/domain"
+)
+
+// MockscheduleRepo is a mock of scheduleRepo interface.
+type MockscheduleRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockscheduleRepoMockRecorder
+}
+
+// MockscheduleRepoMockRecorder is the mock recorder for MockscheduleRepo.
+type MockscheduleRepoMockRecorder struct {
+	mock *MockscheduleRepo
+}
+
+// NewMockscheduleRepo creates a new mock instance.
+func NewMockscheduleRepo(ctrl *gomock.Controller) *MockscheduleRepo {
+	mock := &MockscheduleRepo{ctrl: ctrl}
+	mock.recorder = &MockscheduleRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockscheduleRepo) EXPECT() *MockscheduleRepoMockRecorder {
+	return m.recorder
+}
+
+// ClaimDueSchedules mocks base method.
+func (m *MockscheduleRepo) ClaimDueSchedules(ctx context.Context, now, leaseUntil time.Time, limit int) ([]domain.Schedule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimDueSchedules", ctx, now, leaseUntil, limit)
+	ret0, _ := ret[0].([]domain.Schedule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimDueSchedules indicates an expected call of ClaimDueSchedules.
+func (mr *MockscheduleRepoMockRecorder) ClaimDueSchedules(ctx, now, leaseUntil, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimDueSchedules", reflect.TypeOf((*MockscheduleRepo)(nil).ClaimDueSchedules), ctx, now, leaseUntil, limit)
+}
+
+// CompleteScheduleRun mocks base method.
+func (m *MockscheduleRepo) CompleteScheduleRun(ctx context.Context, s domain.Schedule, leaseUntil time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteScheduleRun", ctx, s, leaseUntil)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CompleteScheduleRun indicates an expected call of CompleteScheduleRun.
+func (mr *MockscheduleRepoMockRecorder) CompleteScheduleRun(ctx, s, leaseUntil interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteScheduleRun", reflect.TypeOf((*MockscheduleRepo)(nil).CompleteScheduleRun), ctx, s, leaseUntil)
+}
+
+// CreateSchedule mocks base method.
+func (m *MockscheduleRepo) CreateSchedule(ctx context.Context, s domain.Schedule) (domain.Schedule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSchedule", ctx, s)
+	ret0, _ := ret[0].(domain.Schedule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateSchedule indicates an expected call of CreateSchedule.
+func (mr *MockscheduleRepoMockRecorder) CreateSchedule(ctx, s interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSchedule", reflect.TypeOf((*MockscheduleRepo)(nil).CreateSchedule), ctx, s)
+}
+
+// GetSchedule mocks base method.
+func (m *MockscheduleRepo) GetSchedule(ctx context.Context, id int64) (domain.Schedule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSchedule", ctx, id)
+	ret0, _ := ret[0].(domain.Schedule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSchedule indicates an expected call of GetSchedule.
+func (mr *MockscheduleRepoMockRecorder) GetSchedule(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSchedule", reflect.TypeOf((*MockscheduleRepo)(nil).GetSchedule), ctx, id)
+}
+
+// ListSchedules mocks base method.
+func (m *MockscheduleRepo) ListSchedules(ctx context.Context, walletID string) ([]domain.Schedule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSchedules", ctx, walletID)
+	ret0, _ := ret[0].([]domain.Schedule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSchedules indicates an expected call of ListSchedules.
+func (mr *MockscheduleRepoMockRecorder) ListSchedules(ctx, walletID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSchedules", reflect.TypeOf((*MockscheduleRepo)(nil).ListSchedules), ctx, walletID)
+}
+
+// UpdateScheduleStatus mocks base method.
+func (m *MockscheduleRepo) UpdateScheduleStatus(ctx context.Context, id int64, from []domain.ScheduleStatus, to domain.ScheduleStatus, nextRunAt time.Time) (domain.Schedule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateScheduleStatus", ctx, id, from, to, nextRunAt)
+	ret0, _ := ret[0].(domain.Schedule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateScheduleStatus indicates an expected call of UpdateScheduleStatus.
+func (mr *MockscheduleRepoMockRecorder) UpdateScheduleStatus(ctx, id, from, to, nextRunAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateScheduleStatus", reflect.TypeOf((*MockscheduleRepo)(nil).UpdateScheduleStatus), ctx, id, from, to, nextRunAt)
+}
+
+// Mocktransferer is a mock of transferer interface.
+type Mocktransferer struct {
+	ctrl     *gomock.Controller
+	recorder *MocktransfererMockRecorder
+}
+
+// MocktransfererMockRecorder is the mock recorder for Mocktransferer.
+type MocktransfererMockRecorder struct {
+	mock *Mocktransferer
+}
+
+// NewMocktransferer creates a new mock instance.
+func NewMocktransferer(ctrl *gomock.Controller) *Mocktransferer {
+	mock := &Mocktransferer{ctrl: ctrl}
+	mock.recorder = &MocktransfererMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *Mocktransferer) EXPECT() *MocktransfererMockRecorder {
+	return m.recorder
+}
+
+// Transfer mocks base method.
+func (m *Mocktransferer) Transfer(ctx context.Context, fromWalletID, toWalletID string, amount int64, idempotencyKey string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Transfer", ctx, fromWalletID, toWalletID, amount, idempotencyKey)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Transfer indicates an expected call of Transfer.
+func (mr *MocktransfererMockRecorder) Transfer(ctx, fromWalletID, toWalletID, amount, idempotencyKey interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Transfer", reflect.TypeOf((*Mocktransferer)(nil).Transfer), ctx, fromWalletID, toWalletID, amount, idempotencyKey)
+}
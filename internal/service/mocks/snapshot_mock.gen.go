@@ -0,0 +1,50 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: snapshot.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MocksnapshotRepo is a mock of snapshotRepo interface.
+type MocksnapshotRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MocksnapshotRepoMockRecorder
+}
+
+// MocksnapshotRepoMockRecorder is the mock recorder for MocksnapshotRepo.
+type MocksnapshotRepoMockRecorder struct {
+	mock *MocksnapshotRepo
+}
+
+// NewMocksnapshotRepo creates a new mock instance.
+func NewMocksnapshotRepo(ctrl *gomock.Controller) *MocksnapshotRepo {
+	mock := &MocksnapshotRepo{ctrl: ctrl}
+	mock.recorder = &MocksnapshotRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MocksnapshotRepo) EXPECT() *MocksnapshotRepoMockRecorder {
+	return m.recorder
+}
+
+// TakeSnapshots mocks base method.
+func (m *MocksnapshotRepo) TakeSnapshots(ctx context.Context, minEntries int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TakeSnapshots", ctx, minEntries)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TakeSnapshots indicates an expected call of TakeSnapshots.
+func (mr *MocksnapshotRepoMockRecorder) TakeSnapshots(ctx, minEntries interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TakeSnapshots", reflect.TypeOf((*MocksnapshotRepo)(nil).TakeSnapshots), ctx, minEntries)
+}
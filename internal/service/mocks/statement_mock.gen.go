@@ -0,0 +1,83 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: statement.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+
+	domain "github.com/Te8va/wallet/internal/domain"
+)
+
+// MockstatementRepo is a mock of statementRepo interface.
+type MockstatementRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockstatementRepoMockRecorder
+}
+
+// MockstatementRepoMockRecorder is the mock recorder for MockstatementRepo.
+type MockstatementRepoMockRecorder struct {
+	mock *MockstatementRepo
+}
+
+// NewMockstatementRepo creates a new mock instance.
+func NewMockstatementRepo(ctrl *gomock.Controller) *MockstatementRepo {
+	mock := &MockstatementRepo{ctrl: ctrl}
+	mock.recorder = &MockstatementRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockstatementRepo) EXPECT() *MockstatementRepoMockRecorder {
+	return m.recorder
+}
+
+// BalanceAt mocks base method.
+func (m *MockstatementRepo) BalanceAt(ctx context.Context, walletID string, at time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BalanceAt", ctx, walletID, at)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BalanceAt indicates an expected call of BalanceAt.
+func (mr *MockstatementRepoMockRecorder) BalanceAt(ctx, walletID, at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BalanceAt", reflect.TypeOf((*MockstatementRepo)(nil).BalanceAt), ctx, walletID, at)
+}
+
+// GetBalance mocks base method.
+func (m *MockstatementRepo) GetBalance(ctx context.Context, walletID string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBalance", ctx, walletID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBalance indicates an expected call of GetBalance.
+func (mr *MockstatementRepoMockRecorder) GetBalance(ctx, walletID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBalance", reflect.TypeOf((*MockstatementRepo)(nil).GetBalance), ctx, walletID)
+}
+
+// ListTransactions mocks base method.
+func (m *MockstatementRepo) ListTransactions(ctx context.Context, walletID string, from, to time.Time) ([]domain.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTransactions", ctx, walletID, from, to)
+	ret0, _ := ret[0].([]domain.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTransactions indicates an expected call of ListTransactions.
+func (mr *MockstatementRepoMockRecorder) ListTransactions(ctx, walletID, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTransactions", reflect.TypeOf((*MockstatementRepo)(nil).ListTransactions), ctx, walletID, from, to)
+}
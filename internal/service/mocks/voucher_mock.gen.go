@@ -0,0 +1,97 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: voucher.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+
+	domain "github.com/Te8va/wallet/internal/domain"
+)
+
+// MockvoucherRepo is a mock of voucherRepo interface.
+type MockvoucherRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockvoucherRepoMockRecorder
+}
+
+// MockvoucherRepoMockRecorder is the mock recorder for MockvoucherRepo.
+type MockvoucherRepoMockRecorder struct {
+	mock *MockvoucherRepo
+}
+
+// NewMockvoucherRepo creates a new mock instance.
+func NewMockvoucherRepo(ctrl *gomock.Controller) *MockvoucherRepo {
+	mock := &MockvoucherRepo{ctrl: ctrl}
+	mock.recorder = &MockvoucherRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockvoucherRepo) EXPECT() *MockvoucherRepoMockRecorder {
+	return m.recorder
+}
+
+// CreateVoucherBatch mocks base method.
+func (m *MockvoucherRepo) CreateVoucherBatch(ctx context.Context, params domain.VoucherBatchParams, codeHashes [][]byte) (domain.VoucherBatch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateVoucherBatch", ctx, params, codeHashes)
+	ret0, _ := ret[0].(domain.VoucherBatch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateVoucherBatch indicates an expected call of CreateVoucherBatch.
+func (mr *MockvoucherRepoMockRecorder) CreateVoucherBatch(ctx, params, codeHashes interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateVoucherBatch", reflect.TypeOf((*MockvoucherRepo)(nil).CreateVoucherBatch), ctx, params, codeHashes)
+}
+
+// ExpireVoucherBatch mocks base method.
+func (m *MockvoucherRepo) ExpireVoucherBatch(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpireVoucherBatch", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExpireVoucherBatch indicates an expected call of ExpireVoucherBatch.
+func (mr *MockvoucherRepoMockRecorder) ExpireVoucherBatch(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpireVoucherBatch", reflect.TypeOf((*MockvoucherRepo)(nil).ExpireVoucherBatch), ctx, id)
+}
+
+// ListExpiredVoucherBatches mocks base method.
+func (m *MockvoucherRepo) ListExpiredVoucherBatches(ctx context.Context, now time.Time, limit int) ([]domain.VoucherBatch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListExpiredVoucherBatches", ctx, now, limit)
+	ret0, _ := ret[0].([]domain.VoucherBatch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListExpiredVoucherBatches indicates an expected call of ListExpiredVoucherBatches.
+func (mr *MockvoucherRepoMockRecorder) ListExpiredVoucherBatches(ctx, now, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListExpiredVoucherBatches", reflect.TypeOf((*MockvoucherRepo)(nil).ListExpiredVoucherBatches), ctx, now, limit)
+}
+
+// RedeemVoucher mocks base method.
+func (m *MockvoucherRepo) RedeemVoucher(ctx context.Context, codeHash []byte, walletID string, now time.Time) (domain.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RedeemVoucher", ctx, codeHash, walletID, now)
+	ret0, _ := ret[0].(domain.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RedeemVoucher indicates an expected call of RedeemVoucher.
+func (mr *MockvoucherRepoMockRecorder) RedeemVoucher(ctx, codeHash, walletID, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RedeemVoucher", reflect.TypeOf((*MockvoucherRepo)(nil).RedeemVoucher), ctx, codeHash, walletID, now)
+}
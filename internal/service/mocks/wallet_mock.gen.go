@@ -7,6 +7,7 @@ package mocks
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 
@@ -36,31 +37,180 @@ func (m *MockwalletServ) EXPECT() *MockwalletServMockRecorder {
 	return m.recorder
 }
 
-// GetBalance mocks base method.
-func (m *MockwalletServ) GetBalance(ctx context.Context, walletID string) (int64, error) {
+// BalanceAsOf mocks base method.
+func (m *MockwalletServ) BalanceAsOf(ctx context.Context, walletID string, asOf time.Time) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetBalance", ctx, walletID)
+	ret := m.ctrl.Call(m, "BalanceAsOf", ctx, walletID, asOf)
 	ret0, _ := ret[0].(int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetBalance indicates an expected call of GetBalance.
-func (mr *MockwalletServMockRecorder) GetBalance(ctx, walletID interface{}) *gomock.Call {
+// BalanceAsOf indicates an expected call of BalanceAsOf.
+func (mr *MockwalletServMockRecorder) BalanceAsOf(ctx, walletID, asOf interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBalance", reflect.TypeOf((*MockwalletServ)(nil).GetBalance), ctx, walletID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BalanceAsOf", reflect.TypeOf((*MockwalletServ)(nil).BalanceAsOf), ctx, walletID, asOf)
+}
+
+// GetTransaction mocks base method.
+func (m *MockwalletServ) GetTransaction(ctx context.Context, id int64) (domain.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransaction", ctx, id)
+	ret0, _ := ret[0].(domain.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransaction indicates an expected call of GetTransaction.
+func (mr *MockwalletServMockRecorder) GetTransaction(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransaction", reflect.TypeOf((*MockwalletServ)(nil).GetTransaction), ctx, id)
+}
+
+// PocketBalances mocks base method.
+func (m *MockwalletServ) PocketBalances(ctx context.Context, walletID string) (domain.PocketBalances, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PocketBalances", ctx, walletID)
+	ret0, _ := ret[0].(domain.PocketBalances)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PocketBalances indicates an expected call of PocketBalances.
+func (mr *MockwalletServMockRecorder) PocketBalances(ctx, walletID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PocketBalances", reflect.TypeOf((*MockwalletServ)(nil).PocketBalances), ctx, walletID)
 }
 
 // ProcessTransaction mocks base method.
-func (m *MockwalletServ) ProcessTransaction(ctx context.Context, walletID string, opType domain.OperationType, amount int64) error {
+func (m *MockwalletServ) ProcessTransaction(ctx context.Context, req domain.TransactionRequest) (domain.TransactionResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProcessTransaction", ctx, req)
+	ret0, _ := ret[0].(domain.TransactionResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ProcessTransaction indicates an expected call of ProcessTransaction.
+func (mr *MockwalletServMockRecorder) ProcessTransaction(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProcessTransaction", reflect.TypeOf((*MockwalletServ)(nil).ProcessTransaction), ctx, req)
+}
+
+// SearchTransactions mocks base method.
+func (m *MockwalletServ) SearchTransactions(ctx context.Context, filter domain.TransactionFilter) (domain.TransactionHistory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchTransactions", ctx, filter)
+	ret0, _ := ret[0].(domain.TransactionHistory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchTransactions indicates an expected call of SearchTransactions.
+func (mr *MockwalletServMockRecorder) SearchTransactions(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchTransactions", reflect.TypeOf((*MockwalletServ)(nil).SearchTransactions), ctx, filter)
+}
+
+// Split mocks base method.
+func (m *MockwalletServ) Split(ctx context.Context, split domain.Split) (domain.SplitPayment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Split", ctx, split)
+	ret0, _ := ret[0].(domain.SplitPayment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Split indicates an expected call of Split.
+func (mr *MockwalletServMockRecorder) Split(ctx, split interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Split", reflect.TypeOf((*MockwalletServ)(nil).Split), ctx, split)
+}
+
+// Transfer mocks base method.
+func (m *MockwalletServ) Transfer(ctx context.Context, fromWalletID, toWalletID string, amount int64, idempotencyKey string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ProcessTransaction", ctx, walletID, opType, amount)
+	ret := m.ctrl.Call(m, "Transfer", ctx, fromWalletID, toWalletID, amount, idempotencyKey)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// ProcessTransaction indicates an expected call of ProcessTransaction.
-func (mr *MockwalletServMockRecorder) ProcessTransaction(ctx, walletID, opType, amount interface{}) *gomock.Call {
+// Transfer indicates an expected call of Transfer.
+func (mr *MockwalletServMockRecorder) Transfer(ctx, fromWalletID, toWalletID, amount, idempotencyKey interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Transfer", reflect.TypeOf((*MockwalletServ)(nil).Transfer), ctx, fromWalletID, toWalletID, amount, idempotencyKey)
+}
+
+// Mockcashbacker is a mock of cashbacker interface.
+type Mockcashbacker struct {
+	ctrl     *gomock.Controller
+	recorder *MockcashbackerMockRecorder
+}
+
+// MockcashbackerMockRecorder is the mock recorder for Mockcashbacker.
+type MockcashbackerMockRecorder struct {
+	mock *Mockcashbacker
+}
+
+// NewMockcashbacker creates a new mock instance.
+func NewMockcashbacker(ctrl *gomock.Controller) *Mockcashbacker {
+	mock := &Mockcashbacker{ctrl: ctrl}
+	mock.recorder = &MockcashbackerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *Mockcashbacker) EXPECT() *MockcashbackerMockRecorder {
+	return m.recorder
+}
+
+// ApplyCashback mocks base method.
+func (m *Mockcashbacker) ApplyCashback(ctx context.Context, t domain.Transaction) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApplyCashback", ctx, t)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ApplyCashback indicates an expected call of ApplyCashback.
+func (mr *MockcashbackerMockRecorder) ApplyCashback(ctx, t interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplyCashback", reflect.TypeOf((*Mockcashbacker)(nil).ApplyCashback), ctx, t)
+}
+
+// MockpointsEarner is a mock of pointsEarner interface.
+type MockpointsEarner struct {
+	ctrl     *gomock.Controller
+	recorder *MockpointsEarnerMockRecorder
+}
+
+// MockpointsEarnerMockRecorder is the mock recorder for MockpointsEarner.
+type MockpointsEarnerMockRecorder struct {
+	mock *MockpointsEarner
+}
+
+// NewMockpointsEarner creates a new mock instance.
+func NewMockpointsEarner(ctrl *gomock.Controller) *MockpointsEarner {
+	mock := &MockpointsEarner{ctrl: ctrl}
+	mock.recorder = &MockpointsEarnerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockpointsEarner) EXPECT() *MockpointsEarnerMockRecorder {
+	return m.recorder
+}
+
+// EarnPoints mocks base method.
+func (m *MockpointsEarner) EarnPoints(ctx context.Context, t domain.Transaction) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EarnPoints", ctx, t)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EarnPoints indicates an expected call of EarnPoints.
+func (mr *MockpointsEarnerMockRecorder) EarnPoints(ctx, t interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProcessTransaction", reflect.TypeOf((*MockwalletServ)(nil).ProcessTransaction), ctx, walletID, opType, amount)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EarnPoints", reflect.TypeOf((*MockpointsEarner)(nil).EarnPoints), ctx, t)
 }
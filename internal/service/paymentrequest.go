@@ -0,0 +1,169 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/Te8va/wallet/internal/domain"
+	appErrors "github.com/Te8va/wallet/internal/errors"
+	"github.com/Te8va/wallet/internal/logging"
+)
+
+//go:generate mockgen -source=paymentrequest.go -destination=mocks/paymentrequest_mock.gen.go -package=mocks
+type paymentRequestRepo interface {
+	CreatePaymentRequest(ctx context.Context, params domain.PaymentRequestParams) (domain.PaymentRequest, error)
+	GetPaymentRequest(ctx context.Context, id int64) (domain.PaymentRequest, error)
+	ListPaymentRequests(ctx context.Context, filter domain.PaymentRequestFilter) ([]domain.PaymentRequest, error)
+	UpdatePaymentRequestStatus(ctx context.Context, id int64, from []domain.PaymentRequestStatus, to domain.PaymentRequestStatus) (domain.PaymentRequest, error)
+	ExpirePaymentRequests(ctx context.Context, now time.Time, limit int) (int, error)
+}
+
+// PaymentRequestPolicy controls payment request defaults and the expiry job.
+type PaymentRequestPolicy struct {
+	Currency   string
+	DefaultTTL time.Duration
+	BatchSize  int
+}
+
+type PaymentRequestService struct {
+	repo   paymentRequestRepo
+	wallet transferer
+	policy PaymentRequestPolicy
+	now    func() time.Time
+}
+
+func NewPaymentRequestService(repo paymentRequestRepo, wallet transferer, policy PaymentRequestPolicy) *PaymentRequestService {
+	return &PaymentRequestService{
+		repo:   repo,
+		wallet: wallet,
+		policy: policy,
+		now:    func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// CreatePaymentRequest issues a request from the payee wallet to the payer
+// wallet. Without an expiry the request expires after the policy default.
+func (s *PaymentRequestService) CreatePaymentRequest(ctx context.Context, params domain.PaymentRequestParams) (domain.PaymentRequest, error) {
+	if params.PayeeWalletID == params.PayerWalletID {
+		return domain.PaymentRequest{}, appErrors.ErrSameWallet
+	}
+
+	if params.Currency == "" {
+		params.Currency = s.policy.Currency
+	}
+	if params.Currency != s.policy.Currency {
+		return domain.PaymentRequest{}, appErrors.ErrCurrencyMismatch
+	}
+
+	if params.ExpiresAt.IsZero() {
+		params.ExpiresAt = s.now().Add(s.policy.DefaultTTL)
+	}
+	params.ExpiresAt = params.ExpiresAt.UTC()
+
+	pr, err := s.repo.CreatePaymentRequest(ctx, params)
+	if err == nil {
+		logPaymentRequest(ctx, pr)
+	}
+	return pr, err
+}
+
+func (s *PaymentRequestService) GetPaymentRequest(ctx context.Context, id int64) (domain.PaymentRequest, error) {
+	return s.repo.GetPaymentRequest(ctx, id)
+}
+
+func (s *PaymentRequestService) ListPaymentRequests(ctx context.Context, filter domain.PaymentRequestFilter) ([]domain.PaymentRequest, error) {
+	return s.repo.ListPaymentRequests(ctx, filter)
+}
+
+// AcceptPaymentRequest pays the request with a transfer from the payer to
+// the payee. The request is claimed as ACCEPTING first, so it cannot be
+// declined or expire while the transfer is posted. The transfer carries an
+// idempotency key derived from the request, so accepting a request again
+// after a failure never pays it twice. A transfer already posted under the
+// key only counts as payment when it moved the amount of the request from
+// the payer to the payee; Transfer reports any other use of the key as
+// ErrIdempotencyKeyReused.
+func (s *PaymentRequestService) AcceptPaymentRequest(ctx context.Context, id int64) (domain.PaymentRequest, error) {
+	pr, err := s.repo.GetPaymentRequest(ctx, id)
+	if err != nil {
+		return domain.PaymentRequest{}, err
+	}
+
+	if pr.Status == domain.PaymentRequestPending && !pr.ExpiresAt.After(s.now()) {
+		return domain.PaymentRequest{}, appErrors.ErrPaymentRequestExpired
+	}
+
+	pr, err = s.transition(ctx, id,
+		[]domain.PaymentRequestStatus{domain.PaymentRequestPending, domain.PaymentRequestAccepting}, domain.PaymentRequestAccepting)
+	if err != nil {
+		return domain.PaymentRequest{}, err
+	}
+
+	key := "payment-request:" + strconv.FormatInt(id, 10)
+	err = s.wallet.Transfer(ctx, pr.PayerWalletID, pr.PayeeWalletID, pr.Amount, key)
+	switch {
+	case err == nil, errors.Is(err, appErrors.ErrDuplicateOperation):
+	case errors.Is(err, appErrors.ErrIdempotencyKeyReused):
+		// Something other than this payment was posted under the key, so
+		// the request is not paid. It stays ACCEPTING for manual review.
+		logging.FromContext(ctx).Error("Payment request key used by another operation",
+			zap.Int64("payment_request_id", id), zap.String("idempotency_key", key))
+		return domain.PaymentRequest{}, err
+	case errors.Is(err, appErrors.ErrInsufficientFunds), errors.Is(err, appErrors.ErrWalletNotFound),
+		errors.Is(err, appErrors.ErrWalletKindNotAllowed):
+		// The transfer was rejected, so the payer may try again.
+		_, revertErr := s.transition(ctx, id, []domain.PaymentRequestStatus{domain.PaymentRequestAccepting}, domain.PaymentRequestPending)
+		return domain.PaymentRequest{}, errors.Join(err, revertErr)
+	default:
+		// The transfer may or may not have been posted. The request stays
+		// ACCEPTING until it is accepted again.
+		return domain.PaymentRequest{}, err
+	}
+
+	paid, err := s.transition(ctx, id, []domain.PaymentRequestStatus{domain.PaymentRequestAccepting}, domain.PaymentRequestPaid)
+	if errors.Is(err, appErrors.ErrPaymentRequestState) {
+		// A concurrent accept has already marked it paid.
+		return s.repo.GetPaymentRequest(ctx, id)
+	}
+	return paid, err
+}
+
+func (s *PaymentRequestService) DeclinePaymentRequest(ctx context.Context, id int64) (domain.PaymentRequest, error) {
+	return s.transition(ctx, id, []domain.PaymentRequestStatus{domain.PaymentRequestPending}, domain.PaymentRequestDeclined)
+}
+
+// RunExpired moves pending requests past their expiry to EXPIRED. It
+// returns the number of requests expired.
+func (s *PaymentRequestService) RunExpired(ctx context.Context) (int, error) {
+	n, err := s.repo.ExpirePaymentRequests(ctx, s.now(), s.policy.BatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("service.RunExpired: %w", err)
+	}
+
+	return n, nil
+}
+
+func (s *PaymentRequestService) transition(ctx context.Context, id int64, from []domain.PaymentRequestStatus, to domain.PaymentRequestStatus) (domain.PaymentRequest, error) {
+	pr, err := s.repo.UpdatePaymentRequestStatus(ctx, id, from, to)
+	if err == nil {
+		logPaymentRequest(ctx, pr)
+	}
+	return pr, err
+}
+
+// logPaymentRequest publishes a state change of a payment request as a
+// structured log event.
+func logPaymentRequest(ctx context.Context, pr domain.PaymentRequest) {
+	logging.FromContext(ctx).Info("Payment request status changed",
+		zap.Int64("payment_request_id", pr.ID),
+		zap.String("status", string(pr.Status)),
+		zap.String("payee_wallet_id", pr.PayeeWalletID),
+		zap.String("payer_wallet_id", pr.PayerWalletID),
+		zap.Int64("amount", pr.Amount),
+	)
+}
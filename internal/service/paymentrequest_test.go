@@ -0,0 +1,186 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Te8va/wallet/internal/domain"
+	appErrors "github.com/Te8va/wallet/internal/errors"
+	"github.com/Te8va/wallet/internal/service"
+	"github.com/Te8va/wallet/internal/service/mocks"
+)
+
+func TestPaymentRequestService_CreatePaymentRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockpaymentRequestRepo(ctrl)
+	svc := service.NewPaymentRequestService(mockRepo, mocks.NewMocktransferer(ctrl), service.PaymentRequestPolicy{
+		Currency:   "RUB",
+		DefaultTTL: 72 * time.Hour,
+	})
+
+	expiresAt := time.Date(2025, 4, 4, 10, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name        string
+		params      domain.PaymentRequestParams
+		mockRepo    func()
+		expectedErr error
+	}{
+		{
+			name:   "wallet currency by default",
+			params: domain.PaymentRequestParams{PayeeWalletID: "payee", PayerWalletID: "payer", Amount: 500, ExpiresAt: expiresAt},
+			mockRepo: func() {
+				mockRepo.EXPECT().CreatePaymentRequest(gomock.Any(), domain.PaymentRequestParams{
+					PayeeWalletID: "payee", PayerWalletID: "payer", Amount: 500, Currency: "RUB", ExpiresAt: expiresAt,
+				}).Return(domain.PaymentRequest{ID: 1, Status: domain.PaymentRequestPending}, nil)
+			},
+		},
+		{
+			name:        "other currency",
+			params:      domain.PaymentRequestParams{PayeeWalletID: "payee", PayerWalletID: "payer", Amount: 500, Currency: "USD"},
+			mockRepo:    func() {},
+			expectedErr: appErrors.ErrCurrencyMismatch,
+		},
+		{
+			name:        "request to itself",
+			params:      domain.PaymentRequestParams{PayeeWalletID: "payee", PayerWalletID: "payee", Amount: 500},
+			mockRepo:    func() {},
+			expectedErr: appErrors.ErrSameWallet,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mockRepo()
+
+			_, err := svc.CreatePaymentRequest(context.Background(), tc.params)
+			require.ErrorIs(t, err, tc.expectedErr)
+		})
+	}
+}
+
+func TestPaymentRequestService_AcceptPaymentRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockpaymentRequestRepo(ctrl)
+	mockWallet := mocks.NewMocktransferer(ctrl)
+	svc := service.NewPaymentRequestService(mockRepo, mockWallet, service.PaymentRequestPolicy{Currency: "RUB"})
+
+	request := func(status domain.PaymentRequestStatus, expiresAt time.Time) domain.PaymentRequest {
+		return domain.PaymentRequest{
+			ID: 7, PayeeWalletID: "payee", PayerWalletID: "payer", Amount: 500, Status: status, ExpiresAt: expiresAt,
+		}
+	}
+	future := time.Now().Add(time.Hour)
+	claim := []domain.PaymentRequestStatus{domain.PaymentRequestPending, domain.PaymentRequestAccepting}
+	accepting := []domain.PaymentRequestStatus{domain.PaymentRequestAccepting}
+
+	testCases := []struct {
+		name        string
+		mock        func()
+		wantStatus  domain.PaymentRequestStatus
+		expectedErr error
+	}{
+		{
+			name: "paid",
+			mock: func() {
+				mockRepo.EXPECT().GetPaymentRequest(gomock.Any(), int64(7)).Return(request(domain.PaymentRequestPending, future), nil)
+				mockRepo.EXPECT().UpdatePaymentRequestStatus(gomock.Any(), int64(7), claim, domain.PaymentRequestAccepting).
+					Return(request(domain.PaymentRequestAccepting, future), nil)
+				mockWallet.EXPECT().Transfer(gomock.Any(), "payer", "payee", int64(500), "payment-request:7").Return(nil)
+				mockRepo.EXPECT().UpdatePaymentRequestStatus(gomock.Any(), int64(7), accepting, domain.PaymentRequestPaid).
+					Return(request(domain.PaymentRequestPaid, future), nil)
+			},
+			wantStatus: domain.PaymentRequestPaid,
+		},
+		{
+			name: "retry after the transfer was posted",
+			mock: func() {
+				mockRepo.EXPECT().GetPaymentRequest(gomock.Any(), int64(7)).Return(request(domain.PaymentRequestAccepting, future), nil)
+				mockRepo.EXPECT().UpdatePaymentRequestStatus(gomock.Any(), int64(7), claim, domain.PaymentRequestAccepting).
+					Return(request(domain.PaymentRequestAccepting, future), nil)
+				mockWallet.EXPECT().Transfer(gomock.Any(), "payer", "payee", int64(500), "payment-request:7").
+					Return(appErrors.ErrDuplicateOperation)
+				mockRepo.EXPECT().UpdatePaymentRequestStatus(gomock.Any(), int64(7), accepting, domain.PaymentRequestPaid).
+					Return(request(domain.PaymentRequestPaid, future), nil)
+			},
+			wantStatus: domain.PaymentRequestPaid,
+		},
+		{
+			name: "key taken by another operation is not paid",
+			mock: func() {
+				mockRepo.EXPECT().GetPaymentRequest(gomock.Any(), int64(7)).Return(request(domain.PaymentRequestAccepting, future), nil)
+				mockRepo.EXPECT().UpdatePaymentRequestStatus(gomock.Any(), int64(7), claim, domain.PaymentRequestAccepting).
+					Return(request(domain.PaymentRequestAccepting, future), nil)
+				mockWallet.EXPECT().Transfer(gomock.Any(), "payer", "payee", int64(500), "payment-request:7").
+					Return(appErrors.ErrIdempotencyKeyReused)
+			},
+			expectedErr: appErrors.ErrIdempotencyKeyReused,
+		},
+		{
+			name: "insufficient funds returns the request to pending",
+			mock: func() {
+				mockRepo.EXPECT().GetPaymentRequest(gomock.Any(), int64(7)).Return(request(domain.PaymentRequestPending, future), nil)
+				mockRepo.EXPECT().UpdatePaymentRequestStatus(gomock.Any(), int64(7), claim, domain.PaymentRequestAccepting).
+					Return(request(domain.PaymentRequestAccepting, future), nil)
+				mockWallet.EXPECT().Transfer(gomock.Any(), "payer", "payee", int64(500), "payment-request:7").
+					Return(appErrors.ErrInsufficientFunds)
+				mockRepo.EXPECT().UpdatePaymentRequestStatus(gomock.Any(), int64(7), accepting, domain.PaymentRequestPending).
+					Return(request(domain.PaymentRequestPending, future), nil)
+			},
+			expectedErr: appErrors.ErrInsufficientFunds,
+		},
+		{
+			name: "unknown transfer outcome keeps the claim",
+			mock: func() {
+				mockRepo.EXPECT().GetPaymentRequest(gomock.Any(), int64(7)).Return(request(domain.PaymentRequestPending, future), nil)
+				mockRepo.EXPECT().UpdatePaymentRequestStatus(gomock.Any(), int64(7), claim, domain.PaymentRequestAccepting).
+					Return(request(domain.PaymentRequestAccepting, future), nil)
+				mockWallet.EXPECT().Transfer(gomock.Any(), "payer", "payee", int64(500), "payment-request:7").
+					Return(errors.New("connection reset"))
+			},
+			expectedErr: errors.New("connection reset"),
+		},
+		{
+			name: "expired",
+			mock: func() {
+				mockRepo.EXPECT().GetPaymentRequest(gomock.Any(), int64(7)).
+					Return(request(domain.PaymentRequestPending, time.Now().Add(-time.Minute)), nil)
+			},
+			expectedErr: appErrors.ErrPaymentRequestExpired,
+		},
+		{
+			name: "declined",
+			mock: func() {
+				mockRepo.EXPECT().GetPaymentRequest(gomock.Any(), int64(7)).Return(request(domain.PaymentRequestDeclined, future), nil)
+				mockRepo.EXPECT().UpdatePaymentRequestStatus(gomock.Any(), int64(7), claim, domain.PaymentRequestAccepting).
+					Return(domain.PaymentRequest{}, appErrors.ErrPaymentRequestState)
+			},
+			expectedErr: appErrors.ErrPaymentRequestState,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mock()
+
+			pr, err := svc.AcceptPaymentRequest(context.Background(), 7)
+
+			if tc.expectedErr != nil {
+				require.Error(t, err)
+				require.ErrorContains(t, err, tc.expectedErr.Error())
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.wantStatus, pr.Status)
+		})
+	}
+}
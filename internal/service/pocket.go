@@ -0,0 +1,41 @@
+package service
+
+import (
+	"context"
+
+	"github.com/Te8va/wallet/internal/domain"
+	appErrors "github.com/Te8va/wallet/internal/errors"
+)
+
+//go:generate mockgen -source=pocket.go -destination=mocks/pocket_mock.gen.go -package=mocks
+type pocketRepo interface {
+	CreatePocket(ctx context.Context, walletID, name string) (domain.Pocket, error)
+	PocketBalances(ctx context.Context, walletID string) (domain.PocketBalances, error)
+	MovePocket(ctx context.Context, move domain.PocketMove) (domain.PocketMove, error)
+}
+
+type PocketService struct {
+	repo pocketRepo
+}
+
+func NewPocketService(repo pocketRepo) *PocketService {
+	return &PocketService{repo: repo}
+}
+
+func (s *PocketService) CreatePocket(ctx context.Context, walletID, name string) (domain.Pocket, error) {
+	return s.repo.CreatePocket(ctx, walletID, name)
+}
+
+func (s *PocketService) PocketBalances(ctx context.Context, walletID string) (domain.PocketBalances, error) {
+	return s.repo.PocketBalances(ctx, walletID)
+}
+
+// MovePocket moves money between the main balance and pockets of a wallet.
+// Moves are not deposits or withdrawals: the wallet total does not change.
+func (s *PocketService) MovePocket(ctx context.Context, move domain.PocketMove) (domain.PocketMove, error) {
+	if move.From == move.To {
+		return domain.PocketMove{}, appErrors.ErrSamePocket
+	}
+
+	return s.repo.MovePocket(ctx, move)
+}
@@ -0,0 +1,70 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Te8va/wallet/internal/domain"
+	appErrors "github.com/Te8va/wallet/internal/errors"
+	"github.com/Te8va/wallet/internal/service"
+	"github.com/Te8va/wallet/internal/service/mocks"
+)
+
+func TestPocketService_MovePocket(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockpocketRepo(ctrl)
+	svc := service.NewPocketService(mockRepo)
+
+	walletID := "123e4567-e89b-12d3-a456-426614174000"
+
+	testCases := []struct {
+		name        string
+		move        domain.PocketMove
+		mockRepo    func()
+		expectedErr error
+	}{
+		{
+			name: "main to pocket",
+			move: domain.PocketMove{WalletID: walletID, To: "vacation", Amount: 300},
+			mockRepo: func() {
+				mockRepo.EXPECT().MovePocket(gomock.Any(), domain.PocketMove{WalletID: walletID, To: "vacation", Amount: 300}).
+					Return(domain.PocketMove{ID: 1, WalletID: walletID, To: "vacation", Amount: 300}, nil)
+			},
+		},
+		{
+			name: "insufficient funds in pocket",
+			move: domain.PocketMove{WalletID: walletID, From: "vacation", Amount: 300},
+			mockRepo: func() {
+				mockRepo.EXPECT().MovePocket(gomock.Any(), domain.PocketMove{WalletID: walletID, From: "vacation", Amount: 300}).
+					Return(domain.PocketMove{}, appErrors.ErrInsufficientFunds)
+			},
+			expectedErr: appErrors.ErrInsufficientFunds,
+		},
+		{
+			name:        "same pocket",
+			move:        domain.PocketMove{WalletID: walletID, From: "vacation", To: "vacation", Amount: 300},
+			mockRepo:    func() {},
+			expectedErr: appErrors.ErrSamePocket,
+		},
+		{
+			name:        "main to main",
+			move:        domain.PocketMove{WalletID: walletID, Amount: 300},
+			mockRepo:    func() {},
+			expectedErr: appErrors.ErrSamePocket,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mockRepo()
+
+			_, err := svc.MovePocket(context.Background(), tc.move)
+			require.ErrorIs(t, err, tc.expectedErr)
+		})
+	}
+}
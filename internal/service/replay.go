@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/Te8va/wallet/internal/domain"
+	appErrors "github.com/Te8va/wallet/internal/errors"
+)
+
+//go:generate mockgen -source=replay.go -destination=mocks/replay_mock.gen.go -package=mocks
+type replayRepo interface {
+	ReplayWallets(ctx context.Context, opts domain.ReplayOptions) (domain.ReplayResult, error)
+}
+
+type replayFunc func(ctx context.Context, opts domain.ReplayOptions) (domain.ReplayResult, error)
+
+// ReplayService rebuilds read models from the journal, the event log. A new
+// read model is added by registering its replay function here.
+type ReplayService struct {
+	projections map[string]replayFunc
+}
+
+func NewReplayService(repo replayRepo) *ReplayService {
+	return &ReplayService{
+		projections: map[string]replayFunc{
+			"wallet": repo.ReplayWallets,
+		},
+	}
+}
+
+// Projections returns the names of the registered projections, sorted.
+func (s *ReplayService) Projections() []string {
+	names := make([]string, 0, len(s.projections))
+	for name := range s.projections {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// Replay rebuilds the named projections, or all of them when none are
+// given, stopping at the first failure.
+func (s *ReplayService) Replay(ctx context.Context, names []string, opts domain.ReplayOptions) ([]domain.ReplayResult, error) {
+	if len(names) == 0 {
+		names = s.Projections()
+	}
+
+	for _, name := range names {
+		if _, ok := s.projections[name]; !ok {
+			return nil, fmt.Errorf("service.Replay: %w: %s", appErrors.ErrUnknownProjection, name)
+		}
+	}
+
+	results := make([]domain.ReplayResult, 0, len(names))
+	for _, name := range names {
+		result, err := s.projections[name](ctx, opts)
+		if err != nil {
+			return results, fmt.Errorf("service.Replay: %s: %w", name, err)
+		}
+		results = append(results, result)
+	}
+
+	return results, nil
+}
@@ -0,0 +1,46 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Te8va/wallet/internal/domain"
+	appErrors "github.com/Te8va/wallet/internal/errors"
+	"github.com/Te8va/wallet/internal/service"
+	"github.com/Te8va/wallet/internal/service/mocks"
+)
+
+func TestReplayService_Replay(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockreplayRepo(ctrl)
+	svc := service.NewReplayService(mockRepo)
+
+	opts := domain.ReplayOptions{FromSnapshot: true}
+
+	t.Run("all projections by default", func(t *testing.T) {
+		want := domain.ReplayResult{Projection: "wallet", FromSnapshot: true, Events: 42, Wallets: 3, Changed: 1}
+		mockRepo.EXPECT().ReplayWallets(gomock.Any(), opts).Return(want, nil)
+
+		results, err := svc.Replay(context.Background(), nil, opts)
+		require.NoError(t, err)
+		require.Equal(t, []domain.ReplayResult{want}, results)
+	})
+
+	t.Run("unknown projection", func(t *testing.T) {
+		_, err := svc.Replay(context.Background(), []string{"wallet", "ledger"}, opts)
+		require.ErrorIs(t, err, appErrors.ErrUnknownProjection)
+	})
+
+	t.Run("replay error", func(t *testing.T) {
+		mockRepo.EXPECT().ReplayWallets(gomock.Any(), opts).Return(domain.ReplayResult{}, appErrors.ErrInconsistentEvent)
+
+		_, err := svc.Replay(context.Background(), []string{"wallet"}, opts)
+		require.True(t, errors.Is(err, appErrors.ErrInconsistentEvent))
+	})
+}
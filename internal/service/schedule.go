@@ -0,0 +1,246 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Te8va/wallet/internal/cron"
+	"github.com/Te8va/wallet/internal/domain"
+	appErrors "github.com/Te8va/wallet/internal/errors"
+)
+
+//go:generate mockgen -source=schedule.go -destination=mocks/schedule_mock.gen.go -package=mocks
+type scheduleRepo interface {
+	CreateSchedule(ctx context.Context, s domain.Schedule) (domain.Schedule, error)
+	GetSchedule(ctx context.Context, id int64) (domain.Schedule, error)
+	ListSchedules(ctx context.Context, walletID string) ([]domain.Schedule, error)
+	UpdateScheduleStatus(ctx context.Context, id int64, from []domain.ScheduleStatus, to domain.ScheduleStatus, nextRunAt time.Time) (domain.Schedule, error)
+	ClaimDueSchedules(ctx context.Context, now, leaseUntil time.Time, limit int) ([]domain.Schedule, error)
+	CompleteScheduleRun(ctx context.Context, s domain.Schedule, leaseUntil time.Time) error
+}
+
+type transferer interface {
+	Transfer(ctx context.Context, fromWalletID, toWalletID string, amount int64, idempotencyKey string) error
+}
+
+// SchedulePolicy controls how due schedules are claimed and what happens
+// when an occurrence cannot be executed.
+type SchedulePolicy struct {
+	BatchSize  int
+	Lease      time.Duration
+	MaxRetries int
+	RetryDelay time.Duration
+	OnFailure  domain.FailurePolicy
+}
+
+type ScheduleService struct {
+	repo   scheduleRepo
+	wallet transferer
+	policy SchedulePolicy
+	now    func() time.Time
+}
+
+func NewScheduleService(repo scheduleRepo, wallet transferer, policy SchedulePolicy) *ScheduleService {
+	return &ScheduleService{
+		repo:   repo,
+		wallet: wallet,
+		policy: policy,
+		now:    func() time.Time { return time.Now().UTC() },
+	}
+}
+
+func (s *ScheduleService) CreateSchedule(ctx context.Context, req domain.ScheduleRequest) (domain.Schedule, error) {
+	sch := domain.Schedule{
+		FromWalletID: req.FromWalletID,
+		ToWalletID:   req.ToWalletID,
+		Amount:       req.Amount,
+		Frequency:    req.Frequency,
+		Cron:         req.Cron,
+		StartAt:      req.StartAt.UTC(),
+	}
+
+	if sch.StartAt.IsZero() {
+		sch.StartAt = s.now()
+	}
+
+	sch.NextRunAt = sch.StartAt
+	if sch.Frequency == domain.CRON {
+		expr, err := cron.Parse(sch.Cron)
+		if err != nil {
+			return domain.Schedule{}, fmt.Errorf("%w: %v", appErrors.ErrInvalidCron, err)
+		}
+
+		sch.NextRunAt = expr.Next(sch.StartAt.Add(-time.Nanosecond))
+		if sch.NextRunAt.IsZero() {
+			return domain.Schedule{}, fmt.Errorf("%w: expression never fires", appErrors.ErrInvalidCron)
+		}
+	}
+
+	return s.repo.CreateSchedule(ctx, sch)
+}
+
+func (s *ScheduleService) GetSchedule(ctx context.Context, id int64) (domain.Schedule, error) {
+	return s.repo.GetSchedule(ctx, id)
+}
+
+func (s *ScheduleService) ListSchedules(ctx context.Context, walletID string) ([]domain.Schedule, error) {
+	return s.repo.ListSchedules(ctx, walletID)
+}
+
+func (s *ScheduleService) PauseSchedule(ctx context.Context, id int64) (domain.Schedule, error) {
+	return s.repo.UpdateScheduleStatus(ctx, id, []domain.ScheduleStatus{domain.ScheduleActive}, domain.SchedulePaused, time.Time{})
+}
+
+// ResumeSchedule reactivates a paused schedule. Recurring occurrences that
+// were missed while paused are skipped rather than executed in a burst.
+func (s *ScheduleService) ResumeSchedule(ctx context.Context, id int64) (domain.Schedule, error) {
+	sch, err := s.repo.GetSchedule(ctx, id)
+	if err != nil {
+		return domain.Schedule{}, err
+	}
+
+	if sch.Status != domain.SchedulePaused {
+		return domain.Schedule{}, appErrors.ErrInvalidScheduleState
+	}
+
+	var next time.Time
+	if now := s.now(); sch.NextRunAt.Before(now) && sch.Frequency != domain.ONCE {
+		next = nextOccurrence(sch, now)
+	}
+
+	return s.repo.UpdateScheduleStatus(ctx, id, []domain.ScheduleStatus{domain.SchedulePaused}, domain.ScheduleActive, next)
+}
+
+func (s *ScheduleService) CancelSchedule(ctx context.Context, id int64) (domain.Schedule, error) {
+	return s.repo.UpdateScheduleStatus(ctx, id,
+		[]domain.ScheduleStatus{domain.ScheduleActive, domain.SchedulePaused}, domain.ScheduleCanceled, time.Time{})
+}
+
+// RunDue claims the schedules that are due and executes them through the
+// wallet service. It returns the number of schedules processed. Every
+// occurrence carries its own idempotency key, kept across its retries, so an
+// occurrence re-claimed after a crash or retried after a lost commit is not
+// executed twice.
+func (s *ScheduleService) RunDue(ctx context.Context) (int, error) {
+	now := s.now()
+	leaseUntil := now.Add(s.policy.Lease)
+
+	schedules, err := s.repo.ClaimDueSchedules(ctx, now, leaseUntil, s.policy.BatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("service.RunDue: %w", err)
+	}
+
+	var errs []error
+	for _, sch := range schedules {
+		sch = s.execute(ctx, sch, now)
+
+		if err := s.repo.CompleteScheduleRun(ctx, sch, leaseUntil); err != nil {
+			errs = append(errs, fmt.Errorf("schedule %d: %w", sch.ID, err))
+		}
+	}
+
+	if len(errs) > 0 {
+		return len(schedules), fmt.Errorf("service.RunDue: %w", errors.Join(errs...))
+	}
+
+	return len(schedules), nil
+}
+
+func (s *ScheduleService) execute(ctx context.Context, sch domain.Schedule, now time.Time) domain.Schedule {
+	key := fmt.Sprintf("schedule:%d:%d", sch.ID, sch.OccurrenceAt.Unix())
+	err := s.wallet.Transfer(ctx, sch.FromWalletID, sch.ToWalletID, sch.Amount, key)
+
+	sch.LastRunAt = &now
+
+	if err == nil || errors.Is(err, appErrors.ErrDuplicateOperation) {
+		sch.Attempts = 0
+		sch.LastError = ""
+		return advance(sch, now, domain.ScheduleCompleted)
+	}
+
+	sch.LastError = err.Error()
+
+	if isRetryable(err) && sch.Attempts < s.policy.MaxRetries {
+		sch.Attempts++
+		sch.NextRunAt = now.Add(s.policy.RetryDelay)
+		return sch
+	}
+
+	sch.Attempts = 0
+	if s.policy.OnFailure == domain.FailurePause {
+		sch.Status = domain.SchedulePaused
+		return sch
+	}
+
+	return advance(sch, now, domain.ScheduleFailed)
+}
+
+// advance moves the schedule to its next occurrence, or to the final status
+// when there is none.
+func advance(sch domain.Schedule, now time.Time, final domain.ScheduleStatus) domain.Schedule {
+	next := nextOccurrence(sch, now)
+	if next.IsZero() {
+		sch.Status = final
+		return sch
+	}
+
+	sch.NextRunAt = next
+	sch.OccurrenceAt = next
+	return sch
+}
+
+func isRetryable(err error) bool {
+	return !errors.Is(err, appErrors.ErrWalletNotFound) && !errors.Is(err, appErrors.ErrSameWallet) &&
+		!errors.Is(err, appErrors.ErrIdempotencyKeyReused)
+}
+
+// nextOccurrence returns the first occurrence strictly after the given
+// moment. Occurrences are anchored to StartAt, so retries and late runs do
+// not shift the schedule. The zero time means there are no more occurrences.
+func nextOccurrence(sch domain.Schedule, after time.Time) time.Time {
+	start := sch.StartAt.UTC()
+	after = after.UTC()
+
+	switch sch.Frequency {
+	case domain.DAILY:
+		return nextByPeriod(start, after, 24*time.Hour)
+	case domain.WEEKLY:
+		return nextByPeriod(start, after, 7*24*time.Hour)
+	case domain.MONTHLY:
+		if after.Before(start) {
+			return start
+		}
+		months := (after.Year()-start.Year())*12 + int(after.Month()) - int(start.Month())
+		next := addMonths(start, months)
+		if !next.After(after) {
+			next = addMonths(start, months+1)
+		}
+		return next
+	case domain.CRON:
+		expr, err := cron.Parse(sch.Cron)
+		if err != nil {
+			return time.Time{}
+		}
+		return expr.Next(after)
+	default:
+		return time.Time{}
+	}
+}
+
+func nextByPeriod(start, after time.Time, period time.Duration) time.Time {
+	if after.Before(start) {
+		return start
+	}
+	n := after.Sub(start)/period + 1
+	return start.Add(n * period)
+}
+
+// addMonths adds n months to t keeping the day of month, clamped to the
+// length of the target month (Jan 31 + 1 month is Feb 28/29).
+func addMonths(t time.Time, n int) time.Time {
+	first := time.Date(t.Year(), t.Month()+time.Month(n), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	lastDay := first.AddDate(0, 1, -1).Day()
+	return first.AddDate(0, 0, min(t.Day(), lastDay)-1)
+}
@@ -0,0 +1,168 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Te8va/wallet/internal/domain"
+	appErrors "github.com/Te8va/wallet/internal/errors"
+	"github.com/Te8va/wallet/internal/service"
+	"github.com/Te8va/wallet/internal/service/mocks"
+)
+
+func TestScheduleService_RunDue(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockscheduleRepo(ctrl)
+	mockWallet := mocks.NewMocktransferer(ctrl)
+
+	policy := service.SchedulePolicy{
+		BatchSize:  10,
+		Lease:      time.Minute,
+		MaxRetries: 2,
+		RetryDelay: time.Hour,
+		OnFailure:  domain.FailureSkip,
+	}
+
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	due := time.Date(2020, 1, 5, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name        string
+		policy      domain.FailurePolicy
+		schedule    domain.Schedule
+		transferErr error
+		check       func(t *testing.T, s domain.Schedule)
+	}{
+		{
+			name:     "successful daily run moves to the next day",
+			schedule: domain.Schedule{ID: 1, FromWalletID: "a", ToWalletID: "b", Amount: 100, Frequency: domain.DAILY, StartAt: start, NextRunAt: due, OccurrenceAt: due, Status: domain.ScheduleActive},
+			check: func(t *testing.T, s domain.Schedule) {
+				require.Equal(t, domain.ScheduleActive, s.Status)
+				require.True(t, s.NextRunAt.After(time.Now()))
+				require.Equal(t, 0, s.NextRunAt.Hour())
+				require.Zero(t, s.Attempts)
+				require.Equal(t, s.NextRunAt, s.OccurrenceAt)
+			},
+		},
+		{
+			name:        "insufficient funds is retried",
+			schedule:    domain.Schedule{ID: 2, FromWalletID: "a", ToWalletID: "b", Amount: 100, Frequency: domain.DAILY, StartAt: start, NextRunAt: due, OccurrenceAt: due, Status: domain.ScheduleActive},
+			transferErr: appErrors.ErrInsufficientFunds,
+			check: func(t *testing.T, s domain.Schedule) {
+				require.Equal(t, domain.ScheduleActive, s.Status)
+				require.Equal(t, 1, s.Attempts)
+				require.Equal(t, appErrors.ErrInsufficientFunds.Error(), s.LastError)
+				require.WithinDuration(t, time.Now().Add(time.Hour), s.NextRunAt, time.Minute)
+				require.Equal(t, due, s.OccurrenceAt)
+			},
+		},
+		{
+			name:        "retry keeps the key of its occurrence",
+			schedule:    domain.Schedule{ID: 7, FromWalletID: "a", ToWalletID: "b", Amount: 100, Frequency: domain.DAILY, StartAt: start, NextRunAt: due.Add(time.Hour), OccurrenceAt: due, Status: domain.ScheduleActive, Attempts: 1},
+			transferErr: errors.New("connection reset"),
+			check: func(t *testing.T, s domain.Schedule) {
+				require.Equal(t, 2, s.Attempts)
+				require.Equal(t, due, s.OccurrenceAt)
+			},
+		},
+		{
+			name:        "exhausted retries skip a one-off schedule",
+			schedule:    domain.Schedule{ID: 3, FromWalletID: "a", ToWalletID: "b", Amount: 100, Frequency: domain.ONCE, StartAt: start, NextRunAt: due, OccurrenceAt: due, Status: domain.ScheduleActive, Attempts: 2},
+			transferErr: appErrors.ErrInsufficientFunds,
+			check: func(t *testing.T, s domain.Schedule) {
+				require.Equal(t, domain.ScheduleFailed, s.Status)
+				require.Zero(t, s.Attempts)
+			},
+		},
+		{
+			name:        "permanent failure pauses the schedule",
+			policy:      domain.FailurePause,
+			schedule:    domain.Schedule{ID: 4, FromWalletID: "a", ToWalletID: "b", Amount: 100, Frequency: domain.WEEKLY, StartAt: start, NextRunAt: due, OccurrenceAt: due, Status: domain.ScheduleActive},
+			transferErr: appErrors.ErrWalletNotFound,
+			check: func(t *testing.T, s domain.Schedule) {
+				require.Equal(t, domain.SchedulePaused, s.Status)
+				require.Equal(t, appErrors.ErrWalletNotFound.Error(), s.LastError)
+			},
+		},
+		{
+			name:        "already executed occurrence completes",
+			schedule:    domain.Schedule{ID: 5, FromWalletID: "a", ToWalletID: "b", Amount: 100, Frequency: domain.ONCE, StartAt: start, NextRunAt: due, OccurrenceAt: due, Status: domain.ScheduleActive},
+			transferErr: appErrors.ErrDuplicateOperation,
+			check: func(t *testing.T, s domain.Schedule) {
+				require.Equal(t, domain.ScheduleCompleted, s.Status)
+				require.Empty(t, s.LastError)
+			},
+		},
+		{
+			name:        "occurrence key taken by another operation fails",
+			schedule:    domain.Schedule{ID: 6, FromWalletID: "a", ToWalletID: "b", Amount: 100, Frequency: domain.ONCE, StartAt: start, NextRunAt: due, OccurrenceAt: due, Status: domain.ScheduleActive},
+			transferErr: appErrors.ErrIdempotencyKeyReused,
+			check: func(t *testing.T, s domain.Schedule) {
+				require.Equal(t, domain.ScheduleFailed, s.Status)
+				require.Equal(t, appErrors.ErrIdempotencyKeyReused.Error(), s.LastError)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			p := policy
+			if tc.policy != "" {
+				p.OnFailure = tc.policy
+			}
+			svc := service.NewScheduleService(mockRepo, mockWallet, p)
+
+			var completed domain.Schedule
+			mockRepo.EXPECT().ClaimDueSchedules(gomock.Any(), gomock.Any(), gomock.Any(), 10).Return([]domain.Schedule{tc.schedule}, nil)
+			mockWallet.EXPECT().Transfer(gomock.Any(), "a", "b", int64(100), fmt.Sprintf("schedule:%d:%d", tc.schedule.ID, due.Unix())).Return(tc.transferErr)
+			mockRepo.EXPECT().CompleteScheduleRun(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ context.Context, s domain.Schedule, _ time.Time) error {
+					completed = s
+					return nil
+				})
+
+			n, err := svc.RunDue(context.Background())
+			require.NoError(t, err)
+			require.Equal(t, 1, n)
+			tc.check(t, completed)
+		})
+	}
+}
+
+func TestScheduleService_CreateSchedule(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockscheduleRepo(ctrl)
+	svc := service.NewScheduleService(mockRepo, mocks.NewMocktransferer(ctrl), service.SchedulePolicy{})
+
+	start := time.Date(2025, 1, 31, 10, 30, 0, 0, time.UTC)
+
+	t.Run("cron schedule starts at the first activation", func(t *testing.T) {
+		mockRepo.EXPECT().CreateSchedule(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, s domain.Schedule) (domain.Schedule, error) {
+				return s, nil
+			})
+
+		s, err := svc.CreateSchedule(context.Background(), domain.ScheduleRequest{
+			FromWalletID: "a", ToWalletID: "b", Amount: 100, Frequency: domain.CRON, Cron: "0 9 * * *", StartAt: start,
+		})
+		require.NoError(t, err)
+		require.Equal(t, time.Date(2025, 2, 1, 9, 0, 0, 0, time.UTC), s.NextRunAt)
+	})
+
+	t.Run("invalid cron expression", func(t *testing.T) {
+		_, err := svc.CreateSchedule(context.Background(), domain.ScheduleRequest{
+			FromWalletID: "a", ToWalletID: "b", Amount: 100, Frequency: domain.CRON, Cron: "0 9 * *", StartAt: start,
+		})
+		require.True(t, errors.Is(err, appErrors.ErrInvalidCron))
+	})
+}
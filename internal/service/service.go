@@ -2,28 +2,226 @@ package service
 
 import (
 	"context"
+	"errors"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
 
 	"github.com/Te8va/wallet/internal/domain"
+	appErrors "github.com/Te8va/wallet/internal/errors"
+	"github.com/Te8va/wallet/internal/logging"
+	"github.com/Te8va/wallet/internal/metrics"
+	"github.com/Te8va/wallet/internal/tracing"
 )
 
 //go:generate mockgen -source=service.go -destination=mocks/wallet_mock.gen.go -package=mocks
 type walletServ interface {
-	ProcessTransaction(ctx context.Context, walletID string, opType domain.OperationType, amount int64) error
-	GetBalance(ctx context.Context, walletID string) (int64, error)
+	ProcessTransaction(ctx context.Context, req domain.TransactionRequest) (domain.TransactionResult, error)
+	PocketBalances(ctx context.Context, walletID string) (domain.PocketBalances, error)
+	BalanceAsOf(ctx context.Context, walletID string, asOf time.Time) (int64, error)
+	GetTransaction(ctx context.Context, id int64) (domain.Transaction, error)
+	SearchTransactions(ctx context.Context, filter domain.TransactionFilter) (domain.TransactionHistory, error)
+	Transfer(ctx context.Context, fromWalletID, toWalletID string, amount int64, idempotencyKey string) error
+	Split(ctx context.Context, split domain.Split) (domain.SplitPayment, error)
+}
+
+// cashbacker pays cashback for an operation once it has been posted.
+type cashbacker interface {
+	ApplyCashback(ctx context.Context, t domain.Transaction) error
+}
+
+// pointsEarner awards loyalty points for an operation once it has been
+// posted.
+type pointsEarner interface {
+	EarnPoints(ctx context.Context, t domain.Transaction) error
+}
+
+var tracer = otel.Tracer(tracing.InstrumentationName)
+
+// WalletPolicy configures how operations are posted.
+type WalletPolicy struct {
+	// UniqueReference rejects a second operation with the same reference
+	// within a wallet, replaying the first one when it is identical.
+	UniqueReference bool
 }
 
 type WalletService struct {
-	repo walletServ
+	repo     walletServ
+	policy   WalletPolicy
+	cashback cashbacker
+	loyalty  pointsEarner
+}
+
+func NewWalletService(repo walletServ, policy WalletPolicy) *WalletService {
+	return &WalletService{repo: repo, policy: policy}
+}
+
+// SetCashback makes ProcessTransaction pay cashback through c after each
+// operation it posts.
+func (s *WalletService) SetCashback(c cashbacker) {
+	s.cashback = c
+}
+
+// SetLoyalty makes ProcessTransaction award loyalty points through l after
+// each operation it posts.
+func (s *WalletService) SetLoyalty(l pointsEarner) {
+	s.loyalty = l
+}
+
+func (s *WalletService) ProcessTransaction(ctx context.Context, req domain.TransactionRequest) (res domain.TransactionResult, err error) {
+	ctx, span := tracer.Start(ctx, "WalletService.ProcessTransaction", trace.WithAttributes(
+		attribute.String("wallet.id", req.WalletID),
+		attribute.String("wallet.operation_type", string(req.OperationType)),
+		attribute.Int64("wallet.amount", req.Amount),
+	))
+	defer func() { tracing.End(span, err) }()
+
+	req.UniqueReference = s.policy.UniqueReference
+	res, err = s.repo.ProcessTransaction(ctx, req)
+
+	recorded := err
+	if err == nil && res.Replayed {
+		recorded = appErrors.ErrDuplicateOperation
+	}
+	s.record(ctx, string(req.OperationType), recorded, zap.String("wallet_id", req.WalletID), zap.Int64("amount", req.Amount))
+
+	// A replayed request runs the hooks again, which pays whatever a failure
+	// left unpaid the first time.
+	if err == nil {
+		s.afterPost(ctx, res.Transaction)
+	}
+
+	return res, err
 }
 
-func NewWalletService(repo walletServ) *WalletService {
-	return &WalletService{repo: repo}
+// RecordPosted counts an operation posted outside ProcessTransaction, such
+// as a batch item, and runs the hooks ProcessTransaction runs after it.
+func (s *WalletService) RecordPosted(ctx context.Context, t domain.Transaction) {
+	ctx, span := tracer.Start(ctx, "WalletService.RecordPosted", trace.WithAttributes(
+		attribute.String("wallet.id", t.WalletID),
+		attribute.String("wallet.operation_type", string(t.OperationType)),
+		attribute.Int64("wallet.transaction_id", t.ID),
+	))
+	defer span.End()
+
+	s.record(ctx, string(t.OperationType), nil)
+	s.afterPost(ctx, t)
+}
+
+// afterPost pays cashback and awards loyalty points for a committed
+// operation. Neither fails the operation; errors are only logged.
+func (s *WalletService) afterPost(ctx context.Context, t domain.Transaction) {
+	if s.cashback != nil {
+		if err := s.cashback.ApplyCashback(context.WithoutCancel(ctx), t); err != nil {
+			logging.FromContext(ctx).Error("Failed to apply cashback", zap.Int64("transaction_id", t.ID), zap.Error(err))
+		}
+	}
+
+	if s.loyalty != nil {
+		if err := s.loyalty.EarnPoints(context.WithoutCancel(ctx), t); err != nil {
+			logging.FromContext(ctx).Error("Failed to earn loyalty points", zap.Int64("transaction_id", t.ID), zap.Error(err))
+		}
+	}
+}
+
+// GetBalance returns the wallet balance broken down into the main balance
+// and the pockets.
+func (s *WalletService) GetBalance(ctx context.Context, walletID string) (balance domain.PocketBalances, err error) {
+	ctx, span := tracer.Start(ctx, "WalletService.GetBalance", trace.WithAttributes(
+		attribute.String("wallet.id", walletID),
+	))
+	defer func() { tracing.End(span, err) }()
+
+	balance, err = s.repo.PocketBalances(ctx, walletID)
+	if err != nil && !errors.Is(err, appErrors.ErrWalletNotFound) {
+		logging.FromContext(ctx).Error("Failed to get balance", zap.String("wallet_id", walletID), zap.Error(err))
+	}
+	return balance, err
+}
+
+// GetBalanceAsOf returns the wallet balance after every operation posted up
+// to and including asOf.
+func (s *WalletService) GetBalanceAsOf(ctx context.Context, walletID string, asOf time.Time) (balance int64, err error) {
+	ctx, span := tracer.Start(ctx, "WalletService.GetBalanceAsOf", trace.WithAttributes(
+		attribute.String("wallet.id", walletID),
+		attribute.String("wallet.as_of", asOf.Format(time.RFC3339Nano)),
+	))
+	defer func() { tracing.End(span, err) }()
+
+	balance, err = s.repo.BalanceAsOf(ctx, walletID, asOf)
+	if err != nil && !errors.Is(err, appErrors.ErrWalletNotFound) {
+		logging.FromContext(ctx).Error("Failed to get balance", zap.String("wallet_id", walletID),
+			zap.Time("as_of", asOf), zap.Error(err))
+	}
+	return balance, err
 }
 
-func (s *WalletService) ProcessTransaction(ctx context.Context, walletID string, opType domain.OperationType, amount int64) error {
-	return s.repo.ProcessTransaction(ctx, walletID, opType, amount)
+func (s *WalletService) GetTransaction(ctx context.Context, id int64) (t domain.Transaction, err error) {
+	ctx, span := tracer.Start(ctx, "WalletService.GetTransaction", trace.WithAttributes(
+		attribute.Int64("wallet.transaction_id", id),
+	))
+	defer func() { tracing.End(span, err) }()
+
+	t, err = s.repo.GetTransaction(ctx, id)
+	if err != nil && !errors.Is(err, appErrors.ErrTransactionNotFound) {
+		logging.FromContext(ctx).Error("Failed to get transaction", zap.Int64("transaction_id", id), zap.Error(err))
+	}
+	return t, err
+}
+
+// SearchTransactions returns a page of the wallet history matching filter.
+func (s *WalletService) SearchTransactions(ctx context.Context, filter domain.TransactionFilter) (history domain.TransactionHistory, err error) {
+	ctx, span := tracer.Start(ctx, "WalletService.SearchTransactions", trace.WithAttributes(
+		attribute.String("wallet.id", filter.WalletID),
+	))
+	defer func() { tracing.End(span, err) }()
+
+	history, err = s.repo.SearchTransactions(ctx, filter)
+	if err != nil && !errors.Is(err, appErrors.ErrWalletNotFound) {
+		logging.FromContext(ctx).Error("Failed to search transactions", zap.String("wallet_id", filter.WalletID), zap.Error(err))
+	}
+	return history, err
+}
+
+func (s *WalletService) Transfer(ctx context.Context, fromWalletID, toWalletID string, amount int64, idempotencyKey string) (err error) {
+	ctx, span := tracer.Start(ctx, "WalletService.Transfer", trace.WithAttributes(
+		attribute.String("wallet.from_id", fromWalletID),
+		attribute.String("wallet.to_id", toWalletID),
+		attribute.Int64("wallet.amount", amount),
+	))
+	defer func() { tracing.End(span, err) }()
+
+	err = s.repo.Transfer(ctx, fromWalletID, toWalletID, amount, idempotencyKey)
+	s.record(ctx, "TRANSFER", err, zap.String("from_wallet_id", fromWalletID), zap.String("to_wallet_id", toWalletID),
+		zap.Int64("amount", amount))
+	return err
+}
+
+// record counts the operation by outcome and logs unexpected failures with
+// the request's logger.
+func (s *WalletService) record(ctx context.Context, opType string, err error, fields ...zap.Field) {
+	result := outcome(err)
+	metrics.Transactions.WithLabelValues(opType, result).Inc()
+
+	if result == metrics.OutcomeError {
+		fields = append(fields, zap.String("operation_type", opType), zap.Error(err))
+		logging.FromContext(ctx).Error("Wallet operation failed", fields...)
+	}
 }
 
-func (s *WalletService) GetBalance(ctx context.Context, walletID string) (int64, error) {
-	return s.repo.GetBalance(ctx, walletID)
+// outcome classifies the result of a wallet operation for metrics.
+func outcome(err error) string {
+	switch {
+	case err == nil:
+		return metrics.OutcomeSuccess
+	case errors.Is(err, appErrors.ErrInsufficientFunds):
+		return metrics.OutcomeInsufficientFunds
+	case errors.Is(err, appErrors.ErrDuplicateOperation):
+		return metrics.OutcomeDuplicate
+	default:
+		return metrics.OutcomeError
+	}
 }
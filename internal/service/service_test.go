@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
@@ -19,7 +20,7 @@ func TestWalletService_ProcessTransaction(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockwalletServ(ctrl)
-	svc := service.NewWalletService(mockRepo)
+	svc := service.NewWalletService(mockRepo, service.WalletPolicy{})
 
 	walletID := "123e4567-e89b-12d3-a456-426614174000"
 
@@ -35,7 +36,7 @@ func TestWalletService_ProcessTransaction(t *testing.T) {
 			opType: domain.DEPOSIT,
 			amount: 1000,
 			mockRepo: func() {
-				mockRepo.EXPECT().ProcessTransaction(gomock.Any(), walletID, domain.DEPOSIT, int64(1000)).Return(nil)
+				mockRepo.EXPECT().ProcessTransaction(gomock.Any(), domain.TransactionRequest{WalletID: walletID, OperationType: domain.DEPOSIT, Amount: 1000}).Return(domain.TransactionResult{}, nil)
 			},
 			expectedErr: nil,
 		},
@@ -44,7 +45,7 @@ func TestWalletService_ProcessTransaction(t *testing.T) {
 			opType: domain.WITHDRAW,
 			amount: 500,
 			mockRepo: func() {
-				mockRepo.EXPECT().ProcessTransaction(gomock.Any(), walletID, domain.WITHDRAW, int64(500)).Return(nil)
+				mockRepo.EXPECT().ProcessTransaction(gomock.Any(), domain.TransactionRequest{WalletID: walletID, OperationType: domain.WITHDRAW, Amount: 500}).Return(domain.TransactionResult{}, nil)
 			},
 			expectedErr: nil,
 		},
@@ -53,7 +54,7 @@ func TestWalletService_ProcessTransaction(t *testing.T) {
 			opType: domain.WITHDRAW,
 			amount: 5000,
 			mockRepo: func() {
-				mockRepo.EXPECT().ProcessTransaction(gomock.Any(), walletID, domain.WITHDRAW, int64(5000)).Return(appErrors.ErrInsufficientFunds)
+				mockRepo.EXPECT().ProcessTransaction(gomock.Any(), domain.TransactionRequest{WalletID: walletID, OperationType: domain.WITHDRAW, Amount: 5000}).Return(domain.TransactionResult{}, appErrors.ErrInsufficientFunds)
 			},
 			expectedErr: appErrors.ErrInsufficientFunds,
 		},
@@ -62,7 +63,7 @@ func TestWalletService_ProcessTransaction(t *testing.T) {
 			opType: domain.DEPOSIT,
 			amount: 100,
 			mockRepo: func() {
-				mockRepo.EXPECT().ProcessTransaction(gomock.Any(), walletID, domain.DEPOSIT, int64(100)).Return(appErrors.ErrWalletNotFound)
+				mockRepo.EXPECT().ProcessTransaction(gomock.Any(), domain.TransactionRequest{WalletID: walletID, OperationType: domain.DEPOSIT, Amount: 100}).Return(domain.TransactionResult{}, appErrors.ErrWalletNotFound)
 			},
 			expectedErr: appErrors.ErrWalletNotFound,
 		},
@@ -71,7 +72,7 @@ func TestWalletService_ProcessTransaction(t *testing.T) {
 			opType: domain.DEPOSIT,
 			amount: 100,
 			mockRepo: func() {
-				mockRepo.EXPECT().ProcessTransaction(gomock.Any(), walletID, domain.DEPOSIT, int64(100)).Return(errors.New("database connection error"))
+				mockRepo.EXPECT().ProcessTransaction(gomock.Any(), domain.TransactionRequest{WalletID: walletID, OperationType: domain.DEPOSIT, Amount: 100}).Return(domain.TransactionResult{}, errors.New("database connection error"))
 			},
 			expectedErr: errors.New("database connection error"),
 		},
@@ -81,7 +82,11 @@ func TestWalletService_ProcessTransaction(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			tc.mockRepo()
 
-			err := svc.ProcessTransaction(context.Background(), walletID, tc.opType, tc.amount)
+			_, err := svc.ProcessTransaction(context.Background(), domain.TransactionRequest{
+				WalletID:      walletID,
+				OperationType: tc.opType,
+				Amount:        tc.amount,
+			})
 
 			if tc.expectedErr != nil {
 				require.Error(t, err)
@@ -93,45 +98,147 @@ func TestWalletService_ProcessTransaction(t *testing.T) {
 	}
 }
 
+func TestWalletService_ProcessTransactionUniqueReference(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockwalletServ(ctrl)
+	svc := service.NewWalletService(mockRepo, service.WalletPolicy{UniqueReference: true})
+
+	req := domain.TransactionRequest{
+		WalletID:      "123e4567-e89b-12d3-a456-426614174000",
+		OperationType: domain.DEPOSIT,
+		Amount:        1000,
+		Reference:     "invoice-17",
+	}
+	want := req
+	want.UniqueReference = true
+
+	mockRepo.EXPECT().ProcessTransaction(gomock.Any(), want).Return(domain.TransactionResult{}, appErrors.ErrDuplicateReference)
+
+	_, err := svc.ProcessTransaction(context.Background(), req)
+	require.ErrorIs(t, err, appErrors.ErrDuplicateReference)
+}
+
+func TestWalletService_ProcessTransactionCashback(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockwalletServ(ctrl)
+	mockCashback := mocks.NewMockcashbacker(ctrl)
+	svc := service.NewWalletService(mockRepo, service.WalletPolicy{})
+	svc.SetCashback(mockCashback)
+
+	req := domain.TransactionRequest{WalletID: "wallet", OperationType: domain.WITHDRAW, Amount: 500}
+	posted := domain.Transaction{ID: 9, WalletID: "wallet", OperationType: domain.WITHDRAW, Amount: -500}
+
+	// A failed cashback does not fail the posted withdrawal.
+	mockRepo.EXPECT().ProcessTransaction(gomock.Any(), req).Return(domain.TransactionResult{Transaction: posted}, nil)
+	mockCashback.EXPECT().ApplyCashback(gomock.Any(), posted).Return(errors.New("connection reset"))
+
+	res, err := svc.ProcessTransaction(context.Background(), req)
+	require.NoError(t, err)
+	require.Equal(t, posted, res.Transaction)
+
+	// A rejected withdrawal earns nothing.
+	mockRepo.EXPECT().ProcessTransaction(gomock.Any(), req).Return(domain.TransactionResult{}, appErrors.ErrInsufficientFunds)
+
+	_, err = svc.ProcessTransaction(context.Background(), req)
+	require.ErrorIs(t, err, appErrors.ErrInsufficientFunds)
+}
+
+func TestWalletService_ProcessTransactionLoyalty(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockwalletServ(ctrl)
+	mockCashback := mocks.NewMockcashbacker(ctrl)
+	mockLoyalty := mocks.NewMockpointsEarner(ctrl)
+	svc := service.NewWalletService(mockRepo, service.WalletPolicy{})
+	svc.SetCashback(mockCashback)
+	svc.SetLoyalty(mockLoyalty)
+
+	req := domain.TransactionRequest{WalletID: "wallet", OperationType: domain.WITHDRAW, Amount: 500}
+	posted := domain.Transaction{ID: 9, WalletID: "wallet", OperationType: domain.WITHDRAW, Amount: -500}
+
+	// Points are earned even when cashback fails, and a failure to earn
+	// them does not fail the posted withdrawal.
+	mockRepo.EXPECT().ProcessTransaction(gomock.Any(), req).Return(domain.TransactionResult{Transaction: posted}, nil)
+	mockCashback.EXPECT().ApplyCashback(gomock.Any(), posted).Return(errors.New("connection reset"))
+	mockLoyalty.EXPECT().EarnPoints(gomock.Any(), posted).Return(errors.New("connection reset"))
+
+	res, err := svc.ProcessTransaction(context.Background(), req)
+	require.NoError(t, err)
+	require.Equal(t, posted, res.Transaction)
+
+	// A rejected withdrawal earns nothing.
+	mockRepo.EXPECT().ProcessTransaction(gomock.Any(), req).Return(domain.TransactionResult{}, appErrors.ErrInsufficientFunds)
+
+	_, err = svc.ProcessTransaction(context.Background(), req)
+	require.ErrorIs(t, err, appErrors.ErrInsufficientFunds)
+}
+
+func TestWalletService_RecordPosted(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCashback := mocks.NewMockcashbacker(ctrl)
+	mockLoyalty := mocks.NewMockpointsEarner(ctrl)
+	svc := service.NewWalletService(mocks.NewMockwalletServ(ctrl), service.WalletPolicy{})
+	svc.SetCashback(mockCashback)
+	svc.SetLoyalty(mockLoyalty)
+
+	posted := domain.Transaction{ID: 12, WalletID: "wallet", OperationType: domain.WITHDRAW, Amount: -500}
+
+	mockCashback.EXPECT().ApplyCashback(gomock.Any(), posted).Return(nil)
+	mockLoyalty.EXPECT().EarnPoints(gomock.Any(), posted).Return(nil)
+
+	svc.RecordPosted(context.Background(), posted)
+}
+
 func TestWalletService_GetBalance(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockwalletServ(ctrl)
-	svc := service.NewWalletService(mockRepo)
+	svc := service.NewWalletService(mockRepo, service.WalletPolicy{})
 
 	walletID := "123e4567-e89b-12d3-a456-426614174000"
 
 	testCases := []struct {
 		name            string
 		mockRepo        func()
-		expectedBalance int64
+		expectedBalance domain.PocketBalances
 		expectedErr     error
 	}{
 		{
 			name: "success wallet exists",
 			mockRepo: func() {
-				mockRepo.EXPECT().GetBalance(gomock.Any(), walletID).Return(int64(1500), nil)
+				mockRepo.EXPECT().PocketBalances(gomock.Any(), walletID).Return(domain.PocketBalances{
+					WalletID: walletID, Balance: 1500, MainBalance: 1200,
+					Pockets: []domain.Pocket{{WalletID: walletID, Name: "vacation", Balance: 300}},
+				}, nil)
+			},
+			expectedBalance: domain.PocketBalances{
+				WalletID: walletID, Balance: 1500, MainBalance: 1200,
+				Pockets: []domain.Pocket{{WalletID: walletID, Name: "vacation", Balance: 300}},
 			},
-			expectedBalance: 1500,
-			expectedErr:     nil,
+			expectedErr: nil,
 		},
 		{
 			name: "wallet not found",
 			mockRepo: func() {
-				mockRepo.EXPECT().GetBalance(gomock.Any(), walletID).Return(int64(0), appErrors.ErrWalletNotFound)
+				mockRepo.EXPECT().PocketBalances(gomock.Any(), walletID).Return(domain.PocketBalances{}, appErrors.ErrWalletNotFound)
 			},
-			expectedBalance: 0,
-			expectedErr:     appErrors.ErrWalletNotFound,
+			expectedErr: appErrors.ErrWalletNotFound,
 		},
 		{
 			name: "repository error",
 			mockRepo: func() {
 				mockRepo.
-					EXPECT().GetBalance(gomock.Any(), walletID).Return(int64(0), errors.New("database error"))
+					EXPECT().PocketBalances(gomock.Any(), walletID).Return(domain.PocketBalances{}, errors.New("database error"))
 			},
-			expectedBalance: 0,
-			expectedErr:     errors.New("database error"),
+			expectedErr: errors.New("database error"),
 		},
 	}
 
@@ -152,3 +259,47 @@ func TestWalletService_GetBalance(t *testing.T) {
 		})
 	}
 }
+
+func TestWalletService_GetBalanceAsOf(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockwalletServ(ctrl)
+	svc := service.NewWalletService(mockRepo, service.WalletPolicy{})
+
+	walletID := "123e4567-e89b-12d3-a456-426614174000"
+	asOf := time.Date(2025, 3, 31, 23, 59, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name            string
+		mockRepo        func()
+		expectedBalance int64
+		expectedErr     error
+	}{
+		{
+			name: "balance from journal",
+			mockRepo: func() {
+				mockRepo.EXPECT().BalanceAsOf(gomock.Any(), walletID, asOf).Return(int64(700), nil)
+			},
+			expectedBalance: 700,
+		},
+		{
+			name: "wallet not found",
+			mockRepo: func() {
+				mockRepo.EXPECT().BalanceAsOf(gomock.Any(), walletID, asOf).Return(int64(0), appErrors.ErrWalletNotFound)
+			},
+			expectedErr: appErrors.ErrWalletNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mockRepo()
+
+			balance, err := svc.GetBalanceAsOf(context.Background(), walletID, asOf)
+
+			require.Equal(t, tc.expectedBalance, balance)
+			require.ErrorIs(t, err, tc.expectedErr)
+		})
+	}
+}
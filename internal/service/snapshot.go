@@ -0,0 +1,34 @@
+package service
+
+import (
+	"context"
+	"fmt"
+)
+
+//go:generate mockgen -source=snapshot.go -destination=mocks/snapshot_mock.gen.go -package=mocks
+type snapshotRepo interface {
+	TakeSnapshots(ctx context.Context, minEntries int) (int, error)
+}
+
+type SnapshotService struct {
+	repo       snapshotRepo
+	minEntries int
+}
+
+// NewSnapshotService creates a service that snapshots wallet balances once
+// they have at least minEntries new journal entries.
+func NewSnapshotService(repo snapshotRepo, minEntries int) *SnapshotService {
+	if minEntries < 1 {
+		minEntries = 1
+	}
+	return &SnapshotService{repo: repo, minEntries: minEntries}
+}
+
+// TakeSnapshots records balance snapshots and reports how many were taken.
+func (s *SnapshotService) TakeSnapshots(ctx context.Context) (int, error) {
+	n, err := s.repo.TakeSnapshots(ctx, s.minEntries)
+	if err != nil {
+		return 0, fmt.Errorf("service.TakeSnapshots: %w", err)
+	}
+	return n, nil
+}
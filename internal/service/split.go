@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"math/bits"
+	"slices"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+
+	"github.com/Te8va/wallet/internal/domain"
+	appErrors "github.com/Te8va/wallet/internal/errors"
+	"github.com/Te8va/wallet/internal/tracing"
+)
+
+const fullShareBps = 10000
+
+// Split debits the payer and credits the recipients in one transaction.
+// Recipients with a fixed amount are served first; the rest of the payment
+// is divided by shares, which must add up to 100%.
+func (s *WalletService) Split(ctx context.Context, req domain.SplitRequest) (payment domain.SplitPayment, err error) {
+	ctx, span := tracer.Start(ctx, "WalletService.Split", trace.WithAttributes(
+		attribute.String("wallet.id", req.WalletID),
+		attribute.Int64("wallet.amount", req.Amount),
+		attribute.Int("wallet.recipients", len(req.Recipients)),
+	))
+	defer func() { tracing.End(span, err) }()
+
+	legs, err := allocateSplit(req.Amount, req.Recipients)
+	if err != nil {
+		return domain.SplitPayment{}, err
+	}
+
+	for _, leg := range legs {
+		if leg.WalletID == req.WalletID {
+			return domain.SplitPayment{}, appErrors.ErrSameWallet
+		}
+	}
+
+	payment, err = s.repo.Split(ctx, domain.Split{
+		WalletID:       req.WalletID,
+		Amount:         req.Amount,
+		IdempotencyKey: req.IdempotencyKey,
+		Reference:      req.Reference,
+		Description:    req.Description,
+		Legs:           legs,
+	})
+
+	recorded := err
+	if err == nil && payment.Replayed {
+		recorded = appErrors.ErrDuplicateOperation
+	}
+	s.record(ctx, "SPLIT", recorded, zap.String("wallet_id", req.WalletID), zap.Int64("amount", req.Amount))
+	return payment, err
+}
+
+// allocateSplit turns recipients into legs that add up to amount. Shares
+// are rounded down and the units left over go one each to the shares with
+// the largest rounded-off fraction, earlier recipients first on ties, so the
+// same request always splits the same way. Legs that round to zero are
+// dropped.
+func allocateSplit(amount int64, recipients []domain.SplitRecipient) ([]domain.SplitLeg, error) {
+	rest := amount
+	var shares int64
+	for _, r := range recipients {
+		switch {
+		case r.ShareBps < 0 || r.Amount < 0 || (r.ShareBps > 0) == (r.Amount > 0):
+			return nil, appErrors.ErrInvalidSplit
+		case r.Amount > rest:
+			return nil, appErrors.ErrInvalidSplit
+		case r.Amount > 0:
+			rest -= r.Amount
+		case r.ShareBps > fullShareBps-shares:
+			return nil, appErrors.ErrInvalidSplit
+		default:
+			shares += r.ShareBps
+		}
+	}
+
+	if shares != fullShareBps && (shares != 0 || rest != 0) {
+		return nil, appErrors.ErrInvalidSplit
+	}
+
+	legs := make([]domain.SplitLeg, len(recipients))
+	fractions := make([]uint64, len(recipients))
+	var byShare []int
+	left := rest
+	for i, r := range recipients {
+		legs[i] = domain.SplitLeg{WalletID: r.WalletID, Amount: r.Amount}
+		if r.ShareBps == 0 {
+			continue
+		}
+
+		// rest*share/10000 does not exceed rest, but the product may
+		// overflow int64.
+		hi, lo := bits.Mul64(uint64(rest), uint64(r.ShareBps))
+		quo, rem := bits.Div64(hi, lo, fullShareBps)
+		legs[i].Amount = int64(quo)
+		fractions[i] = rem
+		left -= legs[i].Amount
+		byShare = append(byShare, i)
+	}
+
+	slices.SortStableFunc(byShare, func(a, b int) int {
+		switch {
+		case fractions[a] > fractions[b]:
+			return -1
+		case fractions[a] < fractions[b]:
+			return 1
+		}
+		return 0
+	})
+	for _, i := range byShare[:left] {
+		legs[i].Amount++
+	}
+
+	return slices.DeleteFunc(legs, func(leg domain.SplitLeg) bool { return leg.Amount == 0 }), nil
+}
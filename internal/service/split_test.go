@@ -0,0 +1,122 @@
+package service_test
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Te8va/wallet/internal/domain"
+	appErrors "github.com/Te8va/wallet/internal/errors"
+	"github.com/Te8va/wallet/internal/service"
+	"github.com/Te8va/wallet/internal/service/mocks"
+)
+
+func TestWalletService_Split(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockwalletServ(ctrl)
+	svc := service.NewWalletService(mockRepo, service.WalletPolicy{})
+
+	leg := func(walletID string, amount int64) domain.SplitLeg {
+		return domain.SplitLeg{WalletID: walletID, Amount: amount}
+	}
+
+	testCases := []struct {
+		name        string
+		amount      int64
+		recipients  []domain.SplitRecipient
+		wantLegs    []domain.SplitLeg
+		expectedErr error
+	}{
+		{
+			name:   "commission and shares",
+			amount: 1000,
+			recipients: []domain.SplitRecipient{
+				{WalletID: "platform", Amount: 100},
+				{WalletID: "a", ShareBps: 3333},
+				{WalletID: "b", ShareBps: 3333},
+				{WalletID: "c", ShareBps: 3334},
+			},
+			wantLegs: []domain.SplitLeg{leg("platform", 100), leg("a", 300), leg("b", 300), leg("c", 300)},
+		},
+		{
+			name:   "remainder goes to the largest fraction, then the earlier recipient",
+			amount: 2,
+			recipients: []domain.SplitRecipient{
+				{WalletID: "a", ShareBps: 3333},
+				{WalletID: "b", ShareBps: 3333},
+				{WalletID: "c", ShareBps: 3334},
+			},
+			wantLegs: []domain.SplitLeg{leg("a", 1), leg("c", 1)},
+		},
+		{
+			name:   "fixed amounts only",
+			amount: 500,
+			recipients: []domain.SplitRecipient{
+				{WalletID: "a", Amount: 200},
+				{WalletID: "b", Amount: 300},
+			},
+			wantLegs: []domain.SplitLeg{leg("a", 200), leg("b", 300)},
+		},
+		{
+			name:   "large amount does not overflow",
+			amount: math.MaxInt64,
+			recipients: []domain.SplitRecipient{
+				{WalletID: "a", ShareBps: 5000},
+				{WalletID: "b", ShareBps: 5000},
+			},
+			wantLegs: []domain.SplitLeg{leg("a", math.MaxInt64/2+1), leg("b", math.MaxInt64/2)},
+		},
+		{
+			name:   "shares below 100%",
+			amount: 1000,
+			recipients: []domain.SplitRecipient{
+				{WalletID: "a", ShareBps: 5000},
+				{WalletID: "b", ShareBps: 4000},
+			},
+			expectedErr: appErrors.ErrInvalidSplit,
+		},
+		{
+			name:        "fixed amounts short of the payment",
+			amount:      1000,
+			recipients:  []domain.SplitRecipient{{WalletID: "a", Amount: 900}},
+			expectedErr: appErrors.ErrInvalidSplit,
+		},
+		{
+			name:   "fixed amounts above the payment",
+			amount: 1000,
+			recipients: []domain.SplitRecipient{
+				{WalletID: "a", Amount: 900},
+				{WalletID: "b", Amount: 200},
+			},
+			expectedErr: appErrors.ErrInvalidSplit,
+		},
+		{
+			name:        "payer is a recipient",
+			amount:      1000,
+			recipients:  []domain.SplitRecipient{{WalletID: "payer", ShareBps: 10000}},
+			expectedErr: appErrors.ErrSameWallet,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.wantLegs != nil {
+				mockRepo.EXPECT().Split(gomock.Any(), domain.Split{WalletID: "payer", Amount: tc.amount, Legs: tc.wantLegs}).
+					Return(domain.SplitPayment{}, nil)
+			}
+
+			_, err := svc.Split(context.Background(), domain.SplitRequest{
+				WalletID:   "payer",
+				Amount:     tc.amount,
+				Recipients: tc.recipients,
+			})
+
+			require.ErrorIs(t, err, tc.expectedErr)
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Te8va/wallet/internal/domain"
+)
+
+//go:generate mockgen -source=statement.go -destination=mocks/statement_mock.gen.go -package=mocks
+type statementRepo interface {
+	GetBalance(ctx context.Context, walletID string) (int64, error)
+	BalanceAt(ctx context.Context, walletID string, at time.Time) (int64, error)
+	ListTransactions(ctx context.Context, walletID string, from, to time.Time) ([]domain.Transaction, error)
+}
+
+type StatementService struct {
+	repo statementRepo
+}
+
+func NewStatementService(repo statementRepo) *StatementService {
+	return &StatementService{repo: repo}
+}
+
+// GetStatement builds the statement of the wallet for the period [from, to)
+// from the transaction journal.
+func (s *StatementService) GetStatement(ctx context.Context, walletID string, from, to time.Time) (domain.Statement, error) {
+	if _, err := s.repo.GetBalance(ctx, walletID); err != nil {
+		return domain.Statement{}, err
+	}
+
+	opening, err := s.repo.BalanceAt(ctx, walletID, from)
+	if err != nil {
+		return domain.Statement{}, fmt.Errorf("service.GetStatement: %w", err)
+	}
+
+	transactions, err := s.repo.ListTransactions(ctx, walletID, from, to)
+	if err != nil {
+		return domain.Statement{}, fmt.Errorf("service.GetStatement: %w", err)
+	}
+
+	return BuildStatement(walletID, from, to, opening, transactions), nil
+}
+
+// BuildStatement assembles a statement from the opening balance and the
+// period's transactions, which must be ordered oldest first.
+func BuildStatement(walletID string, from, to time.Time, opening int64, transactions []domain.Transaction) domain.Statement {
+	st := domain.Statement{
+		WalletID:       walletID,
+		From:           from,
+		To:             to,
+		OpeningBalance: opening,
+		ClosingBalance: opening,
+		Transactions:   transactions,
+		Totals:         []domain.OperationTotal{},
+	}
+
+	if st.Transactions == nil {
+		st.Transactions = []domain.Transaction{}
+	}
+
+	totals := make(map[domain.OperationType]*domain.OperationTotal)
+	for _, t := range transactions {
+		total, ok := totals[t.OperationType]
+		if !ok {
+			total = &domain.OperationTotal{OperationType: t.OperationType}
+			totals[t.OperationType] = total
+		}
+		total.Count++
+		total.Amount += t.Amount
+		st.ClosingBalance = t.Balance
+	}
+
+	for _, total := range totals {
+		st.Totals = append(st.Totals, *total)
+	}
+	slices.SortFunc(st.Totals, func(a, b domain.OperationTotal) int {
+		return strings.Compare(string(a.OperationType), string(b.OperationType))
+	})
+
+	return st
+}
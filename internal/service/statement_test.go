@@ -0,0 +1,68 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Te8va/wallet/internal/domain"
+	appErrors "github.com/Te8va/wallet/internal/errors"
+	"github.com/Te8va/wallet/internal/service"
+	"github.com/Te8va/wallet/internal/service/mocks"
+)
+
+func TestStatementService_GetStatement(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockstatementRepo(ctrl)
+	svc := service.NewStatementService(mockRepo)
+
+	walletID := "123e4567-e89b-12d3-a456-426614174000"
+	from := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("opening, running and closing balances", func(t *testing.T) {
+		transactions := []domain.Transaction{
+			{ID: 1, OperationType: domain.DEPOSIT, Amount: 500, Balance: 1500},
+			{ID: 2, OperationType: domain.WITHDRAW, Amount: -200, Balance: 1300},
+			{ID: 3, OperationType: domain.DEPOSIT, Amount: 100, Balance: 1400},
+		}
+
+		mockRepo.EXPECT().GetBalance(gomock.Any(), walletID).Return(int64(1400), nil)
+		mockRepo.EXPECT().BalanceAt(gomock.Any(), walletID, from).Return(int64(1000), nil)
+		mockRepo.EXPECT().ListTransactions(gomock.Any(), walletID, from, to).Return(transactions, nil)
+
+		st, err := svc.GetStatement(context.Background(), walletID, from, to)
+		require.NoError(t, err)
+		require.Equal(t, int64(1000), st.OpeningBalance)
+		require.Equal(t, int64(1400), st.ClosingBalance)
+		require.Equal(t, transactions, st.Transactions)
+		require.Equal(t, []domain.OperationTotal{
+			{OperationType: domain.DEPOSIT, Count: 2, Amount: 600},
+			{OperationType: domain.WITHDRAW, Count: 1, Amount: -200},
+		}, st.Totals)
+	})
+
+	t.Run("empty period keeps the opening balance", func(t *testing.T) {
+		mockRepo.EXPECT().GetBalance(gomock.Any(), walletID).Return(int64(1000), nil)
+		mockRepo.EXPECT().BalanceAt(gomock.Any(), walletID, from).Return(int64(1000), nil)
+		mockRepo.EXPECT().ListTransactions(gomock.Any(), walletID, from, to).Return(nil, nil)
+
+		st, err := svc.GetStatement(context.Background(), walletID, from, to)
+		require.NoError(t, err)
+		require.Equal(t, int64(1000), st.ClosingBalance)
+		require.Empty(t, st.Transactions)
+		require.Empty(t, st.Totals)
+	})
+
+	t.Run("wallet not found", func(t *testing.T) {
+		mockRepo.EXPECT().GetBalance(gomock.Any(), walletID).Return(int64(0), appErrors.ErrWalletNotFound)
+
+		_, err := svc.GetStatement(context.Background(), walletID, from, to)
+		require.ErrorIs(t, err, appErrors.ErrWalletNotFound)
+	})
+}
@@ -0,0 +1,201 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/Te8va/wallet/internal/domain"
+	appErrors "github.com/Te8va/wallet/internal/errors"
+	"github.com/Te8va/wallet/internal/logging"
+)
+
+const (
+	// voucherAlphabet leaves out 0, 1, I and O, which are easy to confuse
+	// when a code is typed in. Its 32 symbols make a code of
+	// voucherCodeLength symbols carry 80 random bits.
+	voucherAlphabet   = "23456789ABCDEFGHJKLMNPQRSTUVWXYZ"
+	voucherCodeLength = 16
+	voucherCodeGroup  = 4
+
+	// MaxVoucherBatchCount is the largest number of codes in one batch.
+	MaxVoucherBatchCount = 100000
+)
+
+//go:generate mockgen -source=voucher.go -destination=mocks/voucher_mock.gen.go -package=mocks
+type voucherRepo interface {
+	CreateVoucherBatch(ctx context.Context, params domain.VoucherBatchParams, codeHashes [][]byte) (domain.VoucherBatch, error)
+	RedeemVoucher(ctx context.Context, codeHash []byte, walletID string, now time.Time) (domain.Transaction, error)
+	ListExpiredVoucherBatches(ctx context.Context, now time.Time, limit int) ([]domain.VoucherBatch, error)
+	ExpireVoucherBatch(ctx context.Context, id int64) error
+}
+
+// VoucherPolicy controls the voucher expiry job.
+type VoucherPolicy struct {
+	BatchSize int
+}
+
+type VoucherService struct {
+	repo   voucherRepo
+	policy VoucherPolicy
+	now    func() time.Time
+	rand   io.Reader
+}
+
+func NewVoucherService(repo voucherRepo, policy VoucherPolicy) *VoucherService {
+	return &VoucherService{
+		repo:   repo,
+		policy: policy,
+		now:    func() time.Time { return time.Now().UTC() },
+		rand:   rand.Reader,
+	}
+}
+
+// IssueVoucherBatch generates params.Count random codes and funds them from
+// the treasury wallet. The returned batch is the only place the codes can
+// be read from: only their hashes are stored.
+func (s *VoucherService) IssueVoucherBatch(ctx context.Context, params domain.VoucherBatchParams) (domain.VoucherBatch, error) {
+	if params.Count <= 0 || params.Count > MaxVoucherBatchCount || params.Value <= 0 || params.MaxRedemptions <= 0 ||
+		!params.ExpiresAt.After(s.now()) {
+		return domain.VoucherBatch{}, appErrors.ErrInvalidVoucherBatch
+	}
+	// The treasury is debited with the value of every possible redemption.
+	if params.Value > math.MaxInt64/int64(params.MaxRedemptions)/int64(params.Count) {
+		return domain.VoucherBatch{}, appErrors.ErrInvalidVoucherBatch
+	}
+	params.ExpiresAt = params.ExpiresAt.UTC()
+
+	codes := make([]string, 0, params.Count)
+	hashes := make([][]byte, 0, params.Count)
+	seen := make(map[string]bool, params.Count)
+	for len(codes) < params.Count {
+		code, err := generateVoucherCode(s.rand)
+		if err != nil {
+			return domain.VoucherBatch{}, fmt.Errorf("service.IssueVoucherBatch: %w", err)
+		}
+		if seen[code] {
+			continue
+		}
+		seen[code] = true
+
+		codes = append(codes, formatVoucherCode(code))
+		hashes = append(hashes, hashVoucherCode(code))
+	}
+
+	b, err := s.repo.CreateVoucherBatch(ctx, params, hashes)
+	if err != nil {
+		return domain.VoucherBatch{}, err
+	}
+	b.Codes = codes
+
+	logging.FromContext(ctx).Info("Voucher batch issued",
+		zap.Int64("voucher_batch_id", b.ID),
+		zap.String("treasury_wallet_id", b.TreasuryWalletID),
+		zap.Int64("value", b.Value),
+		zap.Int("count", b.Count),
+		zap.Int("max_redemptions", b.MaxRedemptions),
+	)
+
+	return b, nil
+}
+
+// RedeemVoucher credits the value of the voucher to the wallet. Codes are
+// accepted in any case and with or without dashes and spaces; a code that
+// cannot have been issued is reported as not found.
+func (s *VoucherService) RedeemVoucher(ctx context.Context, req domain.VoucherRedeemRequest) (domain.Transaction, error) {
+	code, ok := normalizeVoucherCode(req.Code)
+	if !ok {
+		return domain.Transaction{}, appErrors.ErrVoucherNotFound
+	}
+
+	t, err := s.repo.RedeemVoucher(ctx, hashVoucherCode(code), req.WalletID, s.now())
+	if err != nil {
+		return domain.Transaction{}, err
+	}
+
+	logging.FromContext(ctx).Info("Voucher redeemed",
+		zap.String("wallet_id", t.WalletID),
+		zap.String("reference", t.Reference),
+		zap.Int64("amount", t.Amount),
+	)
+
+	return t, nil
+}
+
+// RunExpired returns the unredeemed value of expired batches to their
+// treasury wallets. It returns the number of batches processed.
+func (s *VoucherService) RunExpired(ctx context.Context) (int, error) {
+	batches, err := s.repo.ListExpiredVoucherBatches(ctx, s.now(), s.policy.BatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("service.RunExpired: %w", err)
+	}
+
+	var errs []error
+	for _, b := range batches {
+		if err := s.repo.ExpireVoucherBatch(ctx, b.ID); err != nil {
+			errs = append(errs, fmt.Errorf("voucher batch %d: %w", b.ID, err))
+		}
+	}
+
+	if len(errs) > 0 {
+		return len(batches), fmt.Errorf("service.RunExpired: %w", errors.Join(errs...))
+	}
+
+	return len(batches), nil
+}
+
+// generateVoucherCode returns a code of voucherCodeLength symbols read from
+// r. Each symbol takes the low 5 bits of a byte, so every symbol is equally
+// likely.
+func generateVoucherCode(r io.Reader) (string, error) {
+	buf := make([]byte, voucherCodeLength)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return "", fmt.Errorf("failed to generate voucher code: %w", err)
+	}
+
+	for i, b := range buf {
+		buf[i] = voucherAlphabet[b&31]
+	}
+
+	return string(buf), nil
+}
+
+// formatVoucherCode splits a code into dash-separated groups for printing.
+func formatVoucherCode(code string) string {
+	var sb strings.Builder
+	for i := 0; i < len(code); i += voucherCodeGroup {
+		if i > 0 {
+			sb.WriteByte('-')
+		}
+		sb.WriteString(code[i:min(i+voucherCodeGroup, len(code))])
+	}
+	return sb.String()
+}
+
+// normalizeVoucherCode strips dashes and spaces from a code as typed in and
+// upper-cases it. It reports false for anything that is not a valid code.
+func normalizeVoucherCode(s string) (string, bool) {
+	code := strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(s))
+	if len(code) != voucherCodeLength {
+		return "", false
+	}
+	for _, c := range code {
+		if !strings.ContainsRune(voucherAlphabet, c) {
+			return "", false
+		}
+	}
+	return code, true
+}
+
+func hashVoucherCode(code string) []byte {
+	sum := sha256.Sum256([]byte(code))
+	return sum[:]
+}
@@ -0,0 +1,140 @@
+package service_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Te8va/wallet/internal/domain"
+	appErrors "github.com/Te8va/wallet/internal/errors"
+	"github.com/Te8va/wallet/internal/service"
+	"github.com/Te8va/wallet/internal/service/mocks"
+)
+
+func TestVoucherService_IssueVoucherBatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockvoucherRepo(ctrl)
+	svc := service.NewVoucherService(mockRepo, service.VoucherPolicy{})
+
+	params := domain.VoucherBatchParams{
+		TreasuryWalletID: "treasury",
+		Value:            500,
+		MaxRedemptions:   1,
+		Count:            50,
+		ExpiresAt:        time.Now().Add(24 * time.Hour),
+	}
+
+	var hashes [][]byte
+	mockRepo.EXPECT().CreateVoucherBatch(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, p domain.VoucherBatchParams, h [][]byte) (domain.VoucherBatch, error) {
+			hashes = h
+			return domain.VoucherBatch{ID: 3, TreasuryWalletID: p.TreasuryWalletID, Value: p.Value, Count: p.Count}, nil
+		})
+
+	b, err := svc.IssueVoucherBatch(context.Background(), params)
+	require.NoError(t, err)
+	require.Len(t, b.Codes, 50)
+	require.Len(t, hashes, 50)
+
+	format := regexp.MustCompile(`^[2-9A-HJ-NP-Z]{4}(-[2-9A-HJ-NP-Z]{4}){3}$`)
+	seen := map[string]bool{}
+	for i, code := range b.Codes {
+		require.Regexp(t, format, code)
+		require.False(t, seen[code], "duplicate code %s", code)
+		seen[code] = true
+
+		sum := sha256.Sum256([]byte(strings.ReplaceAll(code, "-", "")))
+		require.Equal(t, sum[:], hashes[i], "only the hash of the code is stored")
+	}
+
+	invalid := []domain.VoucherBatchParams{
+		{TreasuryWalletID: "treasury", Value: 500, MaxRedemptions: 1, Count: 0, ExpiresAt: params.ExpiresAt},
+		{TreasuryWalletID: "treasury", Value: 500, MaxRedemptions: 1, Count: 1, ExpiresAt: time.Now().Add(-time.Hour)},
+		{TreasuryWalletID: "treasury", Value: 1 << 62, MaxRedemptions: 4, Count: 1, ExpiresAt: params.ExpiresAt},
+	}
+	for _, p := range invalid {
+		_, err := svc.IssueVoucherBatch(context.Background(), p)
+		require.ErrorIs(t, err, appErrors.ErrInvalidVoucherBatch)
+	}
+}
+
+func TestVoucherService_RedeemVoucher(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockvoucherRepo(ctrl)
+	svc := service.NewVoucherService(mockRepo, service.VoucherPolicy{})
+
+	hash := sha256.Sum256([]byte("ABCD2345EFGH6789"))
+
+	testCases := []struct {
+		name        string
+		code        string
+		mockRepo    func()
+		expectedErr error
+	}{
+		{
+			name: "code typed in lower case with spaces",
+			code: "abcd 2345-efgh 6789",
+			mockRepo: func() {
+				mockRepo.EXPECT().RedeemVoucher(gomock.Any(), hash[:], "wallet", gomock.Any()).
+					Return(domain.Transaction{ID: 11, WalletID: "wallet", OperationType: domain.VOUCHER_REDEEM, Amount: 500}, nil)
+			},
+		},
+		{
+			name: "redeemed",
+			code: "ABCD-2345-EFGH-6789",
+			mockRepo: func() {
+				mockRepo.EXPECT().RedeemVoucher(gomock.Any(), hash[:], "wallet", gomock.Any()).
+					Return(domain.Transaction{}, appErrors.ErrVoucherRedeemed)
+			},
+			expectedErr: appErrors.ErrVoucherRedeemed,
+		},
+		{
+			name:        "symbol outside the alphabet",
+			code:        "ABCD-2345-EFGH-678O",
+			mockRepo:    func() {},
+			expectedErr: appErrors.ErrVoucherNotFound,
+		},
+		{
+			name:        "too short",
+			code:        "ABCD-2345",
+			mockRepo:    func() {},
+			expectedErr: appErrors.ErrVoucherNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mockRepo()
+
+			_, err := svc.RedeemVoucher(context.Background(), domain.VoucherRedeemRequest{Code: tc.code, WalletID: "wallet"})
+			require.ErrorIs(t, err, tc.expectedErr)
+		})
+	}
+}
+
+func TestVoucherService_RunExpired(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockvoucherRepo(ctrl)
+	svc := service.NewVoucherService(mockRepo, service.VoucherPolicy{BatchSize: 10})
+
+	mockRepo.EXPECT().ListExpiredVoucherBatches(gomock.Any(), gomock.Any(), 10).
+		Return([]domain.VoucherBatch{{ID: 1}, {ID: 2}}, nil)
+	mockRepo.EXPECT().ExpireVoucherBatch(gomock.Any(), int64(1)).Return(nil)
+	mockRepo.EXPECT().ExpireVoucherBatch(gomock.Any(), int64(2)).Return(nil)
+
+	n, err := svc.RunExpired(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 2, n)
+}
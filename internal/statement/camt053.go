@@ -0,0 +1,225 @@
+package statement
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/hex"
+	"encoding/xml"
+	"io"
+	"time"
+
+	"github.com/Te8va/wallet/internal/domain"
+)
+
+const camt053Namespace = "urn:iso:std:iso:20022:tech:xsd:camt.053.001.02"
+
+type camtDocument struct {
+	XMLName xml.Name `xml:"Document"`
+	Xmlns   string   `xml:"xmlns,attr"`
+	Stmt    camtBkToCstmrStmt
+}
+
+type camtBkToCstmrStmt struct {
+	XMLName xml.Name `xml:"BkToCstmrStmt"`
+	GrpHdr  camtGrpHdr
+	Stmt    camtStmt
+}
+
+type camtGrpHdr struct {
+	MsgID    string `xml:"MsgId"`
+	CreDtTm  string `xml:"CreDtTm"`
+	MsgPgntn camtPagination
+}
+
+type camtPagination struct {
+	PgNb      int  `xml:"PgNb"`
+	LastPgInd bool `xml:"LastPgInd"`
+}
+
+type camtStmt struct {
+	ID        string        `xml:"Id"`
+	CreDtTm   string        `xml:"CreDtTm"`
+	FrToDt    camtFrToDt    `xml:"FrToDt"`
+	Acct      camtAcct      `xml:"Acct"`
+	Bal       []camtBal     `xml:"Bal"`
+	TxsSummry camtTxsSummry `xml:"TxsSummry"`
+	Ntry      []camtNtry    `xml:"Ntry"`
+}
+
+type camtFrToDt struct {
+	FrDtTm string `xml:"FrDtTm"`
+	ToDtTm string `xml:"ToDtTm"`
+}
+
+type camtAcct struct {
+	ID  string `xml:"Id>Othr>Id"`
+	Ccy string `xml:"Ccy"`
+	Nm  string `xml:"Nm"`
+}
+
+type camtAmt struct {
+	Ccy   string `xml:"Ccy,attr"`
+	Value string `xml:",chardata"`
+}
+
+type camtBal struct {
+	Cd        string  `xml:"Tp>CdOrPrtry>Cd"`
+	Amt       camtAmt `xml:"Amt"`
+	CdtDbtInd string  `xml:"CdtDbtInd"`
+	Dt        string  `xml:"Dt>Dt"`
+}
+
+type camtTxsSummry struct {
+	TtlNtries    camtTtlNtries    `xml:"TtlNtries"`
+	TtlCdtNtries camtNumberAndSum `xml:"TtlCdtNtries"`
+	TtlDbtNtries camtNumberAndSum `xml:"TtlDbtNtries"`
+}
+
+type camtTtlNtries struct {
+	NbOfNtries    int    `xml:"NbOfNtries"`
+	Sum           string `xml:"Sum"`
+	TtlNetNtryAmt string `xml:"TtlNetNtryAmt"`
+	CdtDbtInd     string `xml:"CdtDbtInd"`
+}
+
+type camtNumberAndSum struct {
+	NbOfNtries int    `xml:"NbOfNtries"`
+	Sum        string `xml:"Sum"`
+}
+
+type camtNtry struct {
+	NtryRef     string  `xml:"NtryRef"`
+	Amt         camtAmt `xml:"Amt"`
+	CdtDbtInd   string  `xml:"CdtDbtInd"`
+	Sts         string  `xml:"Sts"`
+	BookgDt     string  `xml:"BookgDt>DtTm"`
+	ValDt       string  `xml:"ValDt>DtTm"`
+	AcctSvcrRef string  `xml:"AcctSvcrRef"`
+	BkTxCd      string  `xml:"BkTxCd>Prtry>Cd"`
+	AddtlInf    string  `xml:"AddtlNtryInf"`
+}
+
+// WriteCamt053 renders the statement as an ISO 20022 bank-to-customer
+// statement (camt.053.001.02). Amounts are unsigned; the direction is given
+// by the credit/debit indicator.
+func WriteCamt053(w io.Writer, st domain.Statement, opts Options) error {
+	opts = opts.withDefaults()
+	created := opts.CreatedAt.UTC().Format(time.RFC3339)
+	id := statementID(st)
+
+	doc := camtDocument{
+		Xmlns: camt053Namespace,
+		Stmt: camtBkToCstmrStmt{
+			GrpHdr: camtGrpHdr{
+				MsgID:    id,
+				CreDtTm:  created,
+				MsgPgntn: camtPagination{PgNb: 1, LastPgInd: true},
+			},
+			Stmt: camtStmt{
+				ID:      id,
+				CreDtTm: created,
+				FrToDt: camtFrToDt{
+					FrDtTm: formatTime(st.From),
+					ToDtTm: formatTime(st.To),
+				},
+				Acct: camtAcct{
+					ID:  accountID(st.WalletID, 34),
+					Ccy: opts.Currency,
+					Nm:  st.WalletID,
+				},
+				Bal: []camtBal{
+					camtBalance("OPBD", st.OpeningBalance, st.From, opts),
+					// The period end is exclusive, so the closing balance is
+					// dated by the last day the statement covers.
+					camtBalance("CLBD", st.ClosingBalance, st.To.Add(-time.Nanosecond), opts),
+				},
+			},
+		},
+	}
+
+	var credits, debits camtNumberAndSum
+	var creditSum, debitSum int64
+	for _, t := range st.Transactions {
+		ref := itoa(t.ID)
+		doc.Stmt.Stmt.Ntry = append(doc.Stmt.Stmt.Ntry, camtNtry{
+			NtryRef:     ref,
+			Amt:         camtAmt{Ccy: opts.Currency, Value: opts.formatAmount(abs(t.Amount))},
+			CdtDbtInd:   creditDebit(t.Amount),
+			Sts:         "BOOK",
+			BookgDt:     formatTime(t.CreatedAt),
+			ValDt:       formatTime(t.CreatedAt),
+			AcctSvcrRef: ref,
+			BkTxCd:      string(t.OperationType),
+			AddtlInf:    string(t.OperationType),
+		})
+
+		if t.Amount < 0 {
+			debits.NbOfNtries++
+			debitSum += -t.Amount
+		} else {
+			credits.NbOfNtries++
+			creditSum += t.Amount
+		}
+	}
+
+	credits.Sum = opts.formatAmount(creditSum)
+	debits.Sum = opts.formatAmount(debitSum)
+	net := creditSum - debitSum
+
+	doc.Stmt.Stmt.TxsSummry = camtTxsSummry{
+		TtlNtries: camtTtlNtries{
+			NbOfNtries:    len(st.Transactions),
+			Sum:           opts.formatAmount(creditSum + debitSum),
+			TtlNetNtryAmt: opts.formatAmount(abs(net)),
+			CdtDbtInd:     creditDebit(net),
+		},
+		TtlCdtNtries: credits,
+		TtlDbtNtries: debits,
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func camtBalance(code string, balance int64, at time.Time, opts Options) camtBal {
+	return camtBal{
+		Cd:        code,
+		Amt:       camtAmt{Ccy: opts.Currency, Value: opts.formatAmount(abs(balance))},
+		CdtDbtInd: creditDebit(balance),
+		Dt:        at.UTC().Format(time.DateOnly),
+	}
+}
+
+func creditDebit(amount int64) string {
+	if amount < 0 {
+		return "DBIT"
+	}
+	return "CRDT"
+}
+
+// statementID derives a stable identifier that fits the 35 character limit
+// of ISO 20022 message and statement ids.
+func statementID(st domain.Statement) string {
+	sum := sha1.Sum([]byte(st.WalletID + "|" + formatTime(st.From) + "|" + formatTime(st.To)))
+	return "STMT-" + st.From.UTC().Format("20060102") + "-" + hex.EncodeToString(sum[:8])
+}
+
+// accountID maps the wallet id to an account id of maxLen characters, the
+// limit of the target format. Wallet ids can be longer than that, and
+// cutting them down could give two wallets the same account, so the id is
+// derived from a digest of the whole wallet id instead.
+func accountID(walletID string, maxLen int) string {
+	sum := sha256.Sum256([]byte(walletID))
+	return base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(sum[:])[:maxLen]
+}
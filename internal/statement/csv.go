@@ -0,0 +1,49 @@
+package statement
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/Te8va/wallet/internal/domain"
+)
+
+// WriteCSV renders the statement as a single CSV table. The first column
+// tells the record kind: opening, transaction, total or closing.
+func WriteCSV(w io.Writer, st domain.Statement) error {
+	cw := csv.NewWriter(w)
+
+	records := [][]string{
+		{"record", "date", "id", "operation_type", "count", "amount", "balance"},
+		{"opening", formatTime(st.From), "", "", "", "", itoa(st.OpeningBalance)},
+	}
+
+	for _, t := range st.Transactions {
+		records = append(records, []string{
+			"transaction", formatTime(t.CreatedAt), itoa(t.ID), string(t.OperationType), "", itoa(t.Amount), itoa(t.Balance),
+		})
+	}
+
+	for _, total := range st.Totals {
+		records = append(records, []string{
+			"total", "", "", string(total.OperationType), strconv.Itoa(total.Count), itoa(total.Amount), "",
+		})
+	}
+
+	records = append(records, []string{"closing", formatTime(st.To), "", "", "", "", itoa(st.ClosingBalance)})
+
+	if err := cw.WriteAll(records); err != nil {
+		return err
+	}
+
+	return cw.Error()
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+func itoa(v int64) string {
+	return strconv.FormatInt(v, 10)
+}
@@ -0,0 +1,146 @@
+package statement
+
+import (
+	"encoding/xml"
+	"io"
+	"time"
+
+	"github.com/Te8va/wallet/internal/domain"
+)
+
+const (
+	ofxHeader = `<?OFX OFXHEADER="200" VERSION="220" SECURITY="NONE" OLDFILEUID="NONE" NEWFILEUID="NONE"?>` + "\n"
+
+	ofxBankID = "WALLET"
+)
+
+type ofxDocument struct {
+	XMLName xml.Name `xml:"OFX"`
+	Signon  ofxSignon
+	Bank    ofxBankMsgs
+}
+
+type ofxStatus struct {
+	Code     int    `xml:"CODE"`
+	Severity string `xml:"SEVERITY"`
+}
+
+type ofxSignon struct {
+	XMLName  xml.Name  `xml:"SIGNONMSGSRSV1"`
+	Status   ofxStatus `xml:"SONRS>STATUS"`
+	DTServer string    `xml:"SONRS>DTSERVER"`
+	Language string    `xml:"SONRS>LANGUAGE"`
+}
+
+type ofxBankMsgs struct {
+	XMLName xml.Name  `xml:"BANKMSGSRSV1"`
+	TrnUID  string    `xml:"STMTTRNRS>TRNUID"`
+	Status  ofxStatus `xml:"STMTTRNRS>STATUS"`
+	StmtRs  ofxStmtRs `xml:"STMTTRNRS>STMTRS"`
+}
+
+type ofxStmtRs struct {
+	CurDef    string      `xml:"CURDEF"`
+	BankID    string      `xml:"BANKACCTFROM>BANKID"`
+	AcctID    string      `xml:"BANKACCTFROM>ACCTID"`
+	AcctType  string      `xml:"BANKACCTFROM>ACCTTYPE"`
+	TranList  ofxTranList `xml:"BANKTRANLIST"`
+	LedgerBal ofxBalance  `xml:"LEDGERBAL"`
+}
+
+type ofxTranList struct {
+	DTStart string       `xml:"DTSTART"`
+	DTEnd   string       `xml:"DTEND"`
+	Trn     []ofxStmtTrn `xml:"STMTTRN"`
+}
+
+type ofxStmtTrn struct {
+	TrnType  string `xml:"TRNTYPE"`
+	DTPosted string `xml:"DTPOSTED"`
+	TrnAmt   string `xml:"TRNAMT"`
+	FITID    string `xml:"FITID"`
+	Name     string `xml:"NAME"`
+}
+
+type ofxBalance struct {
+	BalAmt string `xml:"BALAMT"`
+	DTAsOf string `xml:"DTASOF"`
+}
+
+// WriteOFX renders the statement as an OFX 2.2 bank statement response.
+// OFX limits ACCTID to 22 characters, so it is derived from the wallet id;
+// the full id is kept in TRNUID.
+func WriteOFX(w io.Writer, st domain.Statement, opts Options) error {
+	opts = opts.withDefaults()
+
+	doc := ofxDocument{
+		Signon: ofxSignon{
+			Status:   ofxStatus{Code: 0, Severity: "INFO"},
+			DTServer: ofxTime(opts.CreatedAt),
+			Language: "ENG",
+		},
+		Bank: ofxBankMsgs{
+			TrnUID: st.WalletID,
+			Status: ofxStatus{Code: 0, Severity: "INFO"},
+			StmtRs: ofxStmtRs{
+				CurDef:   opts.Currency,
+				BankID:   ofxBankID,
+				AcctID:   accountID(st.WalletID, 22),
+				AcctType: "CHECKING",
+				TranList: ofxTranList{
+					DTStart: ofxTime(st.From),
+					DTEnd:   ofxTime(st.To),
+				},
+				LedgerBal: ofxBalance{
+					BalAmt: opts.formatSignedAmount(st.ClosingBalance),
+					DTAsOf: ofxTime(st.To),
+				},
+			},
+		},
+	}
+
+	for _, t := range st.Transactions {
+		doc.Bank.StmtRs.TranList.Trn = append(doc.Bank.StmtRs.TranList.Trn, ofxStmtTrn{
+			TrnType:  ofxTransactionType(t),
+			DTPosted: ofxTime(t.CreatedAt),
+			TrnAmt:   opts.formatSignedAmount(t.Amount),
+			FITID:    itoa(t.ID),
+			Name:     string(t.OperationType),
+		})
+	}
+
+	if _, err := io.WriteString(w, `<?xml version="1.0" encoding="UTF-8" standalone="no"?>`+"\n"+ofxHeader); err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func ofxTransactionType(t domain.Transaction) string {
+	switch t.OperationType {
+	case domain.DEPOSIT:
+		return "DEP"
+	case domain.INTEREST:
+		return "INT"
+	case domain.TRANSFER_IN, domain.TRANSFER_OUT:
+		return "XFER"
+	case domain.OPENING:
+		return "OTHER"
+	}
+
+	if t.Amount < 0 {
+		return "DEBIT"
+	}
+	return "CREDIT"
+}
+
+func ofxTime(t time.Time) string {
+	return t.UTC().Format("20060102150405.000") + "[0:GMT]"
+}
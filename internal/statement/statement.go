@@ -0,0 +1,184 @@
+package statement
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/Te8va/wallet/internal/domain"
+)
+
+type Format string
+
+const (
+	FormatJSON    Format = "json"
+	FormatCSV     Format = "csv"
+	FormatText    Format = "text"
+	FormatCamt053 Format = "camt053"
+	FormatOFX     Format = "ofx"
+)
+
+var ErrUnknownFormat = errors.New("unknown statement format")
+
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(s); f {
+	case FormatJSON, FormatCSV, FormatText, FormatCamt053, FormatOFX:
+		return f, nil
+	case "":
+		return FormatJSON, nil
+	default:
+		return "", ErrUnknownFormat
+	}
+}
+
+// ContentType returns the media type of a rendered statement.
+func (f Format) ContentType() string {
+	switch f {
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	case FormatText:
+		return "text/plain; charset=utf-8"
+	case FormatCamt053:
+		return "application/xml"
+	case FormatOFX:
+		return "application/x-ofx"
+	default:
+		return "application/json"
+	}
+}
+
+// Extension returns the file extension used for downloads.
+func (f Format) Extension() string {
+	switch f {
+	case FormatText:
+		return "txt"
+	case FormatCamt053:
+		return "xml"
+	default:
+		return string(f)
+	}
+}
+
+// Options carries the settings needed by the bank interchange formats.
+type Options struct {
+	// Currency is the ISO 4217 code of the wallet balances.
+	Currency string
+	// MinorUnits is the number of decimal places the integer amounts carry,
+	// e.g. 2 when amounts are stored in kopecks.
+	MinorUnits int
+	// CreatedAt is the statement creation time; now when zero.
+	CreatedAt time.Time
+}
+
+func (o Options) withDefaults() Options {
+	if o.Currency == "" {
+		o.Currency = "RUB"
+	}
+	if o.CreatedAt.IsZero() {
+		o.CreatedAt = time.Now()
+	}
+	return o
+}
+
+// formatAmount renders a non-negative amount of minor units as a decimal.
+func (o Options) formatAmount(v int64) string {
+	s := itoa(v)
+	if o.MinorUnits <= 0 {
+		return s
+	}
+
+	if len(s) <= o.MinorUnits {
+		s = strings.Repeat("0", o.MinorUnits-len(s)+1) + s
+	}
+
+	return s[:len(s)-o.MinorUnits] + "." + s[len(s)-o.MinorUnits:]
+}
+
+func (o Options) formatSignedAmount(v int64) string {
+	if v < 0 {
+		return "-" + o.formatAmount(-v)
+	}
+	return o.formatAmount(v)
+}
+
+func abs(v int64) int64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+func Write(w io.Writer, f Format, st domain.Statement, opts Options) error {
+	switch f {
+	case FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(st)
+	case FormatCSV:
+		return WriteCSV(w, st)
+	case FormatText:
+		return WriteText(w, st)
+	case FormatCamt053:
+		return WriteCamt053(w, st, opts)
+	case FormatOFX:
+		return WriteOFX(w, st, opts)
+	default:
+		return ErrUnknownFormat
+	}
+}
+
+// PeriodError reports which bound of a statement period is invalid.
+type PeriodError struct {
+	Bound string
+	Err   error
+}
+
+func (e *PeriodError) Error() string {
+	return fmt.Sprintf("statement.ParsePeriod: %s: %v", e.Bound, e.Err)
+}
+
+func (e *PeriodError) Unwrap() error {
+	return e.Err
+}
+
+// ParsePeriod parses the statement period bounds. Both accept either a date
+// (YYYY-MM-DD) or an RFC 3339 timestamp. A date in to includes the whole day.
+// The returned period is half-open: [from, to). An invalid bound is reported
+// as a *PeriodError; a to not after from is reported on to.
+func ParsePeriod(fromStr, toStr string) (time.Time, time.Time, error) {
+	from, _, err := parseBound(fromStr)
+	if err != nil {
+		return time.Time{}, time.Time{}, &PeriodError{Bound: "from", Err: err}
+	}
+
+	to, isDate, err := parseBound(toStr)
+	if err != nil {
+		return time.Time{}, time.Time{}, &PeriodError{Bound: "to", Err: err}
+	}
+
+	if isDate {
+		to = to.AddDate(0, 0, 1)
+	}
+
+	if !from.Before(to) {
+		return time.Time{}, time.Time{}, &PeriodError{Bound: "to", Err: errors.New("must be after from")}
+	}
+
+	return from, to, nil
+}
+
+func parseBound(s string) (time.Time, bool, error) {
+	if t, err := time.Parse(time.DateOnly, s); err == nil {
+		return t, true, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("expected YYYY-MM-DD or RFC 3339 time, got %q", s)
+	}
+
+	return t.UTC(), false, nil
+}
@@ -0,0 +1,396 @@
+package statement_test
+
+import (
+	"bytes"
+	"encoding/xml"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/Te8va/wallet/internal/domain"
+	"github.com/Te8va/wallet/internal/statement"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+func testStatement() domain.Statement {
+	return domain.Statement{
+		WalletID:       "123e4567-e89b-12d3-a456-426614174000",
+		From:           time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+		To:             time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC),
+		OpeningBalance: 1000,
+		ClosingBalance: 1550,
+		Transactions: []domain.Transaction{
+			{ID: 41, WalletID: "123e4567-e89b-12d3-a456-426614174000", OperationType: domain.DEPOSIT, Amount: 1500, Balance: 2500, CreatedAt: time.Date(2025, 3, 3, 9, 15, 0, 0, time.UTC)},
+			{ID: 57, WalletID: "123e4567-e89b-12d3-a456-426614174000", OperationType: domain.WITHDRAW, Amount: -1000, Balance: 1500, CreatedAt: time.Date(2025, 3, 14, 18, 40, 12, 0, time.UTC)},
+			{ID: 63, WalletID: "123e4567-e89b-12d3-a456-426614174000", OperationType: domain.TRANSFER_OUT, Amount: -200, Balance: 1300, CreatedAt: time.Date(2025, 3, 20, 12, 0, 0, 0, time.UTC)},
+			{ID: 88, WalletID: "123e4567-e89b-12d3-a456-426614174000", OperationType: domain.INTEREST, Amount: 250, Balance: 1550, CreatedAt: time.Date(2025, 3, 31, 23, 59, 59, 0, time.UTC)},
+		},
+		Totals: []domain.OperationTotal{
+			{OperationType: domain.DEPOSIT, Count: 1, Amount: 1500},
+			{OperationType: domain.INTEREST, Count: 1, Amount: 250},
+			{OperationType: domain.TRANSFER_OUT, Count: 1, Amount: -200},
+			{OperationType: domain.WITHDRAW, Count: 1, Amount: -1000},
+		},
+	}
+}
+
+func assertGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+
+	path := filepath.Join("testdata", name)
+	if *update {
+		require.NoError(t, os.WriteFile(path, got, 0o644))
+	}
+
+	want, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, string(want), string(got))
+}
+
+func TestWrite(t *testing.T) {
+	testCases := []struct {
+		format statement.Format
+		golden string
+	}{
+		{format: statement.FormatJSON, golden: "statement.json"},
+		{format: statement.FormatCSV, golden: "statement.csv"},
+		{format: statement.FormatText, golden: "statement.txt"},
+		{format: statement.FormatCamt053, golden: "statement.camt053.xml"},
+		{format: statement.FormatOFX, golden: "statement.ofx"},
+	}
+
+	opts := statement.Options{
+		Currency:   "RUB",
+		MinorUnits: 2,
+		CreatedAt:  time.Date(2025, 4, 1, 3, 0, 0, 0, time.UTC),
+	}
+
+	for _, tc := range testCases {
+		t.Run(string(tc.format), func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, statement.Write(&buf, tc.format, testStatement(), opts))
+			assertGolden(t, tc.golden, buf.Bytes())
+		})
+	}
+}
+
+func TestWriteCamt053Reconciles(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, statement.WriteCamt053(&buf, testStatement(), statement.Options{CreatedAt: time.Now()}))
+
+	var doc struct {
+		Stmt struct {
+			Bal []struct {
+				Cd        string `xml:"Tp>CdOrPrtry>Cd"`
+				Amt       int64  `xml:"Amt"`
+				CdtDbtInd string `xml:"CdtDbtInd"`
+			} `xml:"Bal"`
+			Ntry []struct {
+				Amt       int64  `xml:"Amt"`
+				CdtDbtInd string `xml:"CdtDbtInd"`
+			} `xml:"Ntry"`
+		} `xml:"BkToCstmrStmt>Stmt"`
+	}
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &doc))
+	require.Len(t, doc.Stmt.Bal, 2)
+	require.Equal(t, "OPBD", doc.Stmt.Bal[0].Cd)
+	require.Equal(t, "CLBD", doc.Stmt.Bal[1].Cd)
+
+	balance := doc.Stmt.Bal[0].Amt
+	for _, e := range doc.Stmt.Ntry {
+		if e.CdtDbtInd == "DBIT" {
+			balance -= e.Amt
+		} else {
+			balance += e.Amt
+		}
+	}
+	require.Equal(t, doc.Stmt.Bal[1].Amt, balance)
+}
+
+func TestParsePeriod(t *testing.T) {
+	testCases := []struct {
+		name     string
+		from, to string
+		wantFrom time.Time
+		wantTo   time.Time
+		wantErr  string
+	}{
+		{
+			name:     "dates include the last day",
+			from:     "2025-03-01",
+			to:       "2025-03-31",
+			wantFrom: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+			wantTo:   time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "timestamps are used as is",
+			from:     "2025-03-01T00:00:00Z",
+			to:       "2025-03-31T23:59:00+03:00",
+			wantFrom: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+			wantTo:   time.Date(2025, 3, 31, 20, 59, 0, 0, time.UTC),
+		},
+		{
+			name:    "from after to",
+			from:    "2025-04-01",
+			to:      "2025-03-01",
+			wantErr: "to",
+		},
+		{
+			name:    "malformed from",
+			from:    "01.03.2025",
+			to:      "2025-03-31",
+			wantErr: "from",
+		},
+		{
+			name:    "malformed to",
+			from:    "2025-03-01",
+			to:      "31.03.2025",
+			wantErr: "to",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			from, to, err := statement.ParsePeriod(tc.from, tc.to)
+			if tc.wantErr != "" {
+				var periodErr *statement.PeriodError
+				require.ErrorAs(t, err, &periodErr)
+				require.Equal(t, tc.wantErr, periodErr.Bound)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.wantFrom, from)
+			require.Equal(t, tc.wantTo, to)
+		})
+	}
+}
+
+// xmlNode is an element of a parsed document, kept with its children in
+// document order.
+type xmlNode struct {
+	name     string
+	attrs    []xml.Attr
+	text     string
+	children []*xmlNode
+}
+
+func parseXML(t *testing.T, data []byte) *xmlNode {
+	t.Helper()
+
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	root := &xmlNode{}
+	stack := []*xmlNode{root}
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+
+		top := stack[len(stack)-1]
+		switch tok := tok.(type) {
+		case xml.StartElement:
+			n := &xmlNode{name: tok.Name.Local, attrs: tok.Attr}
+			top.children = append(top.children, n)
+			stack = append(stack, n)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			top.text += strings.TrimSpace(string(tok))
+		}
+	}
+
+	require.Len(t, root.children, 1)
+	return root.children[0]
+}
+
+// camtChild is an element of a camt.053.001.02 sequence with its
+// occurrence limits; max 0 is unbounded.
+type camtChild struct {
+	name     string
+	min, max int
+}
+
+// camtSequences is the content model of the camt.053.001.02 types the
+// statement uses, keyed by element path. Choices list their alternatives
+// as optional; camtChoices requires exactly one of them.
+var camtSequences = map[string][]camtChild{
+	"Document":               {{"BkToCstmrStmt", 1, 1}},
+	"Document/BkToCstmrStmt": {{"GrpHdr", 1, 1}, {"Stmt", 1, 0}, {"SplmtryData", 0, 0}},
+	"Document/BkToCstmrStmt/GrpHdr": {
+		{"MsgId", 1, 1}, {"CreDtTm", 1, 1}, {"MsgRcpt", 0, 1}, {"MsgPgntn", 0, 1}, {"AddtlInf", 0, 1},
+	},
+	"Document/BkToCstmrStmt/GrpHdr/MsgPgntn": {{"PgNb", 1, 1}, {"LastPgInd", 1, 1}},
+	"Document/BkToCstmrStmt/Stmt": {
+		{"Id", 1, 1}, {"ElctrncSeqNb", 0, 1}, {"LglSeqNb", 0, 1}, {"CreDtTm", 1, 1}, {"FrToDt", 0, 1},
+		{"CpyDplctInd", 0, 1}, {"RptgSrc", 0, 1}, {"Acct", 1, 1}, {"RltdAcct", 0, 1}, {"Intrst", 0, 0},
+		{"Bal", 1, 0}, {"TxsSummry", 0, 1}, {"Ntry", 0, 0}, {"AddtlStmtInf", 0, 1},
+	},
+	"Document/BkToCstmrStmt/Stmt/FrToDt": {{"FrDtTm", 1, 1}, {"ToDtTm", 1, 1}},
+	"Document/BkToCstmrStmt/Stmt/Acct": {
+		{"Id", 1, 1}, {"Tp", 0, 1}, {"Ccy", 0, 1}, {"Nm", 0, 1}, {"Ownr", 0, 1}, {"Svcr", 0, 1},
+	},
+	"Document/BkToCstmrStmt/Stmt/Acct/Id":      {{"IBAN", 0, 1}, {"Othr", 0, 1}},
+	"Document/BkToCstmrStmt/Stmt/Acct/Id/Othr": {{"Id", 1, 1}, {"SchmeNm", 0, 1}, {"Issr", 0, 1}},
+	"Document/BkToCstmrStmt/Stmt/Bal": {
+		{"Tp", 1, 1}, {"CdtLine", 0, 1}, {"Amt", 1, 1}, {"CdtDbtInd", 1, 1}, {"Dt", 1, 1}, {"Avlbty", 0, 0},
+	},
+	"Document/BkToCstmrStmt/Stmt/Bal/Tp":           {{"CdOrPrtry", 1, 1}, {"SubTp", 0, 1}},
+	"Document/BkToCstmrStmt/Stmt/Bal/Tp/CdOrPrtry": {{"Cd", 0, 1}, {"Prtry", 0, 1}},
+	"Document/BkToCstmrStmt/Stmt/Bal/Dt":           {{"Dt", 0, 1}, {"DtTm", 0, 1}},
+	"Document/BkToCstmrStmt/Stmt/TxsSummry": {
+		{"TtlNtries", 0, 1}, {"TtlCdtNtries", 0, 1}, {"TtlDbtNtries", 0, 1}, {"TtlNtriesPerBkTxCd", 0, 0},
+	},
+	"Document/BkToCstmrStmt/Stmt/TxsSummry/TtlNtries": {
+		{"NbOfNtries", 0, 1}, {"Sum", 0, 1}, {"TtlNetNtryAmt", 0, 1}, {"CdtDbtInd", 0, 1},
+	},
+	"Document/BkToCstmrStmt/Stmt/TxsSummry/TtlCdtNtries": {{"NbOfNtries", 0, 1}, {"Sum", 0, 1}},
+	"Document/BkToCstmrStmt/Stmt/TxsSummry/TtlDbtNtries": {{"NbOfNtries", 0, 1}, {"Sum", 0, 1}},
+	"Document/BkToCstmrStmt/Stmt/Ntry": {
+		{"NtryRef", 0, 1}, {"Amt", 1, 1}, {"CdtDbtInd", 1, 1}, {"RvslInd", 0, 1}, {"Sts", 1, 1},
+		{"BookgDt", 0, 1}, {"ValDt", 0, 1}, {"AcctSvcrRef", 0, 1}, {"Avlbty", 0, 0}, {"BkTxCd", 1, 1},
+		{"ComssnWvrInd", 0, 1}, {"AddtlInfInd", 0, 1}, {"AmtDtls", 0, 1}, {"Chrgs", 0, 1},
+		{"TechInptChanl", 0, 1}, {"Intrst", 0, 1}, {"NtryDtls", 0, 0}, {"AddtlNtryInf", 0, 1},
+	},
+	"Document/BkToCstmrStmt/Stmt/Ntry/BookgDt":      {{"Dt", 0, 1}, {"DtTm", 0, 1}},
+	"Document/BkToCstmrStmt/Stmt/Ntry/ValDt":        {{"Dt", 0, 1}, {"DtTm", 0, 1}},
+	"Document/BkToCstmrStmt/Stmt/Ntry/BkTxCd":       {{"Domn", 0, 1}, {"Prtry", 0, 1}},
+	"Document/BkToCstmrStmt/Stmt/Ntry/BkTxCd/Prtry": {{"Cd", 1, 1}, {"Issr", 0, 1}},
+}
+
+var camtChoices = map[string]bool{
+	"Document/BkToCstmrStmt/Stmt/Acct/Id":          true,
+	"Document/BkToCstmrStmt/Stmt/Bal/Tp/CdOrPrtry": true,
+	"Document/BkToCstmrStmt/Stmt/Bal/Dt":           true,
+	"Document/BkToCstmrStmt/Stmt/Ntry/BookgDt":     true,
+	"Document/BkToCstmrStmt/Stmt/Ntry/ValDt":       true,
+}
+
+var (
+	camtMax35Text   = regexp.MustCompile(`^.{1,35}$`)
+	camtAmount      = regexp.MustCompile(`^[0-9]{1,13}(\.[0-9]{1,5})?$`)
+	camtCurrency    = regexp.MustCompile(`^[A-Z]{3}$`)
+	camtNumericText = regexp.MustCompile(`^[0-9]{1,15}$`)
+)
+
+// camtLeaves checks the simple types of the leaf elements by path suffix.
+var camtLeaves = map[string]func(string) bool{
+	"GrpHdr/MsgId":      camtMax35Text.MatchString,
+	"Stmt/Id":           camtMax35Text.MatchString,
+	"Othr/Id":           regexp.MustCompile(`^.{1,34}$`).MatchString,
+	"Acct/Ccy":          camtCurrency.MatchString,
+	"Acct/Nm":           regexp.MustCompile(`^.{1,70}$`).MatchString,
+	"CreDtTm":           isDateTime,
+	"FrDtTm":            isDateTime,
+	"ToDtTm":            isDateTime,
+	"DtTm":              isDateTime,
+	"Bal/Dt/Dt":         func(s string) bool { _, err := time.Parse(time.DateOnly, s); return err == nil },
+	"PgNb":              regexp.MustCompile(`^[0-9]{1,5}$`).MatchString,
+	"LastPgInd":         func(s string) bool { return s == "true" || s == "false" },
+	"NbOfNtries":        camtNumericText.MatchString,
+	"Sum":               camtAmount.MatchString,
+	"TtlNetNtryAmt":     camtAmount.MatchString,
+	"Amt":               camtAmount.MatchString,
+	"CdtDbtInd":         func(s string) bool { return s == "CRDT" || s == "DBIT" },
+	"Sts":               func(s string) bool { return s == "BOOK" || s == "PDNG" || s == "INFO" },
+	"CdOrPrtry/Cd":      regexp.MustCompile(`^(CLAV|CLBD|FWAV|INFO|ITAV|ITBD|OPAV|OPBD|PRCD|XPCD)$`).MatchString,
+	"NtryRef":           camtMax35Text.MatchString,
+	"AcctSvcrRef":       camtMax35Text.MatchString,
+	"BkTxCd/Prtry/Cd":   camtMax35Text.MatchString,
+	"Ntry/AddtlNtryInf": regexp.MustCompile(`^.{1,500}$`).MatchString,
+}
+
+func isDateTime(s string) bool {
+	_, err := time.Parse(time.RFC3339, s)
+	return err == nil
+}
+
+// checkCamtNode validates n against the content model and leaf types of
+// camt.053.001.02 and returns the problems found.
+func checkCamtNode(path string, n *xmlNode) []string {
+	var problems []string
+
+	seq, ok := camtSequences[path]
+	if !ok {
+		if len(n.children) > 0 {
+			return []string{path + ": unexpected children"}
+		}
+		for suffix, valid := range camtLeaves {
+			if (path == suffix || strings.HasSuffix(path, "/"+suffix)) && !valid(n.text) {
+				problems = append(problems, fmt.Sprintf("%s: invalid value %q", path, n.text))
+			}
+		}
+		return problems
+	}
+
+	i := 0
+	for _, c := range seq {
+		count := 0
+		for i < len(n.children) && n.children[i].name == c.name {
+			count++
+			i++
+		}
+		if count < c.min || (c.max > 0 && count > c.max) {
+			problems = append(problems, fmt.Sprintf("%s/%s: %d occurrences", path, c.name, count))
+		}
+	}
+	if i < len(n.children) {
+		problems = append(problems, fmt.Sprintf("%s/%s: unexpected or out of order", path, n.children[i].name))
+	}
+	if camtChoices[path] && len(n.children) != 1 {
+		problems = append(problems, path+": exactly one choice expected")
+	}
+
+	for _, child := range n.children {
+		problems = append(problems, checkCamtNode(path+"/"+child.name, child)...)
+	}
+
+	return problems
+}
+
+func TestWriteCamt053Schema(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, statement.WriteCamt053(&buf, testStatement(), statement.Options{
+		Currency:   "RUB",
+		MinorUnits: 2,
+		CreatedAt:  time.Date(2025, 4, 1, 3, 0, 0, 0, time.UTC),
+	}))
+
+	doc := parseXML(t, buf.Bytes())
+	require.Equal(t, "Document", doc.name)
+	require.Contains(t, doc.attrs, xml.Attr{Name: xml.Name{Local: "xmlns"}, Value: "urn:iso:std:iso:20022:tech:xsd:camt.053.001.02"})
+	require.Empty(t, checkCamtNode("Document", doc))
+}
+
+func TestWriteOFXAccountID(t *testing.T) {
+	// Both ids share the first 22 characters once hyphens are dropped.
+	walletIDs := []string{"123e4567-e89b-12d3-a456-426614174000", "123e4567-e89b-12d3-a456-426614174001"}
+
+	var accountIDs []string
+	for _, walletID := range walletIDs {
+		st := testStatement()
+		st.WalletID = walletID
+
+		var buf bytes.Buffer
+		require.NoError(t, statement.WriteOFX(&buf, st, statement.Options{}))
+
+		var doc struct {
+			AcctID string `xml:"BANKMSGSRSV1>STMTTRNRS>STMTRS>BANKACCTFROM>ACCTID"`
+		}
+		body := buf.Bytes()[bytes.Index(buf.Bytes(), []byte("<OFX>")):]
+		require.NoError(t, xml.Unmarshal(body, &doc))
+		require.LessOrEqual(t, len(doc.AcctID), 22)
+		accountIDs = append(accountIDs, doc.AcctID)
+	}
+
+	require.NotEqual(t, accountIDs[0], accountIDs[1])
+}
@@ -0,0 +1,50 @@
+package statement
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/Te8va/wallet/internal/domain"
+)
+
+const (
+	textWidth   = 80
+	rowFormat   = "%-10s %-20s %-16s %15s %15s\n"
+	totalFormat = "%-10s %-20s %-16s %15s\n"
+)
+
+// WriteText renders the statement as a fixed-width plain-text report.
+func WriteText(w io.Writer, st domain.Statement) error {
+	bw := bufio.NewWriter(w)
+	rule := strings.Repeat("-", textWidth) + "\n"
+
+	fmt.Fprintf(bw, "%s\n", "ACCOUNT STATEMENT")
+	fmt.Fprintf(bw, "%-17s %s\n", "Wallet:", st.WalletID)
+	fmt.Fprintf(bw, "%-17s %s - %s\n", "Period:", formatTime(st.From), formatTime(st.To))
+	fmt.Fprintf(bw, "%-17s %62d\n", "Opening balance:", st.OpeningBalance)
+	bw.WriteString(rule)
+
+	fmt.Fprintf(bw, rowFormat, "ID", "Date", "Operation", "Amount", "Balance")
+	bw.WriteString(rule)
+	for _, t := range st.Transactions {
+		fmt.Fprintf(bw, rowFormat,
+			itoa(t.ID), t.CreatedAt.UTC().Format(time.DateTime), t.OperationType, itoa(t.Amount), itoa(t.Balance))
+	}
+	if len(st.Transactions) == 0 {
+		fmt.Fprintf(bw, "%s\n", "No transactions in this period")
+	}
+	bw.WriteString(rule)
+
+	fmt.Fprintf(bw, "%s\n", "Totals by operation type")
+	for _, total := range st.Totals {
+		fmt.Fprintf(bw, totalFormat, "", fmt.Sprintf("%d operation(s)", total.Count), total.OperationType, itoa(total.Amount))
+	}
+	bw.WriteString(rule)
+
+	fmt.Fprintf(bw, "%-17s %62d\n", "Closing balance:", st.ClosingBalance)
+
+	return bw.Flush()
+}
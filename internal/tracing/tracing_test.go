@@ -0,0 +1,43 @@
+package tracing_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+
+	"github.com/Te8va/wallet/internal/tracing"
+)
+
+func TestSetup(t *testing.T) {
+	t.Run("stdout exporter", func(t *testing.T) {
+		var buf bytes.Buffer
+		shutdown, err := tracing.Setup(context.Background(), tracing.Options{
+			Exporter:    tracing.ExporterStdout,
+			ServiceName: "wallet-test",
+			SampleRatio: 1,
+			Output:      &buf,
+		})
+		require.NoError(t, err)
+
+		_, span := otel.Tracer(tracing.InstrumentationName).Start(context.Background(), "test span")
+		span.End()
+
+		require.NoError(t, shutdown(context.Background()))
+		require.Contains(t, buf.String(), `"Name":"test span"`)
+		require.Contains(t, buf.String(), "wallet-test")
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		shutdown, err := tracing.Setup(context.Background(), tracing.Options{Exporter: tracing.ExporterNone})
+		require.NoError(t, err)
+		require.NoError(t, shutdown(context.Background()))
+	})
+
+	t.Run("unknown exporter", func(t *testing.T) {
+		_, err := tracing.Setup(context.Background(), tracing.Options{Exporter: "jaeger"})
+		require.ErrorIs(t, err, tracing.ErrUnknownExporter)
+	})
+}
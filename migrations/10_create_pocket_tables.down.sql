@@ -0,0 +1,9 @@
+BEGIN;
+
+DROP TABLE IF EXISTS pocket_move;
+DROP TABLE IF EXISTS wallet_pocket;
+
+ALTER TABLE wallet
+    DROP COLUMN IF EXISTS pocketed;
+
+COMMIT;
@@ -0,0 +1,28 @@
+BEGIN;
+
+-- pocketed is the part of the balance set aside in pockets. It is kept on
+-- the wallet row so that withdrawals see it under the wallet row lock.
+ALTER TABLE wallet
+    ADD COLUMN IF NOT EXISTS pocketed BIGINT NOT NULL DEFAULT 0 CHECK (pocketed >= 0);
+
+CREATE TABLE IF NOT EXISTS wallet_pocket (
+    wallet_id VARCHAR(36) NOT NULL REFERENCES wallet (id),
+    name VARCHAR(64) NOT NULL,
+    balance BIGINT NOT NULL DEFAULT 0 CHECK (balance >= 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (wallet_id, name)
+);
+
+CREATE TABLE IF NOT EXISTS pocket_move (
+    id BIGSERIAL PRIMARY KEY,
+    wallet_id VARCHAR(36) NOT NULL REFERENCES wallet (id),
+    from_pocket VARCHAR(64),
+    to_pocket VARCHAR(64),
+    amount BIGINT NOT NULL CHECK (amount > 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT clock_timestamp()
+);
+
+CREATE INDEX IF NOT EXISTS pocket_move_wallet_id_created_at_idx
+    ON pocket_move (wallet_id, created_at);
+
+COMMIT;
@@ -0,0 +1,9 @@
+BEGIN;
+
+DROP TABLE IF EXISTS escrow_event;
+DROP TABLE IF EXISTS escrow;
+
+ALTER TABLE wallet
+    DROP COLUMN IF EXISTS kind;
+
+COMMIT;
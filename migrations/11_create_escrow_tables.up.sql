@@ -0,0 +1,41 @@
+BEGIN;
+
+-- kind separates user wallets from internal ones, such as the wallets that
+-- hold escrow funds, which only their owning service may post to.
+ALTER TABLE wallet
+    ADD COLUMN IF NOT EXISTS kind VARCHAR(16) NOT NULL DEFAULT 'USER';
+
+CREATE TABLE IF NOT EXISTS escrow (
+    deal_id VARCHAR(64) PRIMARY KEY,
+    wallet_id VARCHAR(36) NOT NULL UNIQUE REFERENCES wallet (id),
+    buyer_wallet_id VARCHAR(36) NOT NULL REFERENCES wallet (id),
+    seller_wallet_id VARCHAR(36) NOT NULL REFERENCES wallet (id),
+    amount BIGINT NOT NULL CHECK (amount > 0),
+    status VARCHAR(16) NOT NULL,
+    on_timeout VARCHAR(16) NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS escrow_funded_expires_at_idx
+    ON escrow (expires_at) WHERE status = 'FUNDED';
+
+-- escrow_event is the audit trail of an escrow: every transition with the
+-- amounts it paid out.
+CREATE TABLE IF NOT EXISTS escrow_event (
+    id BIGSERIAL PRIMARY KEY,
+    deal_id VARCHAR(64) NOT NULL REFERENCES escrow (deal_id),
+    action VARCHAR(16) NOT NULL,
+    from_status VARCHAR(16),
+    to_status VARCHAR(16) NOT NULL,
+    seller_amount BIGINT NOT NULL DEFAULT 0,
+    buyer_amount BIGINT NOT NULL DEFAULT 0,
+    reason TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT clock_timestamp()
+);
+
+CREATE INDEX IF NOT EXISTS escrow_event_deal_id_idx
+    ON escrow_event (deal_id, id);
+
+COMMIT;
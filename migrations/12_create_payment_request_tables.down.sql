@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP TABLE IF EXISTS payment_request_event;
+DROP TABLE IF EXISTS payment_request;
+
+COMMIT;
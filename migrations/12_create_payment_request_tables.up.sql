@@ -0,0 +1,36 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS payment_request (
+    id BIGSERIAL PRIMARY KEY,
+    payee_wallet_id VARCHAR(36) NOT NULL,
+    payer_wallet_id VARCHAR(36) NOT NULL,
+    amount BIGINT NOT NULL CHECK (amount > 0),
+    currency CHAR(3) NOT NULL,
+    memo VARCHAR(500) NOT NULL DEFAULT '',
+    status VARCHAR(16) NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS payment_request_payer_wallet_id_idx
+    ON payment_request (payer_wallet_id, id);
+
+CREATE INDEX IF NOT EXISTS payment_request_payee_wallet_id_idx
+    ON payment_request (payee_wallet_id, id);
+
+CREATE INDEX IF NOT EXISTS payment_request_pending_expires_at_idx
+    ON payment_request (expires_at) WHERE status = 'PENDING';
+
+CREATE TABLE IF NOT EXISTS payment_request_event (
+    id BIGSERIAL PRIMARY KEY,
+    request_id BIGINT NOT NULL REFERENCES payment_request (id),
+    from_status VARCHAR(16),
+    to_status VARCHAR(16) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT clock_timestamp()
+);
+
+CREATE INDEX IF NOT EXISTS payment_request_event_request_id_idx
+    ON payment_request_event (request_id, id);
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+DROP TABLE IF EXISTS voucher_redemption;
+DROP TABLE IF EXISTS voucher;
+DROP TABLE IF EXISTS voucher_batch;
+
+COMMIT;
@@ -0,0 +1,41 @@
+BEGIN;
+
+-- voucher_batch holds the value of its vouchers in its own wallet of kind
+-- VOUCHER, funded from the treasury wallet when the batch is issued.
+CREATE TABLE IF NOT EXISTS voucher_batch (
+    id BIGSERIAL PRIMARY KEY,
+    wallet_id VARCHAR(36) NOT NULL UNIQUE REFERENCES wallet (id),
+    treasury_wallet_id VARCHAR(36) NOT NULL REFERENCES wallet (id),
+    value BIGINT NOT NULL CHECK (value > 0),
+    max_redemptions INT NOT NULL CHECK (max_redemptions > 0),
+    count INT NOT NULL CHECK (count > 0),
+    status VARCHAR(16) NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS voucher_batch_active_expires_at_idx
+    ON voucher_batch (expires_at) WHERE status = 'ACTIVE';
+
+-- Only SHA-256 hashes of the codes are stored.
+CREATE TABLE IF NOT EXISTS voucher (
+    id BIGSERIAL PRIMARY KEY,
+    batch_id BIGINT NOT NULL REFERENCES voucher_batch (id),
+    code_hash BYTEA NOT NULL UNIQUE,
+    redemptions INT NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS voucher_batch_id_idx
+    ON voucher (batch_id);
+
+CREATE TABLE IF NOT EXISTS voucher_redemption (
+    id BIGSERIAL PRIMARY KEY,
+    voucher_id BIGINT NOT NULL REFERENCES voucher (id),
+    wallet_id VARCHAR(36) NOT NULL REFERENCES wallet (id),
+    transaction_id BIGINT NOT NULL REFERENCES wallet_transaction (id),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (voucher_id, wallet_id)
+);
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP TABLE IF EXISTS cashback;
+DROP TABLE IF EXISTS campaign;
+
+COMMIT;
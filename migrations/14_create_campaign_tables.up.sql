@@ -0,0 +1,36 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS campaign (
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(128) NOT NULL,
+    funding_wallet_id VARCHAR(36) NOT NULL REFERENCES wallet (id),
+    rate_bps BIGINT NOT NULL CHECK (rate_bps > 0 AND rate_bps <= 10000),
+    min_amount BIGINT NOT NULL DEFAULT 0,
+    per_wallet_cap BIGINT NOT NULL DEFAULT 0,
+    budget BIGINT NOT NULL CHECK (budget > 0),
+    spent BIGINT NOT NULL DEFAULT 0 CHECK (spent <= budget),
+    starts_at TIMESTAMPTZ NOT NULL,
+    ends_at TIMESTAMPTZ NOT NULL CHECK (ends_at > starts_at),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS campaign_ends_at_idx
+    ON campaign (ends_at);
+
+-- cashback has at most one row per campaign and source transaction, so a
+-- withdrawal is never paid back twice by the same campaign.
+CREATE TABLE IF NOT EXISTS cashback (
+    id BIGSERIAL PRIMARY KEY,
+    campaign_id BIGINT NOT NULL REFERENCES campaign (id),
+    source_transaction_id BIGINT NOT NULL REFERENCES wallet_transaction (id),
+    wallet_id VARCHAR(36) NOT NULL REFERENCES wallet (id),
+    amount BIGINT NOT NULL CHECK (amount > 0),
+    transaction_id BIGINT NOT NULL REFERENCES wallet_transaction (id),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (campaign_id, source_transaction_id)
+);
+
+CREATE INDEX IF NOT EXISTS cashback_campaign_wallet_idx
+    ON cashback (campaign_id, wallet_id);
+
+COMMIT;
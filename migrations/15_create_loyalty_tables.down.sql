@@ -0,0 +1,7 @@
+BEGIN;
+
+DROP TABLE IF EXISTS points_lot;
+DROP TABLE IF EXISTS points_account;
+DROP TABLE IF EXISTS loyalty_rule;
+
+COMMIT;
@@ -0,0 +1,43 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS loyalty_rule (
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(128) NOT NULL,
+    rate_bps BIGINT NOT NULL CHECK (rate_bps > 0),
+    min_amount BIGINT NOT NULL DEFAULT 0,
+    tag VARCHAR(64) NOT NULL DEFAULT '',
+    starts_at TIMESTAMPTZ NOT NULL,
+    ends_at TIMESTAMPTZ CHECK (ends_at > starts_at),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- points_account links a wallet to the wallet of kind POINTS that holds its
+-- loyalty points.
+CREATE TABLE IF NOT EXISTS points_account (
+    wallet_id VARCHAR(36) PRIMARY KEY REFERENCES wallet (id),
+    points_wallet_id VARCHAR(36) NOT NULL UNIQUE REFERENCES wallet (id)
+);
+
+-- points_lot keeps the points earned by one rule for one source
+-- transaction. The balance of a points wallet is the sum of remaining over
+-- its lots.
+CREATE TABLE IF NOT EXISTS points_lot (
+    id BIGSERIAL PRIMARY KEY,
+    points_wallet_id VARCHAR(36) NOT NULL REFERENCES wallet (id),
+    rule_id BIGINT NOT NULL REFERENCES loyalty_rule (id),
+    source_transaction_id BIGINT NOT NULL REFERENCES wallet_transaction (id),
+    points BIGINT NOT NULL CHECK (points > 0),
+    remaining BIGINT NOT NULL CHECK (remaining >= 0 AND remaining <= points),
+    expires_at TIMESTAMPTZ NOT NULL,
+    transaction_id BIGINT NOT NULL REFERENCES wallet_transaction (id),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (rule_id, source_transaction_id)
+);
+
+CREATE INDEX IF NOT EXISTS points_lot_wallet_expires_at_idx
+    ON points_lot (points_wallet_id, expires_at, id) WHERE remaining > 0;
+
+CREATE INDEX IF NOT EXISTS points_lot_expires_at_idx
+    ON points_lot (expires_at) WHERE remaining > 0;
+
+COMMIT;
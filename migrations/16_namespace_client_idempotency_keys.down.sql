@@ -0,0 +1,10 @@
+BEGIN;
+
+UPDATE wallet_transaction
+SET idempotency_key = substr(idempotency_key, length('client:' || wallet_id || ':') + 1)
+WHERE starts_with(idempotency_key, 'client:' || wallet_id || ':');
+
+ALTER TABLE wallet_transaction
+    ALTER COLUMN idempotency_key TYPE VARCHAR(128);
+
+COMMIT;
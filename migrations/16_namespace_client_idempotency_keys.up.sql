@@ -0,0 +1,14 @@
+BEGIN;
+
+-- Client keys are stored as client:<wallet>:<key>, which no longer fits the
+-- 128 characters a client may send.
+ALTER TABLE wallet_transaction
+    ALTER COLUMN idempotency_key TYPE VARCHAR(200);
+
+UPDATE wallet_transaction
+SET idempotency_key = 'client:' || wallet_id || ':' || idempotency_key
+WHERE idempotency_key IS NOT NULL
+    AND idempotency_key !~ '^(schedule|cashback|points):[0-9]+:[0-9]+$'
+    AND idempotency_key !~ '^payment-request:[0-9]+$';
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE schedule
+    DROP COLUMN IF EXISTS occurrence_at;
+
+COMMIT;
@@ -0,0 +1,13 @@
+BEGIN;
+
+-- next_run_at moves forward on every retry, so the occurrence a run belongs
+-- to is kept apart and names its idempotency key.
+ALTER TABLE schedule
+    ADD COLUMN IF NOT EXISTS occurrence_at TIMESTAMPTZ;
+
+UPDATE schedule SET occurrence_at = next_run_at WHERE occurrence_at IS NULL;
+
+ALTER TABLE schedule
+    ALTER COLUMN occurrence_at SET NOT NULL;
+
+COMMIT;
@@ -0,0 +1,20 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS wallet_transaction (
+    id BIGSERIAL PRIMARY KEY,
+    wallet_id VARCHAR(36) NOT NULL REFERENCES wallet (id),
+    operation_type VARCHAR(32) NOT NULL,
+    amount BIGINT NOT NULL,
+    balance_after BIGINT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT clock_timestamp()
+);
+
+CREATE INDEX IF NOT EXISTS wallet_transaction_wallet_id_created_at_idx
+    ON wallet_transaction (wallet_id, created_at);
+
+INSERT INTO wallet_transaction (wallet_id, operation_type, amount, balance_after)
+SELECT id, 'OPENING', balance, balance
+FROM wallet
+WHERE balance <> 0;
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+DROP TABLE IF EXISTS interest_credit;
+DROP TABLE IF EXISTS interest_accrual;
+DROP TABLE IF EXISTS savings_account;
+
+COMMIT;
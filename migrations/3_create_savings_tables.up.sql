@@ -0,0 +1,26 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS savings_account (
+    wallet_id VARCHAR(36) PRIMARY KEY REFERENCES wallet (id),
+    annual_rate_bps BIGINT NOT NULL CHECK (annual_rate_bps >= 0),
+    day_count VARCHAR(16) NOT NULL,
+    accrued_nano BIGINT NOT NULL DEFAULT 0
+);
+
+CREATE TABLE IF NOT EXISTS interest_accrual (
+    wallet_id VARCHAR(36) NOT NULL REFERENCES savings_account (wallet_id),
+    accrual_date DATE NOT NULL,
+    balance BIGINT NOT NULL,
+    amount_nano BIGINT NOT NULL,
+    PRIMARY KEY (wallet_id, accrual_date)
+);
+
+CREATE TABLE IF NOT EXISTS interest_credit (
+    wallet_id VARCHAR(36) NOT NULL REFERENCES savings_account (wallet_id),
+    period DATE NOT NULL,
+    amount BIGINT NOT NULL,
+    transaction_id BIGINT REFERENCES wallet_transaction (id),
+    PRIMARY KEY (wallet_id, period)
+);
+
+COMMIT;
@@ -0,0 +1,11 @@
+BEGIN;
+
+DROP TABLE IF EXISTS schedule;
+
+DROP INDEX IF EXISTS wallet_transaction_idempotency_key_idx;
+
+ALTER TABLE wallet_transaction
+    DROP COLUMN IF EXISTS idempotency_key,
+    DROP COLUMN IF EXISTS parent_id;
+
+COMMIT;
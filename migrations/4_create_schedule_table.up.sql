@@ -0,0 +1,35 @@
+BEGIN;
+
+ALTER TABLE wallet_transaction
+    ADD COLUMN IF NOT EXISTS parent_id BIGINT REFERENCES wallet_transaction (id),
+    ADD COLUMN IF NOT EXISTS idempotency_key VARCHAR(128);
+
+CREATE UNIQUE INDEX IF NOT EXISTS wallet_transaction_idempotency_key_idx
+    ON wallet_transaction (idempotency_key)
+    WHERE idempotency_key IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS schedule (
+    id BIGSERIAL PRIMARY KEY,
+    from_wallet_id VARCHAR(36) NOT NULL,
+    to_wallet_id VARCHAR(36) NOT NULL,
+    amount BIGINT NOT NULL CHECK (amount > 0),
+    frequency VARCHAR(16) NOT NULL,
+    cron VARCHAR(128) NOT NULL DEFAULT '',
+    start_at TIMESTAMPTZ NOT NULL,
+    next_run_at TIMESTAMPTZ NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'ACTIVE',
+    attempts INT NOT NULL DEFAULT 0,
+    last_error TEXT NOT NULL DEFAULT '',
+    last_run_at TIMESTAMPTZ,
+    locked_until TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS schedule_due_idx
+    ON schedule (next_run_at)
+    WHERE status = 'ACTIVE';
+
+CREATE INDEX IF NOT EXISTS schedule_from_wallet_id_idx ON schedule (from_wallet_id);
+CREATE INDEX IF NOT EXISTS schedule_to_wallet_id_idx ON schedule (to_wallet_id);
+
+COMMIT;
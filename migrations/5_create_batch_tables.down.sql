@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP TABLE IF EXISTS batch_item;
+DROP TABLE IF EXISTS batch;
+
+COMMIT;
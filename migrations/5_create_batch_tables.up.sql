@@ -0,0 +1,31 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS batch (
+    id BIGSERIAL PRIMARY KEY,
+    mode VARCHAR(16) NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'PENDING',
+    total INT NOT NULL,
+    succeeded INT NOT NULL DEFAULT 0,
+    failed INT NOT NULL DEFAULT 0,
+    locked_until TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    completed_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS batch_unfinished_idx
+    ON batch (id)
+    WHERE status IN ('PENDING', 'PROCESSING');
+
+CREATE TABLE IF NOT EXISTS batch_item (
+    batch_id BIGINT NOT NULL REFERENCES batch (id),
+    line INT NOT NULL,
+    wallet_id VARCHAR(36) NOT NULL,
+    operation_type VARCHAR(32) NOT NULL,
+    amount BIGINT NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'PENDING',
+    error TEXT NOT NULL DEFAULT '',
+    transaction_id BIGINT REFERENCES wallet_transaction (id),
+    PRIMARY KEY (batch_id, line)
+);
+
+COMMIT;
@@ -0,0 +1,14 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS wallet_balance_snapshot (
+    wallet_id VARCHAR(36) NOT NULL REFERENCES wallet (id),
+    transaction_id BIGINT NOT NULL REFERENCES wallet_transaction (id),
+    balance BIGINT NOT NULL,
+    taken_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (wallet_id, transaction_id)
+);
+
+CREATE INDEX IF NOT EXISTS wallet_balance_snapshot_wallet_id_taken_at_idx
+    ON wallet_balance_snapshot (wallet_id, taken_at);
+
+COMMIT;
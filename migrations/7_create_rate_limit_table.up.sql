@@ -0,0 +1,14 @@
+BEGIN;
+
+-- Buckets are short-lived counters; losing them on a crash only resets the
+-- limits, so the table skips the WAL.
+CREATE UNLOGGED TABLE IF NOT EXISTS rate_limit_bucket (
+    key VARCHAR(200) PRIMARY KEY,
+    tokens DOUBLE PRECISION NOT NULL,
+    allowed BOOLEAN NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS rate_limit_bucket_updated_at_idx ON rate_limit_bucket (updated_at);
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE wallet_transaction
+    DROP COLUMN IF EXISTS metadata;
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE wallet_transaction
+    ADD COLUMN IF NOT EXISTS metadata JSONB;
+
+COMMIT;
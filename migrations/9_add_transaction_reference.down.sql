@@ -0,0 +1,11 @@
+BEGIN;
+
+DROP INDEX IF EXISTS wallet_transaction_tags_idx;
+DROP INDEX IF EXISTS wallet_transaction_wallet_id_reference_idx;
+
+ALTER TABLE wallet_transaction
+    DROP COLUMN IF EXISTS tags,
+    DROP COLUMN IF EXISTS description,
+    DROP COLUMN IF EXISTS reference;
+
+COMMIT;
@@ -0,0 +1,16 @@
+BEGIN;
+
+ALTER TABLE wallet_transaction
+    ADD COLUMN IF NOT EXISTS reference VARCHAR(128),
+    ADD COLUMN IF NOT EXISTS description VARCHAR(500),
+    ADD COLUMN IF NOT EXISTS tags JSONB;
+
+CREATE INDEX IF NOT EXISTS wallet_transaction_wallet_id_reference_idx
+    ON wallet_transaction (wallet_id, reference)
+    WHERE reference IS NOT NULL;
+
+CREATE INDEX IF NOT EXISTS wallet_transaction_tags_idx
+    ON wallet_transaction USING GIN (tags)
+    WHERE tags IS NOT NULL;
+
+COMMIT;